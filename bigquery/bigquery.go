@@ -0,0 +1,215 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bigquery exports the compliance decisions of a run into a BigQuery
+// table via streaming inserts, for longitudinal queries. The table must exist
+// with the following schema, matching `Row`:
+//
+//	timestamp  TIMESTAMP  REQUIRED
+//	org        STRING     REQUIRED
+//	repo       STRING     REQUIRED
+//	number     INTEGER    REQUIRED
+//	title      STRING
+//	url        STRING
+//	status     STRING     REQUIRED
+//	reason     STRING
+//	commits    RECORD     REPEATED
+//	  sha        STRING   REQUIRED
+//	  status     STRING   REQUIRED
+//	  reason     STRING
+//	  company    STRING
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/code-review-bot/report"
+)
+
+// DefaultEndpoint is the base URL of the BigQuery API.
+const DefaultEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+
+// maxRowsPerRequest is the number of rows sent with each streaming insert,
+// well within the limits of the API.
+const maxRowsPerRequest = 500
+
+// Row is the compliance decision for a single pull request, as exported.
+type Row struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Org       string      `json:"org"`
+	Repo      string      `json:"repo"`
+	Number    int         `json:"number"`
+	Title     string      `json:"title,omitempty"`
+	URL       string      `json:"url,omitempty"`
+	Status    string      `json:"status"`
+	Reason    string      `json:"reason,omitempty"`
+	Commits   []CommitRow `json:"commits,omitempty"`
+}
+
+// CommitRow is the compliance decision for a single commit, as exported.
+type CommitRow struct {
+	SHA     string `json:"sha"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Company string `json:"company,omitempty"`
+}
+
+// Exporter inserts rows into a BigQuery table.
+type Exporter struct {
+	client   *http.Client
+	endpoint string
+	project  string
+	dataset  string
+	table    string
+}
+
+// NewExporter returns an exporter into the given table, sending requests via
+// the client, which must authenticate them (see `gcp.NewClient`).
+func NewExporter(client *http.Client, project string, dataset string, table string) *Exporter {
+	return &Exporter{
+		client:   client,
+		endpoint: DefaultEndpoint,
+		project:  project,
+		dataset:  dataset,
+		table:    table,
+	}
+}
+
+// SetEndpoint overrides the base URL of the BigQuery API, e.g., for tests.
+func (e *Exporter) SetEndpoint(endpoint string) {
+	e.endpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+// NewRows converts the pull requests in the report into rows stamped with the
+// time of the run.
+func NewRows(r *report.Report, timestamp time.Time) []Row {
+	var rows []Row
+	for _, pr := range r.PullRequests {
+		row := Row{
+			Timestamp: timestamp,
+			Org:       pr.Org,
+			Repo:      pr.Repo,
+			Number:    pr.Number,
+			Title:     pr.Title,
+			URL:       pr.URL,
+			Status:    pr.Status(),
+			Reason:    pr.Reason,
+		}
+		for _, commit := range pr.Commits {
+			reason := commit.Reason
+			if commit.Exemption != "" {
+				reason = commit.Exemption
+			}
+			row.Commits = append(row.Commits, CommitRow{
+				SHA:     commit.SHA,
+				Status:  commit.Status(),
+				Reason:  reason,
+				Company: commit.Company,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+type insertAllRow struct {
+	InsertID string `json:"insertId"`
+	JSON     Row    `json:"json"`
+}
+
+type insertAllRequest struct {
+	Rows []insertAllRow `json:"rows"`
+}
+
+type insertAllResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+// Export inserts the rows, in batches. Each row has an insert ID derived from
+// the pull request and the timestamp, so that retries are deduplicated.
+func (e *Exporter) Export(ctx context.Context, rows []Row) error {
+	for start := 0; start < len(rows); start += maxRowsPerRequest {
+		end := start + maxRowsPerRequest
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := e.insertAll(ctx, rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) insertAll(ctx context.Context, rows []Row) error {
+	var request insertAllRequest
+	for _, row := range rows {
+		request.Rows = append(request.Rows, insertAllRow{
+			InsertID: fmt.Sprintf("%s/%s#%d@%d", row.Org, row.Repo, row.Number, row.Timestamp.UnixNano()),
+			JSON:     row,
+		})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	insertURL := fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", e.endpoint,
+		url.PathEscape(e.project), url.PathEscape(e.dataset), url.PathEscape(e.table))
+	req, err := http.NewRequest("POST", insertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error inserting rows into BigQuery table %s.%s.%s: %s: %s", e.project, e.dataset, e.table, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var response insertAllResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("error parsing BigQuery response: %s", err)
+	}
+	if len(response.InsertErrors) > 0 {
+		insertError := response.InsertErrors[0]
+		message := "unknown error"
+		if len(insertError.Errors) > 0 {
+			message = insertError.Errors[0].Message
+		}
+		return fmt.Errorf("error inserting %d of %d rows into BigQuery table %s.%s.%s, e.g., row %d: %s",
+			len(response.InsertErrors), len(rows), e.project, e.dataset, e.table, insertError.Index, message)
+	}
+	return nil
+}
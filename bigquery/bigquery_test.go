@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/report"
+)
+
+var runTime = time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func newTestReport() *report.Report {
+	r := report.New()
+	r.AddPullRequest(report.PullRequest{
+		Org: "org", Repo: "repo", Number: 42, Title: "Fix", Reason: "Not a signer",
+		Commits: []report.Commit{
+			{SHA: "aaa111", Compliant: true, Company: "Acme"},
+			{SHA: "bbb222", Reason: "Not a signer"},
+			{SHA: "ccc333", Compliant: true, Exemption: "Vendored import"},
+		},
+	})
+	r.AddPullRequest(report.PullRequest{Org: "org", Repo: "repo", Number: 43, External: true})
+	return r
+}
+
+func TestNewRows(t *testing.T) {
+	rows := NewRows(newTestReport(), runTime)
+	assert.Equal(t, []Row{
+		{
+			Timestamp: runTime, Org: "org", Repo: "repo", Number: 42, Title: "Fix",
+			Status: report.StatusNonCompliant, Reason: "Not a signer",
+			Commits: []CommitRow{
+				{SHA: "aaa111", Status: report.StatusCompliant, Company: "Acme"},
+				{SHA: "bbb222", Status: report.StatusNonCompliant, Reason: "Not a signer"},
+				{SHA: "ccc333", Status: report.StatusExempted, Reason: "Vendored import"},
+			},
+		},
+		{Timestamp: runTime, Org: "org", Repo: "repo", Number: 43, Status: report.StatusExternal},
+	}, rows)
+}
+
+func TestExport(t *testing.T) {
+	var requests []insertAllRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/projects/proj/datasets/cla/tables/decisions/insertAll", req.URL.Path)
+		var request insertAllRequest
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&request))
+		requests = append(requests, request)
+		w.Write([]byte(`{"kind": "bigquery#tableDataInsertAllResponse"}`))
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.Client(), "proj", "cla", "decisions")
+	exporter.SetEndpoint(server.URL + "/")
+
+	// Rows are sent in batches.
+	var rows []Row
+	for i := 0; i < maxRowsPerRequest+1; i++ {
+		rows = append(rows, Row{Timestamp: runTime, Org: "org", Repo: "repo", Number: i, Status: report.StatusCompliant})
+	}
+	assert.Nil(t, exporter.Export(context.Background(), rows))
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, maxRowsPerRequest, len(requests[0].Rows))
+	assert.Equal(t, 1, len(requests[1].Rows))
+	assert.Equal(t, "org/repo#500@1772600767000000000", requests[1].Rows[0].InsertID)
+	assert.Equal(t, 500, requests[1].Rows[0].JSON.Number)
+}
+
+func TestExport_InsertErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`{"insertErrors": [{"index": 1, "errors": [{"reason": "invalid", "message": "no such field: foo"}]}]}`))
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.Client(), "proj", "cla", "decisions")
+	exporter.SetEndpoint(server.URL)
+	err := exporter.Export(context.Background(), NewRows(newTestReport(), runTime))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error inserting 1 of 2 rows")
+	assert.Contains(t, err.Error(), "no such field: foo")
+}
+
+func TestExport_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "table not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	exporter := NewExporter(server.Client(), "proj", "cla", "decisions")
+	exporter.SetEndpoint(server.URL)
+	err := exporter.Export(context.Background(), NewRows(newTestReport(), runTime))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found: table not found")
+}
@@ -0,0 +1,431 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/code-review-bot/bigquery"
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
+	"github.com/google/code-review-bot/gcp"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/code-review-bot/state"
+)
+
+// checkMain implements the `check` subcommand, which checks the CLA compliance
+// of the open PRs in the target repos and, with -update-repo, labels them and
+// comments on them accordingly. It is also run when no subcommand is given.
+func checkMain(args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, or its location in a GitHub repo, as repo://ORG/REPO/PATH[@REF]; required")
+	orgFlag := flags.String("org", "", "Name of organization or username; required if not set in config file")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos, e.g., 'cloud-*,infra-*'; if empty, implies all repos in org")
+	prFlag := flags.String("pr", "", "Comma-separated list of PRs, ranges of PRs, or PR URLs to process, e.g., '100-150,200' or 'https://github.com/ORG/REPO/pull/123'; PR numbers refer to -org and -repo, while URLs may be in other repos, each processed in turn, and set -org and -repo if not given")
+	baseBranchFlag := flags.String("base-branch", "", "Comma-separated names or glob patterns of base branches, e.g., 'main,release-*'; if set, only PRs targeting these branches are processed, overriding base_branches in the config file")
+	updateRepoFlag := flags.Bool("update-repo", false, "Update labels on the repo")
+	noCommentsFlag := flags.Bool("no-comments", false, "Don't comment on non-compliant PRs, only label them, as with no_comments in the config file")
+	logFlags := addLogFlags(flags)
+	diffFlag := flags.Bool("diff", false, "Print only the changes to labels and comments that the run applies (or would apply, without -update-repo), one per line, instead of the regular log")
+	dryRunFlag := flags.Bool("dry-run", false, "Don't update any PRs, but print the changes the run would apply as a JSON action plan instead of the regular log, and exit with status 3 if there are any")
+	summaryFileFlag := flags.String("summary", "", "Path to write a JSON summary of the run, with the PRs processed, labels added and removed, comments posted, errors, API calls, and duration; optional")
+	quietFlag := flags.Bool("quiet", false, "Don't log the details of each PR, but show a progress indicator on stderr instead, with the repos and PRs processed and the estimated time remaining")
+	reportFileFlag := flags.String("report", "", "Path to write a report of the compliance results; optional")
+	contributorsFileFlag := flags.String("contributors", "", "Path to write a CSV list of non-compliant contributors and the PRs they affect; optional")
+	maxAPICallsFlag := flags.Int("max-api-calls", 0, "Budget of GitHub API calls for this run; once exhausted, the run stops and saves its progress to -progress; 0 means unlimited")
+	maxPRsFlag := flags.Int("max-prs", 0, "Maximum number of PRs to process in this run; once reached, the run stops and saves its progress to -progress; 0 means unlimited")
+	progressFileFlag := flags.String("progress", "", "Path to a JSON file where the run saves its progress when -max-api-calls is exhausted or -max-prs is reached, and from which the next run resumes; required with -max-api-calls and -max-prs")
+	stateFileFlag := flags.String("state", "", "Where the bot remembers what it did to each PR across runs, to send reminders and skip PRs which have not changed: a JSON file path, sqlite:PATH for an SQLite database (if built with -tags sqlite), or redis://[:PASSWORD@]HOST:PORT[/DB] for a Redis server shared by multiple instances; optional")
+	reportFormatFlag := flags.String("report-format", report.FormatSARIF, "Format of the report written to -report; accepted: "+strings.Join(report.Formats, ", "))
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s check [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nNote: -cla-signers, -config and -secrets accept YAML and JSON files.\n")
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	connFlags.check()
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	if *maxAPICallsFlag > 0 && *progressFileFlag == "" {
+		logging.Fatalf("-progress flag is required with -max-api-calls")
+	}
+	if *maxPRsFlag > 0 && *progressFileFlag == "" {
+		logging.Fatalf("-progress flag is required with -max-prs")
+	}
+
+	if *dryRunFlag && (*updateRepoFlag || *diffFlag) {
+		logging.Fatalf("-dry-run can't be combined with -update-repo or -diff")
+	}
+
+	if *reportFileFlag != "" && !report.IsSupportedFormat(*reportFormatFlag) {
+		logging.Fatalf("Invalid value for flag -report-format: %s; accepted: %s", *reportFormatFlag, strings.Join(report.Formats, ", "))
+	}
+
+	// Read and parse required auth, config, and CLA signers files.
+	secrets := connFlags.loadSecrets()
+	cfg := config.ParseConfig(*configFileFlag)
+
+	var prGroups []prGroup
+	if *prFlag != "" {
+		groups, err := parsePRFlag(*prFlag)
+		if err != nil {
+			logging.Fatalf("Invalid value for flag -pr: %s", err)
+		}
+		// Without -org and -repo, plain PR numbers refer to the repo of
+		// the first PR URL.
+		for _, group := range groups {
+			if *orgFlag == "" && *repoFlag == "" && group.org != "" {
+				*orgFlag, *repoFlag = group.org, group.repo
+				break
+			}
+		}
+		prGroups = groups
+	}
+
+	orgName, repoName := resolveOrgRepo(*orgFlag, *repoFlag, cfg)
+	prGroups = resolvePRGroups(prGroups, orgName, repoName)
+	if len(prGroups) == 0 {
+		prGroups = []prGroup{{org: orgName, repo: repoName}}
+	}
+	if len(prGroups) > 1 && *progressFileFlag != "" {
+		logging.Fatalf("-progress can't be combined with -pr URLs in more than one repo")
+	}
+
+	checkConfig(cfg)
+
+	if cfg.Reminders.IntervalDays > 0 && *stateFileFlag == "" {
+		logging.Fatalf("-state flag is required with `reminders` in config file")
+	}
+
+	// Process org and repo(s) specified on the command-line.
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	configureGitHubClient(ghc, cfg, secrets)
+	// Fail fast if the token lacks permissions, rather than on each PR; the
+	// requests of this check are not in recordings made before it existed.
+	if *connFlags.replay == "" {
+		for _, group := range prGroups {
+			if err := ghutil.CheckPermissions(ghc, group.org, group.repo, *updateRepoFlag); err != nil {
+				logging.Fatalf("Error checking the permissions of the GitHub token: %s", err)
+			}
+		}
+	}
+	// The CLA signers may be read from a repo via the client.
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+	if *reportFileFlag != "" || *contributorsFileFlag != "" || cfg.BigQuery.Table != "" || cfg.TrackingIssue.Enabled {
+		ghc.Report = report.New()
+	}
+	if *diffFlag {
+		ghc.Diff = ghutil.NewDiffWriter(os.Stdout)
+		logging.SetQuiet(true)
+	} else if *dryRunFlag {
+		ghc.Diff = ghutil.NewDiffWriter(nil)
+		logging.SetQuiet(true)
+	}
+	ghc.Counts = ghutil.NewRunCounts()
+	if *quietFlag {
+		logging.SetQuiet(true)
+		ghc.Progress = ghutil.NewProgress(time.Now())
+	}
+	if *stateFileFlag != "" {
+		store, err := state.Open(*stateFileFlag)
+		if err != nil {
+			logging.Fatalf("Error opening state store '%s': %s", *stateFileFlag, err)
+		}
+		ghc.State = store
+	}
+	if *noCommentsFlag {
+		cfg.NoComments = true
+	}
+	var repoSpecs []ghutil.GitHubProcessOrgRepoSpec
+	for _, group := range prGroups {
+		repoSpec := crbot.NewOrgRepoSpec(cfg, group.org, group.repo, *updateRepoFlag)
+		repoSpec.Pulls = group.pulls
+		if *baseBranchFlag != "" {
+			repoSpec.BaseBranches = ghutil.ParseRepoSelector(*baseBranchFlag)
+		}
+		repoSpec.MaxAPICalls = *maxAPICallsFlag
+		repoSpec.MaxPulls = *maxPRsFlag
+		if *progressFileFlag != "" {
+			repoSpec.ResumeFrom = readCheckpoint(*progressFileFlag, group.org)
+		}
+		repoSpecs = append(repoSpecs, repoSpec)
+	}
+	runTime := time.Now().UTC()
+	errorsBefore := logging.ErrorCount()
+	var indicator *progressIndicator
+	if ghc.Progress != nil {
+		indicator = startProgressIndicator(ghc.Progress)
+	}
+	// The repos of -pr are processed in turn, each despite errors in the
+	// others; all but the last error are logged right away.
+	var checkpoint *ghutil.Checkpoint
+	var runErr error
+	var runErrOrg string
+	for _, repoSpec := range repoSpecs {
+		repoCheckpoint, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+		checkpoint = repoCheckpoint
+		if err != nil {
+			if runErr != nil {
+				logging.Errorf("Error processing org %s: %s", runErrOrg, runErr)
+			}
+			runErr, runErrOrg = err, repoSpec.Org
+		}
+	}
+	if indicator != nil {
+		indicator.stop()
+	}
+	finishGitHubClient(ghc)
+	if ghc.State != nil {
+		if err := ghc.State.Close(); err != nil {
+			logging.Errorf("Error saving state store '%s': %s", *stateFileFlag, err)
+		}
+	}
+	// After an error, the run may not have covered all repos, so it must not
+	// clear the progress of the previous runs.
+	if *progressFileFlag != "" && (runErr == nil || checkpoint != nil) {
+		saveCheckpoint(*progressFileFlag, checkpoint)
+	}
+
+	if *reportFileFlag != "" {
+		writeReport(*reportFileFlag, *reportFormatFlag, ghc.Report)
+	}
+	if *contributorsFileFlag != "" {
+		writeContributors(*contributorsFileFlag, ghc.Report)
+	}
+	if cfg.BigQuery.Table != "" {
+		exportBigQuery(cfg.BigQuery, ghc.Report, runTime)
+	}
+
+	summary := ghc.Counts.Summary()
+	summary.Errors = logging.ErrorCount() - errorsBefore
+	if ghc.Usage != nil {
+		summary.APICalls = ghc.Usage.Usage().Calls
+	}
+	summary.Duration = time.Since(runTime)
+	// With -quiet, the summary takes the place of the log.
+	if *quietFlag {
+		fmt.Fprintln(os.Stderr, summary)
+	} else {
+		logging.Info(summary)
+	}
+	if *summaryFileFlag != "" {
+		writeRunSummary(*summaryFileFlag, summary)
+	}
+
+	if runErr != nil {
+		logging.Fatalf("Error processing org %s: %s", runErrOrg, runErr)
+	}
+	if *dryRunFlag {
+		changes := ghc.Diff.Changes()
+		if err := ghutil.WritePlan(os.Stdout, changes); err != nil {
+			logging.Fatalf("Error writing action plan: %s", err)
+		}
+		if len(changes) > 0 {
+			os.Exit(exitChangesPending)
+		}
+	}
+}
+
+// exitChangesPending is the exit status of a dry run which would have changed
+// any PRs, distinct from those of errors (1) and invalid flags (2).
+const exitChangesPending = 3
+
+// writeContributors writes the list of non-compliant contributors found in
+// this run to the given file.
+func writeContributors(filename string, r *report.Report) {
+	contributorsFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating contributors file '%s': %s", filename, err)
+	}
+	defer contributorsFile.Close()
+
+	if err := report.WriteContributorsCSV(contributorsFile, r); err != nil {
+		logging.Fatalf("Error writing contributors file '%s': %s", filename, err)
+	}
+}
+
+// exportBigQuery exports the compliance decisions of this run to BigQuery;
+// errors are not fatal, as the run itself has completed.
+func exportBigQuery(cfg config.BigQuery, r *report.Report, runTime time.Time) {
+	ctx := context.Background()
+	client, err := gcp.NewClient(ctx, cfg.Credentials, gcp.ScopeBigQueryInsert)
+	if err != nil {
+		logging.Errorf("Error authenticating to BigQuery: %s", err)
+		return
+	}
+	rows := bigquery.NewRows(r, runTime)
+	exporter := bigquery.NewExporter(client, cfg.Project, cfg.Dataset, cfg.Table)
+	if err := exporter.Export(ctx, rows); err != nil {
+		logging.Errorf("Error exporting to BigQuery: %s", err)
+		return
+	}
+	logging.Infof("Exported %d compliance decision(s) to BigQuery table %s.%s.%s", len(rows), cfg.Project, cfg.Dataset, cfg.Table)
+}
+
+// writeReport writes the accumulated results of this run to the given file.
+func writeReport(filename string, format string, r *report.Report) {
+	reportFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating report file '%s': %s", filename, err)
+	}
+	defer reportFile.Close()
+
+	if err := report.Write(reportFile, format, r); err != nil {
+		logging.Fatalf("Error writing report file '%s': %s", filename, err)
+	}
+}
+
+// writeRunSummary writes the summary of this run to the given file as JSON.
+func writeRunSummary(filename string, summary report.RunSummary) {
+	summaryFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating summary file '%s': %s", filename, err)
+	}
+	defer summaryFile.Close()
+
+	if err := report.WriteRunSummaryJSON(summaryFile, summary); err != nil {
+		logging.Fatalf("Error writing summary file '%s': %s", filename, err)
+	}
+}
+
+// prGroup is a repo along with the PRs of it to process, as given via -pr.
+type prGroup struct {
+	org   string
+	repo  string
+	pulls []int
+}
+
+// parsePRFlag parses the value of -pr, in which PRs may also be given by URL,
+// e.g., "https://github.com/org/repo/pull/123", grouping the PRs by repo in
+// order of first appearance. The plain PR numbers, if any, are in the first
+// group, whose org and repo are left empty, as they refer to -org and -repo.
+func parsePRFlag(value string) ([]prGroup, error) {
+	var plain []string
+	var groups []prGroup
+	for _, elt := range strings.Split(value, ",") {
+		elt = strings.TrimSpace(elt)
+		if !strings.Contains(elt, "://") {
+			plain = append(plain, elt)
+			continue
+		}
+		org, repo, number, err := ghutil.ParsePullURL(elt)
+		if err != nil {
+			return nil, err
+		}
+		groups = addToPRGroup(groups, prGroup{org: org, repo: repo, pulls: []int{number}})
+	}
+	if len(plain) == 0 {
+		return groups, nil
+	}
+	prNumbers, err := parsePRNumbers(strings.Join(plain, ","))
+	if err != nil {
+		return nil, err
+	}
+	return append([]prGroup{{pulls: prNumbers}}, groups...), nil
+}
+
+// resolvePRGroups sets the org and repo of the group of plain PR numbers, if
+// any, merging it with the group of PR URLs in the same repo, if any.
+func resolvePRGroups(groups []prGroup, orgName string, repoName string) []prGroup {
+	var resolved []prGroup
+	for _, group := range groups {
+		if group.org == "" {
+			group.org, group.repo = orgName, repoName
+		}
+		resolved = addToPRGroup(resolved, group)
+	}
+	return resolved
+}
+
+// addToPRGroup adds the PRs to the group of the same repo, if any, without
+// duplicates, or as a new group otherwise.
+func addToPRGroup(groups []prGroup, added prGroup) []prGroup {
+	for i, group := range groups {
+		if !strings.EqualFold(group.org, added.org) || !strings.EqualFold(group.repo, added.repo) {
+			continue
+		}
+		for _, number := range added.pulls {
+			if !containsInt(group.pulls, number) {
+				groups[i].pulls = append(groups[i].pulls, number)
+			}
+		}
+		return groups
+	}
+	return append(groups, added)
+}
+
+// containsInt returns whether the slice contains the number.
+func containsInt(numbers []int, number int) bool {
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+	return false
+}
+
+// maxPRRange is the largest number of PRs a single range in -pr may expand to,
+// guarding against typos such as '100-15000'.
+const maxPRRange = 10000
+
+// parsePRNumbers expands a comma-separated list of PR numbers and inclusive
+// ranges of PR numbers, e.g., "100-150,200", in order and without duplicates.
+func parsePRNumbers(value string) ([]int, error) {
+	var prNumbers []int
+	seen := make(map[int]bool)
+	for _, elt := range strings.Split(value, ",") {
+		elt = strings.TrimSpace(elt)
+		first, last := elt, elt
+		if dash := strings.Index(elt, "-"); dash >= 0 {
+			first, last = elt[:dash], elt[dash+1:]
+		}
+		start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 32)
+		if err != nil || start <= 0 {
+			return nil, fmt.Errorf("'%s' is not a PR number or range", elt)
+		}
+		end, err := strconv.ParseInt(strings.TrimSpace(last), 10, 32)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("'%s' is not a PR number or range", elt)
+		}
+		if end-start >= maxPRRange {
+			return nil, fmt.Errorf("range '%s' spans more than %d PRs", elt, maxPRRange)
+		}
+		for num := int(start); num <= int(end); num++ {
+			if !seen[num] {
+				seen[num] = true
+				prNumbers = append(prNumbers, num)
+			}
+		}
+	}
+	return prNumbers, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// checkLocalMain implements the `check-local` subcommand, which checks the
+// CLA compliance of the commits in a local Git checkout against the CLA
+// signers file, without connecting to GitHub, so that contributors and CI can
+// catch problems before pushing. As local commits carry no GitHub logins,
+// they are inferred from the commit emails (see `ghutil.LocalLogin`). It exits
+// with an error if any commit is not compliant.
+func checkLocalMain(args []string) {
+	flags := flag.NewFlagSet("check-local", flag.ExitOnError)
+	configFileFlag := flags.String("config", "", "Path to config file, for unknown_as_external; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers; required")
+	repoPathFlag := flags.String("repo-path", ".", "Path to the local Git checkout")
+	rangeFlag := flags.String("range", "origin/main..HEAD", "Range of commits to check, as accepted by git log")
+	prePushFlag := flags.String("pre-push", "", "Name of the remote being pushed to, as passed to Git pre-push hooks (see hook install); if set, the commits being pushed, as read from stdin, are checked instead of -range")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s check-local [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	var cfg config.Config
+	if *configFileFlag != "" {
+		cfg = config.ParseConfig(*configFileFlag)
+	}
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+
+	var commits []ghutil.LocalCommit
+	if *prePushFlag != "" {
+		updates, err := ghutil.ParsePrePushUpdates(os.Stdin)
+		if err != nil {
+			logging.Fatalf("Error reading refs being pushed: %s", err)
+		}
+		commits = readPushCommits(updates, func(update ghutil.PushUpdate) ([]ghutil.LocalCommit, error) {
+			return ghutil.ReadUnpushedCommits(*repoPathFlag, *prePushFlag, update)
+		})
+	} else {
+		var err error
+		if commits, err = ghutil.ReadLocalCommits(*repoPathFlag, *rangeFlag); err != nil {
+			logging.Fatalf("Error reading commits: %s", err)
+		}
+	}
+
+	nonCompliant := checkLocalCommits(commits, claSigners, cfg.UnknownAsExternal)
+	fmt.Printf("%d commit(s) checked, %d not compliant\n", len(commits), nonCompliant)
+	if nonCompliant > 0 {
+		os.Exit(1)
+	}
+}
+
+// readPushCommits reads the commits of each of the ref updates of a push via
+// `read`, skipping those already read, as the same commits may be pushed to
+// multiple refs at once.
+func readPushCommits(updates []ghutil.PushUpdate, read func(update ghutil.PushUpdate) ([]ghutil.LocalCommit, error)) []ghutil.LocalCommit {
+	var commits []ghutil.LocalCommit
+	seen := make(map[string]bool)
+	for _, update := range updates {
+		pushed, err := read(update)
+		if err != nil {
+			logging.Fatalf("Error reading commits pushed to %s: %s", update.Ref, err)
+		}
+		for _, commit := range pushed {
+			if !seen[commit.SHA] {
+				seen[commit.SHA] = true
+				commits = append(commits, commit)
+			}
+		}
+	}
+	return commits
+}
+
+// checkLocalCommits prints the CLA compliance of each of the local commits,
+// and returns the number of commits which are not compliant.
+func checkLocalCommits(commits []ghutil.LocalCommit, claSigners config.ClaSigners, unknownAsExternal bool) int {
+	nonCompliant := 0
+	for _, commit := range commits {
+		commitStatus := ghutil.CheckLocalCommit(commit, claSigners, unknownAsExternal)
+		sha := commit.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		subject := strings.SplitN(commit.Message, "\n", 2)[0]
+		switch {
+		case commitStatus.External:
+			fmt.Printf("%s %s: CLA managed externally\n", sha, subject)
+		case commitStatus.Exemption != "":
+			fmt.Printf("%s %s: exempt (%s)\n", sha, subject, commitStatus.Exemption)
+		case commitStatus.Compliant:
+			fmt.Printf("%s %s: compliant\n", sha, subject)
+		default:
+			nonCompliant++
+			fmt.Printf("%s %s: not compliant: %s\n", sha, subject, commitStatus.NonComplianceReason)
+			for _, unmatched := range commitStatus.Unmatched {
+				fmt.Printf("    %s: %s <%s>, GitHub: %s\n", unmatched.Role, unmatched.Account.Name, unmatched.Account.Email, unmatched.Account.Login)
+			}
+		}
+	}
+	return nonCompliant
+}
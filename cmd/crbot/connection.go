@@ -0,0 +1,169 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/keyring"
+	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/replay"
+)
+
+// connectionFlags are the flags shared by all subcommands which control how
+// to connect and authenticate to GitHub.
+type connectionFlags struct {
+	secrets *string
+	proxy   *string
+	record  *string
+	replay  *string
+}
+
+// addConnectionFlags registers the connection flags with the flag set.
+func addConnectionFlags(flags *flag.FlagSet) *connectionFlags {
+	return &connectionFlags{
+		secrets: flags.String("secrets", "", "Path to secrets file, or keyring:[SERVICE[/ACCOUNT]] to read the GitHub token from the OS keyring; required unless -replay is set"),
+		proxy:   flags.String("proxy", "", "URL of the HTTP(S) proxy to connect to GitHub through; if empty, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used"),
+		record:  flags.String("record", "", "Path to a file to record all GitHub API interactions of this run to, for later use with -replay; optional"),
+		replay:  flags.String("replay", "", "Path to a file recorded via -record to answer GitHub API requests from, instead of connecting to GitHub; optional"),
+	}
+}
+
+// check validates the connection flags.
+func (c *connectionFlags) check() {
+	if *c.secrets == "" && *c.replay == "" {
+		logging.Fatalf("-secrets flag is required")
+	} else if *c.record != "" && *c.replay != "" {
+		logging.Fatalf("-record and -replay flags are mutually exclusive")
+	}
+}
+
+// loadSecrets reads the secrets from the file given via -secrets or, for a
+// location such as "keyring:crbot/github-token", reads the GitHub token from
+// the OS keyring. No secrets are needed to replay a recording.
+func (c *connectionFlags) loadSecrets() config.Secrets {
+	secrets, err := c.readSecrets()
+	if err != nil {
+		// Errors start in lowercase, while log lines don't.
+		message := err.Error()
+		logging.Fatalf("%s%s", strings.ToUpper(message[:1]), message[1:])
+	}
+	return secrets
+}
+
+// readSecrets is `loadSecrets`, returning any error rather than exiting.
+func (c *connectionFlags) readSecrets() (config.Secrets, error) {
+	location := *c.secrets
+	if location == "" && *c.replay != "" {
+		return config.Secrets{}, nil
+	}
+	if !keyring.IsSpec(location) {
+		return config.LoadSecrets(location)
+	}
+	service, account, err := keyring.ParseSpec(location)
+	if err != nil {
+		return config.Secrets{}, fmt.Errorf("invalid value for flag -secrets: %s", err)
+	}
+	token, err := keyring.Get(service, account)
+	if err != nil {
+		return config.Secrets{}, fmt.Errorf("error reading secrets: %s", err)
+	}
+	return config.Secrets{Auth: token}, nil
+}
+
+// recorder, if non-nil, records the GitHub API interactions of this run, to
+// be saved to the -record file by `finishGitHubClient`.
+var (
+	recorder     *replay.Recorder
+	recordingDst string
+)
+
+// newGitHubClient configures authentication and connects to GitHub, via the
+// -proxy URL or the proxy set in the environment, if any, and trusting the CA
+// bundle and sending the user agent and headers from the config file. With
+// -replay, requests are answered from the recording instead.
+func newGitHubClient(secrets config.Secrets, cfg config.Config, conn *connectionFlags) *ghutil.GitHubClient {
+	var transport http.RoundTripper
+	if *conn.replay != "" {
+		cassette, err := replay.Load(*conn.replay)
+		if err != nil {
+			logging.Fatalf("Error reading recording '%s': %s", *conn.replay, err)
+		}
+		transport = replay.NewReplayer(cassette)
+	} else {
+		networkTransport, err := ghutil.NewTransport(ghutil.TransportOptions{
+			ProxyURL: *conn.proxy,
+			CABundle: cfg.CABundle,
+		})
+		if err != nil {
+			logging.Fatalf("Error configuring connection to GitHub: %s", err)
+		}
+		transport = networkTransport
+		if breaker := crbot.NewBreakerTransport(transport, cfg.CircuitBreaker); breaker != nil {
+			transport = breaker
+		}
+		if *conn.record != "" {
+			recorder = replay.NewRecorder(transport)
+			recordingDst = *conn.record
+			transport = recorder
+		}
+	}
+
+	usage := ghutil.NewUsageTransport(ghutil.NewHeaderTransport(transport, cfg.Headers))
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: usage})
+
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: secrets.Auth},
+	)
+	tc := oauth2.NewClient(ctx, ts)
+	ghc := ghutil.NewClient(tc, cfg.UserAgent)
+	ghc.Usage = usage
+	config.SetRepoFileFetcher(ghc.FetchFile)
+	return ghc
+}
+
+// finishGitHubClient logs the errors suppressed as repeats, the GitHub API
+// calls made during the run, and the remaining rate limit, recording them in
+// the report, if any, and saves the recording of the run, if requested.
+func finishGitHubClient(ghc *ghutil.GitHubClient) {
+	logging.FlushRepeats()
+	if recorder != nil {
+		if err := recorder.Cassette().Save(recordingDst); err != nil {
+			logging.Errorf("Error saving recording '%s': %s", recordingDst, err)
+		} else {
+			logging.Infof("Saved recording of GitHub API interactions to '%s'", recordingDst)
+		}
+	}
+
+	if ghc.Usage == nil {
+		return
+	}
+	usage := ghc.Usage.Usage()
+	logging.Infof("GitHub API calls: %d; rate limit: %d of %d remaining, resets at %s",
+		usage.Calls, usage.Remaining, usage.Limit, usage.Reset.Format(time.RFC3339))
+	if ghc.Report != nil {
+		ghc.Report.SetAPIUsage(usage)
+	}
+}
@@ -15,92 +15,151 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path"
-	"strconv"
 	"strings"
 
-	"golang.org/x/oauth2"
-
 	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
 	"github.com/google/code-review-bot/ghutil"
 	"github.com/google/code-review-bot/logging"
 )
 
-func main() {
-	secretsFileFlag := flag.String("secrets", "", "Path to secrets file; required")
-	configFileFlag := flag.String("config", "", "Path to config file; optional")
-	claSignersFileFlag := flag.String("cla-signers", "", "Path to CLA signers; required")
-	orgFlag := flag.String("org", "", "Name of organization or username; required if not set in config file")
-	repoFlag := flag.String("repo", "", "Name of repo; if empty, implies all repos in org")
-	prFlag := flag.String("pr", "", "Comma-separated list of PRs to process")
-	updateRepoFlag := flag.Bool("update-repo", false, "Update labels on the repo")
+// subcommands maps the name of each subcommand to its implementation, which
+// parses the remaining command-line arguments.
+var subcommands = map[string]func(args []string){
+	"check":       checkMain,
+	"check-local": checkLocalMain,
+	"pre-receive": preReceiveMain,
+	"hook":        hookMain,
+	"serve":       serveMain,
+	"labels":      labelsMain,
+	"validate":    validateMain,
+	"stats":       statsMain,
+	"digest":      digestMain,
+	"doctor":      doctorMain,
+	"signers":     signersMain,
+}
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Syntax: %s [flags]\n\nFlags:\n", path.Base(os.Args[0]))
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nNote: -cla-signers, -config and -secrets accept YAML and JSON files.\n")
+func main() {
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		// For compatibility, flags without a subcommand imply `check`.
+		if len(os.Args) > 1 && (os.Args[1] == "-h" || os.Args[1] == "-help" || os.Args[1] == "--help") {
+			usage()
+			os.Exit(2)
+		}
+		checkMain(os.Args[1:])
+		logging.FlushRepeats()
+		return
+	}
+	if os.Args[1] == "help" {
+		usage()
+		return
 	}
+	subcommand, ok := subcommands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown subcommand: %s\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	subcommand(os.Args[2:])
+	logging.FlushRepeats()
+}
+
+// usage prints the syntax of all subcommands.
+func usage() {
+	fmt.Fprintf(os.Stderr, `Syntax: %[1]s <subcommand> [flags]
 
-	flag.Parse()
+Subcommands:
+  check        Check the CLA compliance of open PRs and, with -update-repo, label them (default)
+  check-local  Check the CLA compliance of commits in a local Git checkout before pushing
+  hook install Install a pre-push hook running check-local on the commits being pushed
+  pre-receive  Reject pushes with commits not covered by a CLA, as a pre-receive hook
+  serve        Check PRs as GitHub delivers webhook events for them
+  labels sync  Create or update the CLA labels on the target repos
+  validate     Validate the config and CLA signers files
+  doctor       Check the connection to GitHub, the token, the rate limit, the CLA
+               labels, and the config files, printing a pass/fail checklist
+  stats        Print a compliance summary per repo and per company
+  digest       Post a digest of compliance changes since the last one, e.g., weekly,
+               to an issue or Slack
+  signers      Import CLA signers from, or export them to, a CSV roster, format
+               CLA signers files, or sync company rosters from a Google
+               Workspace directory (import, export, fmt, sync)
+
+Run '%[1]s <subcommand> -h' for the flags of each subcommand. Each flag can also
+be set via an environment variable, e.g., CRBOT_UPDATE_REPO=true for
+-update-repo; flags given on the command line take precedence.
+`, path.Base(os.Args[0]))
+}
 
-	if *secretsFileFlag == "" {
-		logging.Fatalf("-secrets flag is required")
-	} else if *claSignersFileFlag == "" {
-		logging.Fatalf("-cla-signers flag is required")
+// checkConfig validates the settings of the config file which apply to
+// processing pull requests.
+func checkConfig(cfg config.Config) {
+	if err := crbot.ValidateConfig(cfg); err != nil {
+		logging.Fatalf("Invalid config file: %s", err)
 	}
+}
 
-	// Read and parse required auth, config, and CLA signers files.
-	secrets := config.ParseSecrets(*secretsFileFlag)
-	cfg := config.ParseConfig(*configFileFlag)
-	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+// configureGitHubClient sets up the compliance checkers, event publisher,
+// signer lookup, and repo label cache of the client from the config file.
+func configureGitHubClient(ghc *ghutil.GitHubClient, cfg config.Config, secrets config.Secrets) {
+	if err := crbot.ConfigureClient(ghc, cfg, secrets); err != nil {
+		logging.Fatalf("Error configuring the bot: %s", err)
+	}
+}
 
-	// Get the org name from command-line flags or config file.
+// resolveOrgRepo gets the org and repo names from command-line flags or the
+// config file, with the flags taking precedence.
+func resolveOrgRepo(orgFlag string, repoFlag string, cfg config.Config) (string, string) {
 	var orgName string
-	if *orgFlag != "" {
-		orgName = *orgFlag
+	if orgFlag != "" {
+		orgName = orgFlag
 	} else if cfg.Org != "" {
 		orgName = cfg.Org
 	} else {
 		logging.Fatalf("-org must be non-empty or `org` must be specified in config file")
 	}
 
-	// Get the repo name from command-line flags or config file.
-	repoName := *repoFlag
+	repoName := repoFlag
 	if repoName == "" {
 		repoName = cfg.Repo
 	}
+	return orgName, repoName
+}
 
-	prNumbers := make([]int, 0)
-	if *prFlag != "" {
-		prElements := strings.Split(*prFlag, ",")
-		prNumbers = make([]int, len(prElements))
-		for idx, elt := range prElements {
-			num, err := strconv.ParseInt(elt, 10, 32)
-			if err != nil {
-				logging.Fatalf("Invalid value for flag -pr: %s", *prFlag)
-			}
-			prNumbers[idx] = int(num)
-		}
-	}
+// logFlags are the flags selecting where and what to log.
+type logFlags struct {
+	sink        *string
+	level       *string
+	format      *string
+	repeatLimit *int
+}
 
-	// Configure authentication and connect to GitHub.
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: secrets.Auth},
-	)
-	tc := oauth2.NewClient(context.Background(), ts)
+// addLogFlags registers the logging flags with the flag set.
+func addLogFlags(flags *flag.FlagSet) *logFlags {
+	return &logFlags{
+		sink:        flags.String("log-sink", logging.SinkStd, "Where to write logs; accepted: "+strings.Join(logging.Sinks, ", ")),
+		level:       flags.String("log-level", "info", "Minimum level of log lines to write, e.g., debug for the details of each commit or error for errors only; accepted: "+strings.Join(logging.Levels, ", ")),
+		format:      flags.String("log-format", logging.FormatText, "Format of log lines, except with the stackdriver sink, which always writes JSON; accepted: "+strings.Join(logging.Formats, ", ")),
+		repeatLimit: flags.Int("log-repeat-limit", 0, "Number of errors with the same message format, e.g., the same error for each of many repos, after which further ones are only counted, and logged as a single line with the count at the end of the run, or, for `serve`, after each processed event and every minute; 0 means unlimited"),
+	}
+}
 
-	// Process org and repo(s) specified on the command-line.
-	ghc := ghutil.NewClient(tc)
-	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
-		Org:               orgName,
-		Repo:              repoName,
-		Pulls:             prNumbers,
-		UpdateRepo:        *updateRepoFlag,
-		UnknownAsExternal: cfg.UnknownAsExternal,
+// apply directs all further logging to the selected sink, level, and format.
+func (l *logFlags) apply() {
+	if err := logging.SetFormat(*l.format); err != nil {
+		logging.Fatalf("Invalid value for flag -log-format: %s", err)
+	}
+	level, err := logging.ParseLevel(*l.level)
+	if err != nil {
+		logging.Fatalf("Invalid value for flag -log-level: %s", err)
+	}
+	logging.SetLevel(level)
+	logging.SetRepeatLimit(*l.repeatLimit)
+	if err := logging.SetSink(*l.sink, path.Base(os.Args[0])); err != nil {
+		logging.Fatalf("Invalid value for flag -log-sink: %s", err)
 	}
-	ghc.ProcessOrgRepo(ghc, repoSpec, claSigners)
 }
@@ -0,0 +1,269 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/report"
+)
+
+// slackTimeout bounds the time taken to post a digest to Slack.
+const slackTimeout = 30 * time.Second
+
+// digestMain implements the `digest` subcommand, which checks all open PRs
+// without modifying them and posts a summary of what changed since the last
+// digest, e.g., weekly from a cron job: PRs whose compliance changed, new
+// external-CLA PRs, and repos missing CLA labels.
+func digestMain(args []string) {
+	flags := flag.NewFlagSet("digest", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, or its location in a GitHub repo, as repo://ORG/REPO/PATH[@REF]; required")
+	orgFlag := flags.String("org", "", "Name of organization or username; required if not set in config file")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos, e.g., 'cloud-*,infra-*'; if empty, implies all repos in org")
+	snapshotFileFlag := flags.String("snapshot", "", "Path to a JSON file with the compliance of each PR as of the previous digest, updated once this digest is posted; required")
+	issueFlag := flags.String("issue", "", "URL of the issue to post the digest to as a comment, e.g., https://github.com/ORG/REPO/issues/1; optional")
+	slackFlag := flags.Bool("slack", false, "Post the digest to the Slack incoming webhook given by slack_webhook in the secrets file")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s digest [flags]\n\nWithout -issue or -slack, the digest is written to stdout.\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	connFlags.check()
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	} else if *snapshotFileFlag == "" {
+		logging.Fatalf("-snapshot flag is required")
+	}
+
+	var issueOrg, issueRepo string
+	var issueNumber int
+	if *issueFlag != "" {
+		var err error
+		issueOrg, issueRepo, issueNumber, err = parseIssueURL(*issueFlag)
+		if err != nil {
+			logging.Fatalf("Invalid value for flag -issue: %s", err)
+		}
+	}
+
+	secrets := connFlags.loadSecrets()
+	if *slackFlag && secrets.SlackWebhook == "" {
+		logging.Fatalf("-slack flag requires `slack_webhook` in the secrets file")
+	}
+	cfg := config.ParseConfig(*configFileFlag)
+	orgName, repoName := resolveOrgRepo(*orgFlag, *repoFlag, cfg)
+
+	previous := readSnapshot(*snapshotFileFlag)
+
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	configureGitHubClient(ghc, cfg, secrets)
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+	ghc.Report = report.New()
+	repoSpec := crbot.NewOrgRepoSpec(cfg, orgName, repoName, false)
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	if err != nil {
+		// A digest of only some of the repos would report the PRs in the
+		// others as changed next time.
+		logging.Fatalf("Error checking PRs in org %s: %s", orgName, err)
+	}
+	missingLabels, err := findMissingLabels(ghc, orgName, repoName, cfg)
+	if err != nil {
+		logging.Fatalf("Error retrieving repos: %s", err)
+	}
+
+	now := time.Now().UTC()
+	digest := report.ComputeDigest(ghc.Report, previous, missingLabels, now)
+	snapshot := report.TakeSnapshot(ghc.Report, now)
+
+	posted := false
+	if issueNumber != 0 {
+		var body bytes.Buffer
+		if err := report.WriteDigestMarkdown(&body, digest); err != nil {
+			logging.Fatalf("Error rendering digest: %s", err)
+		}
+		logging.Infof("Posting digest to issue %d of repo '%s/%s'...", issueNumber, issueOrg, issueRepo)
+		comment := &github.IssueComment{Body: github.String(body.String())}
+		if _, _, err := ghc.Issues.CreateComment(context.Background(), issueOrg, issueRepo, issueNumber, comment); err != nil {
+			logging.Fatalf("Error posting digest to issue %d of repo '%s/%s': %s", issueNumber, issueOrg, issueRepo, err)
+		}
+		posted = true
+	}
+	finishGitHubClient(ghc)
+
+	if *slackFlag {
+		var text bytes.Buffer
+		if err := report.WriteDigestSlack(&text, digest); err != nil {
+			logging.Fatalf("Error rendering digest: %s", err)
+		}
+		logging.Infof("Posting digest to Slack...")
+		if err := postSlackMessage(secrets.SlackWebhook, text.String()); err != nil {
+			// Once posted to the issue, the changes must not be reported
+			// again by the next digest.
+			if posted {
+				writeSnapshot(*snapshotFileFlag, snapshot)
+			}
+			logging.Fatalf("Error posting digest to Slack: %s", err)
+		}
+		posted = true
+	}
+
+	if !posted {
+		if err := report.WriteDigestMarkdown(os.Stdout, digest); err != nil {
+			logging.Fatalf("Error writing digest: %s", err)
+		}
+	}
+
+	writeSnapshot(*snapshotFileFlag, snapshot)
+}
+
+// parseIssueURL returns the org, repo, and number of the issue at the given
+// URL, e.g., "https://github.com/org/repo/issues/1".
+func parseIssueURL(issueURL string) (string, string, int, error) {
+	u, err := url.Parse(issueURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", 0, fmt.Errorf("'%s' is not an issue URL", issueURL)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] != "issues" {
+		return "", "", 0, fmt.Errorf("'%s' is not an issue URL", issueURL)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("'%s' is not an issue URL", issueURL)
+	}
+	return parts[0], parts[1], number, nil
+}
+
+// findMissingLabels returns the CLA labels which are not defined in each of the
+// target repos, honoring label names overridden in the org-wide and repo
+// config files, as `labels sync` does.
+func findMissingLabels(ghc *ghutil.GitHubClient, orgName string, repoName string, cfg config.Config) ([]report.MissingLabels, error) {
+	orgConfig, err := ghc.GetOrgConfig(orgName)
+	if err != nil {
+		logging.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.OrgConfigPath, orgName, config.OrgConfigRepo, err)
+	}
+	repos, err := ghc.GetAllRepos(orgName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []report.MissingLabels
+	for _, repo := range repos {
+		if ghutil.RepoSkipReason(repo, cfg.SkipForks) != "" || ghutil.IsExcluded(orgConfig, repo.GetName()) {
+			continue
+		}
+		repoPullSpec := ghutil.GitHubProcessSinglePullSpec{Labels: cfg.Labels}
+		repoConfig, err := ghc.GetRepoConfig(orgName, repo.GetName())
+		if err != nil {
+			logging.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.RepoConfigPath, orgName, repo.GetName(), err)
+		}
+		if repoConfig.Skip || orgConfig.Skip {
+			continue
+		}
+		ghutil.ApplyRepoConfig(&repoPullSpec, orgConfig.RepoConfig)
+		ghutil.ApplyRepoConfig(&repoPullSpec, repoConfig)
+
+		labels := ghutil.ResolveLabels(repoPullSpec.Labels)
+		status := ghc.GetRepoClaLabelStatus(orgName, repo.GetName(), labels)
+		var names []string
+		if !status.HasYes {
+			names = append(names, labels.Compliant)
+		}
+		if !status.HasNo {
+			names = append(names, labels.NonCompliant)
+		}
+		if !status.HasExternal {
+			names = append(names, labels.External)
+		}
+		if len(names) > 0 {
+			missing = append(missing, report.MissingLabels{Repo: fmt.Sprintf("%s/%s", orgName, repo.GetName()), Labels: names})
+		}
+	}
+	return missing, nil
+}
+
+// postSlackMessage posts the text via a Slack incoming webhook.
+func postSlackMessage(webhookURL string, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: slackTimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// readSnapshot reads the snapshot of the previous digest, if any.
+func readSnapshot(filename string) *report.Snapshot {
+	snapshotFile, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		logging.Fatalf("Error reading snapshot file '%s': %s", filename, err)
+	}
+	defer snapshotFile.Close()
+
+	snapshot, err := report.ReadSnapshot(snapshotFile)
+	if err != nil {
+		logging.Fatalf("Error parsing snapshot file '%s': %s", filename, err)
+	}
+	return snapshot
+}
+
+// writeSnapshot saves the compliance of each PR for the next digest.
+func writeSnapshot(filename string, snapshot report.Snapshot) {
+	snapshotFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating snapshot file '%s': %s", filename, err)
+	}
+	defer snapshotFile.Close()
+
+	if err := report.WriteSnapshotJSON(snapshotFile, snapshot); err != nil {
+		logging.Fatalf("Error writing snapshot file '%s': %s", filename, err)
+	}
+}
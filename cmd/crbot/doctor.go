@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// doctorChecklist prints the result of each check as it is made, remembering
+// whether any failed.
+type doctorChecklist struct {
+	failed bool
+}
+
+func (c *doctorChecklist) pass(name string, format string, a ...interface{}) {
+	fmt.Printf("[PASS] %s: %s\n", name, fmt.Sprintf(format, a...))
+}
+
+func (c *doctorChecklist) fail(name string, format string, a ...interface{}) {
+	c.failed = true
+	fmt.Printf("[FAIL] %s: %s\n", name, fmt.Sprintf(format, a...))
+}
+
+func (c *doctorChecklist) skip(name string, reason string) {
+	fmt.Printf("[SKIP] %s: %s\n", name, reason)
+}
+
+// doctorMain implements the `doctor` subcommand, which checks everything a run
+// depends on, printing a pass/fail checklist, rather than leaving the failures
+// to be found across the log of a long run: the config and secrets files, the
+// connection to GitHub, the validity and permissions of the token, the rate
+// limit, the CLA labels of the target repos, and the CLA signers file. It exits
+// with an error if any of the checks fail.
+func doctorMain(args []string) {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, or its location in a GitHub repo, as repo://ORG/REPO/PATH[@REF]; optional")
+	orgFlag := flags.String("org", "", "Name of organization or username; required if not set in config file")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos, e.g., 'cloud-*,infra-*'; if empty, implies all repos in org")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s doctor [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+	// The checklist is written to stdout.
+	logging.SetQuiet(true)
+
+	connFlags.check()
+
+	var checklist doctorChecklist
+
+	cfg, err := config.LoadConfig(*configFileFlag)
+	if err == nil {
+		err = crbot.ValidateConfig(cfg)
+	}
+	if *configFileFlag == "" {
+		checklist.skip("Config file", "-config not given")
+	} else if err != nil {
+		checklist.fail("Config file", "%s", err)
+	} else {
+		checklist.pass("Config file", "%s is valid", *configFileFlag)
+	}
+	orgName, repoName := resolveOrgRepo(*orgFlag, *repoFlag, cfg)
+
+	secrets, err := connFlags.readSecrets()
+	if err != nil {
+		checklist.fail("Secrets", "%s", err)
+	} else if secrets.Auth == "" && *connFlags.replay == "" {
+		checklist.fail("Secrets", "no GitHub token (`auth`) in %s", *connFlags.secrets)
+	} else {
+		checklist.pass("Secrets", "GitHub token found")
+	}
+
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	githubOK := false
+	if err := ghutil.CheckPermissions(ghc, orgName, "", false); err != nil {
+		checklist.fail("GitHub connection and token", "%s", err)
+	} else {
+		checklist.pass("GitHub connection and token", "the token can list the repos in %s", orgName)
+		githubOK = true
+	}
+
+	if !githubOK {
+		checklist.skip("Token permissions", "no connection to GitHub")
+	} else if err := ghutil.CheckPermissions(ghc, orgName, repoName, true); err != nil {
+		checklist.fail("Token permissions", "%s", err)
+	} else {
+		checklist.pass("Token permissions", "the token can label and comment on PRs")
+	}
+
+	if !githubOK {
+		checklist.skip("CLA labels", "no connection to GitHub")
+	} else if missing, err := findMissingLabels(ghc, orgName, repoName, cfg); err != nil {
+		checklist.fail("CLA labels", "%s", err)
+	} else if len(missing) == 0 {
+		checklist.pass("CLA labels", "all target repos have the CLA labels")
+	} else {
+		for _, repo := range missing {
+			checklist.fail("CLA labels", "%s is missing [%s]; run '%s labels sync' to create them", repo.Repo, strings.Join(repo.Labels, "], ["), path.Base(os.Args[0]))
+		}
+	}
+
+	// The CLA signers may be read from a repo via the client.
+	if *claSignersFileFlag == "" {
+		checklist.skip("CLA signers file", "-cla-signers not given")
+	} else if claSigners, err := config.LoadClaSigners(*claSignersFileFlag); err != nil {
+		checklist.fail("CLA signers file", "%s", err)
+	} else {
+		checklist.pass("CLA signers file", "%s is valid (%d people, %d bots, %d companies)", *claSignersFileFlag,
+			len(claSigners.People), len(claSigners.Bots), len(claSigners.Companies))
+	}
+
+	// The rate limit is checked last, as of the latest response.
+	if usage := ghc.Usage.Usage(); usage.Limit == 0 {
+		checklist.skip("Rate limit", "unknown, as GitHub didn't report it")
+	} else if usage.Remaining == 0 {
+		checklist.fail("Rate limit", "exhausted (limit %d); resets at %s", usage.Limit, usage.Reset.Format(time.RFC3339))
+	} else {
+		checklist.pass("Rate limit", "%d of %d remaining; resets at %s", usage.Remaining, usage.Limit, usage.Reset.Format(time.RFC3339))
+	}
+
+	finishGitHubClient(ghc)
+	if checklist.failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/google/code-review-bot/logging"
+)
+
+// envPrefix is the prefix of the environment variables mirroring the flags,
+// e.g., CRBOT_UPDATE_REPO for -update-repo.
+const envPrefix = "CRBOT_"
+
+// envName returns the name of the environment variable mirroring the flag.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// parseFlags sets each flag from its environment variable, if set, and then
+// parses the command-line arguments, so that flags given on the command line
+// take precedence; this way, containerized deployments can be configured via
+// the environment alone.
+func parseFlags(flags *flag.FlagSet, args []string) {
+	flags.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok {
+			return
+		}
+		if err := flags.Set(f.Name, value); err != nil {
+			logging.Fatalf("Invalid value for environment variable %s: %s", envName(f.Name), err)
+		}
+	})
+	flags.Parse(args)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/code-review-bot/logging"
+)
+
+// hookCommands maps the name of each command of the `hook` subcommand to its
+// implementation.
+var hookCommands = map[string]func(args []string){
+	"install": hookInstall,
+}
+
+// hookMain implements the `hook` subcommand, which manages the client-side
+// Git hooks checking commits before they are pushed.
+func hookMain(args []string) {
+	var command func(args []string)
+	if len(args) > 0 {
+		command = hookCommands[args[0]]
+	}
+	if command == nil {
+		logging.Fatalf("Syntax: %s hook install [flags]", path.Base(os.Args[0]))
+	}
+	command(args[1:])
+}
+
+// hookMarker identifies the hooks installed by `hook install`, which it may
+// overwrite without -force.
+const hookMarker = "# Installed by crbot hook install."
+
+// hookInstall installs a pre-push hook running `check-local` on the commits
+// being pushed, so that contributors learn that a commit isn't covered by a
+// CLA before opening a PR, rather than from its CLA label.
+func hookInstall(args []string) {
+	flags := flag.NewFlagSet("hook install", flag.ExitOnError)
+	repoPathFlag := flags.String("repo-path", ".", "Path to the local Git checkout to install the hook in")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, for the hook to check commits against; required")
+	configFileFlag := flags.String("config", "", "Path to config file, for unknown_as_external; optional")
+	forceFlag := flags.Bool("force", false, "Overwrite an existing pre-push hook which was not installed by crbot")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s hook install [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		logging.Fatalf("Error locating the crbot executable: %s", err)
+	}
+	command := []string{executable, "check-local", "-cla-signers", absPath(*claSignersFileFlag)}
+	if *configFileFlag != "" {
+		command = append(command, "-config", absPath(*configFileFlag))
+	}
+	for i, arg := range command {
+		command[i] = shellQuote(arg)
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\n# Checks the CLA compliance of the commits being pushed.\nexec %s -pre-push \"$1\"\n",
+		hookMarker, strings.Join(command, " "))
+
+	hooksDir, err := gitHooksDir(*repoPathFlag)
+	if err != nil {
+		logging.Fatalf("Error locating the Git hooks of '%s': %s", *repoPathFlag, err)
+	}
+	hookFile := filepath.Join(hooksDir, "pre-push")
+	if existing, err := ioutil.ReadFile(hookFile); err == nil && !bytes.Contains(existing, []byte(hookMarker)) && !*forceFlag {
+		logging.Fatalf("Pre-push hook %s already exists; use -force to overwrite it", hookFile)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		logging.Fatalf("Error creating Git hooks directory '%s': %s", hooksDir, err)
+	}
+	if err := ioutil.WriteFile(hookFile, []byte(script), 0755); err != nil {
+		logging.Fatalf("Error writing pre-push hook %s: %s", hookFile, err)
+	}
+	// WriteFile leaves the mode of an existing file alone.
+	if err := os.Chmod(hookFile, 0755); err != nil {
+		logging.Fatalf("Error making pre-push hook %s executable: %s", hookFile, err)
+	}
+	logging.Infof("Installed pre-push hook %s", hookFile)
+}
+
+// gitHooksDir returns the directory holding the Git hooks of the checkout,
+// honoring `core.hooksPath`.
+func gitHooksDir(repoPath string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", repoPath, "rev-parse", "--git-path", "hooks")
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	hooksDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(repoPath, hooksDir)
+	}
+	return hooksDir, nil
+}
+
+// absPath returns the absolute form of the path, as hooks run in the checkout
+// rather than the current directory.
+func absPath(filename string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		logging.Fatalf("Error resolving path '%s': %s", filename, err)
+	}
+	return abs
+}
+
+// shellQuote quotes the argument for a POSIX shell.
+func shellQuote(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
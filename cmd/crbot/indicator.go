@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// Intervals at which the progress indicator is redrawn in place on a
+// terminal, or printed as a new line otherwise, e.g., when stderr is
+// redirected to a file.
+const (
+	indicatorTerminalInterval = 200 * time.Millisecond
+	indicatorLineInterval     = 10 * time.Second
+)
+
+// progressIndicator renders the progress of a run on stderr, in place of the
+// per-PR log, for `check -quiet`. Log entries, e.g., errors, are still written,
+// clearing the indicator first so that they don't run into it.
+type progressIndicator struct {
+	progress *ghutil.Progress
+	terminal bool
+	sink     logging.Sink
+
+	mu    sync.Mutex
+	drawn bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// startProgressIndicator starts rendering the given progress until `stop` is
+// called.
+func startProgressIndicator(progress *ghutil.Progress) *progressIndicator {
+	ind := &progressIndicator{
+		progress: progress,
+		terminal: isTerminal(os.Stderr),
+		sink:     logging.CurrentSink(),
+		done:     make(chan struct{}),
+	}
+	logging.Use(logging.SinkFunc(ind.writeEntry))
+
+	interval := indicatorLineInterval
+	if ind.terminal {
+		interval = indicatorTerminalInterval
+	}
+	ind.wg.Add(1)
+	go func() {
+		defer ind.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ind.draw()
+			case <-ind.done:
+				return
+			}
+		}
+	}()
+	return ind
+}
+
+// stop renders the final progress and restores the previous logging sink.
+func (ind *progressIndicator) stop() {
+	close(ind.done)
+	ind.wg.Wait()
+	ind.draw()
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+	if ind.drawn {
+		fmt.Fprintln(os.Stderr)
+		ind.drawn = false
+	}
+	logging.Use(ind.sink)
+}
+
+// draw renders the current progress, overwriting the previous rendering on a
+// terminal.
+func (ind *progressIndicator) draw() {
+	line := formatProgress(ind.progress.Snapshot(time.Now()))
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+	if ind.terminal {
+		fmt.Fprintf(os.Stderr, "\r%s\033[K", line)
+		ind.drawn = true
+	} else {
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// writeEntry clears the indicator, if drawn, before writing a log entry to the
+// previous sink; the indicator is redrawn on the next tick.
+func (ind *progressIndicator) writeEntry(entry logging.Entry) error {
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+	if ind.drawn {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		ind.drawn = false
+	}
+	return ind.sink.Write(entry)
+}
+
+// formatProgress renders a progress snapshot as a single line, e.g.,
+// "Processed 3/40 repos, 120 PRs; elapsed 1m20s, ETA 2m0s".
+func formatProgress(snapshot ghutil.ProgressSnapshot) string {
+	line := fmt.Sprintf("Processed %d/%d repos, %d PRs; elapsed %s", snapshot.ReposDone, snapshot.Repos, snapshot.PullsDone, snapshot.Elapsed.Round(time.Second))
+	if snapshot.ReposDone < snapshot.Repos || snapshot.Repos == 0 {
+		if snapshot.ETA > 0 {
+			line += fmt.Sprintf(", ETA %s", snapshot.ETA.Round(time.Second))
+		} else {
+			line += ", ETA unknown"
+		}
+	}
+	return line
+}
+
+// isTerminal reports whether the file is a terminal, i.e., a character device,
+// rather than, e.g., a pipe or a regular file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// labelsMain implements the `labels` subcommand; the only supported action is
+// `sync`, which creates or updates the CLA labels on all target repos.
+func labelsMain(args []string) {
+	if len(args) == 0 || args[0] != "sync" {
+		logging.Fatalf("Syntax: %s labels sync [flags]", path.Base(os.Args[0]))
+	}
+
+	flags := flag.NewFlagSet("labels sync", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	orgFlag := flags.String("org", "", "Name of organization or username; required if not set in config file")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos, e.g., 'cloud-*,infra-*'; if empty, implies all repos in org")
+	updateRepoFlag := flags.Bool("update-repo", false, "Create or update labels on the repo(s)")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s labels sync [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args[1:])
+
+	logFlags.apply()
+
+	connFlags.check()
+
+	secrets := connFlags.loadSecrets()
+	cfg := config.ParseConfig(*configFileFlag)
+	orgName, repoName := resolveOrgRepo(*orgFlag, *repoFlag, cfg)
+
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	orgConfig, err := ghc.GetOrgConfig(orgName)
+	if err != nil {
+		logging.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.OrgConfigPath, orgName, config.OrgConfigRepo, err)
+	}
+
+	repos, err := ghc.GetAllRepos(orgName, repoName)
+	if err != nil {
+		logging.Fatalf("Error retrieving repos: %s", err)
+	}
+
+	failed := false
+	for _, repo := range repos {
+		logging.Infof("Repo: %s/%s", orgName, repo.GetName())
+
+		if reason := ghutil.RepoSkipReason(repo, cfg.SkipForks); reason != "" {
+			logging.Infof("  Skipping repo: %s", reason)
+			continue
+		}
+		if ghutil.IsExcluded(orgConfig, repo.GetName()) {
+			logging.Infof("  Skipping repo: excluded by %s in repo '%s/%s'", config.OrgConfigPath, orgName, config.OrgConfigRepo)
+			continue
+		}
+
+		// Honor label names overridden in the org-wide and repo config files.
+		repoPullSpec := ghutil.GitHubProcessSinglePullSpec{Labels: cfg.Labels}
+		repoConfig, err := ghc.GetRepoConfig(orgName, repo.GetName())
+		if err != nil {
+			logging.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.RepoConfigPath, orgName, repo.GetName(), err)
+		}
+		if repoConfig.Skip || orgConfig.Skip {
+			logging.Infof("  Skipping repo: `skip` is set in %s or %s", config.RepoConfigPath, config.OrgConfigPath)
+			continue
+		}
+		ghutil.ApplyRepoConfig(&repoPullSpec, orgConfig.RepoConfig)
+		ghutil.ApplyRepoConfig(&repoPullSpec, repoConfig)
+
+		if err := ghutil.SyncLabels(ghc, orgName, repo.GetName(), ghutil.LabelSpecs(repoPullSpec.Labels), *updateRepoFlag); err != nil {
+			logging.Errorf("Error syncing labels: %s", err)
+			failed = true
+		}
+	}
+	finishGitHubClient(ghc)
+	if failed {
+		os.Exit(1)
+	}
+}
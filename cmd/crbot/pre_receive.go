@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// preReceiveMain implements the `pre-receive` subcommand, which runs as a
+// pre-receive hook, e.g., on GitHub Enterprise Server, in the repo receiving a
+// push. It reads the ref updates from stdin, checks the commits they add
+// against the CLA signers file as `check-local` does, and rejects the push by
+// exiting with an error if any of them is not compliant; its output is shown
+// to the pusher.
+func preReceiveMain(args []string) {
+	flags := flag.NewFlagSet("pre-receive", flag.ExitOnError)
+	configFileFlag := flags.String("config", "", "Path to config file, for unknown_as_external; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers; required")
+	repoPathFlag := flags.String("repo-path", ".", "Path to the repo receiving the push, which hooks run in")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s pre-receive [flags] < REF-UPDATES\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	var cfg config.Config
+	if *configFileFlag != "" {
+		cfg = config.ParseConfig(*configFileFlag)
+	}
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+
+	updates, err := ghutil.ParsePushUpdates(os.Stdin)
+	if err != nil {
+		logging.Fatalf("Error reading ref updates: %s", err)
+	}
+
+	commits := readPushCommits(updates, func(update ghutil.PushUpdate) ([]ghutil.LocalCommit, error) {
+		return ghutil.ReadPushedCommits(*repoPathFlag, update)
+	})
+
+	if nonCompliant := checkLocalCommits(commits, claSigners, cfg.UnknownAsExternal); nonCompliant > 0 {
+		fmt.Printf("Push rejected: %d commit(s) not covered by a CLA; see above.\n", nonCompliant)
+		os.Exit(1)
+	}
+}
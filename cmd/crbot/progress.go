@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// readCheckpoint reads the checkpoint saved by a previous run which stopped
+// early, if any; checkpoints for other orgs are ignored.
+func readCheckpoint(filename string, orgName string) *ghutil.Checkpoint {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		logging.Fatalf("Error reading progress file '%s': %s", filename, err)
+	}
+
+	var checkpoint ghutil.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		logging.Fatalf("Error parsing progress file '%s': %s", filename, err)
+	}
+	if checkpoint.Org != orgName {
+		logging.Infof("Ignoring progress file '%s' for a different org: %s", filename, checkpoint.Org)
+		return nil
+	}
+	logging.Infof("Resuming from repo %s/%s, PR %d", checkpoint.Org, checkpoint.Repo, checkpoint.Pull)
+	return &checkpoint
+}
+
+// saveCheckpoint saves the point at which this run stopped to the progress
+// file or, if the run completed, removes the file so that the next run
+// starts from the beginning.
+func saveCheckpoint(filename string, checkpoint *ghutil.Checkpoint) {
+	if checkpoint == nil {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			logging.Errorf("Error removing progress file '%s': %s", filename, err)
+		}
+		return
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		logging.Fatalf("Error encoding progress: %s", err)
+	}
+	if err := ioutil.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		logging.Fatalf("Error writing progress file '%s': %s", filename, err)
+	}
+	logging.Infof("Saved progress to '%s'; the next run resumes from repo %s/%s", filename, checkpoint.Org, checkpoint.Repo)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/crbot"
+	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/serverless"
+)
+
+// webhookPath is the path at which the server receives webhook deliveries.
+const webhookPath = "/webhook"
+
+// repeatFlushInterval is the interval at which the server logs the errors
+// suppressed as repeats, in addition to after each processing job.
+const repeatFlushInterval = time.Minute
+
+// serveMain implements the `serve` subcommand, which runs the bot as an HTTP
+// server processing each pull request as GitHub delivers webhook events for
+// it. Since the server is exposed to the internet, every delivery must be
+// signed with the `webhook_secret` from the secrets file. If the secrets file
+// has an `admin_token`, the admin endpoints are served as well.
+func serveMain(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, or its location in a GitHub repo, as repo://ORG/REPO/PATH[@REF]; required")
+	orgFlag := flags.String("org", "", "Name of organization or username whose events to process; if empty, events from all orgs are processed")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos whose events to process, e.g., 'cloud-*,infra-*'; if empty, implies all repos")
+	updateRepoFlag := flags.Bool("update-repo", false, "Update labels on the repo")
+	queueDelayFlag := flags.Duration("queue-delay", 10*time.Second, "How long to wait before processing a PR after a webhook delivery for it, coalescing further deliveries for the same PR in the meantime; 0 processes each delivery before responding to it")
+	listenFlag := flags.String("listen", ":8080", "Address to listen on for webhook deliveries, which GitHub must send to "+webhookPath)
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s serve [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	connFlags.check()
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	secrets := connFlags.loadSecrets()
+	if secrets.WebhookSecret == "" {
+		logging.Fatalf("`webhook_secret` is required in the secrets file to verify webhook deliveries")
+	}
+	cfg := config.ParseConfig(*configFileFlag)
+	checkConfig(cfg)
+
+	orgName := *orgFlag
+	if orgName == "" {
+		orgName = cfg.Org
+	}
+	repoName := *repoFlag
+	if repoName == "" {
+		repoName = cfg.Repo
+	}
+
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	configureGitHubClient(ghc, cfg, secrets)
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+	repoSpec := crbot.NewOrgRepoSpec(cfg, orgName, repoName, *updateRepoFlag)
+
+	handler := serverless.NewHandler(ghc, repoSpec, claSigners, []byte(secrets.WebhookSecret))
+	handler.SetRepoLabelCache(ghc.RepoLabels)
+	if *queueDelayFlag > 0 {
+		queue := serverless.NewQueue(*queueDelayFlag, handler.Process)
+		go queue.Run(context.Background())
+		handler.SetQueue(queue)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webhookPath, handler)
+	if secrets.AdminToken != "" {
+		mux.Handle(serverless.RecheckPath, serverless.NewRecheckHandler(handler, secrets.AdminToken))
+	}
+	// Errors suppressed as repeats outside of processing jobs, e.g., of
+	// rejected deliveries, are counted periodically.
+	go func() {
+		for range time.Tick(repeatFlushInterval) {
+			logging.FlushRepeats()
+		}
+	}()
+	logging.Infof("Listening for webhook deliveries on %s%s", *listenFlag, webhookPath)
+	if err := http.ListenAndServe(*listenFlag, mux); err != nil {
+		logging.Fatalf("Error serving webhook deliveries: %s", err)
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/directory"
+	"github.com/google/code-review-bot/gcp"
+	"github.com/google/code-review-bot/logging"
+)
+
+// signersCommands maps the name of each command of the `signers` subcommand
+// to its implementation.
+var signersCommands = map[string]func(args []string){
+	"import": signersImport,
+	"export": signersExport,
+	"fmt":    signersFmt,
+	"sync":   signersSync,
+}
+
+// signersMain implements the `signers` subcommand, which converts between CLA
+// signers files and CSV rosters, e.g., as kept in spreadsheets by the team
+// handling CLA intake, rewrites CLA signers files in canonical form, and syncs
+// company rosters from a Google Workspace directory.
+func signersMain(args []string) {
+	var command func(args []string)
+	if len(args) > 0 {
+		command = signersCommands[args[0]]
+	}
+	if command == nil {
+		logging.Fatalf("Syntax: %s signers import|export|fmt|sync [flags]", path.Base(os.Args[0]))
+	}
+	command(args[1:])
+}
+
+// signersImport converts a CSV roster to a CLA signers file.
+func signersImport(args []string) {
+	flags := flag.NewFlagSet("signers import", flag.ExitOnError)
+	csvFileFlag := flags.String("csv", "", "Path to the CSV roster to import, with the columns "+strings.Join(config.ClaSignersCSVHeader, ", ")+"; required")
+	outputFileFlag := flags.String("output", "", "Path to write the CLA signers to, as JSON if it ends in .json and YAML otherwise; if empty, YAML is written to stdout")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s signers import [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if *csvFileFlag == "" {
+		logging.Fatalf("-csv flag is required")
+	}
+
+	csvFile, err := os.Open(*csvFileFlag)
+	if err != nil {
+		logging.Fatalf("Error reading CSV file '%s': %s", *csvFileFlag, err)
+	}
+	defer csvFile.Close()
+	claSigners, err := config.ReadClaSignersCSV(csvFile)
+	if err != nil {
+		logging.Fatalf("Error parsing CSV file '%s': %s", *csvFileFlag, err)
+	}
+
+	data, err := config.EncodeClaSigners(*outputFileFlag, claSigners)
+	if err != nil {
+		logging.Fatalf("Error encoding CLA signers: %s", err)
+	}
+
+	output, closeOutput := createOutput(*outputFileFlag)
+	defer closeOutput()
+	if _, err := output.Write(data); err != nil {
+		logging.Fatalf("Error writing CLA signers: %s", err)
+	}
+}
+
+// signersExport converts a CLA signers file to a CSV roster.
+func signersExport(args []string) {
+	flags := flag.NewFlagSet("signers export", flag.ExitOnError)
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers; required")
+	csvFileFlag := flags.String("csv", "", "Path to write the CSV roster to; if empty, it is written to stdout")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s signers export [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+	if len(claSigners.ExemptCommits) > 0 || len(claSigners.Maintainers) > 0 {
+		logging.Errorf("Exempt commits and maintainers can't be represented in CSV; leaving them out")
+	}
+
+	output, closeOutput := createOutput(*csvFileFlag)
+	defer closeOutput()
+	if err := config.WriteClaSignersCSV(output, claSigners); err != nil {
+		logging.Fatalf("Error writing CSV: %s", err)
+	}
+}
+
+// signersFmt rewrites CLA signers files in canonical form (see
+// `config.NormalizeClaSigners`), or, with -check, lists those which aren't.
+func signersFmt(args []string) {
+	flags := flag.NewFlagSet("signers fmt", flag.ExitOnError)
+	checkFlag := flags.Bool("check", false, "Don't rewrite the files, but list those which aren't in canonical form and exit with an error if there are any, e.g., in presubmit checks")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s signers fmt [flags] FILE...\n\nRewrites each CLA signers file (but not the files it includes) with accounts sorted by login,\nnormalized emails, and duplicates removed; comments are not preserved.\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if flags.NArg() == 0 {
+		logging.Fatalf("At least one CLA signers file is required")
+	}
+
+	unformatted := false
+	for _, filename := range flags.Args() {
+		original, err := ioutil.ReadFile(filename)
+		if err != nil {
+			logging.Fatalf("Error reading CLA signers file '%s': %s", filename, err)
+		}
+		claSigners := config.NormalizeClaSigners(config.ReadClaSignersFile(filename))
+		formatted, err := config.EncodeClaSigners(filename, claSigners)
+		if err != nil {
+			logging.Fatalf("Error encoding CLA signers file '%s': %s", filename, err)
+		}
+		if bytes.Equal(original, formatted) {
+			continue
+		}
+		if *checkFlag {
+			fmt.Println(filename)
+			unformatted = true
+			continue
+		}
+		if err := ioutil.WriteFile(filename, formatted, 0644); err != nil {
+			logging.Fatalf("Error writing CLA signers file '%s': %s", filename, err)
+		}
+		logging.Infof("Formatted %s", filename)
+	}
+	if unformatted {
+		os.Exit(1)
+	}
+}
+
+// signersSync replaces the people of each company configured in the
+// `directory` section of the config file with its roster in a Google
+// Workspace directory, so that corporate rosters don't lag behind the
+// company's own records; it is meant to run on a schedule, e.g., as a daily
+// cron job whose output is committed or sent for review.
+func signersSync(args []string) {
+	flags := flag.NewFlagSet("signers sync", flag.ExitOnError)
+	configFileFlag := flags.String("config", "", "Path to config file, with the directory section; required")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to the CLA signers file to update (but not the files it includes); required")
+	outputFileFlag := flags.String("output", "", "Path to write the updated CLA signers to; if empty, the -cla-signers file is rewritten")
+	checkFlag := flags.Bool("check", false, "Don't write the CLA signers, but exit with an error if people would be added to or removed from any roster")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s signers sync [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if *configFileFlag == "" {
+		logging.Fatalf("-config flag is required")
+	}
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	}
+	outputFile := *outputFileFlag
+	if outputFile == "" {
+		outputFile = *claSignersFileFlag
+	}
+
+	cfg := config.ParseConfig(*configFileFlag).Directory
+	if cfg.Admin == "" || cfg.LoginField == "" || len(cfg.Companies) == 0 {
+		logging.Fatalf("`directory` in config file requires `admin`, `login_field`, and `companies`")
+	}
+	for _, company := range cfg.Companies {
+		if company.Company == "" || (company.Group == "") == (company.Domain == "") {
+			logging.Fatalf("Each of `directory.companies` in config file requires `company`, and either `group` or `domain`")
+		}
+	}
+
+	ctx := context.Background()
+	client, err := gcp.NewDelegatedClient(ctx, cfg.Credentials, cfg.Admin, gcp.ScopeDirectoryUserReadonly, gcp.ScopeDirectoryGroupMemberReadonly)
+	if err != nil {
+		logging.Fatalf("Error creating Google Workspace directory client: %s", err)
+	}
+	directoryClient, err := directory.NewClient(client, cfg.LoginField)
+	if err != nil {
+		logging.Fatalf("Invalid value for `directory.login_field` in config file: %s", err)
+	}
+
+	claSigners := config.ReadClaSignersFile(*claSignersFileFlag)
+	outdated := false
+	for _, company := range cfg.Companies {
+		accounts, err := directoryClient.Accounts(ctx, company)
+		if err != nil {
+			logging.Fatalf("Error reading roster of %s: %s", company.Company, err)
+		}
+		// An empty roster is more likely a misconfiguration than every
+		// contributor leaving the company at once.
+		if len(accounts) == 0 {
+			logging.Fatalf("No users with GitHub logins found for %s; refusing to empty its roster", company.Company)
+		}
+		added, removed := directory.MergeRoster(&claSigners, company.Company, accounts)
+		for _, account := range added {
+			logging.Infof("%s: adding %s <%s>, GitHub: %s", company.Company, account.Name, account.Email, account.Login)
+		}
+		for _, account := range removed {
+			logging.Infof("%s: removing %s <%s>, GitHub: %s", company.Company, account.Name, account.Email, account.Login)
+		}
+		if len(added) > 0 || len(removed) > 0 {
+			outdated = true
+		}
+	}
+
+	if *checkFlag {
+		if outdated {
+			os.Exit(1)
+		}
+		return
+	}
+	data, err := config.EncodeClaSigners(outputFile, config.NormalizeClaSigners(claSigners))
+	if err != nil {
+		logging.Fatalf("Error encoding CLA signers: %s", err)
+	}
+	if err := ioutil.WriteFile(outputFile, data, 0644); err != nil {
+		logging.Fatalf("Error writing CLA signers file '%s': %s", outputFile, err)
+	}
+}
+
+// createOutput creates the named output file, or returns stdout if the name is
+// empty, along with a function closing it.
+func createOutput(filename string) (io.Writer, func()) {
+	if filename == "" {
+		return os.Stdout, func() {}
+	}
+	outputFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating output file '%s': %s", filename, err)
+	}
+	return outputFile, func() { outputFile.Close() }
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/report"
+)
+
+// statsMain implements the `stats` subcommand, which checks all open PRs
+// without modifying them and prints a compliance summary per repo and per
+// company.
+func statsMain(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	connFlags := addConnectionFlags(flags)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers, or its location in a GitHub repo, as repo://ORG/REPO/PATH[@REF]; required")
+	orgFlag := flags.String("org", "", "Name of organization or username; required if not set in config file")
+	repoFlag := flags.String("repo", "", "Comma-separated names or glob patterns of repos, e.g., 'cloud-*,infra-*'; if empty, implies all repos in org")
+	formatFlag := flags.String("format", "table", "Output format; accepted: table, json")
+	outputFileFlag := flags.String("output", "", "Path to write the stats to; if empty, stats are written to stdout")
+	historyFileFlag := flags.String("history", "", "Path to a JSON file with the stats from the previous run, used to compute trends and updated with the stats from this run; optional")
+	logFlags := addLogFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s stats [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	logFlags.apply()
+
+	connFlags.check()
+	if *claSignersFileFlag == "" {
+		logging.Fatalf("-cla-signers flag is required")
+	} else if *formatFlag != "table" && *formatFlag != "json" {
+		logging.Fatalf("Invalid value for flag -format: %s; accepted: table, json", *formatFlag)
+	}
+
+	secrets := connFlags.loadSecrets()
+	cfg := config.ParseConfig(*configFileFlag)
+	orgName, repoName := resolveOrgRepo(*orgFlag, *repoFlag, cfg)
+
+	var previous *report.Stats
+	if *historyFileFlag != "" {
+		previous = readStatsHistory(*historyFileFlag)
+	}
+
+	ghc := newGitHubClient(secrets, cfg, connFlags)
+	claSigners := config.ParseClaSigners(*claSignersFileFlag)
+	ghc.Report = report.New()
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:               orgName,
+		Repo:              repoName,
+		UnknownAsExternal: cfg.UnknownAsExternal,
+		SkipForks:         cfg.SkipForks,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	finishGitHubClient(ghc)
+	if err != nil {
+		// Stats of only some of the repos would skew the trends.
+		logging.Fatalf("Error checking PRs in org %s: %s", orgName, err)
+	}
+
+	stats := report.ComputeStats(ghc.Report, previous, time.Now().UTC())
+
+	var output io.Writer = os.Stdout
+	if *outputFileFlag != "" {
+		outputFile, err := os.Create(*outputFileFlag)
+		if err != nil {
+			logging.Fatalf("Error creating output file '%s': %s", *outputFileFlag, err)
+		}
+		defer outputFile.Close()
+		output = outputFile
+	}
+
+	if *formatFlag == "json" {
+		err = report.WriteStatsJSON(output, stats)
+	} else {
+		err = report.WriteStatsTable(output, stats)
+	}
+	if err != nil {
+		logging.Fatalf("Error writing stats: %s", err)
+	}
+
+	if *historyFileFlag != "" {
+		writeStatsHistory(*historyFileFlag, stats)
+	}
+}
+
+// readStatsHistory reads the stats from the previous run, if any.
+func readStatsHistory(filename string) *report.Stats {
+	historyFile, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		logging.Fatalf("Error reading history file '%s': %s", filename, err)
+	}
+	defer historyFile.Close()
+
+	stats, err := report.ReadStats(historyFile)
+	if err != nil {
+		logging.Fatalf("Error parsing history file '%s': %s", filename, err)
+	}
+	return stats
+}
+
+// writeStatsHistory saves the stats from this run for computing trends on the
+// next run.
+func writeStatsHistory(filename string, stats report.Stats) {
+	historyFile, err := os.Create(filename)
+	if err != nil {
+		logging.Fatalf("Error creating history file '%s': %s", filename, err)
+	}
+	defer historyFile.Close()
+
+	if err := report.WriteStatsJSON(historyFile, stats); err != nil {
+		logging.Fatalf("Error writing history file '%s': %s", filename, err)
+	}
+}
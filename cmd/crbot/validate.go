@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/logging"
+)
+
+// validateMain implements the `validate` subcommand, which checks the config
+// and CLA signers files the same way as the other subcommands do on startup,
+// without connecting to GitHub, e.g., in the presubmit checks of the repo
+// holding them. It exits with an error if either file is invalid.
+func validateMain(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	configFileFlag := flags.String("config", "", "Path to config file; optional")
+	claSignersFileFlag := flags.String("cla-signers", "", "Path to CLA signers; optional")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Syntax: %s validate [flags]\n\nFlags:\n", path.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+
+	parseFlags(flags, args)
+
+	if *configFileFlag == "" && *claSignersFileFlag == "" {
+		logging.Fatalf("-config or -cla-signers flag is required")
+	}
+
+	if *configFileFlag != "" {
+		checkConfig(config.ParseConfig(*configFileFlag))
+		fmt.Printf("%s: OK\n", *configFileFlag)
+	}
+	if *claSignersFileFlag != "" {
+		claSigners := config.ParseClaSigners(*claSignersFileFlag)
+		fmt.Printf("%s: OK (%d people, %d bots, %d companies)\n", *claSignersFileFlag,
+			len(claSigners.People), len(claSigners.Bots), len(claSigners.Companies))
+	}
+}
@@ -17,34 +17,411 @@ package config
 import (
 	"encoding/json"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-yaml/yaml"
 
 	"github.com/google/code-review-bot/logging"
 )
 
-// Secrets contains the authentication credentials for interacting with GitHub.
+// Secrets contains the authentication credentials for interacting with GitHub
+// and, optionally, with the CLA service configured via `SignerLookup`, as well
+// as the secret which webhook deliveries must be signed with in server mode,
+// the token authenticating requests to its admin endpoints, the secret which
+// events POSTed to webhooks (see `Events`) are signed with, the credentials of
+// the Kafka REST Proxy events are produced via, and the Slack incoming webhook
+// URL which digests are posted to.
 type Secrets struct {
-	Auth string `json:"auth" yaml:"auth"`
+	Auth          string `json:"auth" yaml:"auth"`
+	SignerLookup  string `json:"signer_lookup,omitempty" yaml:"signer_lookup,omitempty"`
+	WebhookSecret string `json:"webhook_secret,omitempty" yaml:"webhook_secret,omitempty"`
+	AdminToken    string `json:"admin_token,omitempty" yaml:"admin_token,omitempty"`
+	EventsSecret  string `json:"events_secret,omitempty" yaml:"events_secret,omitempty"`
+	KafkaAuth     string `json:"kafka_auth,omitempty" yaml:"kafka_auth,omitempty"`
+	SlackWebhook  string `json:"slack_webhook,omitempty" yaml:"slack_webhook,omitempty"`
 }
 
 // Config is the configuration for the `crbot` tool to specify the scope at
 // which it should run, whether for all repos in a single organization, or a
 // single specific repo.
 type Config struct {
+	// SchemaVersion is the version of the format of the file; see
+	// `ConfigSchemaVersion`. Files without it are version 1.
+	SchemaVersion int `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+
+	// Include lists other config files (relative to this one) to load
+	// first; settings in this file override those of the included files.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+
 	Org               string `json:"org,omitempty" yaml:"org,omitempty"`
 	Repo              string `json:"repo,omitempty" yaml:"repo,omitempty"`
 	UnknownAsExternal bool   `json:"unknown_as_external,omitempty" yaml:"unknown_as_external,omitempty"`
+
+	// CABundle is the path to a PEM file with additional CA certificates
+	// to trust when connecting to GitHub, e.g., for GitHub Enterprise
+	// Server instances with internally-issued certificates.
+	CABundle string `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+
+	// UserAgent overrides the user agent sent with GitHub API requests,
+	// and Headers lists additional headers to send with each request.
+	UserAgent string            `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// CircuitBreaker pauses GitHub API requests while the API is failing.
+	CircuitBreaker CircuitBreaker `json:"circuit_breaker,omitempty" yaml:"circuit_breaker,omitempty"`
+
+	// SkipForks excludes forked repos from processing; archived repos are
+	// always skipped, as they are read-only.
+	SkipForks bool `json:"skip_forks,omitempty" yaml:"skip_forks,omitempty"`
+
+	// Checkers lists the names of the compliance checkers to run against
+	// each commit; if empty, only the built-in CLA signers check is run.
+	Checkers []string `json:"checkers,omitempty" yaml:"checkers,omitempty"`
+
+	// ClaURL is the URL where contributors can sign the CLA, included in
+	// the comment posted on non-compliant pull requests.
+	ClaURL string `json:"cla_url,omitempty" yaml:"cla_url,omitempty"`
+
+	// CommentTemplate is a Go `text/template` overriding the default
+	// comment posted on non-compliant pull requests; it has access to the
+	// fields `.Org`, `.Repo`, `.Number`, `.Author`, `.Reason`, `.Reasons`
+	// (the `.SHA` and `.Reason` of each non-compliant commit), `.ClaURL`,
+	// and `.Unsigned` (the identities which still need to sign the CLA).
+	CommentTemplate string `json:"comment_template,omitempty" yaml:"comment_template,omitempty"`
+
+	// Locale selects the language of the built-in comment template and
+	// non-compliance reasons (e.g., "de", "ja", "pt-BR"); RepoLocales
+	// overrides it for individual repos, keyed by repo name.
+	Locale      string            `json:"locale,omitempty" yaml:"locale,omitempty"`
+	RepoLocales map[string]string `json:"repo_locales,omitempty" yaml:"repo_locales,omitempty"`
+
+	// Labels overrides the names of the CLA-related labels.
+	Labels Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// LabelCacheMinutes is how long the CLA labels defined by each repo are
+	// cached, e.g., by `crbot serve`, rather than being looked up for each
+	// PR; it defaults to 60, and a negative value disables the cache.
+	LabelCacheMinutes int `json:"label_cache_minutes,omitempty" yaml:"label_cache_minutes,omitempty"`
+
+	// RequestChanges submits a review requesting changes on non-compliant
+	// pull requests, which is dismissed once they become compliant; with
+	// SkipLabels, the review replaces the CLA labels instead of adding to
+	// them.
+	RequestChanges bool `json:"request_changes,omitempty" yaml:"request_changes,omitempty"`
+	SkipLabels     bool `json:"skip_labels,omitempty" yaml:"skip_labels,omitempty"`
+
+	// BaseBranches, if set, restricts processing to pull requests whose
+	// base branch matches one of these branch names or glob patterns,
+	// e.g., "main" or "release-*", as PRs against short-lived feature
+	// branches often don't need CLA enforcement.
+	BaseBranches []string `json:"base_branches,omitempty" yaml:"base_branches,omitempty"`
+
+	// TrustLevel is how much evidence commits need for their identities
+	// to be trusted: "matched" (the default) accepts identities matching
+	// CLA signers, while "verified" also requires GitHub to have verified
+	// the commit signatures, and the authors to be the committers, whose
+	// emails the signatures vouch for; see `ghutil.TrustLevels`.
+	TrustLevel string `json:"trust_level,omitempty" yaml:"trust_level,omitempty"`
+
+	// NoComments disables commenting on non-compliant pull requests,
+	// including reminders, for orgs which want the CLA labels only.
+	NoComments bool `json:"no_comments,omitempty" yaml:"no_comments,omitempty"`
+
+	// MaxCommentsPerSHA caps the number of comments posted on a
+	// non-compliant pull request while its head commit stays the same,
+	// which requires a state file to track them; it defaults to 1, and a
+	// negative value removes the cap. Reminders are not counted.
+	MaxCommentsPerSHA int `json:"max_comments_per_sha,omitempty" yaml:"max_comments_per_sha,omitempty"`
+
+	// Reminders configures periodic reminders on non-compliant pull
+	// requests, which requires a state file to track them.
+	Reminders Reminders `json:"reminders,omitempty" yaml:"reminders,omitempty"`
+
+	// Reviewers are requested to review pull requests once they become
+	// compliant.
+	Reviewers Reviewers `json:"reviewers,omitempty" yaml:"reviewers,omitempty"`
+
+	// MinimizeComments collapses the bot's earlier comments on a pull
+	// request as outdated whenever it posts a new one, e.g., a reminder,
+	// keeping long threads readable. Only comments posted with it enabled
+	// are recognized as the bot's.
+	MinimizeComments bool `json:"minimize_comments,omitempty" yaml:"minimize_comments,omitempty"`
+
+	// TrackingIssue configures an issue per repo, or a single one for the
+	// org, listing the pull requests which are not compliant.
+	TrackingIssue TrackingIssue `json:"tracking_issue,omitempty" yaml:"tracking_issue,omitempty"`
+
+	// CommitStatus configures reporting the compliance of pull requests
+	// as a commit status on their head commit.
+	CommitStatus CommitStatus `json:"commit_status,omitempty" yaml:"commit_status,omitempty"`
+
+	// Trivial configures exempting trivial documentation changes from the
+	// CLA requirement, per the "obvious fix" rule of many CLA policies.
+	Trivial TrivialPolicy `json:"trivial,omitempty" yaml:"trivial,omitempty"`
+
+	// BigQuery configures exporting the compliance decisions of each run
+	// to a BigQuery table.
+	BigQuery BigQuery `json:"bigquery,omitempty" yaml:"bigquery,omitempty"`
+
+	// PullOrder is the order in which the PRs of each repo are processed,
+	// e.g., "updated-desc" to label the most recently updated PRs first;
+	// see `ghutil.PullOrders`. If empty, the newest PRs are processed first.
+	PullOrder string `json:"pull_order,omitempty" yaml:"pull_order,omitempty"`
+
+	// Events configures publishing an event whenever the bot changes the
+	// CLA labels of a pull request.
+	Events Events `json:"events,omitempty" yaml:"events,omitempty"`
+
+	// SignerLookup configures an external CLA service which is consulted
+	// about identities that don't match any of the CLA signers.
+	SignerLookup SignerLookup `json:"signer_lookup,omitempty" yaml:"signer_lookup,omitempty"`
+
+	// Directory configures `crbot signers sync`, which populates the
+	// people of companies in the CLA signers from a Google Workspace
+	// directory.
+	Directory Directory `json:"directory,omitempty" yaml:"directory,omitempty"`
+}
+
+// Directory identifies the Google Workspace directory to read company rosters
+// from via the Admin SDK. `Credentials` is the path to a service account key
+// file with domain-wide delegation, which impersonates `Admin`, a user allowed
+// to read the directory. `LoginField` names the custom user attribute holding
+// each user's GitHub login, in the form "SCHEMA.FIELD"; users without one are
+// left out, as they can't be matched against commits.
+type Directory struct {
+	Credentials string             `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Admin       string             `json:"admin,omitempty" yaml:"admin,omitempty"`
+	LoginField  string             `json:"login_field,omitempty" yaml:"login_field,omitempty"`
+	Companies   []DirectoryCompany `json:"companies,omitempty" yaml:"companies,omitempty"`
+}
+
+// DirectoryCompany maps a company of the CLA signers to its roster in the
+// directory: either the members of `Group` (by email), including those of
+// nested groups, or all users of `Domain`. Suspended users are left out.
+type DirectoryCompany struct {
+	Company string `json:"company" yaml:"company"`
+	Group   string `json:"group,omitempty" yaml:"group,omitempty"`
+	Domain  string `json:"domain,omitempty" yaml:"domain,omitempty"`
+}
+
+// SignerLookup identifies the CLA service to query about unmatched identities;
+// see `lookup.HTTPLookup` for the protocol. The token from the secrets file, if
+// any, is sent in `AuthHeader`, which defaults to "Authorization". Results are
+// cached for `CacheMinutes`, which defaults to 60.
+//
+// `Service` selects a CLA service with built-in support instead, e.g.,
+// "google" for cla.developers.google.com, in which case `URL` is required,
+// naming an endpoint in front of the service which answers using the same
+// protocol, and requests are authenticated with `Credentials`, as for
+// `BigQuery`.
+//
+// With `Service` "okta", identities are looked up among the members of the
+// Okta `Groups` instead: `URL` is the Okta org URL, e.g.,
+// "https://example.okta.com", and the token from the secrets file is an Okta
+// API token. Members are matched by email and, if `LoginAttribute` is set, by
+// the GitHub login in that attribute of their Okta profile; group memberships
+// are cached for `CacheMinutes`.
+//
+// With `Service` "docusign", individual CLAs are confirmed from the completed
+// DocuSign envelopes signed by the identity's email instead: `URL` is the base
+// URI of the DocuSign account, e.g.,
+// "https://na3.docusign.net/restapi/v2.1/accounts/ACCOUNT_ID", `Credentials`
+// is the path to the JWT grant credentials of the integration (see
+// `lookup.NewDocuSignClient`), and only envelopes whose email subject contains
+// `EnvelopeSubject` count.
+type SignerLookup struct {
+	Service        string      `json:"service,omitempty" yaml:"service,omitempty"`
+	URL            string      `json:"url,omitempty" yaml:"url,omitempty"`
+	Credentials    string      `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	AuthHeader     string      `json:"auth_header,omitempty" yaml:"auth_header,omitempty"`
+	CacheMinutes   int         `json:"cache_minutes,omitempty" yaml:"cache_minutes,omitempty"`
+	Groups         []OktaGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+	LoginAttribute string      `json:"login_attribute,omitempty" yaml:"login_attribute,omitempty"`
+
+	EnvelopeSubject string `json:"envelope_subject,omitempty" yaml:"envelope_subject,omitempty"`
+}
+
+// OktaGroup maps an Okta group, by ID, to the company whose corporate CLA
+// covers its members.
+type OktaGroup struct {
+	ID      string `json:"id" yaml:"id"`
+	Company string `json:"company" yaml:"company"`
+}
+
+// CircuitBreaker configures pausing GitHub API requests once `Failures`
+// consecutive requests have failed with network or server errors, for
+// `CooldownSeconds`, rather than failing each of the remaining PRs. They
+// default to 5 failures and 60 seconds; negative failures disable the breaker.
+type CircuitBreaker struct {
+	Failures        int `json:"failures,omitempty" yaml:"failures,omitempty"`
+	CooldownSeconds int `json:"cooldown_seconds,omitempty" yaml:"cooldown_seconds,omitempty"`
+}
+
+// Events configures where to publish events: `PubSubTopic` is a Pub/Sub topic
+// in the form "projects/PROJECT/topics/TOPIC", and `WebhookURL` is a URL to
+// POST them to as JSON, as are `Webhooks`, e.g., the endpoints of ticketing
+// and merge automation systems, and `Kafka` is a Kafka topic to produce them
+// to; any of them may be set. `Credentials` is used for Pub/Sub as for
+// `BigQuery`. With `Checks`, the compliance decision of every pull request
+// checked is published as well, e.g., for audit pipelines, rather than only
+// changes to its labels.
+type Events struct {
+	PubSubTopic string   `json:"pubsub_topic,omitempty" yaml:"pubsub_topic,omitempty"`
+	WebhookURL  string   `json:"webhook_url,omitempty" yaml:"webhook_url,omitempty"`
+	Webhooks    []string `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	Kafka       Kafka    `json:"kafka,omitempty" yaml:"kafka,omitempty"`
+	Credentials string   `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Checks      bool     `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// Kafka identifies the topic to produce events to via the Kafka REST Proxy at
+// `RESTProxyURL`, e.g., "https://kafka-rest.example.com:8082"; the proxy
+// is authenticated with the `kafka_auth` credentials from the secrets file,
+// if any, in the form "USER:PASSWORD".
+type Kafka struct {
+	RESTProxyURL string `json:"rest_proxy_url,omitempty" yaml:"rest_proxy_url,omitempty"`
+	Topic        string `json:"topic,omitempty" yaml:"topic,omitempty"`
+}
+
+// BigQuery identifies the table to export compliance decisions to; exporting
+// is disabled if `Table` is empty. `Credentials` is the path to a service
+// account key file; if empty, the standard GOOGLE_APPLICATION_CREDENTIALS
+// environment variable or the metadata server of the instance are used.
+type BigQuery struct {
+	Project     string `json:"project,omitempty" yaml:"project,omitempty"`
+	Dataset     string `json:"dataset,omitempty" yaml:"dataset,omitempty"`
+	Table       string `json:"table,omitempty" yaml:"table,omitempty"`
+	Credentials string `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+// TrivialPolicy exempts commits which change fewer than `MaxLines` lines in
+// total (additions plus deletions), all of them in files matching `Paths`;
+// the policy is disabled if `MaxLines` is zero. Each of `Paths` is either a
+// directory prefix ending in "/" (e.g., "docs/"), or a `path.Match` pattern
+// matched against the full path or, if it has no "/", the base name (e.g.,
+// "*.md"); if empty, common documentation paths are used.
+type TrivialPolicy struct {
+	MaxLines int      `json:"max_lines,omitempty" yaml:"max_lines,omitempty"`
+	Paths    []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// Reviewers lists the GitHub users (by login) and teams (by slug) to request
+// reviews from.
+type Reviewers struct {
+	Users []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Teams []string `json:"teams,omitempty" yaml:"teams,omitempty"`
+}
+
+// CommitStatus configures the commit status set on the head commit of each
+// pull request processed: "pending" as soon as its processing starts, then
+// "success" if it is compliant (or its CLA is managed externally), "failure"
+// if not, or "error" if its compliance couldn't be checked. The status is
+// reported under `Context`, which defaults to "cla/crbot", and can be made a
+// required check via branch protection.
+//
+// With `CheckRun`, the final status is instead reported as a check run named
+// `Context`, with an annotation per non-compliant commit identifying the
+// identities which failed to match; this requires running as a GitHub App,
+// and no pending status is reported.
+type CommitStatus struct {
+	Enabled  bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Context  string `json:"context,omitempty" yaml:"context,omitempty"`
+	CheckRun bool   `json:"check_run,omitempty" yaml:"check_run,omitempty"`
+}
+
+// TrackingIssue configures an issue which the bot updates at the end of each
+// run over all open pull requests with a checklist of those which are not
+// compliant, and their reasons. Each repo has its own issue, unless `Repo`
+// names a repo of the org (e.g., a dashboard repo) holding a single issue for
+// all repos. The issue is titled `Title`, which defaults to "Non-compliant
+// pull requests (CLA)", is only created once there are non-compliant pull
+// requests, and is pinned if `Pin` is set.
+type TrackingIssue struct {
+	Enabled bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Repo    string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+	Pin     bool   `json:"pin,omitempty" yaml:"pin,omitempty"`
+}
+
+// Reminders configures re-pinging the authors of non-compliant pull requests
+// every `IntervalDays` days after the initial comment, up to `Max` times (or
+// indefinitely if zero); reminders are disabled if `IntervalDays` is zero.
+type Reminders struct {
+	IntervalDays int `json:"interval_days,omitempty" yaml:"interval_days,omitempty"`
+	Max          int `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+// Labels configures the names of the CLA-related labels; any which are empty
+// use the default names.
+type Labels struct {
+	Compliant    string `json:"compliant,omitempty" yaml:"compliant,omitempty"`
+	NonCompliant string `json:"non_compliant,omitempty" yaml:"non_compliant,omitempty"`
+	External     string `json:"external,omitempty" yaml:"external,omitempty"`
+
+	// Skip is the override label (e.g., "cla: skip") which maintainers
+	// can apply to exclude a pull request from processing; unlike the
+	// other labels, it has no default, so the override is disabled unless
+	// configured.
+	Skip string `json:"skip,omitempty" yaml:"skip,omitempty"`
+}
+
+// RepoConfigPath is the path of the optional per-repo config file within each
+// processed repo.
+const RepoConfigPath = ".github/crbot.yaml"
+
+// RepoConfig is the per-repo configuration read from `RepoConfigPath` in each
+// processed repo; any settings specified here override the corresponding
+// settings of the global config for that repo.
+type RepoConfig struct {
+	// Skip disables processing of this repo entirely.
+	Skip              bool   `json:"skip,omitempty" yaml:"skip,omitempty"`
+	UnknownAsExternal *bool  `json:"unknown_as_external,omitempty" yaml:"unknown_as_external,omitempty"`
+	NoComments        *bool  `json:"no_comments,omitempty" yaml:"no_comments,omitempty"`
+	ClaURL            string `json:"cla_url,omitempty" yaml:"cla_url,omitempty"`
+	CommentTemplate   string `json:"comment_template,omitempty" yaml:"comment_template,omitempty"`
+	Locale            string `json:"locale,omitempty" yaml:"locale,omitempty"`
+	TrustLevel        string `json:"trust_level,omitempty" yaml:"trust_level,omitempty"`
+	Labels            Labels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Reviewers, if any are listed, replace the globally-configured ones.
+	Reviewers Reviewers `json:"reviewers,omitempty" yaml:"reviewers,omitempty"`
+}
+
+// OrgConfigRepo and OrgConfigPath identify the optional org-wide config file,
+// which is read from the organization's `.github` repository.
+const (
+	OrgConfigRepo = ".github"
+	OrgConfigPath = "crbot.yaml"
+)
+
+// OrgConfig is the org-wide configuration read from `OrgConfigPath` in the
+// `OrgConfigRepo` repository of the organization; its settings override those
+// of the global config for all repos in the org, and are in turn overridden by
+// each repo's own `RepoConfig`.
+type OrgConfig struct {
+	RepoConfig `json:",inline" yaml:",inline"`
+
+	// ExcludeRepos lists the names of repos which should not be processed.
+	ExcludeRepos []string `json:"exclude_repos,omitempty" yaml:"exclude_repos,omitempty"`
 }
 
 // Account represents a single user record, whether human or a bot, with a name,
-// email, and GitHub login.
+// email, and GitHub login. In the `bots` sections, each of them may instead be
+// a pattern (see `IsPattern`) matching a family of bots, e.g., all GitHub Apps
+// with `github: /.*\[bot\]/`; the name and email of such an entry may also be
+// omitted to match any.
 type Account struct {
 	Name  string `json:"name" yaml:"name"`
 	Email string `json:"email" yaml:"email"`
 	Login string `json:"github" yaml:"github"`
+
+	// Aliases lists previous or alternate GitHub logins of the same user,
+	// e.g., from before a rename; any of them is accepted in place of Login.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
 }
 
 // Company represents a company record with a name, (optional) domain name(s),
@@ -53,12 +430,50 @@ type Company struct {
 	Name    string    `json:"name" yaml:"name"`
 	Domains []string  `json:"domains,omitempty" yaml:"domains,omitempty"`
 	People  []Account `json:"people" yaml:"people"`
+
+	// Agreement records the corporate CLA itself, whose fields are set
+	// alongside the others, e.g., `agreement_id: CCLA-1234`.
+	Agreement `yaml:",inline"`
+}
+
+// Agreement identifies the record of a corporate CLA, so that each commit
+// covered by it can be traced back to it in reports and logs. All of its
+// fields are optional; `SignedDate` is in the form YYYY-MM-DD.
+type Agreement struct {
+	AgreementID  string `json:"agreement_id,omitempty" yaml:"agreement_id,omitempty"`
+	ContactEmail string `json:"contact_email,omitempty" yaml:"contact_email,omitempty"`
+	SignedDate   string `json:"signed_date,omitempty" yaml:"signed_date,omitempty"`
+}
+
+// String describes the agreement in log lines, e.g., "agreement CCLA-1234,
+// signed 2019-05-01, contact legal@example.com", or is empty if none of its
+// fields are set.
+func (a Agreement) String() string {
+	var parts []string
+	if a.AgreementID != "" {
+		parts = append(parts, "agreement "+a.AgreementID)
+	}
+	if a.SignedDate != "" {
+		parts = append(parts, "signed "+a.SignedDate)
+	}
+	if a.ContactEmail != "" {
+		parts = append(parts, "contact "+a.ContactEmail)
+	}
+	return strings.Join(parts, ", ")
 }
 
+// signedDateLayout is the layout of `Agreement.SignedDate`.
+const signedDateLayout = "2006-01-02"
+
 // ExternalClaSigners represents CLA signers managed by an external process,
 // i.e., not covered by this tool. This is useful for handling migrations into
 // or out of the system provided by Code Review Bot.
 type ExternalClaSigners struct {
+	// ManagedBy names the system managing these CLA signers, e.g.,
+	// "EasyCLA", which is mentioned in logs, comments, and reports on pull
+	// requests labeled as external, so that maintainers know where to look.
+	ManagedBy string `json:"managed_by,omitempty" yaml:"managed_by,omitempty"`
+
 	People    []Account `json:"people,omitempty" yaml:"people,omitempty"`
 	Bots      []Account `json:"bots,omitempty" yaml:"bots,omitempty"`
 	Companies []Company `json:"companies,omitempty" yaml:"companies,omitempty"`
@@ -67,54 +482,331 @@ type ExternalClaSigners struct {
 // ClaSigners provides the overall structure of the CLA config: individual CLA
 // signers, bots, and corporate CLA signers.
 type ClaSigners struct {
+	// SchemaVersion is the version of the format of the file; see
+	// `ClaSignersSchemaVersion`. Files without it are version 1.
+	SchemaVersion int `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+
+	// Include lists other CLA signers files (relative to this one) whose
+	// entries are added to those of this file, e.g., one file per company.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+
 	People    []Account           `json:"people,omitempty" yaml:"people,omitempty"`
 	Bots      []Account           `json:"bots,omitempty" yaml:"bots,omitempty"`
 	Companies []Company           `json:"companies,omitempty" yaml:"companies,omitempty"`
 	External  *ExternalClaSigners `json:"external,omitempty" yaml:"external,omitempty"`
+
+	// ExemptCommits lists commits which are considered compliant
+	// regardless of their authors and committers.
+	ExemptCommits []ExemptCommit `json:"exempt_commits,omitempty" yaml:"exempt_commits,omitempty"`
+
+	// Maintainers lists the accounts which may exempt individual commits
+	// (e.g., cherry-picks of third-party code) via a `CLA-Exempt: <reason>`
+	// trailer in the commit message; the trailer is only honored if the
+	// commit's signature is verified by GitHub and its committer login is
+	// that of one of them, so it is ignored if the list is empty.
+	Maintainers []Account `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+
+	// StrictMatching compares the emails and logins of commits with those
+	// of signers exactly, rather than case-insensitively, with Gmail
+	// addresses canonicalized, and with GitHub noreply addresses and login
+	// aliases accepted. It is only honored in the top-level file, not in
+	// included ones.
+	StrictMatching bool `json:"strict_matching,omitempty" yaml:"strict_matching,omitempty"`
+
+	// RequireCoAuthors requires the co-authors named in `Co-authored-by`
+	// trailers of commit messages, e.g., of squashed commits, to be CLA
+	// signers as well; as trailers carry no GitHub login, they are matched
+	// by email only. It is only honored in the top-level file, not in
+	// included ones.
+	RequireCoAuthors bool `json:"require_co_authors,omitempty" yaml:"require_co_authors,omitempty"`
+
+	// GitHubActions treats commits committed by GitHub Actions (i.e., by
+	// `github-actions[bot]`), e.g., automated changelog or formatting
+	// commits pushed to pull request branches by workflows, as bot commits
+	// without listing it in `Bots`; commits it both authored and committed
+	// need no other signer. It is only honored in the top-level file, not
+	// in included ones.
+	GitHubActions bool `json:"github_actions,omitempty" yaml:"github_actions,omitempty"`
+}
+
+// ExemptCommit is a commit exempt from CLA checks, e.g., a historical import
+// of vendored code, or an emergency fix. `SHA` must be the full SHA of the
+// commit, as abbreviated ones can be matched by brute-forcing commits with the
+// same prefix; `Reason` is recorded in logs and reports.
+type ExemptCommit struct {
+	SHA    string `json:"sha" yaml:"sha"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// IsPattern returns whether the value is a regular expression delimited by
+// slashes, e.g., "/renovate-.*/", rather than a literal value.
+func IsPattern(value string) bool {
+	return len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/")
+}
+
+// CompilePattern compiles the regular expression of a pattern, which matches
+// entire values, ignoring case.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)^(?:" + pattern[1:len(pattern)-1] + ")$")
+}
+
+// checkBotPatterns returns an error for the first invalid pattern among the
+// bots, if any.
+func checkBotPatterns(bots []Account) error {
+	for _, bot := range bots {
+		for _, value := range []string{bot.Login, bot.Name, bot.Email} {
+			if !IsPattern(value) {
+				continue
+			}
+			if _, err := CompilePattern(value); err != nil {
+				return fmt.Errorf("invalid pattern %s for bot: %s", value, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExemptSHALength is the length of the SHAs accepted in `ExemptCommit`, i.e.,
+// of full, hex-encoded SHA-1 commit IDs.
+const ExemptSHALength = 40
+
+// isFullSHA returns whether the value is a full, hex-encoded commit SHA.
+func isFullSHA(value string) bool {
+	if len(value) != ExemptSHALength {
+		return false
+	}
+	for _, c := range value {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
 }
 
 // parseFile is a helper method for parsing any of the YAML or JSON files we
-// need to load: secrets, config, or CLA signers.
-func parseFile(filetype string, filename string, data interface{}) {
-	fileContents, err := ioutil.ReadFile(filename)
+// need to load: secrets, config, or CLA signers. The file may also be in a
+// GitHub repo; see `RepoSourcePrefix`.
+func parseFile(filetype string, filename string, data interface{}) error {
+	fileContents, err := readFile(filename)
 	if err != nil {
-		logging.Fatalf("Error reading %s file '%s': %s", filetype, filename, err)
+		return fmt.Errorf("error reading %s file '%s': %s", filetype, filename, err)
 	}
 
-	if strings.HasSuffix(filename, ".json") {
-		err = json.Unmarshal(fileContents, data)
-	} else if strings.HasSuffix(filename, ".yaml") || strings.HasSuffix(filename, ".yml") {
+	// JSON files may contain comments and trailing commas, as with
+	// *.jsonc files.
+	filePath := locationPath(filename)
+	if strings.HasSuffix(filePath, ".json") || strings.HasSuffix(filePath, ".jsonc") {
+		err = json.Unmarshal(stripJSONC(fileContents), data)
+	} else if strings.HasSuffix(filePath, ".yaml") || strings.HasSuffix(filePath, ".yml") {
 		err = yaml.Unmarshal(fileContents, data)
 	} else {
-		err = errors.New("unsupported file type; accepted: *.json, *.yaml, *.yml")
+		err = errors.New("unsupported file type; accepted: *.json, *.jsonc, *.yaml, *.yml")
 	}
 
 	if err != nil {
-		logging.Fatalf("Error parsing %s file '%s': %s", filetype, filename, err)
+		return fmt.Errorf("error parsing %s file '%s': %s", filetype, filename, err)
 	}
+	return nil
 }
 
-// ParseSecrets parses the secrets (including auth tokens) from a YAML or JSON file.
+// resolveInclude returns the path of an included file, which is relative to
+// the directory of the file including it unless it is an absolute path (or
+// location in a GitHub repo).
+func resolveInclude(filename string, include string) string {
+	if IsRepoSource(include) {
+		return include
+	} else if IsRepoSource(filename) {
+		return resolveRepoInclude(filename, include)
+	} else if filepath.IsAbs(include) {
+		return include
+	}
+	return filepath.Join(filepath.Dir(filename), include)
+}
+
+// pushInclude adds the file to the chain of files being loaded via `include`
+// directives, failing if it is already being loaded, to prevent cycles.
+func pushInclude(filetype string, filename string, chain []string) ([]string, error) {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil || IsRepoSource(filename) {
+		absFilename = filename
+	}
+	for _, loading := range chain {
+		if loading == absFilename {
+			return nil, fmt.Errorf("error parsing %s file '%s': include cycle via %s", filetype, filename, strings.Join(chain, " -> "))
+		}
+	}
+	return append(chain[:len(chain):len(chain)], absFilename), nil
+}
+
+// fatalIfError exits with the error, if any, on behalf of the `Parse*`
+// functions, which are meant for the `crbot` command; programs embedding the
+// bot use the corresponding `Load*` functions instead.
+func fatalIfError(err error) {
+	if err != nil {
+		// Errors start in lowercase, while log lines don't.
+		message := err.Error()
+		logging.Fatalf("%s%s", strings.ToUpper(message[:1]), message[1:])
+	}
+}
+
+// ParseSecrets parses the secrets (including auth tokens) from a YAML or JSON
+// file, exiting on errors.
 func ParseSecrets(filename string) Secrets {
-	var secrets Secrets
-	parseFile("secrets", filename, &secrets)
+	secrets, err := LoadSecrets(filename)
+	fatalIfError(err)
 	return secrets
 }
 
-// ParseConfig parses the config from a YAML or JSON file.
+// LoadSecrets parses the secrets (including auth tokens) from a YAML or JSON
+// file.
+func LoadSecrets(filename string) (Secrets, error) {
+	var secrets Secrets
+	err := parseFile("secrets", filename, &secrets)
+	return secrets, err
+}
+
+// ParseConfig parses the config from a YAML or JSON file, exiting on errors.
 func ParseConfig(filename string) Config {
+	config, err := LoadConfig(filename)
+	fatalIfError(err)
+	return config
+}
+
+// LoadConfig parses the config from a YAML or JSON file.
+func LoadConfig(filename string) (Config, error) {
 	var config Config
 	// This config file is optional, so we shouldn't fail if the filename
 	// is an empty string, but just return an uninitialized Config struct.
-	if filename != "" {
-		parseFile("config", filename, &config)
+	if filename == "" {
+		return config, nil
 	}
-	return config
+	err := parseConfigFile(filename, &config, nil)
+	return config, err
+}
+
+// parseConfigFile parses the config file on top of the files it includes, so
+// that its own settings override those of the included files.
+func parseConfigFile(filename string, config *Config, chain []string) error {
+	chain, err := pushInclude("config", filename, chain)
+	if err != nil {
+		return err
+	}
+
+	var includes Config
+	if err := parseFile("config", filename, &includes); err != nil {
+		return err
+	}
+	for _, include := range includes.Include {
+		if err := parseConfigFile(resolveInclude(filename, include), config, chain); err != nil {
+			return err
+		}
+	}
+
+	if err := parseFile("config", filename, config); err != nil {
+		return err
+	}
+	config.Include = nil
+	if err := migrateConfig(config, includes.SchemaVersion); err != nil {
+		return fmt.Errorf("error parsing config file '%s': %s", filename, err)
+	}
+	return nil
 }
 
-// ParseClaSigners parses the CLA signers config from a YAML or JSON file.
+// ParseRepoConfig parses the contents of a per-repo config file. Unlike the
+// other config files, errors are returned rather than being fatal, as these
+// files are maintained by each repo's owners rather than the bot's operator.
+func ParseRepoConfig(data []byte) (RepoConfig, error) {
+	var repoConfig RepoConfig
+	err := yaml.UnmarshalStrict(data, &repoConfig)
+	return repoConfig, err
+}
+
+// ParseOrgConfig parses the contents of an org-wide config file; as with
+// `ParseRepoConfig`, errors are returned rather than being fatal.
+func ParseOrgConfig(data []byte) (OrgConfig, error) {
+	var orgConfig OrgConfig
+	err := yaml.UnmarshalStrict(data, &orgConfig)
+	return orgConfig, err
+}
+
+// ParseClaSigners parses the CLA signers config from a YAML or JSON file,
+// exiting on errors.
 func ParseClaSigners(filename string) ClaSigners {
-	var claSigners ClaSigners
-	parseFile("CLA signers", filename, &claSigners)
+	claSigners, err := LoadClaSigners(filename)
+	fatalIfError(err)
 	return claSigners
 }
+
+// LoadClaSigners parses the CLA signers config from a YAML or JSON file.
+func LoadClaSigners(filename string) (ClaSigners, error) {
+	return parseClaSignersFile(filename, nil)
+}
+
+// parseClaSignersFile parses the CLA signers file, along with all of the files
+// it includes.
+func parseClaSignersFile(filename string, chain []string) (ClaSigners, error) {
+	var claSigners ClaSigners
+	chain, err := pushInclude("CLA signers", filename, chain)
+	if err != nil {
+		return claSigners, err
+	}
+
+	if err := parseFile("CLA signers", filename, &claSigners); err != nil {
+		return claSigners, err
+	}
+	if err := migrateClaSigners(&claSigners); err != nil {
+		return claSigners, fmt.Errorf("error parsing CLA signers file '%s': %s", filename, err)
+	}
+	for _, include := range claSigners.Include {
+		included, err := parseClaSignersFile(resolveInclude(filename, include), chain)
+		if err != nil {
+			return claSigners, err
+		}
+		claSigners.merge(included)
+	}
+	claSigners.Include = nil
+	bots := claSigners.Bots
+	if claSigners.External != nil {
+		bots = append(bots[:len(bots):len(bots)], claSigners.External.Bots...)
+	}
+	if err := checkBotPatterns(bots); err != nil {
+		return claSigners, fmt.Errorf("error parsing CLA signers file '%s': %s", filename, err)
+	}
+	for _, exempt := range claSigners.ExemptCommits {
+		if !isFullSHA(exempt.SHA) {
+			return claSigners, fmt.Errorf("error parsing CLA signers file '%s': exempt commit SHA '%s' is not a full %d-character SHA", filename, exempt.SHA, ExemptSHALength)
+		}
+	}
+	companies := claSigners.Companies
+	if claSigners.External != nil {
+		companies = append(companies[:len(companies):len(companies)], claSigners.External.Companies...)
+	}
+	for _, company := range companies {
+		if date := company.SignedDate; date != "" {
+			if _, err := time.Parse(signedDateLayout, date); err != nil {
+				return claSigners, fmt.Errorf("error parsing CLA signers file '%s': signed_date '%s' of company '%s' is not in the form YYYY-MM-DD", filename, date, company.Name)
+			}
+		}
+	}
+	return claSigners, nil
+}
+
+// merge adds all of the entries of the other CLA signers to this one.
+func (claSigners *ClaSigners) merge(other ClaSigners) {
+	claSigners.People = append(claSigners.People, other.People...)
+	claSigners.Bots = append(claSigners.Bots, other.Bots...)
+	claSigners.Companies = append(claSigners.Companies, other.Companies...)
+	claSigners.ExemptCommits = append(claSigners.ExemptCommits, other.ExemptCommits...)
+	claSigners.Maintainers = append(claSigners.Maintainers, other.Maintainers...)
+	if other.External != nil {
+		if claSigners.External == nil {
+			claSigners.External = &ExternalClaSigners{}
+		}
+		if claSigners.External.ManagedBy == "" {
+			claSigners.External.ManagedBy = other.External.ManagedBy
+		}
+		claSigners.External.People = append(claSigners.External.People, other.External.People...)
+		claSigners.External.Bots = append(claSigners.External.Bots, other.External.Bots...)
+		claSigners.External.Companies = append(claSigners.External.Companies, other.External.Companies...)
+	}
+}
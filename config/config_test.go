@@ -15,6 +15,9 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-yaml/yaml"
@@ -58,6 +61,20 @@ people:
 	assert.Nil(t, claSigners.External)
 }
 
+func TestParseClaSignersWithAliases(t *testing.T) {
+	claYaml := `
+people:
+  - name: First Last
+    email: first@example.com
+    github: first-last
+    aliases: [firstlast, flast]
+`
+	var claSigners ClaSigners
+	parseClaSigners(t, claYaml, &claSigners)
+	assert.Equal(t, 1, len(claSigners.People), "Should have exactly 1 entry in the `people` section")
+	assert.Equal(t, []string{"firstlast", "flast"}, claSigners.People[0].Aliases)
+}
+
 func TestParseClaSignersWithExternalNamed(t *testing.T) {
 	claYaml := `
 people:
@@ -92,3 +109,175 @@ external:
 	assert.Equal(t, 0, len(external.Bots))
 	assert.Equal(t, 0, len(external.Companies))
 }
+
+func TestParseRepoConfig(t *testing.T) {
+	repoConfigYaml := `
+skip: false
+unknown_as_external: true
+cla_url: https://cla.example.com
+locale: de
+labels:
+  compliant: "cla: signed"
+  non_compliant: "cla: missing"
+`
+	repoConfig, err := ParseRepoConfig([]byte(repoConfigYaml))
+	assert.Nil(t, err)
+	assert.False(t, repoConfig.Skip)
+	assert.NotNil(t, repoConfig.UnknownAsExternal)
+	assert.True(t, *repoConfig.UnknownAsExternal)
+	assert.Equal(t, "https://cla.example.com", repoConfig.ClaURL)
+	assert.Equal(t, "de", repoConfig.Locale)
+	assert.Equal(t, Labels{Compliant: "cla: signed", NonCompliant: "cla: missing"}, repoConfig.Labels)
+}
+
+func TestParseRepoConfigEmpty(t *testing.T) {
+	repoConfig, err := ParseRepoConfig([]byte(""))
+	assert.Nil(t, err)
+	assert.Equal(t, RepoConfig{}, repoConfig)
+}
+
+func TestParseRepoConfigUnknownField(t *testing.T) {
+	_, err := ParseRepoConfig([]byte("skipp: true\n"))
+	assert.NotNil(t, err)
+}
+
+func TestParseOrgConfig(t *testing.T) {
+	orgConfig, err := ParseOrgConfig([]byte("cla_url: https://cla.example.com\nexclude_repos: [website, docs]\nlabels:\n  compliant: \"cla: signed\"\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://cla.example.com", orgConfig.ClaURL)
+	assert.Equal(t, []string{"website", "docs"}, orgConfig.ExcludeRepos)
+	assert.Equal(t, "cla: signed", orgConfig.Labels.Compliant)
+}
+
+func TestParseOrgConfig_UnknownField(t *testing.T) {
+	_, err := ParseOrgConfig([]byte("exclude: [website]\n"))
+	assert.NotNil(t, err)
+}
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "config_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseConfigWithInclude(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"base.yaml": "org: base-org\ncla_url: https://cla.example.com\nrepo_locales:\n  docs: de\n",
+		"main.yaml": "include: [base.yaml]\norg: main-org\nrepo_locales:\n  site: fr\n",
+	})
+	defer os.RemoveAll(dir)
+
+	cfg := ParseConfig(filepath.Join(dir, "main.yaml"))
+	assert.Equal(t, "main-org", cfg.Org)
+	assert.Equal(t, "https://cla.example.com", cfg.ClaURL)
+	assert.Equal(t, map[string]string{"docs": "de", "site": "fr"}, cfg.RepoLocales)
+	assert.Nil(t, cfg.Include)
+}
+
+func TestParseClaSignersWithInclude(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"acme.yaml":   "companies:\n  - name: Acme\n    people:\n      - name: A\n        email: a@acme.com\n        github: a\n",
+		"people.json": `{"people": [{"name": "B", "email": "b@example.com", "github": "b"}], "include": ["acme.yaml"]}`,
+		"main.yaml":   "include: [people.json]\npeople:\n  - name: C\n    email: c@example.com\n    github: c\nexternal:\n  bots:\n    - name: Bot\n      email: bot@example.com\n      github: bot\n",
+	})
+	defer os.RemoveAll(dir)
+
+	claSigners := ParseClaSigners(filepath.Join(dir, "main.yaml"))
+	assert.Equal(t, 2, len(claSigners.People))
+	assert.Equal(t, "c", claSigners.People[0].Login)
+	assert.Equal(t, "b", claSigners.People[1].Login)
+	assert.Equal(t, 1, len(claSigners.Companies))
+	assert.Equal(t, "Acme", claSigners.Companies[0].Name)
+	assert.Equal(t, 1, len(claSigners.External.Bots))
+	assert.Nil(t, claSigners.Include)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"cycle.yaml":   "include: [cycle.yaml]\n",
+		"short.yaml":   "exempt_commits:\n  - sha: 0123456\n",
+		"invalid.json": "{",
+		"config.txt":   "org: org\n",
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := LoadConfig(filepath.Join(dir, "cycle.yaml"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "include cycle")
+	}
+	_, err = LoadConfig(filepath.Join(dir, "config.txt"))
+	assert.NotNil(t, err)
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(t, err)
+	_, err = LoadClaSigners(filepath.Join(dir, "short.yaml"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "not a full 40-character SHA")
+	}
+	_, err = LoadSecrets(filepath.Join(dir, "invalid.json"))
+	assert.NotNil(t, err)
+
+	cfg, err := LoadConfig("")
+	assert.Nil(t, err)
+	assert.Equal(t, Config{}, cfg)
+}
+
+func TestParseClaSignersWithExemptCommits(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"vendored.yaml": "exempt_commits:\n  - sha: 0123456789abcdef0123456789abcdef01234567\n    reason: Vendored import of libfoo\n",
+		"main.yaml":     "include: [vendored.yaml]\nexempt_commits:\n  - sha: fedcba9876543210fedcba9876543210fedcba98\n",
+	})
+	defer os.RemoveAll(dir)
+
+	claSigners := ParseClaSigners(filepath.Join(dir, "main.yaml"))
+	assert.Equal(t, []ExemptCommit{
+		{SHA: "fedcba9876543210fedcba9876543210fedcba98"},
+		{SHA: "0123456789abcdef0123456789abcdef01234567", Reason: "Vendored import of libfoo"},
+	}, claSigners.ExemptCommits)
+}
+
+func TestParseClaSignersWithBotPatterns(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"main.yaml": "bots:\n  - github: /.*\\[bot\\]/\n",
+	})
+	defer os.RemoveAll(dir)
+
+	claSigners := ParseClaSigners(filepath.Join(dir, "main.yaml"))
+	assert.Equal(t, []Account{{Login: `/.*\[bot\]/`}}, claSigners.Bots)
+}
+
+func TestCompilePattern(t *testing.T) {
+	assert.True(t, IsPattern(`/.*\[bot\]/`))
+	assert.False(t, IsPattern("dependabot[bot]"))
+	assert.False(t, IsPattern("/"))
+
+	re, err := CompilePattern(`/.*\[bot\]/`)
+	assert.Nil(t, err)
+	assert.True(t, re.MatchString("Renovate[bot]"))
+	assert.False(t, re.MatchString("renovate[bot] "))
+
+	_, err = CompilePattern("/[bot/")
+	assert.NotNil(t, err)
+}
+
+func TestParseClaSignersWithAgreement(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"signers.yaml": "companies:\n  - name: Acme\n    agreement_id: CCLA-1234\n    contact_email: legal@acme.com\n    signed_date: 2019-05-01\n    people: []\n",
+		"signers.json": `{"companies": [{"name": "Acme", "agreement_id": "CCLA-1234", "contact_email": "legal@acme.com", "signed_date": "2019-05-01", "people": []}]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	expected := Agreement{AgreementID: "CCLA-1234", ContactEmail: "legal@acme.com", SignedDate: "2019-05-01"}
+	for _, name := range []string{"signers.yaml", "signers.json"} {
+		claSigners := ParseClaSigners(filepath.Join(dir, name))
+		assert.Equal(t, expected, claSigners.Companies[0].Agreement, name)
+	}
+	assert.Equal(t, "agreement CCLA-1234, signed 2019-05-01, contact legal@acme.com", expected.String())
+	assert.Equal(t, "", Agreement{}.String())
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Kinds of rows in a CLA signers CSV file, in its `kind` column.
+const (
+	// CSVKindPerson is an individual signer, or a person covered by the
+	// corporate CLA in the `company` column, if set.
+	CSVKindPerson = "person"
+	// CSVKindBot is a bot account.
+	CSVKindBot = "bot"
+	// CSVKindCompany sets the domains and agreement of the company in the
+	// `company` column; its people are listed in rows of their own.
+	CSVKindCompany = "company"
+)
+
+// ClaSignersCSVHeader lists the columns of a CLA signers CSV file. Columns may
+// appear in any order, and only `kind` is required; other columns, e.g., notes
+// kept alongside the roster, are ignored. Multiple aliases or domains are
+// separated by semicolons, and rows with `external` set to true are added to
+// the external CLA signers.
+var ClaSignersCSVHeader = []string{
+	"kind", "company", "name", "email", "github", "aliases", "domains",
+	"agreement_id", "contact_email", "signed_date", "external",
+}
+
+// ReadClaSignersCSV converts a roster of CLA signers in CSV, e.g., as exported
+// from a spreadsheet, with a header row naming its columns (see
+// `ClaSignersCSVHeader`), to CLA signers. Companies are listed in the order in
+// which they first appear.
+func ReadClaSignersCSV(r io.Reader) (ClaSigners, error) {
+	claSigners := ClaSigners{SchemaVersion: ClaSignersSchemaVersion}
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return claSigners, nil
+	} else if err != nil {
+		return claSigners, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["kind"]; !ok {
+		return claSigners, fmt.Errorf("missing column 'kind' in header; accepted columns: %s", strings.Join(ClaSignersCSVHeader, ", "))
+	}
+
+	internal := csvCompanies{companies: &claSigners.Companies}
+	external := csvCompanies{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return claSigners, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		isExternal := false
+		if value := field("external"); value != "" {
+			if isExternal, err = strconv.ParseBool(value); err != nil {
+				return claSigners, fmt.Errorf("row %d: invalid value for 'external': %s", row, value)
+			}
+		}
+		people, bots, companies := &claSigners.People, &claSigners.Bots, &internal
+		if isExternal {
+			if claSigners.External == nil {
+				claSigners.External = &ExternalClaSigners{}
+				external.companies = &claSigners.External.Companies
+			}
+			people, bots, companies = &claSigners.External.People, &claSigners.External.Bots, &external
+		}
+
+		account := Account{
+			Name:    field("name"),
+			Email:   field("email"),
+			Login:   field("github"),
+			Aliases: splitCSVList(field("aliases")),
+		}
+		companyName := field("company")
+		switch kind := strings.ToLower(field("kind")); kind {
+		case CSVKindPerson:
+			if companyName == "" {
+				*people = append(*people, account)
+			} else {
+				company := companies.get(companyName)
+				company.People = append(company.People, account)
+			}
+		case CSVKindBot:
+			if companyName != "" {
+				return claSigners, fmt.Errorf("row %d: bots can't belong to a company", row)
+			}
+			*bots = append(*bots, account)
+		case CSVKindCompany:
+			if companyName == "" {
+				return claSigners, fmt.Errorf("row %d: missing value for 'company'", row)
+			}
+			company := companies.get(companyName)
+			company.Domains = append(company.Domains, splitCSVList(field("domains"))...)
+			if value := field("agreement_id"); value != "" {
+				company.AgreementID = value
+			}
+			if value := field("contact_email"); value != "" {
+				company.ContactEmail = value
+			}
+			if value := field("signed_date"); value != "" {
+				company.SignedDate = value
+			}
+		default:
+			return claSigners, fmt.Errorf("row %d: unknown kind '%s'; accepted: %s, %s, %s", row, kind, CSVKindPerson, CSVKindBot, CSVKindCompany)
+		}
+	}
+	return claSigners, nil
+}
+
+// csvCompanies looks up the companies of a CLA signers CSV file by name,
+// adding them as they first appear.
+type csvCompanies struct {
+	companies *[]Company
+}
+
+// get returns the company with the given name, adding it if necessary.
+func (c csvCompanies) get(name string) *Company {
+	for i := range *c.companies {
+		if strings.EqualFold((*c.companies)[i].Name, name) {
+			return &(*c.companies)[i]
+		}
+	}
+	*c.companies = append(*c.companies, Company{Name: name, People: []Account{}})
+	return &(*c.companies)[len(*c.companies)-1]
+}
+
+// splitCSVList splits a semicolon-separated list, dropping empty entries.
+func splitCSVList(value string) []string {
+	var values []string
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+// WriteClaSignersCSV converts the people, bots, and companies of the CLA
+// signers to CSV, as read by `ReadClaSignersCSV`: each company is written as a
+// row of kind "company" followed by its people. Other settings, such as
+// exempt commits and maintainers, can't be represented, and are left out.
+func WriteClaSignersCSV(w io.Writer, claSigners ClaSigners) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ClaSignersCSVHeader); err != nil {
+		return err
+	}
+
+	writeAccount := func(kind string, company string, account Account, external bool) error {
+		return writer.Write([]string{
+			kind, company, account.Name, account.Email, account.Login, strings.Join(account.Aliases, ";"),
+			"", "", "", "", csvBool(external),
+		})
+	}
+	writeSigners := func(people []Account, bots []Account, companies []Company, external bool) error {
+		for _, account := range people {
+			if err := writeAccount(CSVKindPerson, "", account, external); err != nil {
+				return err
+			}
+		}
+		for _, account := range bots {
+			if err := writeAccount(CSVKindBot, "", account, external); err != nil {
+				return err
+			}
+		}
+		for _, company := range companies {
+			if err := writer.Write([]string{
+				CSVKindCompany, company.Name, "", "", "", "", strings.Join(company.Domains, ";"),
+				company.AgreementID, company.ContactEmail, company.SignedDate, csvBool(external),
+			}); err != nil {
+				return err
+			}
+			for _, account := range company.People {
+				if err := writeAccount(CSVKindPerson, company.Name, account, external); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := writeSigners(claSigners.People, claSigners.Bots, claSigners.Companies, false); err != nil {
+		return err
+	}
+	if ext := claSigners.External; ext != nil {
+		if err := writeSigners(ext.People, ext.Bots, ext.Companies, true); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvBool renders the `external` column, which is left empty unless set.
+func csvBool(value bool) string {
+	if value {
+		return "true"
+	}
+	return ""
+}
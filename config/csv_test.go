@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadClaSignersCSV(t *testing.T) {
+	input := `Name,Kind,Email,GitHub,Company,Domains,Agreement_ID,Signed_Date,External,Notes
+Jane Doe,person,jane@example.com,jane-doe,,,,,,signed online
+Bob,person,bob@acme.com,bob,Acme,,,,,
+,company,,,Acme,acme.com; acme.org,CCLA-1234,2019-05-01,,
+Renovate,bot,bot@example.com,renovate,,,,,,
+Ext,person,ext@example.com,ext,,,,,true,
+`
+	claSigners, err := ReadClaSignersCSV(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, ClaSigners{
+		SchemaVersion: ClaSignersSchemaVersion,
+		People:        []Account{{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"}},
+		Bots:          []Account{{Name: "Renovate", Email: "bot@example.com", Login: "renovate"}},
+		Companies: []Company{{
+			Name:      "Acme",
+			Domains:   []string{"acme.com", "acme.org"},
+			People:    []Account{{Name: "Bob", Email: "bob@acme.com", Login: "bob"}},
+			Agreement: Agreement{AgreementID: "CCLA-1234", SignedDate: "2019-05-01"},
+		}},
+		External: &ExternalClaSigners{
+			People: []Account{{Name: "Ext", Email: "ext@example.com", Login: "ext"}},
+		},
+	}, claSigners)
+}
+
+func TestReadClaSignersCSV_Errors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"name,email\nA,a@example.com\n":  "missing column 'kind' in header",
+		"kind,name\nrobot,A\n":           "row 2: unknown kind 'robot'",
+		"kind,company\ncompany,\n":       "row 2: missing value for 'company'",
+		"kind,company\nbot,Acme\n":       "row 2: bots can't belong to a company",
+		"kind,external\nperson,maybe\n":  "row 2: invalid value for 'external': maybe",
+		"kind,name\nperson,A\nperson\n":  "wrong number of fields",
+		"kind,name\nperson,A\nfoo,B,C\n": "wrong number of fields",
+	} {
+		_, err := ReadClaSignersCSV(strings.NewReader(input))
+		if assert.NotNil(t, err, input) {
+			assert.Contains(t, err.Error(), expected, input)
+		}
+	}
+}
+
+func TestWriteClaSignersCSV_RoundTrip(t *testing.T) {
+	claSigners := ClaSigners{
+		SchemaVersion: ClaSignersSchemaVersion,
+		People:        []Account{{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe", Aliases: []string{"jane-old", "jdoe"}}},
+		Bots:          []Account{{Name: "Renovate", Email: "bot@example.com", Login: "renovate"}},
+		Companies: []Company{
+			{
+				Name:      "Acme",
+				Domains:   []string{"acme.com"},
+				People:    []Account{{Name: "Bob", Email: "bob@acme.com", Login: "bob"}},
+				Agreement: Agreement{AgreementID: "CCLA-1234", ContactEmail: "legal@acme.com", SignedDate: "2019-05-01"},
+			},
+			{
+				Name:   "Empty Corp",
+				People: []Account{},
+			},
+		},
+		External: &ExternalClaSigners{
+			Companies: []Company{{Name: "Acme", People: []Account{{Name: "Eve", Email: "eve@acme.com", Login: "eve"}}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteClaSignersCSV(&buf, claSigners))
+	assert.True(t, strings.HasPrefix(buf.String(), strings.Join(ClaSignersCSVHeader, ",")+"\n"))
+
+	roundTrip, err := ReadClaSignersCSV(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, claSigners, roundTrip)
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+)
+
+// ReadClaSignersFile parses a single CLA signers file as is, without loading
+// the files it includes, e.g., to rewrite it.
+func ReadClaSignersFile(filename string) ClaSigners {
+	var claSigners ClaSigners
+	fatalIfError(parseFile("CLA signers", filename, &claSigners))
+	return claSigners
+}
+
+// EncodeClaSigners encodes the CLA signers for the named file: as JSON if its
+// name ends in .json or .jsonc, and as YAML otherwise. Comments are not
+// preserved.
+func EncodeClaSigners(filename string, claSigners ClaSigners) ([]byte, error) {
+	if strings.HasSuffix(filename, ".json") || strings.HasSuffix(filename, ".jsonc") {
+		data, err := json.MarshalIndent(claSigners, "", "  ")
+		return append(data, '\n'), err
+	}
+	return yaml.Marshal(claSigners)
+}
+
+// NormalizeClaSigners returns the CLA signers in canonical form, so that
+// changes to a roster are easy to review: surrounding whitespace is trimmed,
+// the domains of emails are lowercased, accounts are sorted by login (then
+// email and name), companies are sorted by name, and duplicate accounts,
+// aliases, and domains are dropped, with companies of the same name merged.
+// Bot patterns are left as is.
+func NormalizeClaSigners(claSigners ClaSigners) ClaSigners {
+	claSigners.People = normalizeAccounts(claSigners.People)
+	claSigners.Bots = normalizeAccounts(claSigners.Bots)
+	claSigners.Companies = normalizeCompanies(claSigners.Companies)
+	claSigners.Maintainers = normalizeAccounts(claSigners.Maintainers)
+	if external := claSigners.External; external != nil {
+		claSigners.External = &ExternalClaSigners{
+			ManagedBy: strings.TrimSpace(external.ManagedBy),
+			People:    normalizeAccounts(external.People),
+			Bots:      normalizeAccounts(external.Bots),
+			Companies: normalizeCompanies(external.Companies),
+		}
+	}
+	return claSigners
+}
+
+// normalizeEmail trims the email and lowercases its domain, which, unlike the
+// local part, is case-insensitive.
+func normalizeEmail(email string) string {
+	email = strings.TrimSpace(email)
+	if IsPattern(email) {
+		return email
+	}
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		return email[:at] + strings.ToLower(email[at:])
+	}
+	return email
+}
+
+// normalizeList trims the values, drops empty and duplicate ones (compared
+// case-insensitively), and sorts the rest, lowercased if `lower` is set.
+func normalizeList(values []string, lower bool) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		result = append(result, value)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i]) < strings.ToLower(result[j])
+	})
+	return result
+}
+
+// normalizeAccounts returns the accounts normalized, de-duplicated, and
+// sorted, keeping a nil list nil so that it is still left out when encoded.
+func normalizeAccounts(accounts []Account) []Account {
+	if accounts == nil {
+		return nil
+	}
+	result := make([]Account, 0, len(accounts))
+	seen := make(map[string]bool)
+	for _, account := range accounts {
+		account = Account{
+			Name:    strings.TrimSpace(account.Name),
+			Email:   normalizeEmail(account.Email),
+			Login:   strings.TrimSpace(account.Login),
+			Aliases: normalizeList(account.Aliases, false),
+		}
+		key := strings.Join([]string{strings.ToLower(account.Login), account.Email, account.Name, strings.ToLower(strings.Join(account.Aliases, ","))}, "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, account)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if loginA, loginB := strings.ToLower(a.Login), strings.ToLower(b.Login); loginA != loginB {
+			return loginA < loginB
+		}
+		if emailA, emailB := strings.ToLower(a.Email), strings.ToLower(b.Email); emailA != emailB {
+			return emailA < emailB
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
+
+// normalizeCompanies returns the companies normalized and sorted by name,
+// merging those with the same name, whose agreements are taken from the first
+// one which sets each field.
+func normalizeCompanies(companies []Company) []Company {
+	if companies == nil {
+		return nil
+	}
+	var result []Company
+	index := make(map[string]int)
+	for _, company := range companies {
+		company.Name = strings.TrimSpace(company.Name)
+		key := strings.ToLower(company.Name)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, company)
+			continue
+		}
+		merged := &result[i]
+		merged.Domains = append(merged.Domains, company.Domains...)
+		merged.People = append(merged.People, company.People...)
+		if merged.AgreementID == "" {
+			merged.AgreementID = company.AgreementID
+		}
+		if merged.ContactEmail == "" {
+			merged.ContactEmail = company.ContactEmail
+		}
+		if merged.SignedDate == "" {
+			merged.SignedDate = company.SignedDate
+		}
+	}
+	for i := range result {
+		company := &result[i]
+		company.Domains = normalizeList(company.Domains, true)
+		company.People = normalizeAccounts(company.People)
+		if company.People == nil {
+			company.People = []Account{}
+		}
+		company.AgreementID = strings.TrimSpace(company.AgreementID)
+		company.ContactEmail = normalizeEmail(company.ContactEmail)
+		company.SignedDate = strings.TrimSpace(company.SignedDate)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeClaSigners(t *testing.T) {
+	claSigners := NormalizeClaSigners(ClaSigners{
+		People: []Account{
+			{Name: "Zed", Email: " Zed@Example.COM ", Login: "zed"},
+			{Name: "Amy", Email: "amy@example.com", Login: "Amy", Aliases: []string{"amy-old", "", "AMY-OLD"}},
+			{Name: "Amy", Email: "amy@example.com", Login: "amy", Aliases: []string{"amy-old"}},
+			{Name: "Amy", Email: "amy@work.example.com", Login: "amy"},
+		},
+		Bots: []Account{
+			{Name: "Renovate", Email: "/.*@Renovate\\.example/", Login: "/renovate-.*/"},
+		},
+		Companies: []Company{
+			{Name: "Beta", People: []Account{}},
+			{Name: "acme", Domains: []string{"Acme.com"}, People: []Account{{Name: "B", Email: "b@acme.com", Login: "b"}}},
+			{Name: "Acme ", Domains: []string{"acme.com", "acme.org"}, People: []Account{{Name: "A", Email: "a@acme.com", Login: "a"}}, Agreement: Agreement{AgreementID: "CCLA-1"}},
+		},
+	})
+
+	assert.Equal(t, []Account{
+		{Name: "Amy", Email: "amy@example.com", Login: "Amy", Aliases: []string{"amy-old"}},
+		{Name: "Amy", Email: "amy@work.example.com", Login: "amy"},
+		{Name: "Zed", Email: "Zed@example.com", Login: "zed"},
+	}, claSigners.People)
+	assert.Equal(t, []Account{
+		{Name: "Renovate", Email: "/.*@Renovate\\.example/", Login: "/renovate-.*/"},
+	}, claSigners.Bots)
+	assert.Equal(t, []Company{
+		{
+			Name:      "acme",
+			Domains:   []string{"acme.com", "acme.org"},
+			People:    []Account{{Name: "A", Email: "a@acme.com", Login: "a"}, {Name: "B", Email: "b@acme.com", Login: "b"}},
+			Agreement: Agreement{AgreementID: "CCLA-1"},
+		},
+		{Name: "Beta", People: []Account{}},
+	}, claSigners.Companies)
+	assert.Nil(t, claSigners.Maintainers)
+	assert.Nil(t, claSigners.External)
+}
+
+func TestEncodeClaSigners_RoundTrip(t *testing.T) {
+	claSigners := NormalizeClaSigners(ClaSigners{
+		Include: []string{"acme.yaml"},
+		People:  []Account{{Name: "Amy", Email: "amy@example.com", Login: "amy"}},
+	})
+	for _, name := range []string{"signers.yaml", "signers.json"} {
+		data, err := EncodeClaSigners(name, claSigners)
+		assert.Nil(t, err)
+		dir := writeTestFiles(t, map[string]string{name: string(data)})
+		defer os.RemoveAll(dir)
+		assert.Equal(t, claSigners, ReadClaSignersFile(filepath.Join(dir, name)), name)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripJSONC(t *testing.T) {
+	input := `{
+  // Acme signed the corporate CLA in 2019.
+  "name": "Acme", /* legal name */
+  "url": "https://acme.example.com/*not-a-comment*/",
+  "quote": "\"// still a string\"",
+  "domains": ["acme.com", "acme.org",],
+}`
+	var data map[string]interface{}
+	assert.Nil(t, json.Unmarshal(stripJSONC([]byte(input)), &data))
+	assert.Equal(t, map[string]interface{}{
+		"name":    "Acme",
+		"url":     "https://acme.example.com/*not-a-comment*/",
+		"quote":   `"// still a string"`,
+		"domains": []interface{}{"acme.com", "acme.org"},
+	}, data)
+}
+
+func TestStripJSONC_KeepsOffsets(t *testing.T) {
+	input := "{\n  /* a\n  comment */ \"a\": 1, // b\n}"
+	output := stripJSONC([]byte(input))
+	assert.Equal(t, len(input), len(output))
+	assert.Equal(t, "{\n      \n             \"a\": 1      \n}", string(output))
+}
+
+func TestStripJSONC_KeepsInvalidCommas(t *testing.T) {
+	var data []int
+	assert.NotNil(t, json.Unmarshal(stripJSONC([]byte("[1,,2]")), &data))
+}
+
+func TestParseClaSignersWithComments(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"signers.jsonc": "{\n  // Individual signers.\n  \"people\": [\n    {\"name\": \"A\", \"email\": \"a@example.com\", \"github\": \"a\"},\n  ],\n}\n",
+	})
+	defer os.RemoveAll(dir)
+
+	claSigners := ParseClaSigners(filepath.Join(dir, "signers.jsonc"))
+	assert.Equal(t, 1, len(claSigners.People))
+	assert.Equal(t, "a", claSigners.People[0].Login)
+}
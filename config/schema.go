@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Current versions of the formats of the config and CLA signers files. Files
+// declare the version they were written for via `schema_version`, and older
+// versions are migrated to the current one as they are parsed, so that format
+// changes can be rolled out without breaking existing files. Files for newer
+// versions are rejected, rather than having the fields they rely on silently
+// ignored.
+const (
+	ConfigSchemaVersion     = 1
+	ClaSignersSchemaVersion = 1
+)
+
+// configMigrations maps each schema version of the config file to the function
+// which migrates a config of that version to the next one. A config file may
+// include files of other versions, and migrations are applied to the config
+// merged from all of them, so they must leave settings of later versions
+// alone.
+var configMigrations = map[int]func(*Config){}
+
+// claSignersMigrations maps each schema version of the CLA signers file to the
+// function which migrates CLA signers of that version to the next one.
+var claSignersMigrations = map[int]func(*ClaSigners){}
+
+// schemaVersion returns the schema version declared by a file, where files
+// without one are version 1, or an error if it is not supported.
+func schemaVersion(version int, current int) (int, error) {
+	if version == 0 {
+		version = 1
+	}
+	if version < 1 || version > current {
+		return 0, fmt.Errorf("unsupported schema_version %d; this version of crbot supports versions 1 to %d", version, current)
+	}
+	return version, nil
+}
+
+// migrateConfig migrates a config parsed from a file of the given schema
+// version to the current one.
+func migrateConfig(config *Config, version int) error {
+	version, err := schemaVersion(version, ConfigSchemaVersion)
+	if err != nil {
+		return err
+	}
+	for ; version < ConfigSchemaVersion; version++ {
+		configMigrations[version](config)
+	}
+	config.SchemaVersion = ConfigSchemaVersion
+	return nil
+}
+
+// migrateClaSigners migrates CLA signers parsed from a file to the current
+// schema version.
+func migrateClaSigners(claSigners *ClaSigners) error {
+	version, err := schemaVersion(claSigners.SchemaVersion, ClaSignersSchemaVersion)
+	if err != nil {
+		return err
+	}
+	for ; version < ClaSignersSchemaVersion; version++ {
+		claSignersMigrations[version](claSigners)
+	}
+	claSigners.SchemaVersion = ClaSignersSchemaVersion
+	return nil
+}
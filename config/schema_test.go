@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	version, err := schemaVersion(0, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, version)
+
+	version, err = schemaVersion(2, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, version)
+
+	_, err = schemaVersion(3, 2)
+	assert.EqualError(t, err, "unsupported schema_version 3; this version of crbot supports versions 1 to 2")
+
+	_, err = schemaVersion(-1, 2)
+	assert.NotNil(t, err)
+}
+
+func TestMigrateClaSigners_UnknownVersion(t *testing.T) {
+	claSigners := ClaSigners{SchemaVersion: ClaSignersSchemaVersion + 1}
+	assert.NotNil(t, migrateClaSigners(&claSigners))
+}
+
+func TestParseFilesWithSchemaVersion(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"config.yaml":  "schema_version: 1\norg: org\n",
+		"signers.yaml": "people:\n  - name: A\n    email: a@example.com\n    github: a\n",
+	})
+	defer os.RemoveAll(dir)
+
+	cfg := ParseConfig(filepath.Join(dir, "config.yaml"))
+	assert.Equal(t, ConfigSchemaVersion, cfg.SchemaVersion)
+	assert.Equal(t, "org", cfg.Org)
+
+	claSigners := ParseClaSigners(filepath.Join(dir, "signers.yaml"))
+	assert.Equal(t, ClaSignersSchemaVersion, claSigners.SchemaVersion)
+}
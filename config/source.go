@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// RepoSourcePrefix starts the location of a file stored in a GitHub repo,
+// rather than on the local filesystem, in the form
+// "repo://ORG/REPO/PATH[@REF]", e.g., "repo://acme/cla-config/signers.yaml@main"
+// for a roster kept in a private config repo. Without a ref, the file is read
+// from the default branch of the repo. Files included by such a file are read
+// from the same repo and ref, unless they are `repo://` locations themselves.
+const RepoSourcePrefix = "repo://"
+
+// RepoSource identifies a file stored in a GitHub repo.
+type RepoSource struct {
+	Org  string
+	Repo string
+	Path string
+	Ref  string
+}
+
+// IsRepoSource returns whether the location refers to a file stored in a
+// GitHub repo; see `RepoSourcePrefix`.
+func IsRepoSource(location string) bool {
+	return strings.HasPrefix(location, RepoSourcePrefix)
+}
+
+// ParseRepoSource parses a location of the form "repo://ORG/REPO/PATH[@REF]".
+func ParseRepoSource(location string) (RepoSource, error) {
+	var source RepoSource
+	if !IsRepoSource(location) {
+		return source, fmt.Errorf("location '%s' does not start with %s", location, RepoSourcePrefix)
+	}
+	rest := strings.TrimPrefix(location, RepoSourcePrefix)
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		source.Ref = rest[at+1:]
+		rest = rest[:at]
+		if source.Ref == "" {
+			return source, fmt.Errorf("location '%s' has an empty ref", location)
+		}
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || strings.Trim(parts[2], "/") == "" {
+		return source, fmt.Errorf("location '%s' is not of the form %sORG/REPO/PATH[@REF]", location, RepoSourcePrefix)
+	}
+	source.Org, source.Repo, source.Path = parts[0], parts[1], strings.Trim(parts[2], "/")
+	return source, nil
+}
+
+// String returns the location of the file, as parsed by `ParseRepoSource`.
+func (s RepoSource) String() string {
+	location := RepoSourcePrefix + s.Org + "/" + s.Repo + "/" + s.Path
+	if s.Ref != "" {
+		location += "@" + s.Ref
+	}
+	return location
+}
+
+// RepoFileFetcher fetches the contents of a file from a GitHub repo at the
+// given ref, or at the head of its default branch if the ref is empty.
+type RepoFileFetcher func(orgName string, repoName string, path string, ref string) ([]byte, error)
+
+// repoFileFetcher reads files from `repo://` locations; it is nil until set
+// by `SetRepoFileFetcher`.
+var repoFileFetcher RepoFileFetcher
+
+// SetRepoFileFetcher enables reading CLA signers files from `repo://`
+// locations via the fetcher, typically `ghutil.GitHubClient.FetchFile`, so
+// that they are read with the bot's own token. It has to be called before
+// parsing such files, and thus can't apply to the config file, which
+// configures the connection to GitHub.
+func SetRepoFileFetcher(fetcher RepoFileFetcher) {
+	repoFileFetcher = fetcher
+}
+
+// readFile reads the contents of the file at the location, which is either a
+// path on the local filesystem or a `repo://` location.
+func readFile(location string) ([]byte, error) {
+	if !IsRepoSource(location) {
+		return ioutil.ReadFile(location)
+	}
+	source, err := ParseRepoSource(location)
+	if err != nil {
+		return nil, err
+	}
+	if repoFileFetcher == nil {
+		return nil, errors.New("files in GitHub repos can only be read with a connection to GitHub")
+	}
+	return repoFileFetcher(source.Org, source.Repo, source.Path, source.Ref)
+}
+
+// locationPath returns the path of the file at the location, from which its
+// type is inferred.
+func locationPath(location string) string {
+	if source, err := ParseRepoSource(location); err == nil {
+		return source.Path
+	}
+	return location
+}
+
+// resolveRepoInclude returns the location of a file included by the file at
+// the `repo://` location, which is in the same repo and at the same ref.
+func resolveRepoInclude(location string, include string) string {
+	source, err := ParseRepoSource(location)
+	if err != nil {
+		return include
+	}
+	if strings.HasPrefix(include, "/") {
+		source.Path = strings.TrimPrefix(path.Clean(include), "/")
+	} else {
+		source.Path = path.Join(path.Dir(source.Path), include)
+	}
+	return source.String()
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepoSource(t *testing.T) {
+	source, err := ParseRepoSource("repo://acme/cla-config/rosters/signers.yaml@main")
+	assert.Nil(t, err)
+	assert.Equal(t, RepoSource{Org: "acme", Repo: "cla-config", Path: "rosters/signers.yaml", Ref: "main"}, source)
+	assert.Equal(t, "repo://acme/cla-config/rosters/signers.yaml@main", source.String())
+
+	source, err = ParseRepoSource("repo://acme/cla-config/signers.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, RepoSource{Org: "acme", Repo: "cla-config", Path: "signers.yaml"}, source)
+
+	for _, location := range []string{
+		"signers.yaml",
+		"repo://acme/cla-config",
+		"repo://acme/cla-config/",
+		"repo:///cla-config/signers.yaml",
+		"repo://acme/cla-config/signers.yaml@",
+	} {
+		_, err := ParseRepoSource(location)
+		assert.NotNil(t, err, location)
+	}
+}
+
+func TestResolveInclude_RepoSource(t *testing.T) {
+	location := "repo://acme/cla-config/rosters/signers.yaml@v1"
+	assert.Equal(t, "repo://acme/cla-config/rosters/acme.yaml@v1", resolveInclude(location, "acme.yaml"))
+	assert.Equal(t, "repo://acme/cla-config/companies/acme.yaml@v1", resolveInclude(location, "../companies/acme.yaml"))
+	assert.Equal(t, "repo://acme/cla-config/acme.yaml@v1", resolveInclude(location, "/acme.yaml"))
+	assert.Equal(t, "repo://other/repo/x.yaml", resolveInclude(location, "repo://other/repo/x.yaml"))
+	assert.Equal(t, "repo://other/repo/x.yaml", resolveInclude("/etc/crbot/signers.yaml", "repo://other/repo/x.yaml"))
+}
+
+func TestParseClaSigners_RepoSource(t *testing.T) {
+	files := map[string]string{
+		"acme/cla-config/signers.yaml@main":   "include: [companies.json]\npeople:\n  - name: A\n    email: a@example.com\n    github: a\n",
+		"acme/cla-config/companies.json@main": `{"companies": [{"name": "Acme", "people": []}]}`,
+	}
+	var fetched []string
+	SetRepoFileFetcher(func(orgName string, repoName string, path string, ref string) ([]byte, error) {
+		key := fmt.Sprintf("%s/%s/%s@%s", orgName, repoName, path, ref)
+		fetched = append(fetched, key)
+		return []byte(files[key]), nil
+	})
+	defer SetRepoFileFetcher(nil)
+
+	claSigners := ParseClaSigners("repo://acme/cla-config/signers.yaml@main")
+	assert.Equal(t, []string{"acme/cla-config/signers.yaml@main", "acme/cla-config/companies.json@main"}, fetched)
+	assert.Equal(t, 1, len(claSigners.People))
+	assert.Equal(t, "Acme", claSigners.Companies[0].Name)
+}
+
+func TestReadFile_RepoSourceWithoutFetcher(t *testing.T) {
+	_, err := readFile("repo://acme/cla-config/signers.yaml")
+	assert.NotNil(t, err)
+}
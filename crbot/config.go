@@ -0,0 +1,301 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/events"
+	"github.com/google/code-review-bot/gcp"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/lookup"
+)
+
+// Defaults of the settings of the config file, applied where they are zero.
+const (
+	// DefaultLabelCacheMinutes is how long the CLA labels defined by each
+	// repo are cached.
+	DefaultLabelCacheMinutes = 60
+	// DefaultMaxCommentsPerSHA is how many comments are posted on a
+	// non-compliant PR while its head commit stays the same.
+	DefaultMaxCommentsPerSHA = 1
+	// DefaultSignerLookupCacheMinutes is how long the results of the signer
+	// lookup are cached.
+	DefaultSignerLookupCacheMinutes = 60
+	// DefaultBreakerFailures and DefaultBreakerCooldownSeconds configure
+	// the circuit breaker around the connection to GitHub.
+	DefaultBreakerFailures        = 5
+	DefaultBreakerCooldownSeconds = 60
+)
+
+// serviceTimeout bounds each request to the services other than GitHub, i.e.,
+// event destinations and signer lookups.
+const serviceTimeout = 30 * time.Second
+
+// pubSubTopicPattern matches the full name of a Pub/Sub topic.
+var pubSubTopicPattern = regexp.MustCompile(`^projects/[^/]+/topics/[^/]+$`)
+
+// ValidateConfig validates the settings of the config file which apply to
+// processing pull requests.
+func ValidateConfig(cfg config.Config) error {
+	if _, err := ghutil.ParseCommentTemplate(cfg.CommentTemplate); err != nil {
+		return fmt.Errorf("invalid value for `comment_template`: %s", err)
+	}
+
+	if cfg.SkipLabels && !cfg.RequestChanges {
+		return errors.New("`skip_labels` requires `request_changes`")
+	}
+
+	if strings.Contains(cfg.TrackingIssue.Repo, "/") {
+		return fmt.Errorf("invalid value for `tracking_issue` `repo`: %s; expected the name of a repo of the org", cfg.TrackingIssue.Repo)
+	}
+
+	if cfg.BigQuery.Table != "" && (cfg.BigQuery.Project == "" || cfg.BigQuery.Dataset == "") {
+		return errors.New("`bigquery` requires `project`, `dataset`, and `table`")
+	}
+
+	if !ghutil.IsSupportedTrustLevel(cfg.TrustLevel) {
+		return fmt.Errorf("invalid value for `trust_level`: %s; accepted: %s", cfg.TrustLevel, strings.Join(ghutil.TrustLevels, ", "))
+	}
+	if !ghutil.IsSupportedPullOrder(cfg.PullOrder) {
+		return fmt.Errorf("invalid value for `pull_order`: %s; accepted: %s", cfg.PullOrder, strings.Join(ghutil.PullOrders, ", "))
+	}
+	for _, checkerName := range cfg.Checkers {
+		if _, err := ghutil.LookupChecker(checkerName); err != nil {
+			return fmt.Errorf("invalid value for `checkers`: %s", err)
+		}
+	}
+
+	for _, webhook := range append([]string{cfg.Events.WebhookURL}, cfg.Events.Webhooks...) {
+		if webhook == "" {
+			continue
+		}
+		if u, err := url.Parse(webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL in `events`: %s", webhook)
+		}
+	}
+	if (cfg.Events.Kafka.Topic == "") != (cfg.Events.Kafka.RESTProxyURL == "") {
+		return errors.New("`events.kafka` requires both `rest_proxy_url` and `topic`")
+	}
+	if topic := cfg.Events.PubSubTopic; topic != "" && !pubSubTopicPattern.MatchString(topic) {
+		return fmt.Errorf("invalid value for `events.pubsub_topic`: %s; expected: projects/PROJECT/topics/TOPIC", topic)
+	}
+
+	if !lookup.IsSupportedService(cfg.SignerLookup.Service) {
+		return fmt.Errorf("invalid value for `signer_lookup.service`: %s; supported: %s", cfg.SignerLookup.Service, strings.Join(lookup.Services, ", "))
+	}
+	if cfg.SignerLookup.Service == lookup.ServiceOkta {
+		if cfg.SignerLookup.URL == "" || len(cfg.SignerLookup.Groups) == 0 {
+			return errors.New("`signer_lookup.service` okta requires `url` and `groups`")
+		}
+		for _, group := range cfg.SignerLookup.Groups {
+			if group.ID == "" || group.Company == "" {
+				return errors.New("each of `signer_lookup.groups` requires `id` and `company`")
+			}
+		}
+	}
+	if cfg.SignerLookup.Service == lookup.ServiceGoogle && cfg.SignerLookup.URL == "" {
+		return errors.New("`signer_lookup.service` google requires `url`")
+	}
+	if cfg.SignerLookup.Service == lookup.ServiceDocuSign && (cfg.SignerLookup.URL == "" || cfg.SignerLookup.Credentials == "" || cfg.SignerLookup.EnvelopeSubject == "") {
+		return errors.New("`signer_lookup.service` docusign requires `url`, `credentials`, and `envelope_subject`")
+	}
+
+	for _, locale := range append([]string{cfg.Locale}, localeValues(cfg.RepoLocales)...) {
+		if !ghutil.IsSupportedLocale(locale) {
+			return fmt.Errorf("unsupported locale '%s'; supported: %s", locale, strings.Join(ghutil.SupportedLocales(), ", "))
+		}
+	}
+	return nil
+}
+
+// localeValues returns the locales configured for individual repos.
+func localeValues(repoLocales map[string]string) []string {
+	values := make([]string, 0, len(repoLocales))
+	for _, locale := range repoLocales {
+		values = append(values, locale)
+	}
+	return values
+}
+
+// ConfigureClient sets up the compliance checkers, event publisher, signer
+// lookup, and repo label cache of the client from the config file.
+func ConfigureClient(ghc *ghutil.GitHubClient, cfg config.Config, secrets config.Secrets) error {
+	for _, checkerName := range cfg.Checkers {
+		checker, err := ghutil.LookupChecker(checkerName)
+		if err != nil {
+			return fmt.Errorf("invalid value for `checkers`: %s", err)
+		}
+		ghc.Checkers = append(ghc.Checkers, checker)
+	}
+	publisher, err := NewEventPublisher(cfg.Events, secrets)
+	if err != nil {
+		return err
+	}
+	ghc.Events = publisher
+	ghc.PublishChecks = cfg.Events.Checks
+	signerLookup, err := NewSignerLookup(cfg.SignerLookup, secrets.SignerLookup)
+	if err != nil {
+		return err
+	}
+	ghc.SignerLookup = signerLookup
+	if cfg.LabelCacheMinutes >= 0 {
+		cacheMinutes := cfg.LabelCacheMinutes
+		if cacheMinutes == 0 {
+			cacheMinutes = DefaultLabelCacheMinutes
+		}
+		ghc.RepoLabels = ghutil.NewRepoLabelCache(time.Duration(cacheMinutes) * time.Minute)
+	}
+	return nil
+}
+
+// NewOrgRepoSpec returns the spec for processing the given org and repo(s)
+// with the settings of the config file.
+func NewOrgRepoSpec(cfg config.Config, orgName string, repoName string, updateRepo bool) ghutil.GitHubProcessOrgRepoSpec {
+	return ghutil.GitHubProcessOrgRepoSpec{
+		Org:               orgName,
+		Repo:              repoName,
+		UpdateRepo:        updateRepo,
+		UnknownAsExternal: cfg.UnknownAsExternal,
+		SkipForks:         cfg.SkipForks,
+		BaseBranches:      cfg.BaseBranches,
+		TrustLevel:        cfg.TrustLevel,
+		ClaURL:            cfg.ClaURL,
+		CommentTemplate:   cfg.CommentTemplate,
+		Locale:            cfg.Locale,
+		RepoLocales:       cfg.RepoLocales,
+		Labels:            cfg.Labels,
+		RequestChanges:    cfg.RequestChanges,
+		SkipLabels:        cfg.SkipLabels,
+		NoComments:        cfg.NoComments,
+		MaxCommentsPerSHA: maxCommentsPerSHA(cfg),
+		Reminders:         cfg.Reminders,
+		Reviewers:         cfg.Reviewers,
+		CommitStatus:      cfg.CommitStatus,
+		MinimizeComments:  cfg.MinimizeComments,
+		TrackingIssue:     cfg.TrackingIssue,
+		Trivial:           cfg.Trivial,
+		PullOrder:         cfg.PullOrder,
+	}
+}
+
+// maxCommentsPerSHA returns the configured cap on comments per head commit,
+// or zero for no cap.
+func maxCommentsPerSHA(cfg config.Config) int {
+	switch {
+	case cfg.MaxCommentsPerSHA < 0:
+		return 0
+	case cfg.MaxCommentsPerSHA == 0:
+		return DefaultMaxCommentsPerSHA
+	}
+	return cfg.MaxCommentsPerSHA
+}
+
+// NewEventPublisher returns a publisher to the configured destinations, or nil
+// if there are none, authenticated with the secrets.
+func NewEventPublisher(cfg config.Events, secrets config.Secrets) (events.Publisher, error) {
+	var publishers events.Publishers
+	if cfg.PubSubTopic != "" {
+		client, err := gcp.NewClient(context.Background(), cfg.Credentials, gcp.ScopePubSub)
+		if err != nil {
+			return nil, fmt.Errorf("error authenticating to Pub/Sub: %s", err)
+		}
+		publishers = append(publishers, events.NewPubSubPublisher(client, cfg.PubSubTopic))
+	}
+	webhooks := cfg.Webhooks
+	if cfg.WebhookURL != "" {
+		webhooks = append([]string{cfg.WebhookURL}, webhooks...)
+	}
+	for _, webhook := range webhooks {
+		publisher := events.NewWebhookPublisher(&http.Client{Timeout: serviceTimeout}, webhook)
+		publisher.SetSecret(secrets.EventsSecret)
+		publishers = append(publishers, publisher)
+	}
+	if cfg.Kafka.Topic != "" {
+		publisher := events.NewKafkaPublisher(&http.Client{Timeout: serviceTimeout}, cfg.Kafka.RESTProxyURL, cfg.Kafka.Topic)
+		if secrets.KafkaAuth != "" {
+			credentials := strings.SplitN(secrets.KafkaAuth, ":", 2)
+			if len(credentials) != 2 {
+				return nil, errors.New("invalid value for `kafka_auth` in secrets; expected: USER:PASSWORD")
+			}
+			publisher.SetBasicAuth(credentials[0], credentials[1])
+		}
+		publishers = append(publishers, publisher)
+	}
+	if len(publishers) == 0 {
+		return nil, nil
+	}
+	return publishers, nil
+}
+
+// NewSignerLookup returns a lookup querying the configured CLA service, or nil
+// if there is none.
+func NewSignerLookup(cfg config.SignerLookup, token string) (lookup.SignerLookup, error) {
+	cacheMinutes := cfg.CacheMinutes
+	if cacheMinutes <= 0 {
+		cacheMinutes = DefaultSignerLookupCacheMinutes
+	}
+	var httpLookup *lookup.HTTPLookup
+	switch {
+	case cfg.Service == lookup.ServiceOkta:
+		// The Okta lookup caches group memberships rather than results.
+		return lookup.NewOktaLookup(&http.Client{Timeout: serviceTimeout}, cfg.URL, token, cfg.Groups, cfg.LoginAttribute, time.Duration(cacheMinutes)*time.Minute), nil
+	case cfg.Service == lookup.ServiceGoogle:
+		client, err := gcp.NewClient(context.Background(), cfg.Credentials, gcp.ScopeUserInfoEmail)
+		if err != nil {
+			return nil, fmt.Errorf("error authenticating to Google's CLA service: %s", err)
+		}
+		client.Timeout = serviceTimeout
+		httpLookup = lookup.NewGoogleLookup(client, cfg.URL)
+	case cfg.Service == lookup.ServiceDocuSign:
+		client, err := lookup.NewDocuSignClient(context.Background(), cfg.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("error authenticating to DocuSign: %s", err)
+		}
+		client.Timeout = serviceTimeout
+		return lookup.NewCachedLookup(lookup.NewDocuSignLookup(client, cfg.URL, cfg.EnvelopeSubject), time.Duration(cacheMinutes)*time.Minute), nil
+	case cfg.URL != "":
+		httpLookup = lookup.NewHTTPLookup(&http.Client{Timeout: serviceTimeout}, cfg.URL)
+		httpLookup.SetAuth(cfg.AuthHeader, token)
+	default:
+		return nil, nil
+	}
+	return lookup.NewCachedLookup(httpLookup, time.Duration(cacheMinutes)*time.Minute), nil
+}
+
+// NewBreakerTransport returns the configured circuit breaker around the
+// transport, or nil if it is disabled.
+func NewBreakerTransport(transport http.RoundTripper, cfg config.CircuitBreaker) *ghutil.BreakerTransport {
+	if cfg.Failures < 0 {
+		return nil
+	}
+	failures := cfg.Failures
+	if failures == 0 {
+		failures = DefaultBreakerFailures
+	}
+	cooldownSeconds := cfg.CooldownSeconds
+	if cooldownSeconds <= 0 {
+		cooldownSeconds = DefaultBreakerCooldownSeconds
+	}
+	return ghutil.NewBreakerTransport(transport, failures, time.Duration(cooldownSeconds)*time.Second)
+}
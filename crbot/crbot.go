@@ -0,0 +1,224 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crbot embeds the compliance engine of the bot in other Go programs,
+// as an alternative to running the `crbot` command. A bot is configured once,
+// with the same config, secrets, and CLA signers as the command, and can then
+// process orgs, repos, and pull requests concurrently, returning the results
+// of each run rather than writing them to files:
+//
+//	cfg, err := config.LoadConfig("config.yaml")
+//	...
+//	bot, err := crbot.New(crbot.Options{
+//		Config:     cfg,
+//		Secrets:    secrets,
+//		ClaSigners: claSigners,
+//		UpdateRepo: true,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	results, err := bot.ProcessOrg(ctx, "my-org")
+//
+// Errors are returned rather than exiting the program, and the package keeps
+// no global state: each bot has its own connection to GitHub and caches. The
+// files of the command are loaded via the `Load*` functions of the `config`
+// package, which return errors as well; CLA signers files in GitHub repos
+// are only available to the command, though.
+package crbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/code-review-bot/state"
+)
+
+// Options configures a bot.
+type Options struct {
+	// Config holds the settings of the config file.
+	Config config.Config
+	// Secrets holds the GitHub token and the credentials of other
+	// services.
+	Secrets config.Secrets
+	// ClaSigners are the CLA signers against which commits are checked.
+	ClaSigners config.ClaSigners
+
+	// UpdateRepo labels and comments on the pull requests processed;
+	// otherwise, the bot only reports their compliance.
+	UpdateRepo bool
+
+	// State, if non-nil, remembers what the bot has done to each pull
+	// request across runs; it is required for reminders. The caller
+	// remains responsible for closing it.
+	State state.Store
+
+	// Transport, if non-nil, carries the requests to GitHub instead of a
+	// transport via the proxy set in the environment and trusting the CA
+	// bundle of the config file.
+	Transport http.RoundTripper
+
+	// Client, if non-nil, is used to access GitHub instead of connecting
+	// with the GitHub token of the secrets, e.g., a client bound to a
+	// `ghutiltest.FakeGitHub` in tests. It is configured from the config
+	// file as the bot's own would be, and must not be used elsewhere.
+	// Requests made via its services are not canceled along with the
+	// context of a run.
+	Client *ghutil.GitHubClient
+}
+
+// Bot processes pull requests with fixed options.
+type Bot struct {
+	cfg        config.Config
+	claSigners config.ClaSigners
+	updateRepo bool
+
+	// ghc holds the settings shared by the runs of the bot, from which
+	// the client of each run is copied.
+	ghc *ghutil.GitHubClient
+	// transport and token are the connection to GitHub, unless `Client`
+	// was set in the options.
+	transport http.RoundTripper
+	token     string
+}
+
+// New validates the options and returns a bot configured with them.
+func New(opts Options) (*Bot, error) {
+	cfg := opts.Config
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err)
+	}
+	if cfg.Reminders.IntervalDays > 0 && opts.State == nil {
+		return nil, errors.New("`reminders` require a state store")
+	}
+
+	bot := &Bot{
+		cfg:        cfg,
+		claSigners: opts.ClaSigners,
+		updateRepo: opts.UpdateRepo,
+		ghc:        opts.Client,
+	}
+	if bot.ghc == nil {
+		if opts.Secrets.Auth == "" {
+			return nil, errors.New("a GitHub token is required in the secrets")
+		}
+		bot.ghc = ghutil.NewBasicClient()
+		bot.token = opts.Secrets.Auth
+		bot.transport = opts.Transport
+		if bot.transport == nil {
+			transport, err := ghutil.NewTransport(ghutil.TransportOptions{CABundle: cfg.CABundle})
+			if err != nil {
+				return nil, fmt.Errorf("error configuring connection to GitHub: %s", err)
+			}
+			bot.transport = transport
+		}
+		// The breaker is shared by all runs, as they use the same API.
+		if breaker := NewBreakerTransport(bot.transport, cfg.CircuitBreaker); breaker != nil {
+			bot.transport = breaker
+		}
+	}
+	if err := ConfigureClient(bot.ghc, cfg, opts.Secrets); err != nil {
+		return nil, err
+	}
+	bot.ghc.State = opts.State
+	return bot, nil
+}
+
+// ProcessOrg processes the open pull requests of all repos of the org, or of
+// those selected by `repo` in the config file, if any.
+func (b *Bot) ProcessOrg(ctx context.Context, org string) (*report.Report, error) {
+	return b.process(ctx, NewOrgRepoSpec(b.cfg, org, b.cfg.Repo, b.updateRepo))
+}
+
+// ProcessRepo processes the open pull requests of the repos selected by
+// `repo`, a comma-separated list of names and glob patterns of repos of the
+// org, e.g., "cloud-*,website".
+func (b *Bot) ProcessRepo(ctx context.Context, org string, repo string) (*report.Report, error) {
+	return b.process(ctx, NewOrgRepoSpec(b.cfg, org, repo, b.updateRepo))
+}
+
+// ProcessPullRequests processes the given pull requests of the repo, whether
+// open or not.
+func (b *Bot) ProcessPullRequests(ctx context.Context, org string, repo string, pulls ...int) (*report.Report, error) {
+	repoSpec := NewOrgRepoSpec(b.cfg, org, repo, b.updateRepo)
+	repoSpec.Pulls = pulls
+	return b.process(ctx, repoSpec)
+}
+
+// process runs the repo spec with a client of its own, accumulating the
+// results of the run in its report. If the context is canceled, the requests
+// to GitHub fail from then on, and the run stops with the context's error,
+// returning the results up to that point.
+func (b *Bot) process(ctx context.Context, repoSpec ghutil.GitHubProcessOrgRepoSpec) (*report.Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ghc := b.client(ctx)
+	ghc.Report = report.New()
+	_, err := ghc.ProcessOrgRepo(repoSpec, b.claSigners)
+	if ghc.Usage != nil {
+		ghc.Report.SetAPIUsage(ghc.Usage.Usage())
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ghc.Report, ctxErr
+	}
+	if err != nil {
+		return ghc.Report, fmt.Errorf("error processing %s: %s", repoSpec.Org, err)
+	}
+	return ghc.Report, nil
+}
+
+// client returns a copy of the bot's client for a single run, whose requests
+// to GitHub are bound to the context and counted separately from those of
+// other runs.
+func (b *Bot) client(ctx context.Context) *ghutil.GitHubClient {
+	ghc := *b.ghc
+	if b.transport == nil {
+		return &ghc
+	}
+	usage := ghutil.NewUsageTransport(ghutil.NewHeaderTransport(&contextTransport{ctx: ctx, base: b.transport}, b.cfg.Headers))
+	tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: usage})
+	tc := oauth2.NewClient(tokenCtx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: b.token}))
+	conn := ghutil.NewClient(tc, b.cfg.UserAgent)
+	ghc.Organizations = conn.Organizations
+	ghc.Repositories = conn.Repositories
+	ghc.Issues = conn.Issues
+	ghc.PullRequests = conn.PullRequests
+	ghc.GraphQL = conn.GraphQL
+	ghc.Usage = usage
+	return &ghc
+}
+
+// contextTransport sends requests with its context, as the requests of
+// `ghutil` are not bound to that of the run, so that they fail once the run is
+// canceled.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the transport's context.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
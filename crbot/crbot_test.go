@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crbot
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/ghutiltest"
+	"github.com/google/code-review-bot/report"
+)
+
+var (
+	john = config.Account{Name: "John Doe", Email: "john@example.com", Login: "john-doe"}
+	jane = config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"}
+)
+
+func TestValidateConfig(t *testing.T) {
+	assert.Nil(t, ValidateConfig(config.Config{}))
+
+	invalid := map[string]config.Config{
+		"skip_labels":      {SkipLabels: true},
+		"trust_level":      {TrustLevel: "blind"},
+		"pull_order":       {PullOrder: "random"},
+		"checkers":         {Checkers: []string{"unknown"}},
+		"webhook":          {Events: config.Events{Webhooks: []string{"ftp://example.com"}}},
+		"kafka":            {Events: config.Events{Kafka: config.Kafka{Topic: "cla"}}},
+		"pubsub_topic":     {Events: config.Events{PubSubTopic: "cla"}},
+		"signer_lookup":    {SignerLookup: config.SignerLookup{Service: "unknown"}},
+		"google":           {SignerLookup: config.SignerLookup{Service: "google"}},
+		"okta":             {SignerLookup: config.SignerLookup{Service: "okta"}},
+		"docusign":         {SignerLookup: config.SignerLookup{Service: "docusign", URL: "https://example.com"}},
+		"locale":           {Locale: "xx"},
+		"comment_template": {CommentTemplate: "{{.Unknown"},
+		"tracking_issue":   {TrackingIssue: config.TrackingIssue{Repo: "org/dashboard"}},
+	}
+	for name, cfg := range invalid {
+		assert.NotNil(t, ValidateConfig(cfg), name)
+	}
+}
+
+func TestNewOrgRepoSpec_MaxCommentsPerSHA(t *testing.T) {
+	for value, expected := range map[int]int{-1: 0, 0: DefaultMaxCommentsPerSHA, 3: 3} {
+		repoSpec := NewOrgRepoSpec(config.Config{MaxCommentsPerSHA: value}, "org", "repo", true)
+		assert.Equal(t, expected, repoSpec.MaxCommentsPerSHA, value)
+		assert.True(t, repoSpec.UpdateRepo)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(Options{})
+	assert.NotNil(t, err)
+
+	_, err = New(Options{Config: config.Config{TrustLevel: "blind"}, Secrets: config.Secrets{Auth: "token"}})
+	assert.NotNil(t, err)
+
+	_, err = New(Options{Config: config.Config{Reminders: config.Reminders{IntervalDays: 7}}, Secrets: config.Secrets{Auth: "token"}})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "state store")
+	}
+}
+
+func TestProcessOrg(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddLabel("org", "repo", ghutil.LabelClaYes)
+	fake.AddLabel("org", "repo", ghutil.LabelClaNo)
+	fake.AddPullRequest("org", "repo", &github.PullRequest{Number: github.Int(1), Title: github.String("Signed")}, ghutiltest.NewCommit("abc123", john))
+	fake.AddPullRequest("org", "repo", &github.PullRequest{Number: github.Int(2), Title: github.String("Not signed")}, ghutiltest.NewCommit("def456", jane))
+
+	bot, err := New(Options{
+		ClaSigners: config.ClaSigners{People: []config.Account{john}},
+		UpdateRepo: true,
+		Client:     fake.Client(),
+	})
+	assert.Nil(t, err)
+
+	results, err := bot.ProcessOrg(context.Background(), "org")
+	assert.Nil(t, err)
+	if assert.Len(t, results.PullRequests, 2) {
+		statuses := map[int]string{}
+		for _, pr := range results.PullRequests {
+			statuses[pr.Number] = pr.Status()
+		}
+		assert.Equal(t, map[int]string{1: report.StatusCompliant, 2: report.StatusNonCompliant}, statuses)
+	}
+	assert.Equal(t, []string{ghutil.LabelClaNo}, fake.PullLabels("org", "repo", 2))
+
+	// Each run has results of its own.
+	results, err = bot.ProcessPullRequests(context.Background(), "org", "repo", 1)
+	assert.Nil(t, err)
+	assert.Len(t, results.PullRequests, 1)
+}
+
+func TestProcessOrg_TrackingIssue(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddRepo("org", "dashboard")
+	fake.AddPullRequest("org", "repo", &github.PullRequest{Number: github.Int(1), Title: github.String("Signed")}, ghutiltest.NewCommit("abc123", john))
+	fake.AddPullRequest("org", "repo", &github.PullRequest{Number: github.Int(2), Title: github.String("Not signed")}, ghutiltest.NewCommit("def456", jane))
+
+	bot, err := New(Options{
+		Config:     config.Config{TrackingIssue: config.TrackingIssue{Enabled: true, Repo: "dashboard"}},
+		ClaSigners: config.ClaSigners{People: []config.Account{john}},
+		UpdateRepo: true,
+		Client:     fake.Client(),
+	})
+	assert.Nil(t, err)
+
+	// The issue of the org lists the PRs of all of its repos.
+	_, err = bot.ProcessOrg(context.Background(), "org")
+	assert.Nil(t, err)
+	assert.Empty(t, fake.Issues("org", "repo"))
+	issues := fake.Issues("org", "dashboard")
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].GetBody(), "- [ ] org/repo#2 Not signed")
+		assert.NotContains(t, issues[0].GetBody(), "org/repo#1")
+	}
+
+	// Runs over individual PRs leave it alone.
+	_, err = bot.ProcessPullRequests(context.Background(), "org", "repo", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, issues, fake.Issues("org", "dashboard"))
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestProcessOrg_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requests := 0
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		assert.Equal(t, "crbot-test", req.Header.Get("User-Agent"))
+		// The run is canceled during its first request, so no others
+		// are sent.
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader("[]")),
+			Request:    req,
+		}, nil
+	})
+
+	bot, err := New(Options{
+		Config:    config.Config{UserAgent: "crbot-test", CircuitBreaker: config.CircuitBreaker{Failures: -1}},
+		Secrets:   config.Secrets{Auth: "token"},
+		Transport: transport,
+	})
+	assert.Nil(t, err)
+
+	_, err = bot.ProcessOrg(ctx, "org")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, requests)
+
+	_, err = bot.ProcessOrg(ctx, "org")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package directory reads company rosters from a Google Workspace directory
+// via the Admin SDK Directory API, so that the people of companies in the CLA
+// signers can be kept in sync with the companies' own records. As the
+// directory doesn't know the GitHub logins of users, they are read from a
+// custom user attribute.
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/logging"
+)
+
+var logger = logging.New("directory")
+
+// DefaultEndpoint is the base URL of the Admin SDK Directory API.
+const DefaultEndpoint = "https://admin.googleapis.com/admin/directory/v1"
+
+// Client reads users from the directory.
+type Client struct {
+	client     *http.Client
+	endpoint   string
+	schema     string
+	loginField string
+}
+
+// NewClient returns a client sending requests via `client`, which must
+// authenticate them as a user allowed to read the directory (see
+// `gcp.NewDelegatedClient`). `loginField` names the custom user attribute
+// holding GitHub logins, in the form "SCHEMA.FIELD".
+func NewClient(client *http.Client, loginField string) (*Client, error) {
+	parts := strings.Split(loginField, ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid login field '%s'; expected SCHEMA.FIELD", loginField)
+	}
+	return &Client{
+		client:     client,
+		endpoint:   DefaultEndpoint,
+		schema:     parts[0],
+		loginField: parts[1],
+	}, nil
+}
+
+// SetEndpoint overrides the base URL of the Directory API, e.g., for tests.
+func (c *Client) SetEndpoint(endpoint string) {
+	c.endpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+// user is the subset of a directory user which is needed for its account.
+type user struct {
+	PrimaryEmail string `json:"primaryEmail"`
+	Name         struct {
+		FullName string `json:"fullName"`
+	} `json:"name"`
+	Suspended     bool                              `json:"suspended"`
+	CustomSchemas map[string]map[string]interface{} `json:"customSchemas"`
+}
+
+type usersResponse struct {
+	Users         []user `json:"users"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+type member struct {
+	ID     string `json:"id"`
+	Email  string `json:"email"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+type membersResponse struct {
+	Members       []member `json:"members"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// Accounts returns the accounts of the company's roster: the members of its
+// group, or the users of its domain.
+func (c *Client) Accounts(ctx context.Context, company config.DirectoryCompany) ([]config.Account, error) {
+	if company.Group != "" {
+		return c.GroupAccounts(ctx, company.Group)
+	}
+	return c.DomainAccounts(ctx, company.Domain)
+}
+
+// DomainAccounts returns the accounts of the active users of the domain.
+func (c *Client) DomainAccounts(ctx context.Context, domain string) ([]config.Account, error) {
+	var users []user
+	pageToken := ""
+	for {
+		query := c.userQuery()
+		query.Set("domain", domain)
+		query.Set("maxResults", "500")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var response usersResponse
+		if err := c.get(ctx, "/users", query, &response); err != nil {
+			return nil, fmt.Errorf("error listing users of domain %s: %s", domain, err)
+		}
+		users = append(users, response.Users...)
+		if pageToken = response.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+	return c.accounts(users), nil
+}
+
+// GroupAccounts returns the accounts of the active users who are members of
+// the group, directly or via nested groups.
+func (c *Client) GroupAccounts(ctx context.Context, group string) ([]config.Account, error) {
+	var members []member
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("includeDerivedMembership", "true")
+		query.Set("maxResults", "200")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var response membersResponse
+		if err := c.get(ctx, "/groups/"+url.PathEscape(group)+"/members", query, &response); err != nil {
+			return nil, fmt.Errorf("error listing members of group %s: %s", group, err)
+		}
+		members = append(members, response.Members...)
+		if pageToken = response.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	// Members carry no names or custom attributes, so each user is read
+	// separately.
+	var users []user
+	for _, m := range members {
+		if m.Type != "USER" || m.Status == "SUSPENDED" {
+			continue
+		}
+		var u user
+		if err := c.get(ctx, "/users/"+url.PathEscape(m.ID), c.userQuery(), &u); err != nil {
+			return nil, fmt.Errorf("error reading user %s: %s", m.Email, err)
+		}
+		users = append(users, u)
+	}
+	return c.accounts(users), nil
+}
+
+// userQuery returns the query parameters requesting the custom attribute with
+// the GitHub logins of users.
+func (c *Client) userQuery() url.Values {
+	query := url.Values{}
+	query.Set("projection", "custom")
+	query.Set("customFieldMask", c.schema)
+	return query
+}
+
+// accounts converts the users into accounts sorted by login, leaving out those
+// which are suspended or have no GitHub login.
+func (c *Client) accounts(users []user) []config.Account {
+	var accounts []config.Account
+	for _, u := range users {
+		if u.Suspended {
+			continue
+		}
+		login, _ := u.CustomSchemas[c.schema][c.loginField].(string)
+		if login = strings.TrimSpace(login); login == "" {
+			logger.Debugf("Leaving out %s, who has no GitHub login in %s.%s", u.PrimaryEmail, c.schema, c.loginField)
+			continue
+		}
+		accounts = append(accounts, config.Account{
+			Name:  u.Name.FullName,
+			Email: u.PrimaryEmail,
+			Login: login,
+		})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return strings.ToLower(accounts[i].Login) < strings.ToLower(accounts[j].Login)
+	})
+	return accounts
+}
+
+// get sends a GET request to the API and decodes the JSON response.
+func (c *Client) get(ctx context.Context, path string, query url.Values, response interface{}) error {
+	req, err := http.NewRequest("GET", c.endpoint+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("error parsing response: %s", err)
+	}
+	return nil
+}
+
+// MergeRoster replaces the people of the named company in the CLA signers
+// with the accounts from the directory, adding the company if it isn't listed
+// yet, and returns the accounts which were added and removed. Accounts are
+// compared by login and email, ignoring case, so that changes of names alone
+// are not reported.
+func MergeRoster(claSigners *config.ClaSigners, company string, accounts []config.Account) (added []config.Account, removed []config.Account) {
+	idx := -1
+	for i := range claSigners.Companies {
+		if claSigners.Companies[i].Name == company {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		claSigners.Companies = append(claSigners.Companies, config.Company{Name: company})
+		idx = len(claSigners.Companies) - 1
+	}
+
+	key := func(account config.Account) string {
+		return strings.ToLower(account.Login) + " " + strings.ToLower(account.Email)
+	}
+	previous := make(map[string]bool)
+	for _, account := range claSigners.Companies[idx].People {
+		previous[key(account)] = true
+	}
+	current := make(map[string]bool)
+	for _, account := range accounts {
+		current[key(account)] = true
+		if !previous[key(account)] {
+			added = append(added, account)
+		}
+	}
+	for _, account := range claSigners.Companies[idx].People {
+		if !current[key(account)] {
+			removed = append(removed, account)
+		}
+	}
+	claSigners.Companies[idx].People = accounts
+	return added, removed
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package directory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func()) {
+	server := httptest.NewServer(handler)
+	client, err := NewClient(server.Client(), "GitHub.login")
+	assert.Nil(t, err)
+	client.SetEndpoint(server.URL + "/")
+	return client, server.Close
+}
+
+func TestNewClient_InvalidLoginField(t *testing.T) {
+	for _, loginField := range []string{"", "login", "GitHub.", "a.b.c"} {
+		_, err := NewClient(http.DefaultClient, loginField)
+		assert.NotNil(t, err, loginField)
+	}
+}
+
+func TestDomainAccounts(t *testing.T) {
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/users", req.URL.Path)
+		assert.Equal(t, "example.com", req.URL.Query().Get("domain"))
+		assert.Equal(t, "GitHub", req.URL.Query().Get("customFieldMask"))
+		if req.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"users": [
+				{"primaryEmail": "jane@example.com", "name": {"fullName": "Jane Doe"}, "customSchemas": {"GitHub": {"login": "jane-doe"}}},
+				{"primaryEmail": "nologin@example.com", "name": {"fullName": "No Login"}}
+			], "nextPageToken": "page2"}`))
+			return
+		}
+		assert.Equal(t, "page2", req.URL.Query().Get("pageToken"))
+		w.Write([]byte(`{"users": [
+			{"primaryEmail": "ann@example.com", "name": {"fullName": "Ann Lee"}, "customSchemas": {"GitHub": {"login": "ann-lee"}}},
+			{"primaryEmail": "gone@example.com", "name": {"fullName": "Gone"}, "suspended": true, "customSchemas": {"GitHub": {"login": "gone"}}}
+		]}`))
+	})
+	defer closeServer()
+
+	accounts, err := client.DomainAccounts(context.Background(), "example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []config.Account{
+		{Name: "Ann Lee", Email: "ann@example.com", Login: "ann-lee"},
+		{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"},
+	}, accounts)
+}
+
+func TestGroupAccounts(t *testing.T) {
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/groups/eng@example.com/members":
+			assert.Equal(t, "true", req.URL.Query().Get("includeDerivedMembership"))
+			w.Write([]byte(`{"members": [
+				{"id": "1", "email": "jane@example.com", "type": "USER", "status": "ACTIVE"},
+				{"id": "2", "email": "sub@example.com", "type": "GROUP"},
+				{"id": "3", "email": "gone@example.com", "type": "USER", "status": "SUSPENDED"}
+			]}`))
+		case "/users/1":
+			assert.Equal(t, "custom", req.URL.Query().Get("projection"))
+			w.Write([]byte(`{"primaryEmail": "jane@example.com", "name": {"fullName": "Jane Doe"}, "customSchemas": {"GitHub": {"login": "jane-doe"}}}`))
+		default:
+			t.Errorf("unexpected request for %s", req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer closeServer()
+
+	accounts, err := client.Accounts(context.Background(), config.DirectoryCompany{Company: "Example", Group: "eng@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, []config.Account{{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"}}, accounts)
+}
+
+func TestGroupAccounts_Error(t *testing.T) {
+	client, closeServer := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"message": "Not Authorized to access this resource/api"}}`))
+	})
+	defer closeServer()
+
+	_, err := client.GroupAccounts(context.Background(), "eng@example.com")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestMergeRoster(t *testing.T) {
+	claSigners := config.ClaSigners{
+		Companies: []config.Company{
+			{
+				Name: "Example",
+				People: []config.Account{
+					{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"},
+					{Name: "Left", Email: "left@example.com", Login: "left"},
+				},
+			},
+		},
+	}
+
+	accounts := []config.Account{
+		{Name: "Jane Q. Doe", Email: "Jane@example.com", Login: "Jane-Doe"},
+		{Name: "New Hire", Email: "new@example.com", Login: "new-hire"},
+	}
+	added, removed := MergeRoster(&claSigners, "Example", accounts)
+	assert.Equal(t, []config.Account{{Name: "New Hire", Email: "new@example.com", Login: "new-hire"}}, added)
+	assert.Equal(t, []config.Account{{Name: "Left", Email: "left@example.com", Login: "left"}}, removed)
+	assert.Equal(t, accounts, claSigners.Companies[0].People)
+
+	// Companies which aren't listed yet are added.
+	added, removed = MergeRoster(&claSigners, "Other", accounts[:1])
+	assert.Len(t, added, 1)
+	assert.Empty(t, removed)
+	assert.Equal(t, "Other", claSigners.Companies[1].Name)
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events publishes events about changes the bot makes to pull
+// requests, e.g., to Google Cloud Pub/Sub, Kafka, or a generic webhook, so
+// that downstream automation (merge queues, dashboards, audit pipelines) can
+// react to them.
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TypeLabelsChanged is the type of the event published when the bot changes
+// the CLA labels of a pull request.
+const TypeLabelsChanged = "cla.labels_changed"
+
+// TypeChecked is the type of the event published with the compliance decision
+// of each pull request the bot checks, if enabled, whether or not it changed
+// anything.
+const TypeChecked = "cla.checked"
+
+// TypeRegressed is the type of the event published when a pull request which
+// was compliant no longer is, e.g., after a force-push added commits by an
+// author without a CLA, so that maintainers can act on it before merging.
+const TypeRegressed = "cla.regressed"
+
+// SeverityHigh is the severity of events which need the attention of
+// maintainers, such as `TypeRegressed`.
+const SeverityHigh = "high"
+
+// Event describes a change to a single pull request.
+type Event struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Org       string    `json:"org"`
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	URL       string    `json:"url,omitempty"`
+
+	// Status is the compliance status of the pull request, as in
+	// `report.PullRequest.Status`, and Reason explains it if it is not
+	// compliant.
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// PreviousStatus is the compliance status of the pull request before
+	// the change, if it had a CLA label.
+	PreviousStatus string `json:"previous_status,omitempty"`
+
+	// Added and Removed are the labels added to and removed from the pull
+	// request.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+
+	// Severity is set, e.g., to `SeverityHigh`, on events which need the
+	// attention of maintainers, so that they can be routed separately.
+	Severity string `json:"severity,omitempty"`
+}
+
+// Publisher publishes events.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Publishers publishes each event to all of the publishers in turn, returning
+// the first error, if any.
+type Publishers []Publisher
+
+// Publish publishes the event to all of the publishers.
+func (p Publishers) Publish(ctx context.Context, event Event) error {
+	var firstErr error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// DefaultPubSubEndpoint is the base URL of the Pub/Sub API.
+const DefaultPubSubEndpoint = "https://pubsub.googleapis.com/v1"
+
+// PubSubPublisher publishes events as JSON messages to a Pub/Sub topic, with
+// the event type, org, and repo as message attributes for filtering
+// subscriptions.
+type PubSubPublisher struct {
+	client   *http.Client
+	endpoint string
+	topic    string
+}
+
+// NewPubSubPublisher returns a publisher to the topic, in the form
+// "projects/PROJECT/topics/TOPIC", sending requests via the client, which must
+// authenticate them (see `gcp.NewClient`).
+func NewPubSubPublisher(client *http.Client, topic string) *PubSubPublisher {
+	return &PubSubPublisher{
+		client:   client,
+		endpoint: DefaultPubSubEndpoint,
+		topic:    topic,
+	}
+}
+
+// SetEndpoint overrides the base URL of the Pub/Sub API, e.g., for the
+// emulator or tests.
+func (p *PubSubPublisher) SetEndpoint(endpoint string) {
+	p.endpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+type pubSubMessage struct {
+	Data       string            `json:"data"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type publishRequest struct {
+	Messages []pubSubMessage `json:"messages"`
+}
+
+// Publish publishes the event as a single message.
+func (p *PubSubPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	attributes := map[string]string{
+		"type": event.Type,
+		"org":  event.Org,
+		"repo": event.Repo,
+	}
+	if event.Severity != "" {
+		attributes["severity"] = event.Severity
+	}
+	body, err := json.Marshal(publishRequest{
+		Messages: []pubSubMessage{{
+			Data:       base64.StdEncoding.EncodeToString(data),
+			Attributes: attributes,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	publishURL := fmt.Sprintf("%s/%s:publish", p.endpoint, p.topic)
+	if err := post(ctx, p.client, publishURL, body, nil); err != nil {
+		return fmt.Errorf("error publishing to Pub/Sub topic %s: %s", p.topic, err)
+	}
+	return nil
+}
+
+// WebhookPublisher publishes events by POSTing them as JSON to a URL.
+type WebhookPublisher struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+// NewWebhookPublisher returns a publisher to the URL.
+func NewWebhookPublisher(client *http.Client, url string) *WebhookPublisher {
+	return &WebhookPublisher{client: client, url: url}
+}
+
+// SetSecret sets the secret to sign the events with (see `SignatureHeader`),
+// so that the receiver can verify that they were sent by the bot.
+func (w *WebhookPublisher) SetSecret(secret string) {
+	w.secret = secret
+}
+
+// EventTypeHeader is the header with the type of the event POSTed to a
+// webhook.
+const EventTypeHeader = "X-Crbot-Event"
+
+// SignatureHeader is the header with the signature of the event POSTed to a
+// webhook, if it has a secret: "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of the body, keyed by the secret, as GitHub signs its webhook
+// deliveries.
+const SignatureHeader = "X-Crbot-Signature-256"
+
+// Sign returns the value of `SignatureHeader` for the body.
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish POSTs the event; any 2xx status is a success.
+func (w *WebhookPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{EventTypeHeader: event.Type}
+	if w.secret != "" {
+		headers[SignatureHeader] = Sign(body, w.secret)
+	}
+	if err := post(ctx, w.client, w.url, body, headers); err != nil {
+		return fmt.Errorf("error publishing to webhook %s: %s", w.url, err)
+	}
+	return nil
+}
+
+// post sends the JSON body to the URL, returning an error for any status other
+// than 2xx.
+func post(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvent = Event{
+	Type:      TypeLabelsChanged,
+	Timestamp: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+	Org:       "org",
+	Repo:      "repo",
+	Number:    42,
+	Status:    "compliant",
+	Added:     []string{"cla: yes"},
+	Removed:   []string{"cla: no"},
+}
+
+func TestPubSubPublisher(t *testing.T) {
+	var request publishRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/projects/proj/topics/cla:publish", req.URL.Path)
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&request))
+		w.Write([]byte(`{"messageIds": ["1"]}`))
+	}))
+	defer server.Close()
+
+	publisher := NewPubSubPublisher(server.Client(), "projects/proj/topics/cla")
+	publisher.SetEndpoint(server.URL + "/")
+	assert.Nil(t, publisher.Publish(context.Background(), testEvent))
+
+	assert.Equal(t, 1, len(request.Messages))
+	message := request.Messages[0]
+	assert.Equal(t, map[string]string{"type": TypeLabelsChanged, "org": "org", "repo": "repo"}, message.Attributes)
+	data, err := base64.StdEncoding.DecodeString(message.Data)
+	assert.Nil(t, err)
+	var event Event
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestPubSubPublisher_Severity(t *testing.T) {
+	var request publishRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&request))
+		w.Write([]byte(`{"messageIds": ["1"]}`))
+	}))
+	defer server.Close()
+
+	publisher := NewPubSubPublisher(server.Client(), "projects/proj/topics/cla")
+	publisher.SetEndpoint(server.URL + "/")
+	event := testEvent
+	event.Type = TypeRegressed
+	event.Severity = SeverityHigh
+	assert.Nil(t, publisher.Publish(context.Background(), event))
+
+	if assert.Equal(t, 1, len(request.Messages)) {
+		assert.Equal(t, SeverityHigh, request.Messages[0].Attributes["severity"])
+	}
+}
+
+func TestPubSubPublisher_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "topic not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	publisher := NewPubSubPublisher(server.Client(), "projects/proj/topics/cla")
+	publisher.SetEndpoint(server.URL)
+	err := publisher.Publish(context.Background(), testEvent)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "projects/proj/topics/cla: 404 Not Found: topic not found")
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var event Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, TypeLabelsChanged, req.Header.Get(EventTypeHeader))
+		assert.Equal(t, "", req.Header.Get(SignatureHeader))
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&event))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(server.Client(), server.URL+"/hook")
+	assert.Nil(t, publisher.Publish(context.Background(), testEvent))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestWebhookPublisher_Signed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, Sign(body, "s3cret"), req.Header.Get(SignatureHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(server.Client(), server.URL+"/hook")
+	publisher.SetSecret("s3cret")
+	assert.Nil(t, publisher.Publish(context.Background(), testEvent))
+}
+
+func TestSign(t *testing.T) {
+	// The example from GitHub's docs on validating webhook deliveries.
+	assert.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", Sign([]byte("Hello, World!"), "It's a Secret to Everybody"))
+}
+
+type fakePublisher struct {
+	err    error
+	events int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, _ Event) error {
+	p.events++
+	return p.err
+}
+
+func TestPublishers(t *testing.T) {
+	failing := &fakePublisher{err: errors.New("unavailable")}
+	working := &fakePublisher{}
+
+	err := Publishers{failing, working}.Publish(context.Background(), testEvent)
+	assert.Equal(t, failing.err, err)
+	assert.Equal(t, 1, failing.events)
+	assert.Equal(t, 1, working.events)
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// KafkaPublisher publishes events to a Kafka topic via a Kafka REST Proxy
+// (API v2), e.g., Confluent's, for audit pipelines which consume Kafka. Each
+// event is keyed by its pull request, e.g., "org/repo#42", so that the events
+// of a pull request stay in order within their partition.
+type KafkaPublisher struct {
+	client   *http.Client
+	proxyURL string
+	topic    string
+	username string
+	password string
+}
+
+// NewKafkaPublisher returns a publisher to the topic via the REST Proxy at
+// `proxyURL`.
+func NewKafkaPublisher(client *http.Client, proxyURL string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		client:   client,
+		proxyURL: strings.TrimSuffix(proxyURL, "/"),
+		topic:    topic,
+	}
+}
+
+// SetBasicAuth sets the credentials to authenticate to the REST Proxy with.
+func (p *KafkaPublisher) SetBasicAuth(username string, password string) {
+	p.username = username
+	p.password = password
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish produces the event as a JSON record.
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(kafkaProduceRequest{
+		Records: []kafkaRecord{{
+			Key:   fmt.Sprintf("%s/%s#%d", event.Org, event.Repo, event.Number),
+			Value: event,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := p.produce(ctx, body); err != nil {
+		return fmt.Errorf("error publishing to Kafka topic %s: %s", p.topic, err)
+	}
+	return nil
+}
+
+func (p *KafkaPublisher) produce(ctx context.Context, body []byte) error {
+	req, err := http.NewRequest("POST", p.proxyURL+"/topics/"+url.PathEscape(p.topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	// The proxy reports failures to produce individual records, e.g., to
+	// an unavailable partition, in an otherwise successful response.
+	var response kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("error parsing response: %s", err)
+	}
+	for _, offset := range response.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("error %d: %s", *offset.ErrorCode, offset.Error)
+		}
+	}
+	return nil
+}
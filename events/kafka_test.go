@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaPublisher(t *testing.T) {
+	var request kafkaProduceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/topics/cla-events", req.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", req.Header.Get("Content-Type"))
+		username, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "crbot", username)
+		assert.Equal(t, "s3cret", password)
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&request))
+		w.Write([]byte(`{"offsets": [{"partition": 0, "offset": 12, "error_code": null, "error": null}]}`))
+	}))
+	defer server.Close()
+
+	publisher := NewKafkaPublisher(server.Client(), server.URL+"/", "cla-events")
+	publisher.SetBasicAuth("crbot", "s3cret")
+	assert.Nil(t, publisher.Publish(context.Background(), testEvent))
+	assert.Equal(t, []kafkaRecord{{Key: "org/repo#42", Value: testEvent}}, request.Records)
+}
+
+func TestKafkaPublisher_RecordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`{"offsets": [{"partition": null, "offset": null, "error_code": 50003, "error": "Kafka error: timed out"}]}`))
+	}))
+	defer server.Close()
+
+	err := NewKafkaPublisher(server.Client(), server.URL, "cla-events").Publish(context.Background(), testEvent)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cla-events: error 50003: Kafka error: timed out")
+}
+
+func TestKafkaPublisher_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, `{"error_code": 40401, "message": "Topic not found."}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := NewKafkaPublisher(server.Client(), server.URL, "cla-events").Publish(context.Background(), testEvent)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+}
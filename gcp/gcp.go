@@ -0,0 +1,186 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcp authenticates requests to Google Cloud APIs, either as the
+// service account of a JSON key file, or as the service account attached to
+// the Compute Engine (or Cloud Run, Cloud Functions, etc.) instance the bot
+// runs on, via the metadata server.
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+// OAuth scopes of the Google APIs used by the bot.
+const (
+	ScopeBigQueryInsert = "https://www.googleapis.com/auth/bigquery.insertdata"
+	ScopePubSub         = "https://www.googleapis.com/auth/pubsub"
+	ScopeUserInfoEmail  = "https://www.googleapis.com/auth/userinfo.email"
+
+	ScopeDirectoryUserReadonly        = "https://www.googleapis.com/auth/admin.directory.user.readonly"
+	ScopeDirectoryGroupMemberReadonly = "https://www.googleapis.com/auth/admin.directory.group.member.readonly"
+)
+
+// CredentialsEnv is the standard environment variable pointing to a service
+// account key file, used if no credentials file is configured.
+const CredentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+
+// MetadataHostEnv is the standard environment variable overriding the host of
+// the metadata server, e.g., for emulators.
+const MetadataHostEnv = "GCE_METADATA_HOST"
+
+// defaultTokenURL is the token endpoint used for service account keys which
+// don't specify one.
+const defaultTokenURL = "https://oauth2.googleapis.com/token"
+
+// serviceAccountKey is the subset of a service account JSON key file needed to
+// obtain tokens.
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// TokenSource returns a source of tokens with the given scopes: for the
+// service account of the key file, if `credentialsFile` (or the file named by
+// `CredentialsEnv`) is set, or from the metadata server otherwise.
+func TokenSource(ctx context.Context, credentialsFile string, scopes ...string) (oauth2.TokenSource, error) {
+	return tokenSource(ctx, credentialsFile, "", scopes)
+}
+
+// DelegatedTokenSource returns a source of tokens with the given scopes for
+// the service account of the key file, impersonating `subject` via
+// domain-wide delegation, as required by, e.g., the Google Workspace Admin
+// SDK. The instance's service account can't impersonate users, so a key file
+// is required.
+func DelegatedTokenSource(ctx context.Context, credentialsFile string, subject string, scopes ...string) (oauth2.TokenSource, error) {
+	return tokenSource(ctx, credentialsFile, subject, scopes)
+}
+
+// tokenSource implements `TokenSource` and `DelegatedTokenSource`.
+func tokenSource(ctx context.Context, credentialsFile string, subject string, scopes []string) (oauth2.TokenSource, error) {
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv(CredentialsEnv)
+	}
+	if credentialsFile == "" {
+		if subject != "" {
+			return nil, fmt.Errorf("impersonating %s requires a service account key file", subject)
+		}
+		return oauth2.ReuseTokenSource(nil, &metadataTokenSource{ctx: ctx, scopes: scopes}), nil
+	}
+
+	data, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("error parsing credentials file '%s': %s", credentialsFile, err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("unsupported credentials type '%s' in '%s'; only service account keys are supported", key.Type, credentialsFile)
+	}
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	jwtConfig := jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       scopes,
+		TokenURL:     tokenURL,
+		Subject:      subject,
+	}
+	return jwtConfig.TokenSource(ctx), nil
+}
+
+// NewClient returns an HTTP client authenticating its requests with tokens
+// from `TokenSource`.
+func NewClient(ctx context.Context, credentialsFile string, scopes ...string) (*http.Client, error) {
+	tokenSource, err := TokenSource(ctx, credentialsFile, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.NewClient(ctx, tokenSource), nil
+}
+
+// NewDelegatedClient returns an HTTP client authenticating its requests with
+// tokens from `DelegatedTokenSource`.
+func NewDelegatedClient(ctx context.Context, credentialsFile string, subject string, scopes ...string) (*http.Client, error) {
+	tokenSource, err := DelegatedTokenSource(ctx, credentialsFile, subject, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.NewClient(ctx, tokenSource), nil
+}
+
+// metadataTokenSource obtains tokens for the instance's service account from
+// the metadata server.
+type metadataTokenSource struct {
+	ctx    context.Context
+	scopes []string
+}
+
+// Token fetches a new token from the metadata server.
+func (s *metadataTokenSource) Token() (*oauth2.Token, error) {
+	host := os.Getenv(MetadataHostEnv)
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	tokenURL := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token"
+	if len(s.scopes) > 0 {
+		tokenURL += "?scopes=" + url.QueryEscape(strings.Join(s.scopes, ","))
+	}
+	req, err := http.NewRequest("GET", tokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req.WithContext(s.ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching token from metadata server: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching token from metadata server: %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("error parsing token from metadata server: %s", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
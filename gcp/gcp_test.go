@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSource_Metadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Google", req.Header.Get("Metadata-Flavor"))
+		assert.Equal(t, "/computeMetadata/v1/instance/service-accounts/default/token", req.URL.Path)
+		assert.Equal(t, ScopePubSub, req.URL.Query().Get("scopes"))
+		w.Write([]byte(`{"access_token": "metadata-token", "expires_in": 3599, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	os.Setenv(MetadataHostEnv, strings.TrimPrefix(server.URL, "http://"))
+	defer os.Unsetenv(MetadataHostEnv)
+	os.Unsetenv(CredentialsEnv)
+
+	tokenSource, err := TokenSource(context.Background(), "", ScopePubSub)
+	assert.Nil(t, err)
+	token, err := tokenSource.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "metadata-token", token.AccessToken)
+	assert.True(t, token.Valid())
+}
+
+// writeServiceAccountKey writes a service account key file using the token
+// endpoint to the directory, and returns its path.
+func writeServiceAccountKey(t *testing.T, dir string, tokenURL string) string {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	keyFile, err := json.Marshal(serviceAccountKey{
+		Type:        "service_account",
+		ClientEmail: "crbot@project.iam.gserviceaccount.com",
+		PrivateKey:  string(keyPEM),
+		TokenURI:    tokenURL,
+	})
+	assert.Nil(t, err)
+
+	filename := filepath.Join(dir, "key.json")
+	assert.Nil(t, ioutil.WriteFile(filename, keyFile, 0600))
+	return filename
+}
+
+func TestTokenSource_ServiceAccountKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Nil(t, req.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", req.Form.Get("grant_type"))
+		assert.NotEmpty(t, req.Form.Get("assertion"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "key-token", "expires_in": 3600, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "gcp")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := writeServiceAccountKey(t, dir, server.URL)
+
+	tokenSource, err := TokenSource(context.Background(), filename, ScopeBigQueryInsert)
+	assert.Nil(t, err)
+	token, err := tokenSource.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "key-token", token.AccessToken)
+}
+
+func TestDelegatedTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Nil(t, req.ParseForm())
+		// The claims of the assertion name the impersonated user.
+		parts := strings.Split(req.Form.Get("assertion"), ".")
+		if assert.Len(t, parts, 3) {
+			claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+			assert.Nil(t, err)
+			assert.Contains(t, string(claims), `"sub":"admin@example.com"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "delegated-token", "expires_in": 3600, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "gcp")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := writeServiceAccountKey(t, dir, server.URL)
+
+	tokenSource, err := DelegatedTokenSource(context.Background(), filename, "admin@example.com", ScopeDirectoryUserReadonly)
+	assert.Nil(t, err)
+	token, err := tokenSource.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "delegated-token", token.AccessToken)
+
+	// The metadata server can't impersonate users.
+	os.Unsetenv(CredentialsEnv)
+	_, err = DelegatedTokenSource(context.Background(), "", "admin@example.com", ScopeDirectoryUserReadonly)
+	assert.NotNil(t, err)
+}
+
+func TestTokenSource_UnsupportedCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gcp")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "user.json")
+	assert.Nil(t, ioutil.WriteFile(filename, []byte(`{"type": "authorized_user"}`), 0600))
+
+	_, err = TokenSource(context.Background(), filename)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "authorized_user")
+}
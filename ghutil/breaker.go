@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerTransport is a circuit breaker around the GitHub API: once
+// `failures` consecutive requests have failed, it trips, and pauses all
+// requests until the cool-down has passed, rather than letting the bot hammer
+// a degraded API and log an error for each PR. The first request after the
+// cool-down is a trial: if it fails too, the breaker trips again right away.
+// It is safe for concurrent use.
+type BreakerTransport struct {
+	base     http.RoundTripper
+	failures int
+	cooldown time.Duration
+
+	mu sync.Mutex
+	// consecutive is the number of requests which failed in a row.
+	consecutive int
+	// openUntil is the end of the current cool-down, if the breaker has
+	// tripped.
+	openUntil time.Time
+	// trips counts how often the breaker tripped.
+	trips int
+}
+
+// NewBreakerTransport returns a transport sending requests via `base`, which
+// trips after `failures` consecutive failures, and pauses requests for
+// `cooldown`.
+func NewBreakerTransport(base http.RoundTripper, failures int, cooldown time.Duration) *BreakerTransport {
+	return &BreakerTransport{
+		base:     base,
+		failures: failures,
+		cooldown: cooldown,
+	}
+}
+
+// RoundTrip implements `http.RoundTripper`, waiting out the cool-down if the
+// breaker has tripped, unless the request is canceled first.
+func (t *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	wait := time.Until(t.openUntil)
+	t.mu.Unlock()
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !isAPIFailure(resp, err) {
+		t.consecutive = 0
+		return resp, err
+	}
+	t.consecutive++
+	if now := time.Now(); t.consecutive >= t.failures && !t.openUntil.After(now) {
+		t.openUntil = now.Add(t.cooldown)
+		t.trips++
+		logger.Errorf("GitHub API failed %d time(s) in a row; pausing requests for %s", t.consecutive, t.cooldown)
+	}
+	return resp, err
+}
+
+// Trips returns how often the breaker has tripped so far.
+func (t *BreakerTransport) Trips() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.trips
+}
+
+// isAPIFailure returns whether the request failed in a way which suggests
+// that the API is degraded: a network error, or a server error. Client errors,
+// such as a missing PR, are the caller's concern.
+func isAPIFailure(resp *http.Response, err error) bool {
+	return err != nil || resp == nil || resp.StatusCode >= 500
+}
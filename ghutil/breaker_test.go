@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestBreakerTransport(t *testing.T) {
+	status := http.StatusBadGateway
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status}, nil
+	})
+	const cooldown = 50 * time.Millisecond
+	transport := ghutil.NewBreakerTransport(base, 2, cooldown)
+	roundTrip := func() time.Duration {
+		start := time.Now()
+		req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+		_, err := transport.RoundTrip(req)
+		assert.Nil(t, err)
+		return time.Since(start)
+	}
+
+	// The breaker trips after two failures in a row, and the next request
+	// waits for the cool-down; as it fails too, the breaker trips again.
+	assert.True(t, roundTrip() < cooldown)
+	assert.True(t, roundTrip() < cooldown)
+	assert.Equal(t, 1, transport.Trips())
+	assert.True(t, roundTrip() >= cooldown)
+	assert.Equal(t, 2, transport.Trips())
+
+	// Once the API recovers, requests go through without delay.
+	status = http.StatusOK
+	assert.True(t, roundTrip() >= cooldown)
+	assert.True(t, roundTrip() < cooldown)
+	assert.Equal(t, 2, transport.Trips())
+	assert.Equal(t, 5, calls)
+}
+
+func TestBreakerTransport_ClientErrorsDontTrip(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound}, nil
+	})
+	transport := ghutil.NewBreakerTransport(base, 1, time.Hour)
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+		_, err := transport.RoundTrip(req)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 0, transport.Trips())
+}
+
+func TestBreakerTransport_CanceledWhileOpen(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+	})
+	transport := ghutil.NewBreakerTransport(base, 1, time.Hour)
+	req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+	_, err := transport.RoundTrip(req)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = transport.RoundTrip(req.WithContext(ctx))
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
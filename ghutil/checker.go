@@ -0,0 +1,226 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// DefaultCheckerName is the name under which the built-in checker, which
+// matches commits against the CLA signers config via `ProcessCommit`, is
+// registered.
+const DefaultCheckerName = "cla-signers"
+
+// VerifiedEmailCheckerName is the name of the built-in checker which requires
+// the author email of each commit to be verified as belonging to the author's
+// GitHub account (see `CheckVerifiedEmail`); it is meant to be enabled along
+// with the default checker.
+const VerifiedEmailCheckerName = "verified-email"
+
+// ComplianceChecker determines the CLA compliance of a single commit.
+//
+// Custom checkers (e.g., ones which consult an internal CLA database) can be
+// registered via `RegisterChecker` and enabled by name in the config file, or
+// assigned directly to `GitHubClient.Checkers` by code embedding this package.
+type ComplianceChecker interface {
+	CheckCommit(ctx context.Context, commit *github.RepositoryCommit, claSigners config.ClaSigners) (CommitStatus, error)
+}
+
+// ComplianceCheckerFunc is an adapter to allow the use of ordinary functions
+// as a `ComplianceChecker`.
+type ComplianceCheckerFunc func(ctx context.Context, commit *github.RepositoryCommit, claSigners config.ClaSigners) (CommitStatus, error)
+
+// CheckCommit calls `f(ctx, commit, claSigners)`.
+func (f ComplianceCheckerFunc) CheckCommit(ctx context.Context, commit *github.RepositoryCommit, claSigners config.ClaSigners) (CommitStatus, error) {
+	return f(ctx, commit, claSigners)
+}
+
+var (
+	checkersMu sync.RWMutex
+	checkers   = map[string]ComplianceChecker{
+		DefaultCheckerName: ComplianceCheckerFunc(func(_ context.Context, commit *github.RepositoryCommit, claSigners config.ClaSigners) (CommitStatus, error) {
+			return ProcessCommit(commit, claSigners), nil
+		}),
+		VerifiedEmailCheckerName: ComplianceCheckerFunc(func(_ context.Context, commit *github.RepositoryCommit, _ config.ClaSigners) (CommitStatus, error) {
+			return CheckVerifiedEmail(commit), nil
+		}),
+	}
+)
+
+// CheckVerifiedEmail checks that the author email of the commit belongs to the
+// GitHub account it is attributed to. GitHub attributes commits to accounts
+// by email alone, so anyone can claim a CLA signer's identity by setting
+// their address as `user.email`. A verified signature doesn't settle this on
+// its own, as GitHub verifies it against the committer email, not the author
+// email: the commit must have been signed with a key of the account which
+// has the committer email as a verified email. The author email is therefore
+// only verified if it is the committer email. Unsigned commits, those whose
+// signature GitHub could not verify, and those authored by someone other than
+// the committer are not compliant. This includes commits committed and signed
+// by GitHub itself (as `web-flow`), e.g., edits via its web UI, as GitHub also
+// re-signs commits it rebases, e.g., via "Update branch", keeping their
+// original, possibly forged, author.
+//
+// The author email is not checked against the set of verified emails of the
+// author's account, which the GitHub API only discloses to the account itself;
+// a signature by the committer is the only evidence considered.
+func CheckVerifiedEmail(commit *github.RepositoryCommit) CommitStatus {
+	commitStatus := CommitStatus{SHA: commit.GetSHA(), Compliant: true}
+	if authorEmailVerified(commit) {
+		return commitStatus
+	}
+	author := commit.GetCommit().GetAuthor()
+	commitStatus.Compliant = false
+	commitStatus.NonComplianceReason = ReasonAuthorEmailUnverified
+	commitStatus.Unmatched = []UnmatchedIdentity{{
+		Role:    RoleAuthor,
+		Account: config.Account{Name: author.GetName(), Email: author.GetEmail(), Login: AuthorLogin(commit)},
+	}}
+	return commitStatus
+}
+
+// authorEmailVerified returns whether GitHub verified the signature of the
+// commit, and the signature vouches for its author email; see
+// `CheckVerifiedEmail`.
+func authorEmailVerified(commit *github.RepositoryCommit) bool {
+	if !commit.GetCommit().GetVerification().GetVerified() {
+		return false
+	}
+	authorEmail := commit.GetCommit().GetAuthor().GetEmail()
+	committerEmail := commit.GetCommit().GetCommitter().GetEmail()
+	return authorEmail != "" && strings.EqualFold(authorEmail, committerEmail) &&
+		strings.EqualFold(commit.GetAuthor().GetLogin(), commit.GetCommitter().GetLogin())
+}
+
+// RegisterChecker makes a compliance checker available under the given name.
+// It panics if `checker` is nil or if a checker with the same name is already
+// registered; it is intended to be called from `init` functions.
+func RegisterChecker(name string, checker ComplianceChecker) {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	if checker == nil {
+		panic("ghutil: RegisterChecker checker is nil")
+	}
+	if _, dup := checkers[name]; dup {
+		panic("ghutil: RegisterChecker called twice for checker " + name)
+	}
+	checkers[name] = checker
+}
+
+// LookupChecker returns the compliance checker registered under `name`.
+func LookupChecker(name string) (ComplianceChecker, error) {
+	checkersMu.RLock()
+	checker, ok := checkers[name]
+	checkersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown compliance checker '%s'; registered: %v", name, CheckerNames())
+	}
+	return checker, nil
+}
+
+// CheckerNames returns the sorted names of all registered compliance checkers.
+func CheckerNames() []string {
+	checkersMu.RLock()
+	defer checkersMu.RUnlock()
+	var names []string
+	for name := range checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkCommit runs all of the client's compliance checkers against the
+// commit; a commit is compliant only if every checker considers it compliant.
+// If the client has no checkers configured, the default checker is used.
+// If the commit is non-compliant only because some of its identities are not
+// listed as CLA signers, the client's signer lookup, if any, is consulted.
+func checkCommit(ctx context.Context, ghc *GitHubClient, commit *github.RepositoryCommit, claSigners config.ClaSigners) (CommitStatus, error) {
+	commitCheckers := ghc.Checkers
+	if len(commitCheckers) == 0 {
+		defaultChecker, err := LookupChecker(DefaultCheckerName)
+		if err != nil {
+			return CommitStatus{}, err
+		}
+		commitCheckers = []ComplianceChecker{defaultChecker}
+	}
+
+	// The signer lookup is consulted about each failing checker in turn,
+	// so that it can't make the remaining checkers, e.g., the one verifying
+	// author emails, be skipped.
+	var commitStatus CommitStatus
+	for idx, checker := range commitCheckers {
+		status, err := checker.CheckCommit(ctx, commit, claSigners)
+		if err != nil {
+			return status, err
+		}
+		if !status.Compliant && ghc.SignerLookup != nil {
+			status, err = lookupUnmatched(ctx, ghc, status)
+			if err != nil {
+				return status, err
+			}
+		}
+		if idx == 0 || !status.Compliant {
+			commitStatus = status
+		}
+		if !status.Compliant {
+			break
+		}
+	}
+	commitStatus.SHA = commit.GetSHA()
+	return commitStatus, nil
+}
+
+// lookupUnmatched asks the client's signer lookup about each unmatched
+// identity of a non-compliant commit, and considers the commit compliant if
+// all of them have signed the CLA. Identities which are incomplete are never
+// looked up, since they could not have been matched against the CLA signers
+// config either.
+func lookupUnmatched(ctx context.Context, ghc *GitHubClient, commitStatus CommitStatus) (CommitStatus, error) {
+	if !IsNotSignerReason(commitStatus.NonComplianceReason) {
+		return commitStatus, nil
+	}
+	company := commitStatus.Company
+	for _, unmatched := range commitStatus.Unmatched {
+		account := unmatched.Account
+		if account.Name == "" || account.Email == "" || account.Login == "" {
+			return commitStatus, nil
+		}
+		result, err := ghc.SignerLookup.Lookup(ctx, account)
+		if err != nil {
+			return commitStatus, err
+		}
+		if !result.Signed {
+			return commitStatus, nil
+		}
+		if unmatched.Role == RoleAuthor {
+			company = result.Company
+		}
+		logger.Debugf("    %s %s <%s> found via signer lookup", unmatched.Role, account.Name, account.Email)
+	}
+	commitStatus.Compliant = true
+	commitStatus.NonComplianceReason = ""
+	commitStatus.Unmatched = nil
+	commitStatus.Company = company
+	return commitStatus, nil
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/lookup"
+)
+
+func newStaticChecker(compliant bool, reason string, err error) ghutil.ComplianceChecker {
+	return ghutil.ComplianceCheckerFunc(func(_ context.Context, _ *github.RepositoryCommit, _ config.ClaSigners) (ghutil.CommitStatus, error) {
+		return ghutil.CommitStatus{
+			Compliant:           compliant,
+			NonComplianceReason: reason,
+		}, err
+	})
+}
+
+func TestRegisterChecker_Lookup(t *testing.T) {
+	checker := newStaticChecker(true, "", nil)
+	ghutil.RegisterChecker("test-lookup", checker)
+
+	found, err := ghutil.LookupChecker("test-lookup")
+	assert.Nil(t, err)
+	assert.NotNil(t, found)
+	assert.Contains(t, ghutil.CheckerNames(), ghutil.DefaultCheckerName)
+	assert.Contains(t, ghutil.CheckerNames(), "test-lookup")
+
+	assert.Panics(t, func() { ghutil.RegisterChecker("test-lookup", checker) })
+
+	_, err = ghutil.LookupChecker("no-such-checker")
+	assert.NotNil(t, err)
+}
+
+func TestCheckVerifiedEmail(t *testing.T) {
+	john, _ := createUserAccounts()
+	commit := createCommit(john, john)
+
+	commitStatus := ghutil.CheckVerifiedEmail(commit)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonAuthorEmailUnverified, commitStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{{Role: ghutil.RoleAuthor, Account: john}}, commitStatus.Unmatched)
+
+	commit.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+	commitStatus = ghutil.CheckVerifiedEmail(commit)
+	assert.True(t, commitStatus.Compliant)
+	assert.Equal(t, "abc123def456", commitStatus.SHA)
+
+	_, err := ghutil.LookupChecker(ghutil.VerifiedEmailCheckerName)
+	assert.Nil(t, err)
+}
+
+func TestCheckVerifiedEmail_AuthorNotCommitter(t *testing.T) {
+	john, jane := createUserAccounts()
+	// Jane signed a commit claiming John, a CLA signer, as its author; her
+	// signature only vouches for her own email.
+	commit := createCommit(john, jane)
+	commit.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+
+	commitStatus := ghutil.CheckVerifiedEmail(commit)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonAuthorEmailUnverified, commitStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{{Role: ghutil.RoleAuthor, Account: john}}, commitStatus.Unmatched)
+}
+
+func TestCheckVerifiedEmail_CommittedByGitHubRejected(t *testing.T) {
+	john, _ := createUserAccounts()
+	webFlow := config.Account{Name: "GitHub", Email: "noreply@github.com", Login: "web-flow"}
+	commit := createCommit(john, webFlow)
+	commit.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+
+	// GitHub also signs the commits it rebases, keeping their authors.
+	assert.False(t, ghutil.CheckVerifiedEmail(commit).Compliant)
+}
+
+func TestCheckPullRequestCompliance_CustomCheckerOverridesDefault(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	// John isn't listed in the CLA signers, but the custom checker
+	// replaces the default one and accepts everyone.
+	ghc.Checkers = []ghutil.ComplianceChecker{newStaticChecker(true, "", nil)}
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "abc123def456", pullRequestStatus.Commits[0].SHA)
+}
+
+func TestCheckPullRequestCompliance_AllCheckersMustPass(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	defaultChecker, err := ghutil.LookupChecker(ghutil.DefaultCheckerName)
+	assert.Nil(t, err)
+	ghc.Checkers = []ghutil.ComplianceChecker{
+		defaultChecker,
+		newStaticChecker(false, "Not found in internal CLA database.", nil),
+	}
+
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), claSigners)
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "Not found in internal CLA database.", pullRequestStatus.NonComplianceReason)
+}
+
+func TestCheckPullRequestCompliance_CheckerError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	checkerErr := errors.New("CLA database unavailable")
+	ghc.Checkers = []ghutil.ComplianceChecker{newStaticChecker(false, "", checkerErr)}
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Equal(t, checkerErr, err)
+	assert.False(t, pullRequestStatus.Compliant)
+}
+
+// staticLookup answers signer lookups from a map of emails to results.
+type staticLookup struct {
+	results map[string]lookup.Result
+	err     error
+	lookups []string
+}
+
+func (l *staticLookup) Lookup(_ context.Context, account config.Account) (lookup.Result, error) {
+	l.lookups = append(l.lookups, account.Email)
+	return l.results[account.Email], l.err
+}
+
+func TestCheckPullRequestCompliance_SignerLookupFound(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	signerLookup := &staticLookup{
+		results: map[string]lookup.Result{
+			john.Email: {Signed: true, Company: "Acme"},
+		},
+	}
+	ghc.SignerLookup = signerLookup
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "Acme", pullRequestStatus.Commits[0].Company)
+	assert.Equal(t, []string{john.Email, john.Email}, signerLookup.lookups)
+}
+
+func TestCheckPullRequestCompliance_SignerLookupNotFound(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, jane),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	ghc.SignerLookup = &staticLookup{
+		results: map[string]lookup.Result{
+			john.Email: {Signed: true},
+		},
+	}
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCommitterNotSigner, pullRequestStatus.NonComplianceReason)
+}
+
+func TestCheckPullRequestCompliance_SignerLookupSkipsIncompleteIdentity(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	john.Login = ""
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	signerLookup := &staticLookup{}
+	ghc.SignerLookup = signerLookup
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, 0, len(signerLookup.lookups))
+}
+
+func TestCheckPullRequestCompliance_SignerLookupDoesNotSkipCheckers(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The forged, unsigned commit claims John's email, which the signer
+	// lookup confirms; the author email is still checked.
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	for _, name := range []string{ghutil.DefaultCheckerName, ghutil.VerifiedEmailCheckerName} {
+		checker, err := ghutil.LookupChecker(name)
+		assert.Nil(t, err)
+		ghc.Checkers = append(ghc.Checkers, checker)
+	}
+	ghc.SignerLookup = &staticLookup{
+		results: map[string]lookup.Result{
+			john.Email: {Signed: true},
+		},
+	}
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonAuthorEmailUnverified, pullRequestStatus.Commits[0].NonComplianceReason)
+}
+
+func TestCheckPullRequestCompliance_SignerLookupError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	lookupErr := errors.New("CLA service unavailable")
+	ghc.SignerLookup = &staticLookup{err: lookupErr}
+
+	_, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Equal(t, lookupErr, err)
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"strings"
+	"text/template"
+)
+
+// DefaultCommentTemplate is the template used for the comment posted on
+// non-compliant pull requests if no custom template is configured.
+const DefaultCommentTemplate = reasonsTemplate + `
+{{- if .Unsigned}}
+
+The following contributors need to sign the CLA or fix their commit identity; all other contributors to this pull request are already covered:` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `If these commits were made with the wrong name or email address, you can rewrite them with the identity you signed the CLA with by running the following commands on your branch:` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+Please sign the Contributor License Agreement (CLA) at {{.ClaURL}} before we can accept your contribution. Once you've signed it (or fixed any problems above), the CLA status of this pull request will be updated automatically.
+{{- end}}`
+
+// DefaultExternalComment is the comment posted when a PR is labeled as
+// external, if the system managing the external CLA signers is known; `%s` is
+// its name.
+const DefaultExternalComment = "The CLA of the contributors to this pull request is managed by %s; please check its status there."
+
+// reasonsTemplate renders the reason the PR is not compliant or, if several of
+// its commits are not, the reason of each; it is shared by all of the
+// localized templates.
+const reasonsTemplate = `{{if gt (len .Reasons) 1}}{{range $index, $reason := .Reasons}}{{if $index}}
+{{end}}* {{$reason.ShortSHA}}: {{$reason.Reason}}{{end}}{{else}}{{.Reason}}{{end}}`
+
+// unsignedListTemplate renders the list of identities which could not be
+// matched to a CLA signer; it is shared by all of the localized templates.
+const unsignedListTemplate = `{{range .Unsigned}}
+* {{.}}: {{.CommitList}}
+{{- end}}`
+
+// fixCommandsStart and fixCommandsEnd surround the introduction to the
+// `FixCommands`, rendering them only if there are any; they are shared by all
+// of the localized templates.
+const (
+	fixCommandsStart = `
+{{- if .FixCommands}}
+
+`
+	fixCommandsEnd = `
+
+~~~shell
+{{.FixCommands}}
+~~~
+{{- end}}`
+)
+
+// CommentData is the data available to the comment template when rendering
+// the comment posted on a non-compliant pull request. `Locale` selects the
+// translation of the default template and of the built-in reasons.
+type CommentData struct {
+	Org    string
+	Repo   string
+	Number int
+	Author string
+	Reason string
+	ClaURL string
+	Locale string
+
+	// Reasons lists the reason of each non-compliant commit on the PR;
+	// `Reason` is the last of them.
+	Reasons []CommitReason
+
+	// Unsigned lists the identities on the PR which could not be matched
+	// to a CLA signer.
+	Unsigned []IdentityStatus
+
+	// FixCommands are the shell commands which rewrite the commits with
+	// unsigned identities to use the identity the PR author signed the CLA
+	// with, if known (see `FixCommands`).
+	FixCommands string
+}
+
+// ParseCommentTemplate parses the comment template, using
+// `DefaultCommentTemplate` if `text` is empty.
+func ParseCommentTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultCommentTemplate
+	}
+	return template.New("comment").Option("missingkey=error").Parse(text)
+}
+
+// RenderComment renders the comment posted on a non-compliant pull request
+// from the given template text (or the default template for `data.Locale` if
+// empty).
+func RenderComment(text string, data CommentData) (string, error) {
+	if text == "" {
+		text = LocalizedCommentTemplate(data.Locale)
+	}
+	data.Reason = LocalizeReason(data.Locale, data.Reason)
+	reasons := make([]CommitReason, len(data.Reasons))
+	for idx, reason := range data.Reasons {
+		reasons[idx] = CommitReason{SHA: reason.SHA, Reason: LocalizeReason(data.Locale, reason.Reason)}
+	}
+	data.Reasons = reasons
+
+	tmpl, err := ParseCommentTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var comment strings.Builder
+	if err := tmpl.Execute(&comment, data); err != nil {
+		return "", err
+	}
+	return comment.String(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestRenderComment_DefaultWithoutClaURL(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason: "Author is not a CLA signer.",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Author is not a CLA signer.", comment)
+}
+
+func TestRenderComment_DefaultWithClaURL(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason: "Author is not a CLA signer.",
+		ClaURL: "https://cla.example.com",
+	})
+	assert.Nil(t, err)
+	assert.True(t, len(comment) > len("Author is not a CLA signer."))
+	assert.Contains(t, comment, "https://cla.example.com")
+}
+
+func TestRenderComment_DefaultWithUnsigned(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason: "Author is not a CLA signer.",
+		Unsigned: []ghutil.IdentityStatus{
+			{
+				Account: config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane"},
+				Roles:   []string{ghutil.RoleAuthor},
+				Commits: []string{"1111111aaaa", "2222222bbbb"},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Author is not a CLA signer.\n\n"+
+		"The following contributors need to sign the CLA or fix their commit identity; all other contributors to this pull request are already covered:\n"+
+		"* Jane Doe <jane@example.com> (@jane): 1111111, 2222222", comment)
+}
+
+func TestRenderComment_DefaultWithReasons(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason: ghutil.ReasonCommitterNotSigner,
+		Reasons: []ghutil.CommitReason{
+			{SHA: "1111111aaaa", Reason: ghutil.ReasonAuthorNotSigner},
+			{SHA: "2222222bbbb", Reason: ghutil.ReasonCommitterNotSigner},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "* 1111111: "+ghutil.ReasonAuthorNotSigner+"\n"+
+		"* 2222222: "+ghutil.ReasonCommitterNotSigner, comment)
+}
+
+func TestRenderComment_DefaultWithSingleReason(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason:  ghutil.ReasonAuthorNotSigner,
+		Reasons: []ghutil.CommitReason{{SHA: "1111111aaaa", Reason: ghutil.ReasonAuthorNotSigner}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, ghutil.ReasonAuthorNotSigner, comment)
+}
+
+func TestRenderComment_CustomTemplate(t *testing.T) {
+	comment, err := ghutil.RenderComment("@{{.Author}}: {{.Reason}} Sign at {{.ClaURL}} ({{.Org}}/{{.Repo}}#{{.Number}})", ghutil.CommentData{
+		Org:    "org",
+		Repo:   "repo",
+		Number: 42,
+		Author: "jane",
+		Reason: "Missing CLA.",
+		ClaURL: "https://cla.example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "@jane: Missing CLA. Sign at https://cla.example.com (org/repo#42)", comment)
+}
+
+func TestRenderComment_InvalidTemplate(t *testing.T) {
+	_, err := ghutil.ParseCommentTemplate("{{.Reason")
+	assert.NotNil(t, err)
+
+	_, err = ghutil.RenderComment("{{.NoSuchField}}", ghutil.CommentData{})
+	assert.NotNil(t, err)
+}
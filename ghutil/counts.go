@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"sync"
+
+	"github.com/google/code-review-bot/report"
+)
+
+// RunCounts counts what `ProcessOrgRepo` did, e.g., for a summary at the end
+// of the run; it is safe for concurrent use. Labels and comments are only
+// counted once applied to the PRs, i.e., with `UpdateRepo`. Its methods do
+// nothing on a nil `RunCounts`.
+type RunCounts struct {
+	mu     sync.Mutex
+	counts report.RunSummary
+}
+
+// NewRunCounts returns a `RunCounts` with all counts zero.
+func NewRunCounts() *RunCounts {
+	return &RunCounts{}
+}
+
+func (c *RunCounts) add(f func(counts *report.RunSummary)) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f(&c.counts)
+}
+
+func (c *RunCounts) pullProcessed() {
+	c.add(func(counts *report.RunSummary) { counts.PullRequests++ })
+}
+
+func (c *RunCounts) labelsChanged(added int, removed int) {
+	c.add(func(counts *report.RunSummary) {
+		counts.LabelsAdded += added
+		counts.LabelsRemoved += removed
+	})
+}
+
+func (c *RunCounts) commentPosted() {
+	c.add(func(counts *report.RunSummary) { counts.Comments++ })
+}
+
+// Summary returns the counts so far; the errors, API calls and duration of the
+// run are left for the caller to fill in, e.g., from `logging.ErrorCount`.
+func (c *RunCounts) Summary() report.RunSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts
+}
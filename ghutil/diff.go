@@ -0,0 +1,144 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Kinds of changes to a pull request written by `DiffWriter`.
+const (
+	ChangeAddLabel    = "+label"
+	ChangeRemoveLabel = "-label"
+	ChangeAddComment  = "+comment"
+
+	ChangeRequestChanges = "+review"
+	ChangeDismissReview  = "-review"
+
+	ChangeRequestReviewers = "+reviewers"
+
+	ChangeSetStatus = "+status"
+
+	// Changes to issues, e.g., tracking issues, rather than pull requests.
+	ChangeCreateIssue = "+issue"
+	ChangeEditIssue   = "~issue"
+	ChangePinIssue    = "+pin"
+)
+
+// maxDiffCommentLength is the length beyond which comments are truncated in
+// the diff output, which is meant to be skimmed rather than read in full.
+const maxDiffCommentLength = 72
+
+// DiffWriter writes each change applied to a pull request (or which would be
+// applied, without `-update-repo`) as a single line, e.g.:
+//
+//	org/repo#42 +label "cla: no"
+//	org/repo#42 -label "cla: yes"
+//	org/repo#42 +comment "Please sign the Contributor License Agreement…"
+//	org/repo#43 -review "CLA requirements are now satisfied."
+//
+// The changes are also recorded, e.g., for `WritePlan`. It is safe for
+// concurrent use.
+type DiffWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	changes []Change
+}
+
+// Change is a single change to a pull request, as recorded by `DiffWriter`;
+// comments are summarized as in the diff output. Changes to issues are
+// recorded the same way, with `Number` 0 for issues yet to be created.
+type Change struct {
+	Org    string `json:"org"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+}
+
+// NewDiffWriter returns a diff writer writing to `w`, or only recording the
+// changes if `w` is nil.
+func NewDiffWriter(w io.Writer) *DiffWriter {
+	return &DiffWriter{w: w}
+}
+
+// Write writes a single change; it is a no-op on a nil writer.
+func (d *DiffWriter) Write(orgName string, repoName string, pullNumber int, kind string, text string) {
+	if d == nil {
+		return
+	}
+	if kind == ChangeAddComment || kind == ChangeRequestChanges {
+		text = summarizeComment(text)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.changes = append(d.changes, Change{Org: orgName, Repo: repoName, Number: pullNumber, Kind: kind, Text: text})
+	if d.w != nil {
+		fmt.Fprintf(d.w, "%s/%s#%d %s %q\n", orgName, repoName, pullNumber, kind, text)
+	}
+}
+
+// Changes returns the changes written so far, in order.
+func (d *DiffWriter) Changes() []Change {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Change(nil), d.changes...)
+}
+
+// Plan is the action plan of a dry run: the changes which the run would have
+// applied, for review before running it for real.
+type Plan struct {
+	Mutations int      `json:"mutations"`
+	Changes   []Change `json:"changes"`
+}
+
+// WritePlan writes the plan of the given changes as indented JSON.
+func WritePlan(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+	data, err := json.MarshalIndent(Plan{Mutations: len(changes), Changes: changes}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// summarizeComment returns the first line of the comment, truncated to
+// `maxDiffCommentLength` characters.
+func summarizeComment(comment string) string {
+	comment = strings.TrimSpace(comment)
+	truncated := false
+	if idx := strings.Index(comment, "\n"); idx >= 0 {
+		comment = strings.TrimSpace(comment[:idx])
+		truncated = true
+	}
+	if runes := []rune(comment); len(runes) > maxDiffCommentLength {
+		comment = string(runes[:maxDiffCommentLength])
+		truncated = true
+	}
+	if truncated {
+		comment += "…"
+	}
+	return comment
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestDiffWriter(t *testing.T) {
+	var buf bytes.Buffer
+	diff := ghutil.NewDiffWriter(&buf)
+	diff.Write("org", "repo", 42, ghutil.ChangeAddLabel, "cla: no")
+	diff.Write("org", "repo", 42, ghutil.ChangeRemoveLabel, "cla: yes")
+	diff.Write("org", "repo", 42, ghutil.ChangeAddComment, "Author is not a CLA signer.\n\nPlease sign the CLA.")
+	diff.Write("org", "repo", 43, ghutil.ChangeAddComment, strings.Repeat("x", 100))
+
+	assert.Equal(t, `org/repo#42 +label "cla: no"
+org/repo#42 -label "cla: yes"
+org/repo#42 +comment "Author is not a CLA signer.…"
+org/repo#43 +comment "`+strings.Repeat("x", 72)+`…"
+`, buf.String())
+}
+
+func TestDiffWriter_Nil(t *testing.T) {
+	var diff *ghutil.DiffWriter
+	diff.Write("org", "repo", 42, ghutil.ChangeAddLabel, "cla: no")
+}
+
+func TestDiffWriter_RecordOnly(t *testing.T) {
+	diff := ghutil.NewDiffWriter(nil)
+	diff.Write("org", "repo", 42, ghutil.ChangeAddLabel, "cla: no")
+	diff.Write("org", "repo", 42, ghutil.ChangeAddComment, "Author is not a CLA signer.\n\nPlease sign the CLA.")
+
+	assert.Equal(t, []ghutil.Change{
+		{Org: "org", Repo: "repo", Number: 42, Kind: ghutil.ChangeAddLabel, Text: "cla: no"},
+		{Org: "org", Repo: "repo", Number: 42, Kind: ghutil.ChangeAddComment, Text: "Author is not a CLA signer.…"},
+	}, diff.Changes())
+}
+
+func TestWritePlan(t *testing.T) {
+	var buf bytes.Buffer
+	err := ghutil.WritePlan(&buf, []ghutil.Change{
+		{Org: "org", Repo: "repo", Number: 42, Kind: ghutil.ChangeAddLabel, Text: "cla: no"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{
+  "mutations": 1,
+  "changes": [
+    {
+      "org": "org",
+      "repo": "repo",
+      "number": 42,
+      "kind": "+label",
+      "text": "cla: no"
+    }
+  ]
+}
+`, buf.String())
+}
+
+func TestWritePlan_NoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	err := ghutil.WritePlan(&buf, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"mutations\": 0,\n  \"changes\": []\n}\n", buf.String())
+}
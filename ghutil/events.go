@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/events"
+	"github.com/google/code-review-bot/report"
+)
+
+// publishLabelsChanged publishes an event for the labels which were added to
+// and removed from the PR, if any, logging any errors; a failure to publish
+// doesn't fail processing the PR, as the labels have been updated.
+func publishLabelsChanged(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus, added []string, removed []string) {
+	if ghc.Events == nil || (len(added) == 0 && len(removed) == 0) {
+		return
+	}
+	pull := prSpec.Pull
+	event := events.Event{
+		Type:      events.TypeLabelsChanged,
+		Timestamp: time.Now().UTC(),
+		Org:       prSpec.Org,
+		Repo:      prSpec.Repo,
+		Number:    pull.GetNumber(),
+		URL:       pull.GetHTMLURL(),
+		Status:    newReportPullRequest(prSpec, pullRequestStatus).Status(),
+		Reason:    pullRequestStatus.NonComplianceReason,
+		Added:     added,
+		Removed:   removed,
+
+		PreviousStatus: previousStatus(prSpec.Labels, removed),
+	}
+	if err := ghc.Events.Publish(ctx, event); err != nil {
+		logger.Errorf("  Error publishing label changes of PR %d: %v", pull.GetNumber(), err)
+	}
+}
+
+// publishChecked publishes the compliance decision of the PR, if enabled,
+// logging any errors.
+func publishChecked(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus) {
+	if ghc.Events == nil || !ghc.PublishChecks {
+		return
+	}
+	pull := prSpec.Pull
+	event := events.Event{
+		Type:      events.TypeChecked,
+		Timestamp: time.Now().UTC(),
+		Org:       prSpec.Org,
+		Repo:      prSpec.Repo,
+		Number:    pull.GetNumber(),
+		URL:       pull.GetHTMLURL(),
+		Status:    newReportPullRequest(prSpec, pullRequestStatus).Status(),
+		Reason:    pullRequestStatus.NonComplianceReason,
+	}
+	if err := ghc.Events.Publish(ctx, event); err != nil {
+		logger.Errorf("  Error publishing compliance decision of PR %d: %v", pull.GetNumber(), err)
+	}
+}
+
+// previousStatus returns the compliance status which the CLA labels removed
+// from a PR stood for, or an empty string if none of them was removed.
+func previousStatus(labels config.Labels, removed []string) string {
+	labels = ResolveLabels(labels)
+	for _, label := range removed {
+		switch {
+		case strings.EqualFold(label, labels.Compliant):
+			return report.StatusCompliant
+		case strings.EqualFold(label, labels.NonCompliant):
+			return report.StatusNonCompliant
+		case strings.EqualFold(label, labels.External):
+			return report.StatusExternal
+		}
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// safeEmailPattern matches the email addresses which can be embedded in the
+// generated shell commands without quoting.
+var safeEmailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+$`)
+
+// FindSigner returns the individual or corporate CLA signer with the given
+// GitHub login, if any.
+func FindSigner(login string, claSigners config.ClaSigners) (config.Account, bool) {
+	for _, person := range claSigners.People {
+		if MatchLogin(login, person) {
+			return person, true
+		}
+	}
+	for _, company := range claSigners.Companies {
+		for _, person := range company.People {
+			if MatchLogin(login, person) {
+				return person, true
+			}
+		}
+	}
+	return config.Account{}, false
+}
+
+// FixEmailPlaceholder stands in for the email of the CLA signer in the
+// `FixCommands`, which are posted publicly, as the CLA signers config may
+// not be.
+const FixEmailPlaceholder = "<the email on your CLA>"
+
+// FixCommands returns the shell commands which rewrite the commits of the PR
+// made with any of the `unsigned` identities to use the identity of `signer`
+// instead, i.e., the one the PR author signed the CLA with, and then update
+// the PR; the email of the signer is left as `FixEmailPlaceholder` for the
+// author to fill in. Commits authored with an unsigned email are re-authored;
+// all commits from the oldest affected one onwards are re-committed, which
+// also fixes their committer. It returns an empty string if no commits can be
+// fixed this way, e.g., if the signer has no name.
+func FixCommands(unsigned []IdentityStatus, commits []CommitStatus, signer config.Account) string {
+	if signer.Name == "" {
+		return ""
+	}
+
+	affected := make(map[string]bool)
+	var authorEmails []string
+	for _, identity := range unsigned {
+		email := identity.Account.Email
+		if email == "" || (CanonicalizeEmail(email) == CanonicalizeEmail(signer.Email) && identity.Account.Name == signer.Name) {
+			continue
+		}
+		if !safeEmailPattern.MatchString(email) {
+			return ""
+		}
+		for _, role := range identity.Roles {
+			if role == RoleAuthor {
+				authorEmails = append(authorEmails, email)
+			}
+		}
+		for _, sha := range identity.Commits {
+			affected[sha] = true
+		}
+	}
+
+	// Commits are listed oldest first, so the rebase starts at the parent of
+	// the first affected one or, if it has none, at the root.
+	upstream := ""
+	for _, commit := range commits {
+		if affected[commit.SHA] {
+			upstream = commit.SHA + "~1"
+			if commit.Root {
+				upstream = "--root"
+			}
+			break
+		}
+	}
+	if upstream == "" {
+		return ""
+	}
+
+	lines := []string{
+		"git config user.name " + shellQuote(signer.Name),
+		"git config user.email " + shellQuote(FixEmailPlaceholder),
+	}
+	if len(authorEmails) > 0 {
+		amend := fmt.Sprintf(`case "$(git log -1 --format=%%ae)" in %s) git commit --amend --no-edit --reset-author;; esac`, strings.Join(authorEmails, "|"))
+		lines = append(lines, fmt.Sprintf("git rebase --force-rebase --exec %s %s", shellQuote(amend), upstream))
+	} else {
+		lines = append(lines, fmt.Sprintf("git rebase --force-rebase %s", upstream))
+	}
+	lines = append(lines, "git push --force-with-lease")
+	return strings.Join(lines, "\n")
+}
+
+// shellQuote quotes the value as a single shell word.
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
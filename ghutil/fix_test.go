@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+var fixSigner = config.Account{Name: "Jane O'Doe", Email: "jane@example.com", Login: "jane"}
+
+var fixCommits = []ghutil.CommitStatus{
+	{SHA: "1111111111"},
+	{SHA: "2222222222"},
+	{SHA: "3333333333"},
+}
+
+func TestFindSigner(t *testing.T) {
+	claSigners := config.ClaSigners{
+		People: []config.Account{fixSigner},
+		Companies: []config.Company{
+			{Name: "Acme", People: []config.Account{{Name: "John Doe", Email: "john@acme.com", Login: "john"}}},
+		},
+	}
+	signer, ok := ghutil.FindSigner("Jane", claSigners)
+	assert.True(t, ok)
+	assert.Equal(t, fixSigner, signer)
+
+	signer, ok = ghutil.FindSigner("john", claSigners)
+	assert.True(t, ok)
+	assert.Equal(t, "john@acme.com", signer.Email)
+
+	_, ok = ghutil.FindSigner("someone-else", claSigners)
+	assert.False(t, ok)
+}
+
+func TestFixCommands_WrongAuthorEmail(t *testing.T) {
+	unsigned := []ghutil.IdentityStatus{
+		{
+			Account: config.Account{Name: "Jane", Email: "jane@laptop.local"},
+			Roles:   []string{ghutil.RoleAuthor, ghutil.RoleCommitter},
+			Commits: []string{"3333333333", "2222222222"},
+		},
+	}
+	assert.Equal(t, `git config user.name 'Jane O'\''Doe'
+git config user.email '<the email on your CLA>'
+git rebase --force-rebase --exec 'case "$(git log -1 --format=%ae)" in jane@laptop.local) git commit --amend --no-edit --reset-author;; esac' 2222222222~1
+git push --force-with-lease`, ghutil.FixCommands(unsigned, fixCommits, fixSigner))
+}
+
+func TestFixCommands_WrongCommitterOnly(t *testing.T) {
+	unsigned := []ghutil.IdentityStatus{
+		{
+			Account: config.Account{Name: "Jane", Email: "jane@laptop.local"},
+			Roles:   []string{ghutil.RoleCommitter},
+			Commits: []string{"3333333333"},
+		},
+	}
+	assert.Equal(t, `git config user.name 'Jane O'\''Doe'
+git config user.email '<the email on your CLA>'
+git rebase --force-rebase 3333333333~1
+git push --force-with-lease`, ghutil.FixCommands(unsigned, fixCommits, fixSigner))
+}
+
+func TestFixCommands_RootCommit(t *testing.T) {
+	unsigned := []ghutil.IdentityStatus{
+		{
+			Account: config.Account{Name: "Jane", Email: "jane@laptop.local"},
+			Roles:   []string{ghutil.RoleCommitter},
+			Commits: []string{"1111111111"},
+		},
+	}
+	commits := []ghutil.CommitStatus{{SHA: "1111111111", Root: true}, {SHA: "2222222222"}}
+	assert.Equal(t, `git config user.name 'Jane O'\''Doe'
+git config user.email '<the email on your CLA>'
+git rebase --force-rebase --root
+git push --force-with-lease`, ghutil.FixCommands(unsigned, commits, fixSigner))
+}
+
+func TestFixCommands_NothingToFix(t *testing.T) {
+	// The identity matches the signer except for the GitHub login, which
+	// rewriting the commits doesn't fix.
+	unsigned := []ghutil.IdentityStatus{
+		{
+			Account: config.Account{Name: fixSigner.Name, Email: fixSigner.Email},
+			Roles:   []string{ghutil.RoleAuthor},
+			Commits: []string{"1111111111"},
+		},
+	}
+	assert.Equal(t, "", ghutil.FixCommands(unsigned, fixCommits, fixSigner))
+
+	// Emails which would need quoting aren't embedded in commands.
+	unsigned[0].Account.Email = "jane$(rm -rf ~)@example.com"
+	assert.Equal(t, "", ghutil.FixCommands(unsigned, fixCommits, fixSigner))
+}
+
+func TestRenderComment_DefaultWithFixCommands(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason:      "Author is not a CLA signer.",
+		FixCommands: "git push --force-with-lease",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Author is not a CLA signer.\n\n"+
+		"If these commits were made with the wrong name or email address, you can rewrite them with the identity you signed the CLA with by running the following commands on your branch:\n\n"+
+		"~~~shell\ngit push --force-with-lease\n~~~", comment)
+}
+
+func TestCheckPullRequestCompliance_MarksRootCommits(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	root := createCommit(john, john)
+	root.Parents = []github.Commit{}
+	child := createCommit(john, john)
+	child.SHA = github.String("def456abc123")
+	child.Parents = []github.Commit{{SHA: root.SHA}}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return([]*github.RepositoryCommit{root, child}, nil, nil)
+
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(getSinglePullSpec(), config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Commits[0].Root)
+	assert.False(t, pullRequestStatus.Commits[1].Root)
+}
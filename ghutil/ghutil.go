@@ -12,11 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// TODO(mbrukman): in the future, consider using the recently-added
-// `-copyright_filename` flag: https://github.com/golang/mock/pull/234
-//
-//go:generate mockgen -source ghutil.go -destination mock_ghutil.go -package ghutil -self_package github.com/google/code-review-bot/ghutil
-
 // Package ghutil provides utility methods for determining CLA compliance of
 // pull requests on GitHub repositories, and adding/removing labels and
 // comments.
@@ -26,21 +21,102 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v21/github"
 
 	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/events"
 	"github.com/google/code-review-bot/logging"
+	"github.com/google/code-review-bot/lookup"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/code-review-bot/state"
 )
 
-// The CLA-related labels we expect to be predefined on a given repository.
+// logger logs on behalf of this package, so that applications embedding it
+// can route or filter its output by module.
+var logger = logging.New("ghutil")
+
+// The default names of the CLA-related labels we expect to be predefined on a
+// given repository; these may be overridden via `config.Labels`.
 const (
 	LabelClaYes      = "cla: yes"
 	LabelClaNo       = "cla: no"
 	LabelClaExternal = "cla: external"
 )
 
+// ResolveLabels returns the configured label names, using the default
+// `LabelCla*` names for any which are unset.
+func ResolveLabels(labels config.Labels) config.Labels {
+	if labels.Compliant == "" {
+		labels.Compliant = LabelClaYes
+	}
+	if labels.NonCompliant == "" {
+		labels.NonCompliant = LabelClaNo
+	}
+	if labels.External == "" {
+		labels.External = LabelClaExternal
+	}
+	return labels
+}
+
+// Built-in non-compliance reasons reported by `ProcessCommit`; these also
+// serve as the keys for their translations in other locales.
+const (
+	ReasonAuthorIdentity     = "Please verify the author name, email, and GitHub username association are all correct and match CLA records."
+	ReasonCommitterIdentity  = "Please verify the committer name, email, and GitHub username association are all correct and match CLA records."
+	ReasonAuthorNotSigner    = "Author of one or more commits is not listed as a CLA signer, either individual or as a member of an organization."
+	ReasonCommitterNotSigner = "Committer of one or more commits is not listed as a CLA signer, either individual or as a member of an organization."
+	ReasonCoAuthorNotSigner  = "Co-author of one or more commits, named in a Co-authored-by trailer, is not listed as a CLA signer, either individual or as a member of an organization."
+
+	ReasonAuthorNameMismatch     = "The author name of one or more commits does not match the name in the CLA record with the same email address or GitHub username."
+	ReasonAuthorEmailMismatch    = "The author email of one or more commits does not match the email address in the CLA record with the same GitHub username."
+	ReasonAuthorLoginMismatch    = "The author GitHub username of one or more commits does not match the username in the CLA record with the same email address."
+	ReasonCommitterNameMismatch  = "The committer name of one or more commits does not match the name in the CLA record with the same email address or GitHub username."
+	ReasonCommitterEmailMismatch = "The committer email of one or more commits does not match the email address in the CLA record with the same GitHub username."
+	ReasonCommitterLoginMismatch = "The committer GitHub username of one or more commits does not match the username in the CLA record with the same email address."
+
+	// ReasonAuthorEmailUnverified is reported by `CheckVerifiedEmail`.
+	ReasonAuthorEmailUnverified = "The author email of one or more commits could not be verified as belonging to the author's GitHub account; please sign the commits with a key added to that account."
+)
+
+// mismatchReasons maps each role and identity field to the reason reported
+// when the field is the first one which differs from the closest CLA record.
+var mismatchReasons = map[string]map[string]string{
+	RoleAuthor: {
+		FieldName:  ReasonAuthorNameMismatch,
+		FieldEmail: ReasonAuthorEmailMismatch,
+		FieldLogin: ReasonAuthorLoginMismatch,
+	},
+	RoleCommitter: {
+		FieldName:  ReasonCommitterNameMismatch,
+		FieldEmail: ReasonCommitterEmailMismatch,
+		FieldLogin: ReasonCommitterLoginMismatch,
+	},
+}
+
+// IsNotSignerReason returns whether the reason reported by `ProcessCommit`
+// means that an identity of the commit is complete, but did not match any of
+// the CLA signers, as opposed to being incomplete.
+func IsNotSignerReason(reason string) bool {
+	if reason == ReasonAuthorNotSigner || reason == ReasonCommitterNotSigner || reason == ReasonCoAuthorNotSigner {
+		return true
+	}
+	for _, fieldReasons := range mismatchReasons {
+		for _, fieldReason := range fieldReasons {
+			if reason == fieldReason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // OrganizationsService is the subset of `github.OrganizationsService` used by
 // this module.
 type OrganizationsService interface {
@@ -49,17 +125,43 @@ type OrganizationsService interface {
 // RepositoriesService is the subset of `github.RepositoriesService` used by
 // this module.
 type RepositoriesService interface {
+	CompareCommits(ctx context.Context, owner string, repo string, base string, head string) (*github.CommitsComparison, *github.Response, error)
+	CreateStatus(ctx context.Context, owner string, repo string, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
 	Get(ctx context.Context, owner string, repo string) (*github.Repository, *github.Response, error)
+	GetCommit(ctx context.Context, owner string, repo string, sha string) (*github.RepositoryCommit, *github.Response, error)
+	GetContents(ctx context.Context, owner string, repo string, path string, opt *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
 	List(ctx context.Context, user string, opt *github.RepositoryListOptions) ([]*github.Repository, *github.Response, error)
 }
 
 // IssuesService is the subset of `github.IssuesService` used by this module.
 type IssuesService interface {
-	AddLabelsToIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
+	Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
 	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	CreateLabel(ctx context.Context, owner string, repo string, label *github.Label) (*github.Label, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	EditLabel(ctx context.Context, owner string, repo string, name string, label *github.Label) (*github.Label, *github.Response, error)
 	GetLabel(ctx context.Context, owner string, repo string, name string) (*github.Label, *github.Response, error)
+	ListByRepo(ctx context.Context, owner string, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	ListComments(ctx context.Context, owner string, repo string, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
 	ListLabelsByIssue(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error)
-	RemoveLabelForIssue(ctx context.Context, owner string, repo string, number int, label string) (*github.Response, error)
+	ReplaceLabelsForIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
+}
+
+// ChecksService is the subset of `github.ChecksService` used by this module.
+type ChecksService interface {
+	CreateCheckRun(ctx context.Context, owner string, repo string, opt github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// UsersService is the subset of `github.UsersService` used by this module.
+type UsersService interface {
+	Get(ctx context.Context, user string) (*github.User, *github.Response, error)
+}
+
+// GraphQLService is the subset of the GitHub GraphQL API used by this module,
+// for operations which have no REST equivalent.
+type GraphQLService interface {
+	MinimizeComment(ctx context.Context, subjectID string, classifier string) error
+	PinIssue(ctx context.Context, issueID string) error
 }
 
 // PullRequestsService is the subset of `github.PullRequestsService` used by
@@ -68,39 +170,80 @@ type PullRequestsService interface {
 	List(ctx context.Context, owner string, repo string, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
 	ListCommits(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error)
 	Get(ctx context.Context, owner string, repo string, number int) (*github.PullRequest, *github.Response, error)
+	ListReviews(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	CreateReview(ctx context.Context, owner string, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	DismissReview(ctx context.Context, owner string, repo string, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error)
+	RequestReviewers(ctx context.Context, owner string, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error)
 }
 
-// GitHubUtilApi is the locally-defined API for interfacing with GitHub, using
-// the methods in GitHubClient.
+// GitHubUtilApi is the locally-defined API for interfacing with GitHub,
+// implemented by `GitHubClient`.
 type GitHubUtilApi interface {
-	GetAllRepos(*GitHubClient, string, string) []*github.Repository
-	CheckPullRequestCompliance(*GitHubClient, GitHubProcessSinglePullSpec, config.ClaSigners) (PullRequestStatus, error)
-	ProcessPullRequest(*GitHubClient, GitHubProcessSinglePullSpec, config.ClaSigners, RepoClaLabelStatus) error
-	ProcessOrgRepo(*GitHubClient, GitHubProcessOrgRepoSpec, config.ClaSigners)
-	GetIssueClaLabelStatus(*GitHubClient, string, string, int) IssueClaLabelStatus
-	GetRepoClaLabelStatus(*GitHubClient, string, string) RepoClaLabelStatus
+	GetAllRepos(orgName string, repoName string) ([]*github.Repository, error)
+	CheckPullRequestCompliance(prSpec GitHubProcessSinglePullSpec, claSigners config.ClaSigners) (PullRequestStatus, error)
+	ProcessPullRequest(prSpec GitHubProcessSinglePullSpec, claSigners config.ClaSigners, repoClaLabelStatus RepoClaLabelStatus) error
+	ProcessOrgRepo(repoSpec GitHubProcessOrgRepoSpec, claSigners config.ClaSigners) (*Checkpoint, error)
+	GetIssueClaLabelStatus(orgName string, repoName string, pullNumber int, labels config.Labels) IssueClaLabelStatus
+	GetRepoClaLabelStatus(orgName string, repoName string, labels config.Labels) RepoClaLabelStatus
+	GetRepoConfig(orgName string, repoName string) (config.RepoConfig, error)
+	GetOrgConfig(orgName string) (config.OrgConfig, error)
 }
 
 // GitHubClient provides an interface to the GitHub APIs used in this module.
 type GitHubClient struct {
-	// Note: we can't simply use `*GitHubUtilApi` to import all the
-	// interface methods here, as they will not be assignable fields and
-	// compile will error out with:
-	//
-	//     cannot use promoted field GitHubUtilApi.GetAllRepos in struct literal of type GitHubClient
-	//
-	// for each of the methods listed here.
-	GetAllRepos                func(*GitHubClient, string, string) []*github.Repository
-	CheckPullRequestCompliance func(*GitHubClient, GitHubProcessSinglePullSpec, config.ClaSigners) (PullRequestStatus, error)
-	ProcessPullRequest         func(*GitHubClient, GitHubProcessSinglePullSpec, config.ClaSigners, RepoClaLabelStatus) error
-	ProcessOrgRepo             func(*GitHubClient, GitHubProcessOrgRepoSpec, config.ClaSigners)
-	GetIssueClaLabelStatus     func(*GitHubClient, string, string, int) IssueClaLabelStatus
-	GetRepoClaLabelStatus      func(*GitHubClient, string, string) RepoClaLabelStatus
+	// Api, if non-nil, is used by the methods of `GitHubUtilApi` to call each
+	// other, e.g., by `ProcessOrgRepo` to process each PR, instead of the
+	// client itself; tests replace it with a mock.
+	Api GitHubUtilApi
 
 	Organizations OrganizationsService
 	Repositories  RepositoriesService
 	Issues        IssuesService
 	PullRequests  PullRequestsService
+	Checks        ChecksService
+	Users         UsersService
+	GraphQL       GraphQLService
+
+	// Checkers are the compliance checkers run against each commit; if
+	// empty, only the checker registered as `DefaultCheckerName` is used.
+	Checkers []ComplianceChecker
+
+	// SignerLookup, if non-nil, is consulted about identities which don't
+	// match any of the CLA signers, e.g., an external CLA service.
+	SignerLookup lookup.SignerLookup
+
+	// Report, if non-nil, accumulates the compliance results of each pull
+	// request processed by this client.
+	Report *report.Report
+
+	// Usage, if non-nil, tracks the API calls made by this client.
+	Usage *UsageTransport
+
+	// Diff, if non-nil, receives each change to a pull request made by this
+	// client, or which it would make if updating the repo were enabled.
+	Diff *DiffWriter
+
+	// State, if non-nil, remembers what this client has done to each pull
+	// request across runs, e.g., for sending reminders.
+	State state.Store
+
+	// Events, if non-nil, receives an event for each pull request whose
+	// labels this client changes.
+	Events events.Publisher
+	// PublishChecks also publishes an event with the compliance decision
+	// of each pull request checked, rather than only label changes.
+	PublishChecks bool
+
+	// RepoLabels, if non-nil, caches which CLA labels each repo defines
+	// between calls to `GetRepoClaLabelStatus`.
+	RepoLabels *RepoLabelCache
+
+	// Progress, if non-nil, tracks how far `ProcessOrgRepo` has got.
+	Progress *Progress
+
+	// Counts, if non-nil, counts the PRs processed by `ProcessOrgRepo`,
+	// and the labels, comments, and errors of each.
+	Counts *RunCounts
 }
 
 // GitHubProcessOrgRepoSpec is the specification of the work to be done for an
@@ -111,6 +254,46 @@ type GitHubProcessOrgRepoSpec struct {
 	Pulls             []int
 	UpdateRepo        bool
 	UnknownAsExternal bool
+	SkipForks         bool
+	BaseBranches      []string
+	TrustLevel        string
+	ClaURL            string
+	CommentTemplate   string
+	Locale            string
+	RepoLocales       map[string]string
+	Labels            config.Labels
+	RequestChanges    bool
+	SkipLabels        bool
+	NoComments        bool
+	MaxCommentsPerSHA int
+	Reminders         config.Reminders
+	Reviewers         config.Reviewers
+	CommitStatus      config.CommitStatus
+	MinimizeComments  bool
+	TrackingIssue     config.TrackingIssue
+	Trivial           config.TrivialPolicy
+	PullOrder         string
+
+	// MaxAPICalls, if positive, is the budget of GitHub API calls for the
+	// run, checked before each repo and PR; once exhausted, processing
+	// stops and a `Checkpoint` is returned. It requires `ghc.Usage`.
+	MaxAPICalls int
+	// MaxPulls, if positive, is the number of PRs to process in the run,
+	// across all repos; once reached, processing stops and a `Checkpoint`
+	// is returned, as with `MaxAPICalls`.
+	MaxPulls int
+	// ResumeFrom, if non-nil, is the checkpoint of a previous run at which
+	// processing resumes; repos and PRs before it are skipped.
+	ResumeFrom *Checkpoint
+}
+
+// Checkpoint identifies the point at which processing of an org stopped
+// early, so that a later run can resume from there. `Pull` is the number of
+// the next PR to process in `Repo`, or zero to start at its first PR.
+type Checkpoint struct {
+	Org  string `json:"org"`
+	Repo string `json:"repo"`
+	Pull int    `json:"pull,omitempty"`
 }
 
 // GitHubProcessSinglePullSpec is the specification of work to be processed for
@@ -122,18 +305,86 @@ type GitHubProcessSinglePullSpec struct {
 	Pull              *github.PullRequest
 	UpdateRepo        bool
 	UnknownAsExternal bool
+	ClaURL            string
+	CommentTemplate   string
+	Locale            string
+	Labels            config.Labels
+
+	// BaseBranches, if non-empty, restricts processing to PRs whose base
+	// branch matches one of these branch names or glob patterns, e.g.,
+	// "main" or "release-*"; other PRs are skipped.
+	BaseBranches []string
+
+	// TrustLevel is the evidence commits need for their identities to be
+	// trusted; see `TrustLevels`. If empty, matching CLA signers suffices.
+	TrustLevel string
+
+	// RequestChanges submits a review requesting changes on non-compliant
+	// PRs (carrying the comment which would otherwise be posted), which is
+	// dismissed once the PR becomes compliant; SkipLabels disables the
+	// CLA labels, leaving the review as the only signal.
+	RequestChanges bool
+	SkipLabels     bool
+
+	// NoComments disables commenting on non-compliant PRs, including
+	// reminders, leaving the CLA labels (and the review, with
+	// `RequestChanges`) as the only signal.
+	NoComments bool
+
+	// MaxCommentsPerSHA, if positive, caps the number of comments
+	// explaining why the PR is not compliant which are posted while its
+	// head commit stays the same, so that labels flapping between runs
+	// can't flood it with identical comments; it requires the client to
+	// have a state store. Reminders are not counted.
+	MaxCommentsPerSHA int
+
+	// Reminders configures re-pinging the author of a non-compliant PR;
+	// it requires the client to have a state store.
+	Reminders config.Reminders
+
+	// Reviewers are requested to review the PR when it becomes compliant,
+	// as recorded in the client's state store, if any, or else when it is
+	// labeled as compliant.
+	Reviewers config.Reviewers
+	// CommitStatus, if enabled, reports the compliance of the PR as a
+	// commit status on its head commit, which is pending while the PR is
+	// being checked.
+	CommitStatus config.CommitStatus
+	// MinimizeComments marks the comments posted on the PR, and collapses
+	// the earlier ones as outdated whenever a new one is posted.
+	MinimizeComments bool
+	// Trivial exempts trivial documentation changes; checking a commit
+	// against it requires an extra API call, which is only made for
+	// commits which are otherwise non-compliant.
+	Trivial config.TrivialPolicy
+	// ClaSignersDigest identifies the CLA signers the PR is checked
+	// against (see `ClaSignersDigest`); if set, and the client has a state
+	// store, PRs whose head commit and labels haven't changed since they
+	// were last processed with the same signers and settings are skipped.
+	ClaSignersDigest string
 }
 
-// NewClient creates a client to work with the GitHub API.
-func NewClient(tc *http.Client) *GitHubClient {
+// DefaultUserAgent is the user agent sent with GitHub API requests unless
+// overridden via `NewClient`.
+const DefaultUserAgent = "cla-helper"
+
+// NewClient creates a client to work with the GitHub API, identifying itself
+// with the given user agent, or `DefaultUserAgent` if empty.
+func NewClient(tc *http.Client, userAgent string) *GitHubClient {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
 	client := github.NewClient(tc)
-	client.UserAgent = "cla-helper"
+	client.UserAgent = userAgent
 
 	ghc := NewBasicClient()
 	ghc.Organizations = client.Organizations
 	ghc.PullRequests = client.PullRequests
 	ghc.Issues = client.Issues
 	ghc.Repositories = client.Repositories
+	ghc.Checks = client.Checks
+	ghc.Users = client.Users
+	ghc.GraphQL = &graphQLClient{client: client}
 
 	return ghc
 }
@@ -144,16 +395,64 @@ func NewClient(tc *http.Client) *GitHubClient {
 // with additional bindings added in `NewClient` or for testing by assigning
 // mocked methods for the other services.
 func NewBasicClient() *GitHubClient {
-	ghc := GitHubClient{
-		GetAllRepos:                getAllRepos,
-		CheckPullRequestCompliance: checkPullRequestCompliance,
-		ProcessPullRequest:         processPullRequest,
-		ProcessOrgRepo:             processOrgRepo,
-		GetIssueClaLabelStatus:     getIssueClaLabelStatus,
-		GetRepoClaLabelStatus:      getRepoClaLabelStatus,
+	return &GitHubClient{}
+}
+
+// Verify that GitHubClient implements GitHubUtilApi.
+var _ GitHubUtilApi = (*GitHubClient)(nil)
+
+// api returns the implementation through which the methods of the client call
+// each other.
+func (ghc *GitHubClient) api() GitHubUtilApi {
+	if ghc.Api != nil {
+		return ghc.Api
 	}
+	return ghc
+}
+
+// GetAllRepos retrieves the repos selected by `repoName`; see `getAllRepos`.
+func (ghc *GitHubClient) GetAllRepos(orgName string, repoName string) ([]*github.Repository, error) {
+	return getAllRepos(ghc, orgName, repoName)
+}
 
-	return &ghc
+// CheckPullRequestCompliance checks the CLA compliance of all commits of a PR;
+// see `checkPullRequestCompliance`.
+func (ghc *GitHubClient) CheckPullRequestCompliance(prSpec GitHubProcessSinglePullSpec, claSigners config.ClaSigners) (PullRequestStatus, error) {
+	return checkPullRequestCompliance(ghc, prSpec, claSigners)
+}
+
+// ProcessPullRequest checks a PR and updates its labels and comments; see
+// `processPullRequest`.
+func (ghc *GitHubClient) ProcessPullRequest(prSpec GitHubProcessSinglePullSpec, claSigners config.ClaSigners, repoClaLabelStatus RepoClaLabelStatus) error {
+	return processPullRequest(ghc, prSpec, claSigners, repoClaLabelStatus)
+}
+
+// ProcessOrgRepo processes all PRs of the repos selected by the spec; see
+// `processOrgRepo`.
+func (ghc *GitHubClient) ProcessOrgRepo(repoSpec GitHubProcessOrgRepoSpec, claSigners config.ClaSigners) (*Checkpoint, error) {
+	return processOrgRepo(ghc, repoSpec, claSigners)
+}
+
+// GetIssueClaLabelStatus returns which CLA labels a PR has; see
+// `getIssueClaLabelStatus`.
+func (ghc *GitHubClient) GetIssueClaLabelStatus(orgName string, repoName string, pullNumber int, labels config.Labels) IssueClaLabelStatus {
+	return getIssueClaLabelStatus(ghc, orgName, repoName, pullNumber, labels)
+}
+
+// GetRepoClaLabelStatus returns which CLA labels a repo defines; see
+// `getRepoClaLabelStatus`.
+func (ghc *GitHubClient) GetRepoClaLabelStatus(orgName string, repoName string, labels config.Labels) RepoClaLabelStatus {
+	return getRepoClaLabelStatus(ghc, orgName, repoName, labels)
+}
+
+// GetRepoConfig reads the config file of a repo; see `getRepoConfig`.
+func (ghc *GitHubClient) GetRepoConfig(orgName string, repoName string) (config.RepoConfig, error) {
+	return getRepoConfig(ghc, orgName, repoName)
+}
+
+// GetOrgConfig reads the org-wide config file; see `getOrgConfig`.
+func (ghc *GitHubClient) GetOrgConfig(orgName string) (config.OrgConfig, error) {
+	return getOrgConfig(ghc, orgName)
 }
 
 // AuthorLogin retrieves the author from a `RepositoryCommit`.
@@ -185,22 +484,145 @@ func CommitterLogin(c *github.RepositoryCommit) string {
 	return ""
 }
 
-// getAllRepos retrieves either a single repository (if `repoName` is non-empty)
-// or all repositories in an organization of `repoName` is empty.
-func getAllRepos(ghc *GitHubClient, orgName string, repoName string) []*github.Repository {
+// listRepos returns all repos in the organization, following pagination.
+func listRepos(ctx context.Context, ghc *GitHubClient, orgName string) ([]*github.Repository, error) {
+	var repos []*github.Repository
+	opt := &github.RepositoryListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := ghc.Repositories.List(ctx, orgName, opt)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+		if resp == nil || resp.NextPage == 0 {
+			return repos, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// getAllRepos retrieves the repositories selected by `repoName` (see
+// `ParseRepoSelector`), or all repositories in the organization if `repoName`
+// is empty. Repos named literally are looked up individually, while patterns
+// are matched against the list of all repos in the organization.
+func getAllRepos(ghc *GitHubClient, orgName string, repoName string) ([]*github.Repository, error) {
 	ctx := context.Background()
-	if repoName == "" {
-		repos, _, err := ghc.Repositories.List(ctx, orgName, nil)
+	selectors := ParseRepoSelector(repoName)
+	if len(selectors) == 0 || hasRepoPattern(selectors) {
+		repos, err := listRepos(ctx, ghc, orgName)
+		if err != nil {
+			return nil, fmt.Errorf("error listing all repos in org %s: %s", orgName, err)
+		}
+		if len(selectors) == 0 {
+			return repos, nil
+		}
+		var selected []*github.Repository
+		for _, repo := range repos {
+			if MatchRepo(selectors, repo.GetName()) {
+				selected = append(selected, repo)
+			}
+		}
+		return selected, nil
+	}
+	// A repo which can't be looked up, e.g., as it was deleted, shouldn't
+	// prevent processing the others; it is an error only if none can be.
+	var repos []*github.Repository
+	var lastErr error
+	for _, selector := range selectors {
+		repo, _, err := ghc.Repositories.Get(ctx, orgName, selector)
 		if err != nil {
-			logging.Fatalf("Error listing all repos in org %s: %s", orgName, err)
+			lastErr = fmt.Errorf("error looking up %s/%s: %s", orgName, selector, err)
+			logError(ghc, "Error looking up %s/%s; skipping it: %s", orgName, selector, err)
+			continue
 		}
-		return repos
+		repos = append(repos, repo)
 	}
-	repo, _, err := ghc.Repositories.Get(ctx, orgName, repoName)
-	if err != nil {
-		logging.Fatalf("Error looking up %s/%s: %s", orgName, repoName, err)
+	if len(repos) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return repos, nil
+}
+
+// ParseRepoSelector splits a comma-separated list of repo names and glob
+// patterns (as in `path.Match`), e.g., "cloud-*,infra-*,website".
+func ParseRepoSelector(repoSelector string) []string {
+	var selectors []string
+	for _, selector := range strings.Split(repoSelector, ",") {
+		if selector = strings.TrimSpace(selector); selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+	return selectors
+}
+
+// hasRepoPattern returns whether any of the selectors is a glob pattern rather
+// than a repo name.
+func hasRepoPattern(selectors []string) bool {
+	for _, selector := range selectors {
+		if strings.ContainsAny(selector, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRepo returns whether the repo name matches any of the selectors, which
+// are repo names or glob patterns; like repo names, they ignore case.
+func MatchRepo(selectors []string, repoName string) bool {
+	for _, selector := range selectors {
+		if matched, _ := path.Match(strings.ToLower(selector), strings.ToLower(repoName)); matched {
+			return true
+		}
 	}
-	return []*github.Repository{repo}
+	return false
+}
+
+// MatchBranch returns whether the branch name matches any of the patterns,
+// which are branch names or glob patterns (as in `path.Match`), e.g.,
+// "release-*".
+func MatchBranch(patterns []string, branchName string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branchName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePullURL returns the org, repo, and number of the PR at the given URL,
+// e.g., "https://github.com/org/repo/pull/123", as copied from the browser;
+// any other host is accepted as well, for GitHub Enterprise, and trailing path
+// elements (e.g., "/files"), queries, and fragments are ignored.
+func ParsePullURL(pullURL string) (string, string, int, error) {
+	u, err := url.Parse(pullURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", 0, fmt.Errorf("'%s' is not a PR URL", pullURL)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "pull" {
+		return "", "", 0, fmt.Errorf("'%s' is not a PR URL", pullURL)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("'%s' is not a PR URL", pullURL)
+	}
+	return parts[0], parts[1], number, nil
+}
+
+// RepoSkipReason returns the reason the given repo should not be processed, or
+// an empty string if it should be. Archived repos are read-only, so any attempt
+// to label or comment on them fails; forks are skipped only if requested.
+//
+// Note: GitHub also reports disabled repos, but the version of the API client
+// in use does not expose that attribute, so they are not filtered here.
+func RepoSkipReason(repo *github.Repository, skipForks bool) string {
+	if repo.GetArchived() {
+		return "repo is archived"
+	}
+	if skipForks && repo.GetFork() {
+		return "repo is a fork"
+	}
+	return ""
 }
 
 // RepoClaLabelStatus provides the availability of CLA-related labels in the repo.
@@ -212,16 +634,31 @@ type RepoClaLabelStatus struct {
 
 // getRepoClaLabelStatus checks whether the given GitHub repo has the
 // CLA-related labels defined.
-func getRepoClaLabelStatus(ghc *GitHubClient, orgName string, repoName string) (repoClaLabelStatus RepoClaLabelStatus) {
+func getRepoClaLabelStatus(ghc *GitHubClient, orgName string, repoName string, labels config.Labels) (repoClaLabelStatus RepoClaLabelStatus) {
+	labels = ResolveLabels(labels)
+	if ghc.RepoLabels != nil {
+		if cached, ok := ghc.RepoLabels.Get(orgName, repoName, labels); ok {
+			return cached
+		}
+	}
 	ctx := context.Background()
+	// Only definite answers are cached, so that a transient error doesn't
+	// stop the bot from labeling the repo's PRs until the cache expires.
+	cacheable := true
 	repoHasLabel := func(labelName string) bool {
 		label, _, err := ghc.Issues.GetLabel(ctx, orgName, repoName, labelName)
+		if err != nil && !isNotFound(err) {
+			cacheable = false
+		}
 		return label != nil && err == nil
 	}
 
-	repoClaLabelStatus.HasYes = repoHasLabel(LabelClaYes)
-	repoClaLabelStatus.HasNo = repoHasLabel(LabelClaNo)
-	repoClaLabelStatus.HasExternal = repoHasLabel(LabelClaExternal)
+	repoClaLabelStatus.HasYes = repoHasLabel(labels.Compliant)
+	repoClaLabelStatus.HasNo = repoHasLabel(labels.NonCompliant)
+	repoClaLabelStatus.HasExternal = repoHasLabel(labels.External)
+	if ghc.RepoLabels != nil && cacheable {
+		ghc.RepoLabels.Put(orgName, repoName, labels, repoClaLabelStatus)
+	}
 	return
 }
 
@@ -231,29 +668,67 @@ type IssueClaLabelStatus struct {
 	HasYes      bool
 	HasNo       bool
 	HasExternal bool
+
+	// Labels are the names of all labels of the issue, which are kept
+	// when its CLA labels are replaced, or nil if they couldn't be listed.
+	Labels []string
 }
 
 // getIssueClaLabelStatus computes the settings of CLA-related Labels for a
 // specific issue.
-func getIssueClaLabelStatus(ghc *GitHubClient, orgName string, repoName string, pullNumber int) (issueClaLabelStatus IssueClaLabelStatus) {
+func getIssueClaLabelStatus(ghc *GitHubClient, orgName string, repoName string, pullNumber int, labels config.Labels) (issueClaLabelStatus IssueClaLabelStatus) {
+	claLabels := ResolveLabels(labels)
 	ctx := context.Background()
-	labels, _, err := ghc.Issues.ListLabelsByIssue(ctx, orgName, repoName, pullNumber, nil)
+	issueLabels, err := listIssueLabels(ctx, ghc, orgName, repoName, pullNumber)
 	if err != nil {
-		logging.Errorf("Error listing labels for repo '%s/%s, PR %d: %v", orgName, repoName, pullNumber, err)
+		logger.Errorf("Error listing labels for repo '%s/%s, PR %d: %v", orgName, repoName, pullNumber, err)
 		return
 	}
-	for _, label := range labels {
-		if strings.EqualFold(*label.Name, LabelClaYes) {
+	issueClaLabelStatus.Labels = issueLabels
+	for _, label := range issueLabels {
+		if strings.EqualFold(label, claLabels.Compliant) {
 			issueClaLabelStatus.HasYes = true
-		} else if strings.EqualFold(*label.Name, LabelClaNo) {
+		} else if strings.EqualFold(label, claLabels.NonCompliant) {
 			issueClaLabelStatus.HasNo = true
-		} else if strings.EqualFold(*label.Name, LabelClaExternal) {
+		} else if strings.EqualFold(label, claLabels.External) {
 			issueClaLabelStatus.HasExternal = true
 		}
 	}
 	return
 }
 
+// listIssueLabels returns the names of all labels of the issue, reading all
+// pages of them, since replacing the labels of an issue with only those on
+// the first page would remove the rest.
+func listIssueLabels(ctx context.Context, ghc *GitHubClient, orgName string, repoName string, number int) ([]string, error) {
+	names := []string{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := ghc.Issues.ListLabelsByIssue(ctx, orgName, repoName, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return names, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// HasLabel returns whether the PR, as listed or retrieved via the API, carries
+// the label.
+func HasLabel(pull *github.PullRequest, label string) bool {
+	for _, pullLabel := range pull.Labels {
+		if strings.EqualFold(pullLabel.GetName(), label) {
+			return true
+		}
+	}
+	return false
+}
+
 // CanonicalizeEmail returns a canonical version of the email address. For all
 // addresses, it will lowercase the email. For Gmail addresses, it will also
 // remove the periods in the email address, as those are ignored, and hence
@@ -271,37 +746,384 @@ func CanonicalizeEmail(email string) string {
 	return email
 }
 
+// noreplyEmailSuffix is the domain of the private email addresses GitHub uses
+// for commits by users who have enabled email privacy.
+const noreplyEmailSuffix = "@users.noreply.github.com"
+
+// NoreplyLogin returns the GitHub login embedded in a GitHub noreply email
+// address, in either the current "12345+login@users.noreply.github.com" form
+// or the older "login@users.noreply.github.com" form; the second return value
+// reports whether the email is such an address at all.
+func NoreplyLogin(email string) (string, bool) {
+	email = strings.ToLower(email)
+	if !strings.HasSuffix(email, noreplyEmailSuffix) {
+		return "", false
+	}
+	login := strings.TrimSuffix(email, noreplyEmailSuffix)
+	if idx := strings.Index(login, "+"); idx >= 0 {
+		login = login[idx+1:]
+	}
+	return login, login != ""
+}
+
+// matchEmail returns whether the email address used in a commit matches the
+// email address of a CLA signer. A GitHub noreply address matches if it embeds
+// the signer's login, since contributors with email privacy enabled cannot be
+// expected to list their private address in their commits.
+func matchEmail(email string, signer config.Account) bool {
+	if CanonicalizeEmail(email) == CanonicalizeEmail(signer.Email) {
+		return true
+	}
+	login, ok := NoreplyLogin(email)
+	return ok && MatchLogin(login, signer)
+}
+
+// MatchLogin returns whether the GitHub login belongs to the given account,
+// either as its primary login or as one of its aliases; GitHub logins are
+// case-insensitive.
+func MatchLogin(login string, account config.Account) bool {
+	if login == "" {
+		return false
+	}
+	if strings.EqualFold(login, account.Login) {
+		return true
+	}
+	for _, alias := range account.Aliases {
+		if strings.EqualFold(login, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFields returns whether the name, email, and login of the account match
+// those of the signer. Unless `strict` is set, emails are compared in their
+// canonical form (see `matchEmail`), and logins case-insensitively, including
+// the signer's aliases; otherwise, both must be identical.
+func matchFields(account config.Account, signer config.Account, strict bool) (name bool, email bool, login bool) {
+	name = account.Name == signer.Name
+	if strict {
+		return name, account.Email == signer.Email, account.Login == signer.Login
+	}
+	return name, matchEmail(account.Email, signer), strings.EqualFold(account.Login, signer.Login) || MatchLogin(account.Login, signer)
+}
+
 // MatchAccount returns whether the provided account matches any of the accounts
 // in the passed-in configuration for enforcing the CLA.
 func MatchAccount(account config.Account, accounts []config.Account) bool {
+	return matchAccount(account, accounts, false)
+}
+
+// matchAccount is `MatchAccount`, optionally requiring identical emails and
+// logins (see `matchFields`).
+func matchAccount(account config.Account, accounts []config.Account, strict bool) bool {
 	for _, account2 := range accounts {
-		if account.Name == account2.Name &&
-			CanonicalizeEmail(account.Email) == CanonicalizeEmail(account2.Email) &&
-			strings.EqualFold(account.Login, account2.Login) {
+		if name, email, login := matchFields(account, account2, strict); name && email && login {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields of an identity, as reported in `UnmatchedIdentity.Mismatched`.
+const (
+	FieldName  = "name"
+	FieldEmail = "email"
+	FieldLogin = "login"
+)
+
+// MismatchedFields returns the fields of the account which differ from the
+// closest of the accounts, in the order name, email, and login, or nil if none
+// of the accounts shares the login or email of the account. The closest
+// account is the one sharing the most fields, preferring the login over the
+// email, and the email over the name, as the stronger identifiers; accounts
+// sharing only the name are not considered, as names are not unique.
+func MismatchedFields(account config.Account, accounts []config.Account) []string {
+	return mismatchedFields(account, accounts, false)
+}
+
+// mismatchedFields is `MismatchedFields`, optionally requiring identical emails
+// and logins (see `matchFields`).
+func mismatchedFields(account config.Account, accounts []config.Account, strict bool) []string {
+	var closest []string
+	bestScore := 0
+	for _, account2 := range accounts {
+		score := 0
+		var mismatched []string
+		name, email, login := matchFields(account, account2, strict)
+		if name {
+			score++
+		} else {
+			mismatched = append(mismatched, FieldName)
+		}
+		if email {
+			score += 2
+		} else {
+			mismatched = append(mismatched, FieldEmail)
+		}
+		if login {
+			score += 4
+		} else {
+			mismatched = append(mismatched, FieldLogin)
+		}
+		if score >= 2 && score > bestScore {
+			bestScore = score
+			closest = mismatched
+		}
+	}
+	return closest
+}
+
+// patterns caches the compiled patterns of bot accounts, keyed by pattern.
+var patterns sync.Map
+
+// matchPattern returns whether the value matches the pattern, which is assumed
+// to be valid, as patterns are checked when parsing the CLA signers.
+func matchPattern(pattern string, value string) bool {
+	if cached, ok := patterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp).MatchString(value)
+	}
+	re, err := config.CompilePattern(pattern)
+	if err != nil {
+		return false
+	}
+	patterns.Store(pattern, re)
+	return re.MatchString(value)
+}
+
+// matchBotLogin returns whether the GitHub login belongs to the given bot,
+// whose login may be a pattern.
+func matchBotLogin(login string, bot config.Account) bool {
+	if config.IsPattern(bot.Login) {
+		return login != "" && matchPattern(bot.Login, login)
+	}
+	return MatchLogin(login, bot)
+}
+
+// GitHubActionsLogin is the GitHub login of GitHub Actions, which commits on
+// behalf of workflows.
+const GitHubActionsLogin = "github-actions[bot]"
+
+// gitHubActionsBot matches the commits of GitHub Actions by login, whatever
+// name and email the workflow committed them with.
+var gitHubActionsBot = config.Account{Login: "/" + regexp.QuoteMeta(GitHubActionsLogin) + "/"}
+
+// signerBots returns the bots of the CLA signers, along with GitHub Actions if
+// its commits are accepted.
+func signerBots(claSigners config.ClaSigners) []config.Account {
+	if !claSigners.GitHubActions {
+		return claSigners.Bots
+	}
+	return append(claSigners.Bots[:len(claSigners.Bots):len(claSigners.Bots)], gitHubActionsBot)
+}
+
+// MatchBot returns whether the provided account matches any of the bots, like
+// `MatchAccount`, except that the login, name, and email of a bot may be
+// patterns, and that the name and email of a bot with a login pattern match
+// any if omitted.
+func MatchBot(account config.Account, bots []config.Account) bool {
+	for _, bot := range bots {
+		if !config.IsPattern(bot.Login) && !config.IsPattern(bot.Name) && !config.IsPattern(bot.Email) {
+			if MatchAccount(account, []config.Account{bot}) {
+				return true
+			}
+			continue
+		}
+		anyIfOmitted := config.IsPattern(bot.Login)
+		matchName := (anyIfOmitted && bot.Name == "") || account.Name == bot.Name ||
+			(config.IsPattern(bot.Name) && matchPattern(bot.Name, account.Name))
+		matchBotEmail := (anyIfOmitted && bot.Email == "") || matchEmail(account.Email, bot) ||
+			(config.IsPattern(bot.Email) && matchPattern(bot.Email, account.Email))
+		if matchName && matchBotEmail && matchBotLogin(account.Login, bot) {
 			return true
 		}
 	}
 	return false
 }
 
+// Roles of the identities associated with a commit.
+const (
+	RoleAuthor    = "author"
+	RoleCommitter = "committer"
+	RoleCoAuthor  = "co-author"
+)
+
+// UnmatchedIdentity is an author or committer identity of a commit which is
+// either incomplete or not covered by any CLA. `Mismatched` lists the fields
+// which differ from the closest CLA record, if there is one (see
+// `MismatchedFields`).
+type UnmatchedIdentity struct {
+	Role       string
+	Account    config.Account
+	Mismatched []string
+}
+
+// newUnmatchedSigner returns the unmatched identity for a complete account
+// which is not covered by any CLA, along with the reason to report for it,
+// which names the first mismatched field if the account is close to one of the
+// CLA signers.
+func newUnmatchedSigner(role string, account config.Account, claSigners config.ClaSigners, notSignerReason string) (UnmatchedIdentity, string) {
+	signers := append([]config.Account{}, claSigners.People...)
+	for _, company := range claSigners.Companies {
+		signers = append(signers, company.People...)
+	}
+	unmatched := UnmatchedIdentity{
+		Role:       role,
+		Account:    account,
+		Mismatched: mismatchedFields(account, signers, claSigners.StrictMatching),
+	}
+	if len(unmatched.Mismatched) == 0 {
+		return unmatched, notSignerReason
+	}
+	logger.Debugf("    %s %s does not match the CLA record", role, strings.Join(unmatched.Mismatched, ", "))
+	return unmatched, mismatchReasons[role][unmatched.Mismatched[0]]
+}
+
 // CommitStatus provides a signal as to the CLA-compliance of a specific
 // commit.
 type CommitStatus struct {
+	SHA                 string
 	Compliant           bool
 	NonComplianceReason string
 	External            bool
+	// Company is the name of the company through whose corporate CLA the
+	// author is covered, if any, and Agreement the record of that CLA.
+	Company   string
+	Agreement config.Agreement
+	// Unmatched lists the identities which caused the commit to be
+	// non-compliant.
+	Unmatched []UnmatchedIdentity
+	// Exemption is the reason the commit is exempt from CLA checks, if it
+	// is listed in `config.ClaSigners.ExemptCommits`.
+	Exemption string
+	// Root is whether the commit has no parents, e.g., so that rewriting
+	// it starts at the root of the history rather than at its parent.
+	Root bool
+}
+
+// verifiedMaintainerCommit returns whether the commit was committed by one of
+// the maintainers, as established by GitHub from the commit's verified
+// signature, rather than by the committer name and email, which anyone can set.
+func verifiedMaintainerCommit(commit *github.RepositoryCommit, maintainers []config.Account) bool {
+	login := commit.GetCommitter().GetLogin()
+	if !commit.GetCommit().GetVerification().GetVerified() || login == "" {
+		return false
+	}
+	for _, maintainer := range maintainers {
+		if strings.EqualFold(maintainer.Login, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindExemptCommit returns the entry exempting the commit with the given SHA,
+// if any; only full SHAs match, case-insensitively.
+func FindExemptCommit(sha string, exemptCommits []config.ExemptCommit) (config.ExemptCommit, bool) {
+	for _, exempt := range exemptCommits {
+		if len(exempt.SHA) == config.ExemptSHALength && strings.EqualFold(sha, exempt.SHA) {
+			return exempt, true
+		}
+	}
+	return config.ExemptCommit{}, false
+}
+
+// ExemptTrailer is the commit message trailer via which maintainers can exempt
+// an individual commit from CLA checks, e.g., "CLA-Exempt: upstream fix".
+const ExemptTrailer = "CLA-Exempt"
+
+// CommitTrailer returns the value of the trailer with the given key (compared
+// case-insensitively) in the last paragraph of the commit message, where Git
+// places trailers such as "Signed-off-by"; the subject line is never treated
+// as a trailer.
+func CommitTrailer(message string, key string) (string, bool) {
+	values := CommitTrailers(message, key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// CommitTrailers returns the values of all the trailers with the given key, as
+// found by `CommitTrailer`, in order.
+func CommitTrailers(message string, key string) []string {
+	paragraphs := strings.Split(strings.TrimSpace(strings.Replace(message, "\r\n", "\n", -1)), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+	var values []string
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(line[:idx]), key) {
+			values = append(values, strings.TrimSpace(line[idx+1:]))
+		}
+	}
+	return values
+}
+
+// CoAuthorTrailer is the commit message trailer via which GitHub credits the
+// co-authors of a commit, e.g., "Co-authored-by: Jane Doe <jane@example.com>".
+const CoAuthorTrailer = "Co-authored-by"
+
+// CoAuthors returns the co-authors named in the `CoAuthorTrailer` trailers of
+// the commit message, skipping malformed ones.
+func CoAuthors(message string) []config.Account {
+	var coAuthors []config.Account
+	for _, value := range CommitTrailers(message, CoAuthorTrailer) {
+		start, end := strings.LastIndex(value, "<"), strings.LastIndex(value, ">")
+		if start < 0 || end < start {
+			continue
+		}
+		email := strings.TrimSpace(value[start+1 : end])
+		if email == "" {
+			continue
+		}
+		coAuthors = append(coAuthors, config.Account{Name: strings.TrimSpace(value[:start]), Email: email})
+	}
+	return coAuthors
+}
+
+// matchCoAuthor returns whether the email of the co-author belongs to any of
+// the CLA signers, as co-author trailers carry no GitHub login to match.
+func matchCoAuthor(coAuthor config.Account, claSigners config.ClaSigners) bool {
+	signers := append([]config.Account{}, claSigners.People...)
+	for _, company := range claSigners.Companies {
+		signers = append(signers, company.People...)
+	}
+	for _, signer := range signers {
+		if claSigners.StrictMatching && coAuthor.Email == signer.Email {
+			return true
+		}
+		if !claSigners.StrictMatching && matchEmail(coAuthor.Email, signer) {
+			return true
+		}
+	}
+	return false
 }
 
 // ProcessCommit processes a single commit and returns compliance status and
 // failure reason, if any.
 func ProcessCommit(commit *github.RepositoryCommit, claSigners config.ClaSigners) CommitStatus {
-	logging.Infof("  - commit: %s", *commit.SHA)
+	logger.Debugf("  - commit: %s", *commit.SHA)
 
 	commitStatus := CommitStatus{
+		SHA:       *commit.SHA,
 		Compliant: true,
 		External:  false,
 	}
 
+	if exempt, ok := FindExemptCommit(*commit.SHA, claSigners.ExemptCommits); ok {
+		commitStatus.Exemption = exempt.Reason
+		if commitStatus.Exemption == "" {
+			commitStatus.Exemption = "exempt commit"
+		}
+		logger.Debugf("    exempted: %s", commitStatus.Exemption)
+		return commitStatus
+	}
+
 	authorLogin := AuthorLogin(commit)
 	committerLogin := CommitterLogin(commit)
 	var authorName, authorEmail string
@@ -330,14 +1152,37 @@ func ProcessCommit(commit *github.RepositoryCommit, claSigners config.ClaSigners
 		}
 	}
 
+	author := config.Account{
+		Name:  authorName,
+		Email: authorEmail,
+		Login: authorLogin,
+	}
+
+	committer := config.Account{
+		Name:  committerName,
+		Email: committerEmail,
+		Login: committerLogin,
+	}
+
+	if reason, ok := CommitTrailer(commit.GetCommit().GetMessage(), ExemptTrailer); ok && reason != "" {
+		if verifiedMaintainerCommit(commit, claSigners.Maintainers) {
+			commitStatus.Exemption = reason
+			logger.Debugf("    exempted via %s trailer: %s", ExemptTrailer, reason)
+			return commitStatus
+		}
+		logger.Debugf("    ignoring %s trailer, as committer %s <%s> is not a maintainer with a verified signature", ExemptTrailer, committerName, committerEmail)
+	}
+
 	if authorName == "" || authorEmail == "" || authorLogin == "" {
 		commitStatus.Compliant = false
-		commitStatus.NonComplianceReason = "Please verify the author name, email, and GitHub username association are all correct and match CLA records."
+		commitStatus.NonComplianceReason = ReasonAuthorIdentity
+		commitStatus.Unmatched = append(commitStatus.Unmatched, UnmatchedIdentity{Role: RoleAuthor, Account: author})
 	}
 
 	if committerName == "" || committerEmail == "" || committerLogin == "" {
 		commitStatus.Compliant = false
-		commitStatus.NonComplianceReason = "Please verify the committer name, email, and GitHub username association are all correct and match CLA records."
+		commitStatus.NonComplianceReason = ReasonCommitterIdentity
+		commitStatus.Unmatched = append(commitStatus.Unmatched, UnmatchedIdentity{Role: RoleCommitter, Account: committer})
 	}
 
 	// Assuming the commit is compliant thus far, verify that both the author
@@ -346,41 +1191,69 @@ func ProcessCommit(commit *github.RepositoryCommit, claSigners config.ClaSigners
 		authorClaMatchFound := false
 		committerClaMatchFound := false
 
-		author := config.Account{
-			Name:  authorName,
-			Email: authorEmail,
-			Login: authorLogin,
-		}
+		strict := claSigners.StrictMatching
+		authorClaMatchFound = authorClaMatchFound || matchAccount(author, claSigners.People, strict)
+		committerClaMatchFound = committerClaMatchFound || matchAccount(committer, claSigners.People, strict)
+		committerClaMatchFound = committerClaMatchFound || MatchBot(committer, signerBots(claSigners))
 
-		committer := config.Account{
-			Name:  committerName,
-			Email: committerEmail,
-			Login: committerLogin,
+		for _, company := range claSigners.Companies {
+			if !authorClaMatchFound && matchAccount(author, company.People, strict) {
+				authorClaMatchFound = true
+				commitStatus.Company = company.Name
+				commitStatus.Agreement = company.Agreement
+			}
+			committerClaMatchFound = committerClaMatchFound || matchAccount(committer, company.People, strict)
 		}
 
-		authorClaMatchFound = authorClaMatchFound || MatchAccount(author, claSigners.People)
-		committerClaMatchFound = committerClaMatchFound || MatchAccount(committer, claSigners.People)
-		committerClaMatchFound = committerClaMatchFound || MatchAccount(committer, claSigners.Bots)
-
-		for _, company := range claSigners.Companies {
-			authorClaMatchFound = authorClaMatchFound || MatchAccount(author, company.People)
-			committerClaMatchFound = committerClaMatchFound || MatchAccount(committer, company.People)
+		// Commits both authored and committed by GitHub Actions are made
+		// by workflows, without any human author to sign the CLA.
+		if claSigners.GitHubActions && MatchBot(author, []config.Account{gitHubActionsBot}) && MatchBot(committer, []config.Account{gitHubActionsBot}) {
+			authorClaMatchFound = true
 		}
 
 		if !authorClaMatchFound {
-			commitStatus.NonComplianceReason = "Author of one or more commits is not listed as a CLA signer, either individual or as a member of an organization."
+			unmatched, reason := newUnmatchedSigner(RoleAuthor, author, claSigners, ReasonAuthorNotSigner)
+			commitStatus.NonComplianceReason = reason
+			commitStatus.Unmatched = append(commitStatus.Unmatched, unmatched)
 		}
 
 		if !committerClaMatchFound {
-			commitStatus.NonComplianceReason = "Committer of one or more commits is not listed as a CLA signer, either individual or as a member of an organization."
+			unmatched, reason := newUnmatchedSigner(RoleCommitter, committer, claSigners, ReasonCommitterNotSigner)
+			commitStatus.NonComplianceReason = reason
+			commitStatus.Unmatched = append(commitStatus.Unmatched, unmatched)
 		}
 
 		commitStatus.Compliant = commitStatus.Compliant && authorClaMatchFound && committerClaMatchFound
 	}
 
+	if claSigners.RequireCoAuthors {
+		for _, coAuthor := range CoAuthors(commit.GetCommit().GetMessage()) {
+			// The author and committer were checked above.
+			if CanonicalizeEmail(coAuthor.Email) == CanonicalizeEmail(authorEmail) || CanonicalizeEmail(coAuthor.Email) == CanonicalizeEmail(committerEmail) {
+				continue
+			}
+			if matchCoAuthor(coAuthor, claSigners) {
+				continue
+			}
+			logger.Debugf("    co-author %s <%s> is not a CLA signer", coAuthor.Name, coAuthor.Email)
+			if commitStatus.Compliant {
+				commitStatus.Compliant = false
+				commitStatus.NonComplianceReason = ReasonCoAuthorNotSigner
+			}
+			commitStatus.Unmatched = append(commitStatus.Unmatched, UnmatchedIdentity{Role: RoleCoAuthor, Account: coAuthor})
+		}
+	}
+
 	// Put it all together now for display.
-	logging.Infof("    author: %s <%s>, GitHub: %s", authorName, authorEmail, authorLogin)
-	logging.Infof("    committer: %s <%s>, GitHub: %s", committerName, committerEmail, committerLogin)
+	logger.Debugf("    author: %s <%s>, GitHub: %s", authorName, authorEmail, authorLogin)
+	logger.Debugf("    committer: %s <%s>, GitHub: %s", committerName, committerEmail, committerLogin)
+	if commitStatus.Company != "" {
+		if agreement := commitStatus.Agreement.String(); agreement != "" {
+			logger.Debugf("    company: %s (%s)", commitStatus.Company, agreement)
+		} else {
+			logger.Debugf("    company: %s", commitStatus.Company)
+		}
+	}
 	return commitStatus
 }
 
@@ -390,9 +1263,147 @@ func ProcessCommit(commit *github.RepositoryCommit, claSigners config.ClaSigners
 // The only way to have a fully-compliant PR is to have all commits on the PR
 // compliant.
 type PullRequestStatus struct {
-	Compliant           bool
+	Compliant bool
+	// NonComplianceReason is the reason of the last non-compliant commit;
+	// `Reasons` lists those of all of them.
 	NonComplianceReason string
+	Reasons             []CommitReason
 	External            bool
+	Commits             []CommitStatus
+	Unsigned            []IdentityStatus
+
+	// ManagedBy names the system managing the CLA of an external PR, if
+	// configured via `config.ExternalClaSigners.ManagedBy`.
+	ManagedBy string
+}
+
+// CommitReason is the reason a single commit of a PR is not compliant.
+type CommitReason struct {
+	SHA    string
+	Reason string
+}
+
+// ShortSHA returns the SHA of the commit abbreviated to 7 characters.
+func (r CommitReason) ShortSHA() string {
+	if len(r.SHA) > 7 {
+		return r.SHA[:7]
+	}
+	return r.SHA
+}
+
+// IdentityStatus summarizes a single identity on a PR which could not be
+// matched to a CLA signer, along with the roles in which it appears and the
+// commits it affects. This lets a PR which mixes commits by covered
+// contributors (e.g., a maintainer's rebase or suggested changes) with those
+// of an uncovered contributor point at exactly who still needs to sign.
+type IdentityStatus struct {
+	Account config.Account
+	Roles   []string
+	Commits []string
+}
+
+// String formats the identity for display as "Name <email> (@login)", leaving
+// out any parts which are missing.
+func (identity IdentityStatus) String() string {
+	parts := make([]string, 0, 3)
+	if identity.Account.Name != "" {
+		parts = append(parts, identity.Account.Name)
+	}
+	if identity.Account.Email != "" {
+		parts = append(parts, "<"+identity.Account.Email+">")
+	}
+	if identity.Account.Login != "" {
+		parts = append(parts, "(@"+identity.Account.Login+")")
+	}
+	if len(parts) == 0 {
+		return "(unknown)"
+	}
+	return strings.Join(parts, " ")
+}
+
+// CommitList returns the comma-separated list of abbreviated SHAs of the
+// commits affected by this identity.
+func (identity IdentityStatus) CommitList() string {
+	shas := make([]string, len(identity.Commits))
+	for idx, sha := range identity.Commits {
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		shas[idx] = sha
+	}
+	return strings.Join(shas, ", ")
+}
+
+// summarizeUnsigned collects the unmatched identities across all commits of a
+// PR, merging repeated appearances of the same identity, in order of first
+// appearance.
+func summarizeUnsigned(commitStatuses []CommitStatus) []IdentityStatus {
+	type identityKey struct {
+		name, email, login string
+	}
+	var identities []IdentityStatus
+	index := make(map[identityKey]int)
+	appendUnique := func(values []string, value string) []string {
+		for _, existing := range values {
+			if existing == value {
+				return values
+			}
+		}
+		return append(values, value)
+	}
+	for _, commitStatus := range commitStatuses {
+		for _, unmatched := range commitStatus.Unmatched {
+			key := identityKey{unmatched.Account.Name, unmatched.Account.Email, unmatched.Account.Login}
+			idx, ok := index[key]
+			if !ok {
+				idx = len(identities)
+				index[key] = idx
+				identities = append(identities, IdentityStatus{Account: unmatched.Account})
+			}
+			identities[idx].Roles = appendUnique(identities[idx].Roles, unmatched.Role)
+			identities[idx].Commits = appendUnique(identities[idx].Commits, commitStatus.SHA)
+		}
+	}
+	return identities
+}
+
+// excludeBaseCommits drops the commits of a PR which are already on its base
+// branch, e.g., brought in by merging an upstream branch into the PR, so that
+// they don't count against its contributor. As this takes an extra API call,
+// the PR is only compared with its base branch if it has merge commits; if the
+// comparison fails, or is truncated, all commits are kept.
+func excludeBaseCommits(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, commits []*github.RepositoryCommit) []*github.RepositoryCommit {
+	hasMerge := false
+	for _, commit := range commits {
+		hasMerge = hasMerge || len(commit.Parents) > 1
+	}
+	baseRef, headSHA := prSpec.Pull.GetBase().GetRef(), prSpec.Pull.GetHead().GetSHA()
+	if !hasMerge || baseRef == "" || headSHA == "" {
+		return commits
+	}
+
+	comparison, _, err := ghc.Repositories.CompareCommits(ctx, prSpec.Org, prSpec.Repo, baseRef, headSHA)
+	if err != nil {
+		logger.Errorf("Error comparing PR %d with base branch %s; checking all of its commits: %s", prSpec.Pull.GetNumber(), baseRef, err)
+		return commits
+	}
+	if comparison.GetTotalCommits() > len(comparison.Commits) {
+		return commits
+	}
+	unique := make(map[string]bool)
+	for _, commit := range comparison.Commits {
+		unique[commit.GetSHA()] = true
+	}
+	var prCommits []*github.RepositoryCommit
+	for _, commit := range commits {
+		if unique[commit.GetSHA()] {
+			prCommits = append(prCommits, commit)
+		}
+	}
+	if skipped := len(commits) - len(prCommits); skipped > 0 {
+		logger.Infof("  Skipping %d commit(s) already on base branch %s", skipped, baseRef)
+	}
+	return prCommits
 }
 
 // checkPullRequestCompliance reports the compliance status of a pull request,
@@ -409,9 +1420,10 @@ func checkPullRequestCompliance(ghc *GitHubClient, prSpec GitHubProcessSinglePul
 	// List all commits for this PR
 	commits, _, err := ghc.PullRequests.ListCommits(ctx, prSpec.Org, prSpec.Repo, pullNumber, nil)
 	if err != nil {
-		logging.Error("Error finding all commits on PR", pullNumber)
+		logger.Error("Error finding all commits on PR", pullNumber)
 		return pullRequestStatus, err
 	}
+	commits = excludeBaseCommits(ctx, ghc, prSpec, commits)
 
 	// Start off with the base case that the PR is compliant and disqualify it if
 	// anything is amiss.
@@ -423,22 +1435,60 @@ func checkPullRequestCompliance(ghc *GitHubClient, prSpec GitHubProcessSinglePul
 		isExternal := IsExternal(commit, claSigners, prSpec.UnknownAsExternal)
 		if isExternal {
 			pullRequestStatus.External = true
+			if claSigners.External != nil {
+				pullRequestStatus.ManagedBy = claSigners.External.ManagedBy
+			}
 			break
 		}
 
-		commitStatus := ProcessCommit(commit, claSigners)
+		commitStatus, err := checkCommit(ctx, ghc, commit, claSigners)
+		if err != nil {
+			logger.Errorf("Error checking commit %s on PR %d: %v", commit.GetSHA(), pullNumber, err)
+			pullRequestStatus.Compliant = false
+			return pullRequestStatus, err
+		}
+		commitStatus = applyTrustLevel(commit, commitStatus, prSpec.TrustLevel)
+		if !commitStatus.Compliant && prSpec.Trivial.MaxLines > 0 {
+			commitStatus = checkTrivialCommit(ctx, ghc, prSpec, commitStatus)
+		}
+		// GitHub lists the parents of each commit, so only an empty list,
+		// rather than a missing one, marks a root commit.
+		commitStatus.Root = commit.Parents != nil && len(commit.Parents) == 0
+		pullRequestStatus.Commits = append(pullRequestStatus.Commits, commitStatus)
 
 		if commitStatus.Compliant {
-			logging.Info("    compliant: true")
+			logger.Debug("    compliant: true")
 		} else {
-			logging.Info("    compliant: false:", commitStatus.NonComplianceReason)
+			logger.Debugf("    compliant: false: %s", commitStatus.NonComplianceReason)
 			pullRequestStatus.NonComplianceReason = commitStatus.NonComplianceReason
+			pullRequestStatus.Reasons = append(pullRequestStatus.Reasons, CommitReason{
+				SHA:    commitStatus.SHA,
+				Reason: commitStatus.NonComplianceReason,
+			})
 			pullRequestStatus.Compliant = false
 		}
 	}
+	pullRequestStatus.Unsigned = summarizeUnsigned(pullRequestStatus.Commits)
 	return pullRequestStatus, nil
 }
 
+// reportSkippedPull adds the PR to the report, if any, as skipped for the
+// given reason.
+func reportSkippedPull(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, reason string) {
+	if ghc.Report == nil {
+		return
+	}
+	ghc.Report.AddPullRequest(report.PullRequest{
+		Org:     prSpec.Org,
+		Repo:    prSpec.Repo,
+		Number:  prSpec.Pull.GetNumber(),
+		Title:   prSpec.Pull.GetTitle(),
+		URL:     prSpec.Pull.GetHTMLURL(),
+		Skipped: true,
+		Reason:  reason,
+	})
+}
+
 // processPullRequest validates all the commits for a particular pull request,
 // and optionally adds/removes labels and comments on a pull request (if the PR
 // is non-compliant) to alert the code author and reviewers that they need to
@@ -451,136 +1501,387 @@ func processPullRequest(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, c
 	pull := prSpec.Pull
 	updateRepo := prSpec.UpdateRepo
 
-	logging.Infof("PR %d: %s", *pull.Number, *pull.Title)
+	logger.Infof("PR %d: %s", *pull.Number, *pull.Title)
+
+	if skipLabel := prSpec.Labels.Skip; skipLabel != "" && HasLabel(pull, skipLabel) {
+		logger.Infof("  PR has [%s] label; skipping", skipLabel)
+		reportSkippedPull(ghc, prSpec, fmt.Sprintf("PR has [%s] label", skipLabel))
+		return nil
+	}
+
+	if baseBranch := pull.GetBase().GetRef(); len(prSpec.BaseBranches) > 0 && !MatchBranch(prSpec.BaseBranches, baseBranch) {
+		logger.Infof("  PR targets branch %s, which is not checked; skipping", baseBranch)
+		reportSkippedPull(ghc, prSpec, fmt.Sprintf("PR targets branch %s", baseBranch))
+		return nil
+	}
+
+	// PRs which haven't changed can only be skipped if they don't need to
+	// be reported, nor checked for reminders.
+	fingerprint := pullFingerprint(prSpec)
+	if ghc.Report == nil && prSpec.Reminders.IntervalDays <= 0 && isUnchanged(ghc, prSpec, fingerprint) {
+		logger.Info("  No changes since last processed; skipping")
+		return nil
+	}
 
-	pullRequestStatus, err := ghc.CheckPullRequestCompliance(ghc, prSpec, claSigners)
+	setPendingStatus(ctx, ghc, prSpec)
+	pullRequestStatus, err := ghc.api().CheckPullRequestCompliance(prSpec, claSigners)
 	if err != nil {
+		setErrorStatus(ctx, ghc, prSpec)
 		return err
 	}
 
-	issueClaLabelStatus := ghc.GetIssueClaLabelStatus(ghc, orgName, repoName, *pull.Number)
-	logging.Infof("  CLA label status [%s]: %v, [%s]: %v, [%s]: %v",
-		LabelClaYes, issueClaLabelStatus.HasYes, LabelClaNo, issueClaLabelStatus.HasNo,
-		LabelClaExternal, issueClaLabelStatus.HasExternal)
+	// The PR is reported once done, along with the labels added and removed.
+	reportPull := newReportPullRequest(prSpec, pullRequestStatus)
 
-	addLabel := func(label string) {
-		logging.Infof("  Adding label [%s] to repo '%s/%s' PR %d...", label, orgName, repoName, *pull.Number)
-		if updateRepo {
-			_, _, err := ghc.Issues.AddLabelsToIssue(ctx, orgName, repoName, *pull.Number, []string{label})
-			if err != nil {
-				logging.Errorf("Error adding label [%s] to repo '%s/%s' PR %d: %v", label, orgName, repoName, *pull.Number, err)
-			}
-		} else {
-			logging.Info("  ... but -update-repo flag is disabled; skipping")
+	// Track the labels of the PR as they are updated, to record them once
+	// done, unless any updates fail, so the PR is processed again.
+	currentLabels := make(map[string]bool)
+	for _, label := range pullLabels(pull) {
+		currentLabels[label] = true
+	}
+	updatesFailed := false
+	var addedLabels, removedLabels []string
+	hadCompliantLabel := false
+	defer func() {
+		if !updatesFailed {
+			recordProcessed(ghc, prSpec, fingerprint, currentLabels)
+		}
+		checkRegression(ctx, ghc, prSpec, pullRequestStatus, hadCompliantLabel)
+		publishChecked(ctx, ghc, prSpec, pullRequestStatus)
+		publishLabelsChanged(ctx, ghc, prSpec, pullRequestStatus, addedLabels, removedLabels)
+		ghc.Counts.labelsChanged(len(addedLabels), len(removedLabels))
+		setFinalStatus(ctx, ghc, prSpec, pullRequestStatus)
+		if ghc.Report != nil {
+			ghc.Report.AddPullRequest(reportPull)
+		}
+	}()
+
+	// renderComment renders the comment explaining why the PR is not
+	// compliant, which is posted on its own or as the body of a review.
+	renderComment := func() string {
+		var fixCommands string
+		if signer, ok := FindSigner(pull.GetUser().GetLogin(), claSigners); ok {
+			fixCommands = FixCommands(pullRequestStatus.Unsigned, pullRequestStatus.Commits, signer)
 		}
+		comment, err := RenderComment(prSpec.CommentTemplate, CommentData{
+			Org:         orgName,
+			Repo:        repoName,
+			Number:      *pull.Number,
+			Author:      pull.GetUser().GetLogin(),
+			Reason:      pullRequestStatus.NonComplianceReason,
+			Reasons:     pullRequestStatus.Reasons,
+			Unsigned:    pullRequestStatus.Unsigned,
+			FixCommands: fixCommands,
+			ClaURL:      prSpec.ClaURL,
+			Locale:      prSpec.Locale,
+		})
+		if err != nil {
+			logger.Errorf("  Error rendering comment for PR %d: %v", *pull.Number, err)
+			comment = pullRequestStatus.NonComplianceReason
+		}
+		return comment
+	}
+
+	if prSpec.SkipLabels {
+		if pullRequestStatus.Compliant && !pullRequestStatus.External && becameCompliant(ghc, prSpec, false, false) {
+			requestReviewers(ctx, ghc, prSpec)
+		}
+		if prSpec.RequestChanges {
+			syncReview(ctx, ghc, prSpec, pullRequestStatus, renderComment)
+		}
+		return nil
+	}
+
+	labels := ResolveLabels(prSpec.Labels)
+	issueClaLabelStatus := ghc.api().GetIssueClaLabelStatus(orgName, repoName, *pull.Number, prSpec.Labels)
+	logger.Debugf("  CLA label status [%s]: %v, [%s]: %v, [%s]: %v",
+		labels.Compliant, issueClaLabelStatus.HasYes, labels.NonCompliant, issueClaLabelStatus.HasNo,
+		labels.External, issueClaLabelStatus.HasExternal)
+	hadCompliantLabel = issueClaLabelStatus.HasYes
+
+	// Label changes are applied at once by `applyLabels`, replacing all of
+	// the labels of the PR, so that it never shows both [cla: yes] and
+	// [cla: no] at the same time.
+	var labelsToAdd, labelsToRemove []string
+	addLabel := func(label string) {
+		logger.Infof("  Adding label [%s] to repo '%s/%s' PR %d...", label, orgName, repoName, *pull.Number)
+		ghc.Diff.Write(orgName, repoName, *pull.Number, ChangeAddLabel, label)
+		reportPull.LabelsAdded = append(reportPull.LabelsAdded, label)
+		labelsToAdd = append(labelsToAdd, label)
 	}
 
 	removeLabel := func(label string) {
-		logging.Infof("  Removing label [%s] from repo '%s/%s' PR %d...", label, orgName, repoName, *pull.Number)
-		if updateRepo {
-			_, err := ghc.Issues.RemoveLabelForIssue(ctx, orgName, repoName, *pull.Number, label)
-			if err != nil {
-				logging.Errorf("  Error removing label [%s] from repo '%s/%s' PR %d: %v", label, orgName, repoName, *pull.Number, err)
-			}
-		} else {
-			logging.Info("  ... but -update-repo flag is disabled; skipping")
+		logger.Infof("  Removing label [%s] from repo '%s/%s' PR %d...", label, orgName, repoName, *pull.Number)
+		ghc.Diff.Write(orgName, repoName, *pull.Number, ChangeRemoveLabel, label)
+		reportPull.LabelsRemoved = append(reportPull.LabelsRemoved, label)
+		labelsToRemove = append(labelsToRemove, label)
+	}
+
+	applyLabels := func() {
+		toAdd, toRemove := labelsToAdd, labelsToRemove
+		labelsToAdd, labelsToRemove = nil, nil
+		if len(toAdd) == 0 && len(toRemove) == 0 {
+			return
+		}
+		if !updateRepo {
+			logger.Info("  ... but -update-repo flag is disabled; skipping label changes")
+			return
+		}
+		if issueClaLabelStatus.Labels == nil {
+			logError(ghc, "Error updating labels of repo '%s/%s' PR %d: its current labels are unknown", orgName, repoName, *pull.Number)
+			updatesFailed = true
+			return
+		}
+		// The labels are listed again right before they are replaced, so
+		// that labels added by others in the meantime are kept.
+		currentIssueLabels, err := listIssueLabels(ctx, ghc, orgName, repoName, *pull.Number)
+		if err != nil {
+			logError(ghc, "Error updating labels of repo '%s/%s' PR %d: error listing its current labels: %v", orgName, repoName, *pull.Number, err)
+			updatesFailed = true
+			return
+		}
+		newLabels := replaceLabels(currentIssueLabels, toAdd, toRemove)
+		logger.Infof("  Setting labels of repo '%s/%s' PR %d to [%s]...", orgName, repoName, *pull.Number, strings.Join(newLabels, "], ["))
+		if _, _, err := ghc.Issues.ReplaceLabelsForIssue(ctx, orgName, repoName, *pull.Number, newLabels); err != nil {
+			logError(ghc, "Error updating labels of repo '%s/%s' PR %d: %v", orgName, repoName, *pull.Number, err)
+			updatesFailed = true
+			return
+		}
+		for label := range currentLabels {
+			delete(currentLabels, label)
 		}
+		for _, label := range newLabels {
+			currentLabels[strings.ToLower(label)] = true
+		}
+		issueClaLabelStatus.Labels = newLabels
+		addedLabels = append(addedLabels, toAdd...)
+		removedLabels = append(removedLabels, toRemove...)
 	}
 
 	addComment := func(comment string) {
-		logging.Infof("  Adding comment to repo '%s/%s/ PR %d: %s", orgName, repoName, *pull.Number, comment)
+		logger.Infof("  Adding comment to repo '%s/%s/ PR %d: %s", orgName, repoName, *pull.Number, comment)
+		ghc.Diff.Write(orgName, repoName, *pull.Number, ChangeAddComment, comment)
 		if updateRepo {
+			if !claimAction(ghc, prSpec, "comment:"+digestJSON(comment)) {
+				logger.Info("  ... but another instance already posted it; skipping")
+				return
+			}
+			body := comment
+			if prSpec.MinimizeComments {
+				body = body + "\n\n" + CommentMarker
+			}
 			issueComment := github.IssueComment{
-				Body: &comment,
+				Body: &body,
 			}
-			_, _, err := ghc.Issues.CreateComment(ctx, orgName, repoName, *pull.Number, &issueComment)
+			created, _, err := ghc.Issues.CreateComment(ctx, orgName, repoName, *pull.Number, &issueComment)
 			if err != nil {
-				logging.Errorf("  Error leaving comment on PR %d: %v", *pull.Number, err)
+				logError(ghc, "  Error leaving comment on PR %d: %v", *pull.Number, err)
+				updatesFailed = true
+				return
+			}
+			ghc.Counts.commentPosted()
+			if prSpec.MinimizeComments {
+				minimizeOutdatedComments(ctx, ghc, prSpec, created)
 			}
 		} else {
-			logging.Info("  ... but -update-repo flag is disabled; skipping")
+			logger.Info("  ... but -update-repo flag is disabled; skipping")
 		}
 	}
 
 	if pullRequestStatus.External {
-		logging.Info("  PR has externally-managed CLA signer")
+		managedBy := pullRequestStatus.ManagedBy
+		if managedBy != "" {
+			logger.Infof("  PR has externally-managed CLA signer (managed by %s)", managedBy)
+		} else {
+			logger.Info("  PR has externally-managed CLA signer")
+		}
 
+		addExternalComment := false
 		if issueClaLabelStatus.HasExternal {
-			logging.Infof("  PR already has [%s] label", LabelClaExternal)
+			logger.Infof("  PR already has [%s] label", labels.External)
 		} else {
-			logging.Infof("  PR doesn't have [%s] label, but should", LabelClaExternal)
+			logger.Infof("  PR doesn't have [%s] label, but should", labels.External)
 			if repoClaLabelStatus.HasExternal {
-				addLabel(LabelClaExternal)
+				addLabel(labels.External)
+				addExternalComment = managedBy != "" && !prSpec.NoComments
 			}
 		}
 		if issueClaLabelStatus.HasYes {
-			removeLabel(LabelClaYes)
+			removeLabel(labels.Compliant)
 		}
 		if issueClaLabelStatus.HasNo {
-			removeLabel(LabelClaNo)
+			removeLabel(labels.NonCompliant)
+		}
+		applyLabels()
+		if addExternalComment {
+			addComment(ExternalComment(prSpec.Locale, managedBy))
 		}
+		if prSpec.RequestChanges {
+			syncReview(ctx, ghc, prSpec, pullRequestStatus, renderComment)
+		}
+		forgetReminders(ghc, prSpec)
 
 		// No need to add any other CLA-related labels or comments to this PR.
 		return nil
 	}
 
 	if issueClaLabelStatus.HasExternal {
-		logging.Infof("  PR has [%s] label, but shouldn't", LabelClaExternal)
-		removeLabel(LabelClaExternal)
+		logger.Infof("  PR has [%s] label, but shouldn't", labels.External)
+		removeLabel(labels.External)
 	} else {
-		logging.Infof("  PR doesn't have [%s] label, and shouldn't", LabelClaExternal)
+		logger.Infof("  PR doesn't have [%s] label, and shouldn't", labels.External)
 		// Nothing to do here.
 	}
 
 	if pullRequestStatus.Compliant {
-		logging.Info("  PR is CLA-compliant")
+		logger.Info("  PR is CLA-compliant")
 	} else {
-		logging.Info("  PR is NOT CLA-compliant:", pullRequestStatus.NonComplianceReason)
+		logger.Info("  PR is NOT CLA-compliant:", pullRequestStatus.NonComplianceReason)
 	}
 
 	// Add or remove [cla: yes] and [cla: no] labels, as appropriate.
 	if pullRequestStatus.Compliant {
+		forgetReminders(ghc, prSpec)
 		// if PR has [cla: no] label, remove it.
 		if issueClaLabelStatus.HasNo {
-			removeLabel(LabelClaNo)
+			removeLabel(labels.NonCompliant)
 		} else {
-			logging.Infof("  No action needed: [%s] label already missing", LabelClaNo)
+			logger.Infof("  No action needed: [%s] label already missing", labels.NonCompliant)
 		}
 		// if PR doesn't have [cla: yes] label, add it.
+		addedCompliantLabel := false
 		if !issueClaLabelStatus.HasYes {
 			if repoClaLabelStatus.HasYes {
-				addLabel(LabelClaYes)
+				addLabel(labels.Compliant)
+				addedCompliantLabel = true
 			}
 		} else {
-			logging.Infof("  No action needed: [%s] label already added", LabelClaYes)
+			logger.Infof("  No action needed: [%s] label already added", labels.Compliant)
+		}
+		applyLabels()
+		if becameCompliant(ghc, prSpec, hadCompliantLabel, addedCompliantLabel && !updatesFailed) {
+			requestReviewers(ctx, ghc, prSpec)
 		}
 	} else /* !pullRequestIsCompliant */ {
 		shouldAddComment := false
 		// if PR doesn't have [cla: no] label, add it.
 		if !issueClaLabelStatus.HasNo {
 			if repoClaLabelStatus.HasNo {
-				addLabel(LabelClaNo)
+				addLabel(labels.NonCompliant)
 			}
 			shouldAddComment = true
 		} else {
-			logging.Infof("  No action needed: [%s] label already added", LabelClaNo)
+			logger.Infof("  No action needed: [%s] label already added", labels.NonCompliant)
 		}
 		// if PR has [cla: yes] label, remove it.
 		if issueClaLabelStatus.HasYes {
-			removeLabel(LabelClaYes)
+			removeLabel(labels.Compliant)
 			shouldAddComment = true
 		} else {
-			logging.Infof("  No action needed: [%s] label already missing", LabelClaYes)
+			logger.Infof("  No action needed: [%s] label already missing", labels.Compliant)
 		}
+		applyLabels()
 
-		if shouldAddComment {
-			addComment(pullRequestStatus.NonComplianceReason)
+		// With `RequestChanges`, the comment is the body of the review.
+		if prSpec.NoComments {
+			if shouldAddComment {
+				logger.Info("  Comments are disabled; not commenting")
+			}
+		} else if shouldAddComment {
+			if !prSpec.RequestChanges && !commentCapped(ghc, prSpec) {
+				addComment(renderComment())
+			}
+			recordComment(ghc, prSpec)
+		} else if reminderDue(ghc, prSpec) {
+			addComment(reminderComment(pull.GetUser().GetLogin(), renderComment()))
+			recordReminder(ghc, prSpec)
 		}
 	}
 
+	if prSpec.RequestChanges {
+		syncReview(ctx, ghc, prSpec, pullRequestStatus, renderComment)
+	}
+
 	return nil
 }
 
+// replaceLabels returns the labels with those in `remove` removed and those in
+// `add` added, comparing label names case-insensitively, as GitHub does.
+func replaceLabels(labels []string, add []string, remove []string) []string {
+	result := make([]string, 0, len(labels)+len(add))
+	contains := func(labels []string, label string) bool {
+		for _, existing := range labels {
+			if strings.EqualFold(existing, label) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, label := range labels {
+		if !contains(remove, label) {
+			result = append(result, label)
+		}
+	}
+	for _, label := range add {
+		if !contains(result, label) {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// logError logs the error, and records it in the report, if any.
+func logError(ghc *GitHubClient, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	logger.Error(message)
+	if ghc.Report != nil {
+		ghc.Report.AddError(strings.TrimSpace(message))
+	}
+}
+
+// newReportPullRequest converts the compliance status of a pull request into
+// the form recorded in a run report.
+func newReportPullRequest(prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus) report.PullRequest {
+	pull := prSpec.Pull
+	reportPull := report.PullRequest{
+		Org:       prSpec.Org,
+		Repo:      prSpec.Repo,
+		Number:    pull.GetNumber(),
+		Title:     pull.GetTitle(),
+		URL:       pull.GetHTMLURL(),
+		Compliant: pullRequestStatus.Compliant,
+		External:  pullRequestStatus.External,
+		ManagedBy: pullRequestStatus.ManagedBy,
+		Reason:    pullRequestStatus.NonComplianceReason,
+	}
+	for _, commitStatus := range pullRequestStatus.Commits {
+		reportCommit := report.Commit{
+			SHA:       commitStatus.SHA,
+			Compliant: commitStatus.Compliant,
+			External:  commitStatus.External,
+			Reason:    commitStatus.NonComplianceReason,
+			Company:   commitStatus.Company,
+			Agreement: report.Agreement{
+				ID:           commitStatus.Agreement.AgreementID,
+				ContactEmail: commitStatus.Agreement.ContactEmail,
+				SignedDate:   commitStatus.Agreement.SignedDate,
+			},
+			Exemption: commitStatus.Exemption,
+		}
+		for _, unmatched := range commitStatus.Unmatched {
+			reportCommit.Unmatched = append(reportCommit.Unmatched, report.Identity{
+				Role:       unmatched.Role,
+				Name:       unmatched.Account.Name,
+				Email:      unmatched.Account.Email,
+				Login:      unmatched.Account.Login,
+				Mismatched: unmatched.Mismatched,
+			})
+		}
+		reportPull.Commits = append(reportPull.Commits, reportCommit)
+	}
+	return reportPull
+}
+
 // IsExternal computes whether the given commit should be processed by this
 // tool, or if it should be covered by an external CLA management tool.
 func IsExternal(commit *github.RepositoryCommit, claSigners config.ClaSigners, unknownAsExternal bool) bool {
@@ -592,10 +1893,10 @@ func IsExternal(commit *github.RepositoryCommit, claSigners config.ClaSigners, u
 		logins = append(logins, committerLogin)
 	}
 
-	matchAny := func(logins []string, accounts []config.Account) bool {
+	matchAny := func(logins []string, accounts []config.Account, matchLogin func(string, config.Account) bool) bool {
 		for _, username := range logins {
 			for _, account := range accounts {
-				if username == account.Login {
+				if matchLogin(username, account) {
 					return true
 				}
 			}
@@ -603,12 +1904,12 @@ func IsExternal(commit *github.RepositoryCommit, claSigners config.ClaSigners, u
 		return false
 	}
 
-	matchAllWithRemainder := func(logins []string, accounts []config.Account) []string {
+	matchAllWithRemainder := func(logins []string, accounts []config.Account, matchLogin func(string, config.Account) bool) []string {
 		remainder := make([]string, 0)
 		for _, username := range logins {
 			found := false
 			for _, account := range accounts {
-				if username == account.Login {
+				if matchLogin(username, account) {
 					found = true
 					break
 				}
@@ -622,13 +1923,13 @@ func IsExternal(commit *github.RepositoryCommit, claSigners config.ClaSigners, u
 
 	if claSigners.External != nil {
 		external := claSigners.External
-		if matchAny(logins, external.People) ||
-			matchAny(logins, external.Bots) {
+		if matchAny(logins, external.People, MatchLogin) ||
+			matchAny(logins, external.Bots, matchBotLogin) {
 			return true
 		}
 
 		for _, company := range external.Companies {
-			if matchAny(logins, company.People) {
+			if matchAny(logins, company.People, MatchLogin) {
 				return true
 			}
 		}
@@ -637,29 +1938,236 @@ func IsExternal(commit *github.RepositoryCommit, claSigners config.ClaSigners, u
 	// If any of the logins don't match any of the CLA Signers *and* the
 	// `unknownAsExternal` is true, then this is an externally-managed
 	// contributor.
-	remainder := matchAllWithRemainder(logins, claSigners.People)
-	remainder = matchAllWithRemainder(remainder, claSigners.Bots)
+	remainder := matchAllWithRemainder(logins, claSigners.People, MatchLogin)
+	remainder = matchAllWithRemainder(remainder, signerBots(claSigners), matchBotLogin)
 	for _, company := range claSigners.Companies {
-		remainder = matchAllWithRemainder(remainder, company.People)
+		remainder = matchAllWithRemainder(remainder, company.People, MatchLogin)
 	}
 
 	return len(remainder) > 0 && unknownAsExternal
 }
 
+// getFileContents retrieves the contents of a file from the given repo; if
+// there is no such file (or repo), it returns nil contents and no error.
+func getFileContents(ghc *GitHubClient, orgName string, repoName string, path string) ([]byte, error) {
+	return getFileContentsAt(ghc, orgName, repoName, path, "")
+}
+
+// getFileContentsAt retrieves the contents of a file at the given ref of the
+// repo, or at the head of its default branch if the ref is empty; as with
+// `getFileContents`, a missing file has nil contents.
+func getFileContentsAt(ghc *GitHubClient, orgName string, repoName string, path string, ref string) ([]byte, error) {
+	ctx := context.Background()
+	var opt *github.RepositoryContentGetOptions
+	if ref != "" {
+		opt = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+	fileContent, _, _, err := ghc.Repositories.GetContents(ctx, orgName, repoName, path, opt)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if fileContent == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// FetchFile retrieves the contents of a file at the given ref of the repo, or
+// at the head of its default branch if the ref is empty, e.g., a CLA signers
+// file kept in a private config repo; see `config.SetRepoFileFetcher`. Unlike
+// the per-repo config files, the file is required to exist.
+func (ghc *GitHubClient) FetchFile(orgName string, repoName string, path string, ref string) ([]byte, error) {
+	content, err := getFileContentsAt(ghc, orgName, repoName, path, ref)
+	if err == nil && content == nil {
+		err = fmt.Errorf("no file %s in repo '%s/%s'", path, orgName, repoName)
+	}
+	return content, err
+}
+
+// getRepoConfig reads the optional per-repo config file from the repo; if the
+// repo has no such file, it returns an empty config.
+func getRepoConfig(ghc *GitHubClient, orgName string, repoName string) (config.RepoConfig, error) {
+	content, err := getFileContents(ghc, orgName, repoName, config.RepoConfigPath)
+	if err != nil || content == nil {
+		return config.RepoConfig{}, err
+	}
+	return config.ParseRepoConfig(content)
+}
+
+// getOrgConfig reads the optional org-wide config file from the org's
+// `.github` repo; if there is no such file, it returns an empty config.
+func getOrgConfig(ghc *GitHubClient, orgName string) (config.OrgConfig, error) {
+	content, err := getFileContents(ghc, orgName, config.OrgConfigRepo, config.OrgConfigPath)
+	if err != nil || content == nil {
+		return config.OrgConfig{}, err
+	}
+	return config.ParseOrgConfig(content)
+}
+
+// IsExcluded returns whether the repo is excluded by the org-wide config.
+func IsExcluded(orgConfig config.OrgConfig, repoName string) bool {
+	for _, excluded := range orgConfig.ExcludeRepos {
+		if strings.EqualFold(excluded, repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyRepoConfig overrides the settings in the spec with any which are set
+// in the repo's own config file.
+func ApplyRepoConfig(prSpec *GitHubProcessSinglePullSpec, repoConfig config.RepoConfig) {
+	if repoConfig.UnknownAsExternal != nil {
+		prSpec.UnknownAsExternal = *repoConfig.UnknownAsExternal
+	}
+	if repoConfig.NoComments != nil {
+		prSpec.NoComments = *repoConfig.NoComments
+	}
+	if repoConfig.TrustLevel != "" {
+		if IsSupportedTrustLevel(repoConfig.TrustLevel) {
+			prSpec.TrustLevel = repoConfig.TrustLevel
+		} else {
+			logger.Errorf("Invalid value for `trust_level` in config of repo '%s/%s': %s; ignoring it", prSpec.Org, prSpec.Repo, repoConfig.TrustLevel)
+		}
+	}
+	if repoConfig.ClaURL != "" {
+		prSpec.ClaURL = repoConfig.ClaURL
+	}
+	if repoConfig.CommentTemplate != "" {
+		prSpec.CommentTemplate = repoConfig.CommentTemplate
+	}
+	if repoConfig.Locale != "" {
+		prSpec.Locale = repoConfig.Locale
+	}
+	if repoConfig.Labels.Compliant != "" {
+		prSpec.Labels.Compliant = repoConfig.Labels.Compliant
+	}
+	if repoConfig.Labels.NonCompliant != "" {
+		prSpec.Labels.NonCompliant = repoConfig.Labels.NonCompliant
+	}
+	if repoConfig.Labels.External != "" {
+		prSpec.Labels.External = repoConfig.Labels.External
+	}
+	if repoConfig.Labels.Skip != "" {
+		prSpec.Labels.Skip = repoConfig.Labels.Skip
+	}
+	if len(repoConfig.Reviewers.Users) > 0 || len(repoConfig.Reviewers.Teams) > 0 {
+		prSpec.Reviewers = repoConfig.Reviewers
+	}
+}
+
+// listPulls returns all open PRs of the repo, following pagination, as listed
+// by the API in the given order.
+func listPulls(ctx context.Context, ghc *GitHubClient, orgName string, repoName string, order string) ([]*github.PullRequest, error) {
+	var pulls []*github.PullRequest
+	opt := pullListOptions(order)
+	for {
+		page, resp, err := ghc.PullRequests.List(ctx, orgName, repoName, opt)
+		if err != nil {
+			return nil, err
+		}
+		pulls = append(pulls, page...)
+		if resp == nil || resp.NextPage == 0 {
+			return pulls, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
 // processOrgRepo handles all PRs in specified repos in the organization or user
 // account. If `repoName` is empty, it processes all repos, if `repoName` is
-// non-empty, it processes the specified repo.
-func processOrgRepo(ghc *GitHubClient, repoSpec GitHubProcessOrgRepoSpec, claSigners config.ClaSigners) {
+// non-empty, it processes the specified repo. It returns an error if the repos
+// can't be retrieved; a repo whose PRs can't be listed is skipped, and listed
+// in the error returned once all other repos have been processed.
+func processOrgRepo(ghc *GitHubClient, repoSpec GitHubProcessOrgRepoSpec, claSigners config.ClaSigners) (*Checkpoint, error) {
 	ctx := context.Background()
 	// Retrieve all repositories for the given organization or user.
 	orgName := repoSpec.Org
-	repos := ghc.GetAllRepos(ghc, orgName, repoSpec.Repo)
+	repos, err := ghc.api().GetAllRepos(orgName, repoSpec.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	orgConfig, err := ghc.api().GetOrgConfig(orgName)
+	if err != nil {
+		logger.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.OrgConfigPath, orgName, config.OrgConfigRepo, err)
+	}
+
+	var claSignersDigest string
+	if ghc.State != nil {
+		claSignersDigest = ClaSignersDigest(claSigners)
+	}
+
+	resume := repoSpec.ResumeFrom
+	if resume != nil && !containsRepo(repos, resume.Repo) {
+		logger.Infof("Repo '%s/%s' from checkpoint not found; starting from the beginning", orgName, resume.Repo)
+		resume = nil
+	}
+
+	// Tracking issues list the non-compliant PRs found in the report, so
+	// they can only be updated by runs over all open PRs which have one.
+	tracking := repoSpec.TrackingIssue
+	if tracking.Enabled && len(repoSpec.Pulls) == 0 && ghc.Report == nil {
+		logger.Debug("Not updating tracking issues, as the run has no report")
+	}
+	trackIssues := tracking.Enabled && len(repoSpec.Pulls) == 0 && ghc.Report != nil
+	trackOrg := trackIssues && tracking.Repo != "" && resume == nil
 
 	// For repository, find all outstanding (non-closed / non-merged PRs)
+	processedPulls := 0
+	var failedRepos []string
+	ghc.Progress.setRepos(len(repos))
+	defer ghc.Progress.finish()
 	for _, repo := range repos {
 		repoName := *repo.Name
+		ghc.Progress.startRepo()
 
-		logging.Infof("Repo: %s/%s", orgName, repoName)
+		if resume != nil && repoName != resume.Repo {
+			logger.Infof("Repo: %s/%s: skipping, as it precedes the checkpoint", orgName, repoName)
+			continue
+		}
+		resumePull := 0
+		if resume != nil {
+			resumePull = resume.Pull
+			resume = nil
+		}
+
+		if budgetExhausted(ghc, repoSpec.MaxAPICalls) {
+			logger.Infof("API call budget of %d exhausted; stopping before repo %s/%s", repoSpec.MaxAPICalls, orgName, repoName)
+			return &Checkpoint{Org: orgName, Repo: repoName}, failedReposError(orgName, failedRepos)
+		}
+		if pullLimitReached(processedPulls, repoSpec.MaxPulls) {
+			logger.Infof("Limit of %d PRs reached; stopping before repo %s/%s", repoSpec.MaxPulls, orgName, repoName)
+			return &Checkpoint{Org: orgName, Repo: repoName}, failedReposError(orgName, failedRepos)
+		}
+
+		logger.Infof("Repo: %s/%s", orgName, repoName)
+
+		if reason := RepoSkipReason(repo, repoSpec.SkipForks); reason != "" {
+			logger.Infof("  Skipping repo: %s", reason)
+			continue
+		}
+
+		if IsExcluded(orgConfig, repoName) {
+			logger.Infof("  Skipping repo: excluded by %s in repo '%s/%s'", config.OrgConfigPath, orgName, config.OrgConfigRepo)
+			continue
+		}
+
+		repoConfig, err := ghc.api().GetRepoConfig(orgName, repoName)
+		if err != nil {
+			logger.Errorf("Error reading %s from repo '%s/%s'; ignoring it: %s", config.RepoConfigPath, orgName, repoName, err)
+		}
+		if repoConfig.Skip || orgConfig.Skip {
+			logger.Infof("  Skipping repo: `skip` is set in %s or %s", config.RepoConfigPath, config.OrgConfigPath)
+			continue
+		}
 
 		var pulls []*github.PullRequest
 		if len(repoSpec.Pulls) > 0 {
@@ -671,27 +2179,109 @@ func processOrgRepo(ghc *GitHubClient, repoSpec GitHubProcessOrgRepoSpec, claSig
 			}
 		} else {
 			// Find all pull requests for the given repo, if not specified.
-			retrievedPulls, _, err := ghc.PullRequests.List(ctx, orgName, repoName, nil)
+			retrievedPulls, err := listPulls(ctx, ghc, orgName, repoName, repoSpec.PullOrder)
 			if err != nil {
-				logging.Fatalf("Error listing pull requests for %s/%s: %s", orgName, repoName, err)
+				logError(ghc, "Error listing pull requests for %s/%s; skipping repo: %s", orgName, repoName, err)
+				failedRepos = append(failedRepos, repoName)
+				continue
 			}
 			pulls = retrievedPulls
 		}
+		SortPulls(pulls, repoSpec.PullOrder)
+
+		locale := repoSpec.Locale
+		if repoLocale, ok := repoSpec.RepoLocales[repoName]; ok {
+			locale = repoLocale
+		}
+		repoPullSpec := GitHubProcessSinglePullSpec{
+			Org:               orgName,
+			Repo:              repoName,
+			UpdateRepo:        repoSpec.UpdateRepo,
+			UnknownAsExternal: repoSpec.UnknownAsExternal,
+			BaseBranches:      repoSpec.BaseBranches,
+			TrustLevel:        repoSpec.TrustLevel,
+			ClaURL:            repoSpec.ClaURL,
+			CommentTemplate:   repoSpec.CommentTemplate,
+			Locale:            locale,
+			Labels:            repoSpec.Labels,
+			RequestChanges:    repoSpec.RequestChanges,
+			SkipLabels:        repoSpec.SkipLabels,
+			NoComments:        repoSpec.NoComments,
+			MaxCommentsPerSHA: repoSpec.MaxCommentsPerSHA,
+			Reminders:         repoSpec.Reminders,
+			Reviewers:         repoSpec.Reviewers,
+			CommitStatus:      repoSpec.CommitStatus,
+			MinimizeComments:  repoSpec.MinimizeComments,
+			Trivial:           repoSpec.Trivial,
+			ClaSignersDigest:  claSignersDigest,
+		}
+		ApplyRepoConfig(&repoPullSpec, orgConfig.RepoConfig)
+		ApplyRepoConfig(&repoPullSpec, repoConfig)
 
 		// Process each pull request for author & commiter CLA status.
-		repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+		repoClaLabelStatus := ghc.api().GetRepoClaLabelStatus(orgName, repoName, repoPullSpec.Labels)
+		pulls = pulls[resumeIndex(pulls, resumePull, repoSpec.PullOrder):]
+		ghc.Progress.setRepoPulls(len(pulls))
 		for _, pull := range pulls {
-			prSpec := GitHubProcessSinglePullSpec{
-				Org:               orgName,
-				Repo:              repoName,
-				Pull:              pull,
-				UpdateRepo:        repoSpec.UpdateRepo,
-				UnknownAsExternal: repoSpec.UnknownAsExternal,
-			}
-			err := ghc.ProcessPullRequest(ghc, prSpec, claSigners, repoClaLabelStatus)
+			if budgetExhausted(ghc, repoSpec.MaxAPICalls) {
+				logger.Infof("API call budget of %d exhausted; stopping before PR %d", repoSpec.MaxAPICalls, pull.GetNumber())
+				return &Checkpoint{Org: orgName, Repo: repoName, Pull: pull.GetNumber()}, failedReposError(orgName, failedRepos)
+			}
+			if pullLimitReached(processedPulls, repoSpec.MaxPulls) {
+				logger.Infof("Limit of %d PRs reached; stopping before PR %d", repoSpec.MaxPulls, pull.GetNumber())
+				return &Checkpoint{Org: orgName, Repo: repoName, Pull: pull.GetNumber()}, failedReposError(orgName, failedRepos)
+			}
+			processedPulls++
+
+			prSpec := repoPullSpec
+			prSpec.Pull = pull
+			err := ghc.api().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
 			if err != nil {
-				logging.Errorf("Error processing PR %d: %s", *pull.Number, err)
+				logError(ghc, "Error processing %s/%s PR %d: %s", orgName, repoName, *pull.Number, err)
 			}
+			ghc.Progress.pullDone()
+			ghc.Counts.pullProcessed()
 		}
+
+		// A repo whose processing resumed midway has only part of its PRs
+		// in the report.
+		if trackIssues && tracking.Repo == "" && resumePull == 0 {
+			updateTrackingIssue(ctx, ghc, orgName, repoName, tracking, reportedPulls(ghc, orgName, repoName), repoSpec.UpdateRepo)
+		}
+	}
+	if trackOrg && len(failedRepos) == 0 {
+		updateTrackingIssue(ctx, ghc, orgName, tracking.Repo, tracking, reportedPulls(ghc, orgName, ""), repoSpec.UpdateRepo)
 	}
+	return nil, failedReposError(orgName, failedRepos)
+}
+
+// failedReposError returns an error listing the repos which could not be
+// processed, if any.
+func failedReposError(orgName string, failedRepos []string) error {
+	if len(failedRepos) == 0 {
+		return nil
+	}
+	return fmt.Errorf("error listing pull requests for %d repo(s) in org %s: %s", len(failedRepos), orgName, strings.Join(failedRepos, ", "))
+}
+
+// budgetExhausted returns whether the client has made at least `maxAPICalls`
+// API calls; a non-positive budget is unlimited.
+func budgetExhausted(ghc *GitHubClient, maxAPICalls int) bool {
+	return maxAPICalls > 0 && ghc.Usage != nil && ghc.Usage.Usage().Calls >= maxAPICalls
+}
+
+// pullLimitReached returns whether `processedPulls` PRs have reached the limit
+// of `maxPulls`; a non-positive limit is unlimited.
+func pullLimitReached(processedPulls int, maxPulls int) bool {
+	return maxPulls > 0 && processedPulls >= maxPulls
+}
+
+// containsRepo returns whether the list of repos includes the named repo.
+func containsRepo(repos []*github.Repository, repoName string) bool {
+	for _, repo := range repos {
+		if repo.GetName() == repoName {
+			return true
+		}
+	}
+	return false
 }
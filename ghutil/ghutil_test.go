@@ -15,49 +15,32 @@
 package ghutil_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/events"
 	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/ghutiltest"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/code-review-bot/state"
 	"github.com/google/go-github/v21/github"
 )
 
-type MockGitHubClient struct {
-	Organizations *ghutil.MockOrganizationsService
-	PullRequests  *ghutil.MockPullRequestsService
-	Issues        *ghutil.MockIssuesService
-	Repositories  *ghutil.MockRepositoriesService
-	Api           *ghutil.MockGitHubUtilApi
-}
-
-func NewMockGitHubClient(ghc *ghutil.GitHubClient, ctrl *gomock.Controller) *MockGitHubClient {
-	mockGhc := &MockGitHubClient{
-		Organizations: ghutil.NewMockOrganizationsService(ctrl),
-		PullRequests:  ghutil.NewMockPullRequestsService(ctrl),
-		Issues:        ghutil.NewMockIssuesService(ctrl),
-		Repositories:  ghutil.NewMockRepositoriesService(ctrl),
-		Api:           ghutil.NewMockGitHubUtilApi(ctrl),
-	}
-
-	// Patch the original GitHubClient with our mock services.
-	ghc.Organizations = mockGhc.Organizations
-	ghc.PullRequests = mockGhc.PullRequests
-	ghc.Issues = mockGhc.Issues
-	ghc.Repositories = mockGhc.Repositories
-
-	return mockGhc
-}
-
 // Common parameters used across most, if not all, tests.
 var (
 	ctrl    *gomock.Controller
 	ghc     *ghutil.GitHubClient
-	mockGhc *MockGitHubClient
+	mockGhc *ghutiltest.MockGitHubClient
 
 	noLabel *github.Label = nil
 	any                   = gomock.Any()
@@ -72,7 +55,7 @@ const (
 func setUp(t *testing.T) {
 	ctrl = gomock.NewController(t)
 	ghc = ghutil.NewBasicClient()
-	mockGhc = NewMockGitHubClient(ghc, ctrl)
+	mockGhc = ghutiltest.NewMockGitHubClient(ghc, ctrl)
 }
 
 func tearDown(_ *testing.T) {
@@ -87,7 +70,8 @@ func TestGetAllRepos_OrgAndRepo(t *testing.T) {
 
 	mockGhc.Repositories.EXPECT().Get(any, orgName, repoName).Return(&repo, nil, nil)
 
-	repos := ghc.GetAllRepos(ghc, orgName, repoName)
+	repos, err := ghc.GetAllRepos(orgName, repoName)
+	assert.Nil(t, err)
 	assert.Equal(t, 1, len(repos), "repos is not of length 1: %v", repos)
 }
 
@@ -100,12 +84,94 @@ func TestGetAllRepos_OrgOnly(t *testing.T) {
 		{},
 	}
 
-	mockGhc.Repositories.EXPECT().List(any, orgName, nil).Return(expectedRepos, nil, nil)
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(expectedRepos, nil, nil)
 
-	actualRepos := ghc.GetAllRepos(ghc, orgName, "")
+	actualRepos, err := ghc.GetAllRepos(orgName, "")
+	assert.Nil(t, err)
 	assert.Equal(t, len(expectedRepos), len(actualRepos), "Expected repos: %v, actual repos: %v", expectedRepos, actualRepos)
 }
 
+func TestGetAllRepos_ListsAllPages(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	repo1 := github.Repository{Name: github.String("repo1")}
+	repo2 := github.Repository{Name: github.String("repo2")}
+	firstPage := &github.RepositoryListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	secondPage := *firstPage
+	secondPage.Page = 2
+	gomock.InOrder(
+		mockGhc.Repositories.EXPECT().List(any, orgName, firstPage).Return([]*github.Repository{&repo1}, &github.Response{NextPage: 2}, nil),
+		mockGhc.Repositories.EXPECT().List(any, orgName, &secondPage).Return([]*github.Repository{&repo2}, &github.Response{}, nil),
+	)
+
+	repos, err := ghc.GetAllRepos(orgName, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*github.Repository{&repo1, &repo2}, repos)
+}
+
+func TestGetAllRepos_MultipleRepos(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	repo1 := github.Repository{}
+	repo2 := github.Repository{}
+	mockGhc.Repositories.EXPECT().Get(any, orgName, "repo1").Return(&repo1, nil, nil)
+	mockGhc.Repositories.EXPECT().Get(any, orgName, "repo2").Return(&repo2, nil, nil)
+
+	repos, err := ghc.GetAllRepos(orgName, "repo1, repo2")
+	assert.Nil(t, err)
+	assert.Equal(t, []*github.Repository{&repo1, &repo2}, repos)
+}
+
+func TestGetAllRepos_ListError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, nil, errors.New("server error"))
+
+	repos, err := ghc.GetAllRepos(orgName, "")
+	assert.Nil(t, repos)
+	assert.EqualError(t, err, "error listing all repos in org org: server error")
+}
+
+func TestGetAllRepos_SkipsMissingRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	repo2 := github.Repository{}
+	mockGhc.Repositories.EXPECT().Get(any, orgName, "repo1").Return(nil, nil, errors.New("404 Not Found"))
+	mockGhc.Repositories.EXPECT().Get(any, orgName, "repo2").Return(&repo2, nil, nil)
+
+	repos, err := ghc.GetAllRepos(orgName, "repo1,repo2")
+	assert.Nil(t, err)
+	assert.Equal(t, []*github.Repository{&repo2}, repos)
+
+	mockGhc.Repositories.EXPECT().Get(any, orgName, "repo1").Return(nil, nil, errors.New("404 Not Found"))
+	repos, err = ghc.GetAllRepos(orgName, "repo1")
+	assert.Nil(t, repos)
+	assert.EqualError(t, err, "error looking up org/repo1: 404 Not Found")
+}
+
+func TestGetAllRepos_Patterns(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	var allRepos []*github.Repository
+	for _, name := range []string{"cloud-api", "Cloud-Web", "infra-dns", "website", "docs"} {
+		allRepos = append(allRepos, &github.Repository{Name: github.String(name)})
+	}
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(allRepos, nil, nil)
+
+	repos, err := ghc.GetAllRepos(orgName, "cloud-*,infra-*,docs")
+	assert.Nil(t, err)
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.GetName())
+	}
+	assert.Equal(t, []string{"cloud-api", "Cloud-Web", "infra-dns", "docs"}, names)
+}
+
 func expectRepoLabels(orgName string, repoName string, hasYes bool, hasNo bool, hasExternal bool) {
 	labels := map[string]bool{
 		ghutil.LabelClaYes:      hasYes,
@@ -127,7 +193,7 @@ func TestVerifyRepoHasClaLabels_NoLabels(t *testing.T) {
 
 	expectRepoLabels(orgName, repoName, false, false, false)
 
-	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
 	assert.False(t, repoClaLabelStatus.HasYes)
 	assert.False(t, repoClaLabelStatus.HasNo)
 	assert.False(t, repoClaLabelStatus.HasExternal)
@@ -139,7 +205,7 @@ func TestGetRepoClaLabelStatus_HasYesOnly(t *testing.T) {
 
 	expectRepoLabels(orgName, repoName, true, false, false)
 
-	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
 	assert.True(t, repoClaLabelStatus.HasYes)
 	assert.False(t, repoClaLabelStatus.HasNo)
 	assert.False(t, repoClaLabelStatus.HasExternal)
@@ -151,7 +217,7 @@ func TestGetRepoClaLabelStatus_HasNoOnly(t *testing.T) {
 
 	expectRepoLabels(orgName, repoName, false, true, false)
 
-	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
 	assert.False(t, repoClaLabelStatus.HasYes)
 	assert.True(t, repoClaLabelStatus.HasNo)
 	assert.False(t, repoClaLabelStatus.HasExternal)
@@ -163,7 +229,7 @@ func TestGetRepoClaLabelStatus_YesAndNoLabels(t *testing.T) {
 
 	expectRepoLabels(orgName, repoName, true, true, false)
 
-	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
 	assert.True(t, repoClaLabelStatus.HasYes)
 	assert.True(t, repoClaLabelStatus.HasNo)
 	assert.False(t, repoClaLabelStatus.HasExternal)
@@ -175,12 +241,85 @@ func TestGetRepoClaLabelStatus_YesNoAndExternalLabels(t *testing.T) {
 
 	expectRepoLabels(orgName, repoName, true, true, true)
 
-	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(ghc, orgName, repoName)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
 	assert.True(t, repoClaLabelStatus.HasYes)
 	assert.True(t, repoClaLabelStatus.HasNo)
 	assert.True(t, repoClaLabelStatus.HasExternal)
 }
 
+func TestGetRepoClaLabelStatus_Cached(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.RepoLabels = ghutil.NewRepoLabelCache(time.Hour)
+	expectRepoLabels(orgName, repoName, true, true, false)
+
+	// Only the first call looks up the labels.
+	for i := 0; i < 2; i++ {
+		repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
+		assert.Equal(t, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true}, repoClaLabelStatus)
+	}
+
+	// Invalidating the repo, or using other label names, looks them up again.
+	ghc.RepoLabels.Invalidate(orgName, repoName)
+	expectRepoLabels(orgName, repoName, true, true, true)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
+	assert.Equal(t, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true, HasExternal: true}, repoClaLabelStatus)
+
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, "cla: ok").Return(&github.Label{}, nil, nil)
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, ghutil.LabelClaNo).Return(nil, nil, nil)
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, ghutil.LabelClaExternal).Return(nil, nil, nil)
+	repoClaLabelStatus = ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{Compliant: "cla: ok"})
+	assert.Equal(t, ghutil.RepoClaLabelStatus{HasYes: true}, repoClaLabelStatus)
+}
+
+func TestGetRepoClaLabelStatus_ErrorNotCached(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.RepoLabels = ghutil.NewRepoLabelCache(time.Hour)
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, ghutil.LabelClaYes).Return(nil, nil, errors.New("connection reset"))
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, ghutil.LabelClaNo).Return(&github.Label{}, nil, nil)
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, ghutil.LabelClaExternal).Return(nil, nil, nil)
+	repoClaLabelStatus := ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
+	assert.Equal(t, ghutil.RepoClaLabelStatus{HasNo: true}, repoClaLabelStatus)
+
+	expectRepoLabels(orgName, repoName, true, true, false)
+	repoClaLabelStatus = ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{})
+	assert.Equal(t, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true}, repoClaLabelStatus)
+}
+
+func TestGetIssueClaLabelStatus_ListsAllPages(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	firstPage := make([]*github.Label, 100)
+	for i := range firstPage {
+		firstPage[i] = &github.Label{Name: github.String(fmt.Sprintf("label-%d", i))}
+	}
+	secondPage := []*github.Label{{Name: github.String(ghutil.LabelClaYes)}}
+	mockGhc.Issues.EXPECT().ListLabelsByIssue(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100}).Return(firstPage, &github.Response{NextPage: 2}, nil)
+	mockGhc.Issues.EXPECT().ListLabelsByIssue(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100, Page: 2}).Return(secondPage, &github.Response{}, nil)
+
+	status := ghc.GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{})
+	assert.True(t, status.HasYes)
+	assert.Len(t, status.Labels, 101)
+	assert.Equal(t, ghutil.LabelClaYes, status.Labels[100])
+}
+
+func TestRepoLabelCache_Expires(t *testing.T) {
+	cache := ghutil.NewRepoLabelCache(-time.Second)
+	cache.Put(orgName, repoName, config.Labels{}, ghutil.RepoClaLabelStatus{HasYes: true})
+	_, ok := cache.Get(orgName, repoName, config.Labels{})
+	assert.False(t, ok)
+
+	cache = ghutil.NewRepoLabelCache(time.Hour)
+	cache.Put("Org", "Repo", config.Labels{}, ghutil.RepoClaLabelStatus{HasYes: true})
+	status, ok := cache.Get(orgName, repoName, config.Labels{})
+	assert.True(t, ok)
+	assert.True(t, status.HasYes)
+}
+
 func TestMatchAccount_MatchesCase(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
@@ -227,6 +366,128 @@ func TestMatchAccount_DoesNotMatchCase(t *testing.T) {
 	assert.True(t, ghutil.MatchAccount(account, accounts))
 }
 
+func TestMatchAccount_NoreplyEmail(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// CLA as configured by the project, with the signer's real email.
+	accounts := []config.Account{
+		{
+			Name:  "Jane Doe",
+			Email: "jane@example.com",
+			Login: "JaneDoe",
+		},
+	}
+
+	for _, email := range []string{"12345+janedoe@users.noreply.github.com", "JaneDoe@users.noreply.github.com"} {
+		account := config.Account{
+			Name:  "Jane Doe",
+			Email: email,
+			Login: "JaneDoe",
+		}
+		assert.True(t, ghutil.MatchAccount(account, accounts), email)
+	}
+
+	// The noreply address must embed the signer's own login.
+	account := config.Account{
+		Name:  "Jane Doe",
+		Email: "12345+someone-else@users.noreply.github.com",
+		Login: "JaneDoe",
+	}
+	assert.False(t, ghutil.MatchAccount(account, accounts))
+}
+
+func TestMatchAccount_Alias(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The signer has since renamed their GitHub account.
+	accounts := []config.Account{
+		{
+			Name:    "Jane Doe",
+			Email:   "jane@example.com",
+			Login:   "jane-doe",
+			Aliases: []string{"JaneDoe"},
+		},
+	}
+
+	account := config.Account{
+		Name:  "Jane Doe",
+		Email: "jane@example.com",
+		Login: "janedoe",
+	}
+	assert.True(t, ghutil.MatchAccount(account, accounts))
+
+	account.Login = "someone-else"
+	assert.False(t, ghutil.MatchAccount(account, accounts))
+}
+
+func TestMatchBot_LoginPattern(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	bots := []config.Account{
+		{
+			Login: `/.*\[bot\]/`,
+		},
+	}
+
+	account := config.Account{
+		Name:  "dependabot[bot]",
+		Email: "49699333+dependabot[bot]@users.noreply.github.com",
+		Login: "Dependabot[bot]",
+	}
+	assert.True(t, ghutil.MatchBot(account, bots))
+
+	// Patterns match entire logins.
+	account.Login = "dependabot[bot]-impostor"
+	assert.False(t, ghutil.MatchBot(account, bots))
+}
+
+func TestMatchBot_NameAndEmailPatterns(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	bots := []config.Account{
+		{
+			Name:  "/Release Bot \\d+/",
+			Email: "/release-bot-\\d+@example\\.com/",
+			Login: "/release-bot-\\d+/",
+		},
+		{
+			Name:  "Exact Bot",
+			Email: "exact@example.com",
+			Login: "exact-bot",
+		},
+	}
+
+	account := config.Account{
+		Name:  "Release Bot 7",
+		Email: "release-bot-7@example.com",
+		Login: "release-bot-7",
+	}
+	assert.True(t, ghutil.MatchBot(account, bots))
+
+	account.Email = "release-bot-7@example.org"
+	assert.False(t, ghutil.MatchBot(account, bots))
+
+	// Bots without patterns match as with `MatchAccount`.
+	assert.True(t, ghutil.MatchBot(bots[1], bots))
+}
+
+func TestNoreplyLogin(t *testing.T) {
+	login, ok := ghutil.NoreplyLogin("12345+JaneDoe@users.noreply.github.com")
+	assert.True(t, ok)
+	assert.Equal(t, "janedoe", login)
+
+	login, ok = ghutil.NoreplyLogin("janedoe@users.noreply.github.com")
+	assert.True(t, ok)
+	assert.Equal(t, "janedoe", login)
+
+	_, ok = ghutil.NoreplyLogin("janedoe@example.com")
+	assert.False(t, ok)
+}
+
 func TestProcessCommit_DifferentAuthorAndCommitter(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
@@ -260,128 +521,477 @@ func TestProcessCommit_DifferentAuthorAndCommitter(t *testing.T) {
 	assert.True(t, commitStatus.Compliant, "Commit should have been marked compliant; reason: ", commitStatus.NonComplianceReason)
 }
 
-func TestProcessCommit_DifferentCaseInCommitEmailVsCLA(t *testing.T) {
+func TestProcessCommit_GitHubActions(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	userUC := config.Account{
-		Name:  "User Name",
-		Email: "User.Name@example.com",
-		Login: "User-Name",
+	john := config.Account{
+		Name:  "John Doe",
+		Email: "john@example.com",
+		Login: "john-doe",
 	}
-	userLC := config.Account{
-		Name:  userUC.Name,
-		Email: strings.ToLower(userUC.Email),
-		Login: strings.ToLower(userUC.Login),
+	actions := config.Account{
+		Name:  "github-actions",
+		Email: "41898282+github-actions[bot]@users.noreply.github.com",
+		Login: ghutil.GitHubActionsLogin,
+	}
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+
+	// Without the option, GitHub Actions needs to be listed as a bot.
+	assert.False(t, ghutil.ProcessCommit(createCommit(john, actions), claSigners).Compliant)
+	assert.False(t, ghutil.ProcessCommit(createCommit(actions, actions), claSigners).Compliant)
+
+	// With it, it is accepted as a committer, and as the author of its own
+	// commits, but not as the author of commits committed by others.
+	claSigners.GitHubActions = true
+	assert.True(t, ghutil.ProcessCommit(createCommit(john, actions), claSigners).Compliant)
+	assert.True(t, ghutil.ProcessCommit(createCommit(actions, actions), claSigners).Compliant)
+	assert.False(t, ghutil.ProcessCommit(createCommit(actions, john), claSigners).Compliant)
+	assert.False(t, ghutil.IsExternal(createCommit(actions, actions), claSigners, true))
+	assert.Empty(t, claSigners.Bots)
+}
+
+func TestProcessCommit_ExemptCommit(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// Neither the author nor the committer is a CLA signer.
+	unknown := config.Account{
+		Name:  "Jane Doe",
+		Email: "jane@example.com",
+		Login: "janedoe",
 	}
+	commit := createCommit(unknown, unknown)
+	commit.SHA = github.String("0123456789abcdef0123456789abcdef01234567")
 
 	claSigners := config.ClaSigners{
-		Companies: []config.Company{
-			{
-				Name:   "Acme, Inc.",
-				People: []config.Account{userUC},
-			},
+		ExemptCommits: []config.ExemptCommit{
+			{SHA: "0123456789ABCDEF0123456789ABCDEF01234567", Reason: "Vendored import of libfoo"},
 		},
 	}
-
-	commit := createCommit(userLC, userLC)
 	commitStatus := ghutil.ProcessCommit(commit, claSigners)
-	assert.True(t, commitStatus.Compliant, "Commit should have been marked compliant; reason: ", commitStatus.NonComplianceReason)
+	assert.True(t, commitStatus.Compliant)
+	assert.Equal(t, "Vendored import of libfoo", commitStatus.Exemption)
+	assert.Empty(t, commitStatus.Unmatched)
 }
 
-func TestCanonicalizeEmail_Gmail(t *testing.T) {
+func TestProcessCommit_ExemptTrailerFromMaintainer(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	var goldenInputOutput = map[string]string{
-		"username@gmail.com":         "username@gmail.com",
-		"user.name@gmail.com":        "username@gmail.com",
-		"UserName@Gmail.Com":         "username@gmail.com",
-		"User.Name@Gmail.Com":        "username@gmail.com",
-		"U.s.e.r.N.a.m.e.@Gmail.Com": "username@gmail.com",
-		"User.Name@example.com":      "user.name@example.com",
-		"User.Name@Example.com":      "user.name@example.com",
+	upstream := config.Account{
+		Name:  "Upstream Dev",
+		Email: "dev@upstream.example.com",
+		Login: "upstreamdev",
 	}
+	maintainer := config.Account{
+		Name:  "Mae Tainer",
+		Email: "mae@example.com",
+		Login: "maetainer",
+	}
+	commit := createCommit(upstream, maintainer)
+	commit.Commit.Message = github.String("Fix overflow in parser\n\nCherry-picked from upstream.\n\nCLA-Exempt: upstream third-party fix\nSigned-off-by: Mae Tainer <mae@example.com>")
+	commit.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
 
-	for input, expected := range goldenInputOutput {
-		assert.Equal(t, expected, ghutil.CanonicalizeEmail(input))
+	claSigners := config.ClaSigners{
+		People:      []config.Account{maintainer},
+		Maintainers: []config.Account{maintainer},
 	}
+	commitStatus := ghutil.ProcessCommit(commit, claSigners)
+	assert.True(t, commitStatus.Compliant)
+	assert.Equal(t, "upstream third-party fix", commitStatus.Exemption)
 }
 
-func TestGmailAddress_PeriodsDoNotMatchCLA(t *testing.T) {
+func TestProcessCommit_ExemptTrailerFromSpoofedMaintainerIgnored(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	var accountVsCla = map[string]string{
-		"jane.doe@gmail.com":      "janedoe@gmail.com",
-		"JaneDoe@gmail.com":       "Jane.Doe@gmail.com",
-		"janeDoe@gmail.com":       "JaneDoe@gmail.com",
-		"jane.doe@googlemail.com": "janedoe@googlemail.com",
-		"JaneDoe@googlemail.com":  "Jane.Doe@googlemail.com",
-		"janeDoe@googlemail.com":  "JaneDoe@googlemail.com",
+	maintainer := config.Account{
+		Name:  "Mae Tainer",
+		Email: "mae@example.com",
+		Login: "maetainer",
+	}
+	contributor := config.Account{
+		Name:  "Con Tributor",
+		Email: "con@example.com",
+		Login: "contributor",
+	}
+	claSigners := config.ClaSigners{
+		People:      []config.Account{maintainer},
+		Maintainers: []config.Account{maintainer},
 	}
 
-	for acctEmail, claEmail := range accountVsCla {
-		// Credentials as provided by the user.
-		account := config.Account{
-			Name:  "Jane Doe",
-			Email: acctEmail,
-			Login: "janedoe",
-		}
+	// The committer name and email are set to the maintainer's, e.g., via
+	// GIT_COMMITTER_NAME and GIT_COMMITTER_EMAIL, without a signature.
+	commit := createCommit(contributor, maintainer)
+	commit.Commit.Message = github.String("Add feature\n\nCLA-Exempt: trust me")
+	commitStatus := ghutil.ProcessCommit(commit, claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, "", commitStatus.Exemption)
+
+	// A verified signature of another account doesn't help either.
+	commit.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+	commit.Committer = &github.User{Login: github.String(contributor.Login)}
+	commitStatus = ghutil.ProcessCommit(commit, claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, "", commitStatus.Exemption)
+}
 
-		// CLA as configured by the project.
-		accounts := []config.Account{
-			{
-				Name:  "Jane Doe",
-				Email: claEmail,
-				Login: "janedoe",
-			},
-		}
+func TestProcessCommit_ExemptTrailerFromNonMaintainerIgnored(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
 
-		assert.True(t, ghutil.MatchAccount(account, accounts))
+	contributor := config.Account{
+		Name:  "Con Tributor",
+		Email: "con@example.com",
+		Login: "contributor",
 	}
+	commit := createCommit(contributor, contributor)
+	commit.Commit.Message = github.String("Add feature\n\nCLA-Exempt: trust me")
+
+	commitStatus := ghutil.ProcessCommit(commit, config.ClaSigners{})
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, "", commitStatus.Exemption)
 }
 
-func getSinglePullSpec() ghutil.GitHubProcessSinglePullSpec {
-	localPullNumber := pullNumber
-	localPullTitle := "no title"
-	pull := github.PullRequest{
-		Number: &localPullNumber,
-		Title:  &localPullTitle,
-	}
+func TestCommitTrailer(t *testing.T) {
+	reason, ok := ghutil.CommitTrailer("Subject\r\n\r\nBody text.\r\n\r\ncla-exempt:  vendored code \r\n", ghutil.ExemptTrailer)
+	assert.True(t, ok)
+	assert.Equal(t, "vendored code", reason)
+
+	// Trailers are only recognized in the last paragraph, and never in
+	// the subject.
+	_, ok = ghutil.CommitTrailer("Subject\n\nCLA-Exempt: not a trailer\n\nSigned-off-by: A <a@example.com>", ghutil.ExemptTrailer)
+	assert.False(t, ok)
+	_, ok = ghutil.CommitTrailer("CLA-Exempt: subject", ghutil.ExemptTrailer)
+	assert.False(t, ok)
+}
 
-	return ghutil.GitHubProcessSinglePullSpec{
-		Org:  orgName,
-		Repo: repoName,
-		Pull: &pull,
-	}
+func TestCoAuthors(t *testing.T) {
+	coAuthors := ghutil.CoAuthors("Squashed change\n\nCo-authored-by: Jane Doe <jane@example.com>\nco-authored-by: <anon@example.com>\nCo-authored-by: no email\nSigned-off-by: John Doe <john@example.com>")
+	assert.Equal(t, []config.Account{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Email: "anon@example.com"},
+	}, coAuthors)
 }
 
-func TestCheckPullRequestCompliance_ListCommitsError(t *testing.T) {
+func TestProcessCommit_CoAuthors(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	err := errors.New("Invalid PR")
-	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(nil, nil, err)
+	john, jane := createUserAccounts()
+	commit := createCommit(john, john)
+	commit.Commit.Message = github.String("Squashed change\n\nCo-authored-by: John Doe <" + john.Email + ">\nCo-authored-by: Jane Doe <" + jane.Email + ">")
 
-	prSpec := getSinglePullSpec()
-	claSigners := config.ClaSigners{}
-	pullRequestStatus, retErr := ghc.CheckPullRequestCompliance(ghc, prSpec, claSigners)
-	assert.False(t, pullRequestStatus.Compliant)
-	assert.Equal(t, "", pullRequestStatus.NonComplianceReason)
-	assert.Equal(t, err, retErr)
+	// Co-authors are only checked if required.
+	claSigners := config.ClaSigners{People: []config.Account{john}}
+	commitStatus := ghutil.ProcessCommit(commit, claSigners)
+	assert.True(t, commitStatus.Compliant)
+
+	claSigners.RequireCoAuthors = true
+	commitStatus = ghutil.ProcessCommit(commit, claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCoAuthorNotSigner, commitStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{
+		{Role: ghutil.RoleCoAuthor, Account: config.Account{Name: "Jane Doe", Email: jane.Email}},
+	}, commitStatus.Unmatched)
+
+	claSigners.People = append(claSigners.People, jane)
+	commitStatus = ghutil.ProcessCommit(commit, claSigners)
+	assert.True(t, commitStatus.Compliant)
 }
 
-func createCommit(author config.Account, committer config.Account) *github.RepositoryCommit {
-	// Uniqueness of SHA fingerprints for commits is not an invariant
-	// that's required or enforced anywhere; we just need a non-null value
-	// here, so it's OK to use the same value for all commits to avoid
-	// dummy data in our test code.
-	sha := "abc123def456"
+func TestFindExemptCommit(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	exemptCommits := []config.ExemptCommit{
+		{SHA: "0123456", Reason: "Abbreviated SHAs don't match"},
+		{SHA: "fedcba9876543210fedcba9876543210fedcba98", Reason: "Other commit"},
+	}
+	_, ok := ghutil.FindExemptCommit(sha, exemptCommits)
+	assert.False(t, ok)
 
-	return &github.RepositoryCommit{
-		SHA: &sha,
-		Commit: &github.Commit{
+	exemptCommits = append(exemptCommits, config.ExemptCommit{SHA: sha})
+	exempt, ok := ghutil.FindExemptCommit(sha, exemptCommits)
+	assert.True(t, ok)
+	assert.Equal(t, sha, exempt.SHA)
+}
+
+func TestProcessCommit_RecordsAuthorCompany(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+		Companies: []config.Company{
+			{
+				Name:      "Acme Inc.",
+				People:    []config.Account{jane},
+				Agreement: config.Agreement{AgreementID: "CCLA-1234", SignedDate: "2019-05-01"},
+			},
+		},
+	}
+
+	commitStatus := ghutil.ProcessCommit(createCommit(jane, john), claSigners)
+	assert.True(t, commitStatus.Compliant)
+	assert.Equal(t, "Acme Inc.", commitStatus.Company)
+	assert.Equal(t, "CCLA-1234", commitStatus.Agreement.AgreementID)
+
+	commitStatus = ghutil.ProcessCommit(createCommit(john, jane), claSigners)
+	assert.True(t, commitStatus.Compliant)
+	assert.Equal(t, "", commitStatus.Company)
+	assert.Equal(t, config.Agreement{}, commitStatus.Agreement)
+}
+
+func TestProcessCommit_RecordsUnmatchedIdentities(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+
+	commitStatus := ghutil.ProcessCommit(createCommit(jane, john), claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{
+		{Role: ghutil.RoleAuthor, Account: jane},
+	}, commitStatus.Unmatched)
+
+	commitStatus = ghutil.ProcessCommit(createCommit(jane, jane), config.ClaSigners{})
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{
+		{Role: ghutil.RoleAuthor, Account: jane},
+		{Role: ghutil.RoleCommitter, Account: jane},
+	}, commitStatus.Unmatched)
+}
+
+func TestProcessCommit_ReportsMismatchedField(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+		Companies: []config.Company{
+			{
+				Name:   "Acme Inc.",
+				People: []config.Account{jane},
+			},
+		},
+	}
+
+	// The author committed with a personal email address.
+	janePersonal := jane
+	janePersonal.Email = "jane@personal.example.com"
+	commitStatus := ghutil.ProcessCommit(createCommit(janePersonal, john), claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonAuthorEmailMismatch, commitStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{
+		{Role: ghutil.RoleAuthor, Account: janePersonal, Mismatched: []string{ghutil.FieldEmail}},
+	}, commitStatus.Unmatched)
+
+	// The committer used a nickname.
+	johnny := john
+	johnny.Name = "Johnny"
+	commitStatus = ghutil.ProcessCommit(createCommit(jane, johnny), claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCommitterNameMismatch, commitStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.UnmatchedIdentity{
+		{Role: ghutil.RoleCommitter, Account: johnny, Mismatched: []string{ghutil.FieldName}},
+	}, commitStatus.Unmatched)
+}
+
+func TestMismatchedFields(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	accounts := []config.Account{john, jane}
+
+	account := jane
+	account.Login = "jane-other"
+	assert.Equal(t, []string{ghutil.FieldLogin}, ghutil.MismatchedFields(account, accounts))
+
+	// Only the login matches.
+	account = jane
+	account.Name = "J. Doe"
+	account.Email = "jdoe@example.com"
+	assert.Equal(t, []string{ghutil.FieldName, ghutil.FieldEmail}, ghutil.MismatchedFields(account, accounts))
+
+	// Sharing just the name is not enough to identify the signer.
+	account = config.Account{Name: jane.Name, Email: "someone@example.com", Login: "someone"}
+	assert.Nil(t, ghutil.MismatchedFields(account, accounts))
+
+	assert.Nil(t, ghutil.MismatchedFields(jane, accounts[:1]))
+}
+
+func TestIsNotSignerReason(t *testing.T) {
+	assert.True(t, ghutil.IsNotSignerReason(ghutil.ReasonAuthorNotSigner))
+	assert.True(t, ghutil.IsNotSignerReason(ghutil.ReasonCommitterLoginMismatch))
+	assert.False(t, ghutil.IsNotSignerReason(ghutil.ReasonAuthorIdentity))
+	assert.False(t, ghutil.IsNotSignerReason("Custom reason."))
+}
+
+func TestProcessCommit_DifferentCaseInCommitEmailVsCLA(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	userUC := config.Account{
+		Name:  "User Name",
+		Email: "User.Name@example.com",
+		Login: "User-Name",
+	}
+	userLC := config.Account{
+		Name:  userUC.Name,
+		Email: strings.ToLower(userUC.Email),
+		Login: strings.ToLower(userUC.Login),
+	}
+
+	claSigners := config.ClaSigners{
+		Companies: []config.Company{
+			{
+				Name:   "Acme, Inc.",
+				People: []config.Account{userUC},
+			},
+		},
+	}
+
+	commit := createCommit(userLC, userLC)
+	commitStatus := ghutil.ProcessCommit(commit, claSigners)
+	assert.True(t, commitStatus.Compliant, "Commit should have been marked compliant; reason: ", commitStatus.NonComplianceReason)
+}
+
+func TestProcessCommit_StrictMatching(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	signer := config.Account{
+		Name:  "Jane Doe",
+		Email: "jane.doe@gmail.com",
+		Login: "JaneDoe",
+	}
+	committed := config.Account{
+		Name:  signer.Name,
+		Email: "JaneDoe@gmail.com",
+		Login: "janedoe",
+	}
+	claSigners := config.ClaSigners{
+		People: []config.Account{signer},
+	}
+
+	commitStatus := ghutil.ProcessCommit(createCommit(committed, committed), claSigners)
+	assert.True(t, commitStatus.Compliant, "Commit should have been marked compliant; reason: ", commitStatus.NonComplianceReason)
+
+	claSigners.StrictMatching = true
+	commitStatus = ghutil.ProcessCommit(createCommit(committed, committed), claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCommitterNotSigner, commitStatus.NonComplianceReason)
+
+	committed.Login = signer.Login
+	commitStatus = ghutil.ProcessCommit(createCommit(committed, committed), claSigners)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCommitterEmailMismatch, commitStatus.NonComplianceReason)
+
+	commitStatus = ghutil.ProcessCommit(createCommit(signer, signer), claSigners)
+	assert.True(t, commitStatus.Compliant, "Commit should have been marked compliant; reason: ", commitStatus.NonComplianceReason)
+}
+
+func TestCanonicalizeEmail_Gmail(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	var goldenInputOutput = map[string]string{
+		"username@gmail.com":         "username@gmail.com",
+		"user.name@gmail.com":        "username@gmail.com",
+		"UserName@Gmail.Com":         "username@gmail.com",
+		"User.Name@Gmail.Com":        "username@gmail.com",
+		"U.s.e.r.N.a.m.e.@Gmail.Com": "username@gmail.com",
+		"User.Name@example.com":      "user.name@example.com",
+		"User.Name@Example.com":      "user.name@example.com",
+	}
+
+	for input, expected := range goldenInputOutput {
+		assert.Equal(t, expected, ghutil.CanonicalizeEmail(input))
+	}
+}
+
+func TestGmailAddress_PeriodsDoNotMatchCLA(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	var accountVsCla = map[string]string{
+		"jane.doe@gmail.com":      "janedoe@gmail.com",
+		"JaneDoe@gmail.com":       "Jane.Doe@gmail.com",
+		"janeDoe@gmail.com":       "JaneDoe@gmail.com",
+		"jane.doe@googlemail.com": "janedoe@googlemail.com",
+		"JaneDoe@googlemail.com":  "Jane.Doe@googlemail.com",
+		"janeDoe@googlemail.com":  "JaneDoe@googlemail.com",
+	}
+
+	for acctEmail, claEmail := range accountVsCla {
+		// Credentials as provided by the user.
+		account := config.Account{
+			Name:  "Jane Doe",
+			Email: acctEmail,
+			Login: "janedoe",
+		}
+
+		// CLA as configured by the project.
+		accounts := []config.Account{
+			{
+				Name:  "Jane Doe",
+				Email: claEmail,
+				Login: "janedoe",
+			},
+		}
+
+		assert.True(t, ghutil.MatchAccount(account, accounts))
+	}
+}
+
+func getSinglePullSpec() ghutil.GitHubProcessSinglePullSpec {
+	localPullNumber := pullNumber
+	localPullTitle := "no title"
+	pull := github.PullRequest{
+		Number: &localPullNumber,
+		Title:  &localPullTitle,
+	}
+
+	return ghutil.GitHubProcessSinglePullSpec{
+		Org:  orgName,
+		Repo: repoName,
+		Pull: &pull,
+	}
+}
+
+func TestCheckPullRequestCompliance_ListCommitsError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	err := errors.New("Invalid PR")
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(nil, nil, err)
+
+	prSpec := getSinglePullSpec()
+	claSigners := config.ClaSigners{}
+	pullRequestStatus, retErr := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "", pullRequestStatus.NonComplianceReason)
+	assert.Equal(t, err, retErr)
+}
+
+func createCommit(author config.Account, committer config.Account) *github.RepositoryCommit {
+	// Uniqueness of SHA fingerprints for commits is not an invariant
+	// that's required or enforced anywhere; we just need a non-null value
+	// here, so it's OK to use the same value for all commits to avoid
+	// dummy data in our test code.
+	sha := "abc123def456"
+
+	return &github.RepositoryCommit{
+		SHA: &sha,
+		Commit: &github.Commit{
 			Author: &github.CommitAuthor{
 				Name:  &author.Name,
 				Email: &author.Email,
@@ -429,7 +1039,7 @@ func TestCheckPullRequestCompliance_OneCommitDifferentEmailCase(t *testing.T) {
 			},
 		},
 	}
-	pullRequestStatus, err := ghc.CheckPullRequestCompliance(ghc, prSpec, claSigners)
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
 	assert.True(t, pullRequestStatus.Compliant)
 	assert.Equal(t, "", pullRequestStatus.NonComplianceReason)
 	assert.Nil(t, err)
@@ -451,101 +1061,336 @@ func TestCheckPullRequestCompliance_TwoCompliantCommits(t *testing.T) {
 	claSigners := config.ClaSigners{
 		People: []config.Account{john, jane},
 	}
-	pullRequestStatus, err := ghc.CheckPullRequestCompliance(ghc, prSpec, claSigners)
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
 	assert.True(t, pullRequestStatus.Compliant)
 	assert.Equal(t, "", pullRequestStatus.NonComplianceReason)
 	assert.Nil(t, err)
 }
 
-func TestCheckPullRequestCompliance_OneCompliantOneNot(t *testing.T) {
+func TestCheckPullRequestCompliance_ExcludesBaseCommits(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
 	john, jane := createUserAccounts()
 
-	commits := []*github.RepositoryCommit{
-		createCommit(john, john),
-		createCommit(jane, jane),
-	}
+	// John merged the base branch, including a commit by Jane, into his PR.
+	johnCommit := createCommit(john, john)
+	johnCommit.SHA = github.String("aaa111")
+	janeCommit := createCommit(jane, jane)
+	janeCommit.SHA = github.String("bbb222")
+	mergeCommit := createCommit(john, john)
+	mergeCommit.SHA = github.String("ccc333")
+	mergeCommit.Parents = []github.Commit{{SHA: github.String("aaa111")}, {SHA: github.String("bbb222")}}
+	commits := []*github.RepositoryCommit{johnCommit, janeCommit, mergeCommit}
 	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+	mockGhc.Repositories.EXPECT().CompareCommits(any, orgName, repoName, "main", "ccc333").Return(&github.CommitsComparison{
+		TotalCommits: github.Int(2),
+		Commits:      []github.RepositoryCommit{*johnCommit, *mergeCommit},
+	}, nil, nil)
 
 	prSpec := getSinglePullSpec()
+	prSpec.Pull.Base = &github.PullRequestBranch{Ref: github.String("main")}
+	prSpec.Pull.Head = &github.PullRequestBranch{SHA: github.String("ccc333")}
 	claSigners := config.ClaSigners{
 		People: []config.Account{john},
 	}
-	pullRequestStatus, err := ghc.CheckPullRequestCompliance(ghc, prSpec, claSigners)
-	assert.False(t, pullRequestStatus.Compliant)
-	assert.Equal(t, "Committer of one or more commits is not listed as a CLA signer, either individual or as a member of an organization.", pullRequestStatus.NonComplianceReason)
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
 	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Compliant)
+	assert.Len(t, pullRequestStatus.Commits, 2)
 }
 
-type ProcessPullRequest_TestParams struct {
-	RepoClaLabelStatus  ghutil.RepoClaLabelStatus
-	IssueClaLabelStatus ghutil.IssueClaLabelStatus
-	PullRequestStatus   ghutil.PullRequestStatus
-	UpdateRepo          bool
-	LabelsToAdd         []string
-	LabelsToRemove      []string
-}
+func TestCheckPullRequestCompliance_ExternalManagedBy(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
 
-func runProcessPullRequestTestScenario(t *testing.T, params ProcessPullRequest_TestParams) {
-	// Dummy CLA signers data as we don't actually need to use it here,
-	// since we're mocking out the functions that would otherwise process
-	// this data.
-	claSigners := config.ClaSigners{}
+	john, _ := createUserAccounts()
 
-	prSpec := getSinglePullSpec()
-	prSpec.UpdateRepo = params.UpdateRepo
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
 
-	ghc.CheckPullRequestCompliance = mockGhc.Api.CheckPullRequestCompliance
-	mockGhc.Api.EXPECT().CheckPullRequestCompliance(ghc, prSpec, claSigners).Return(params.PullRequestStatus, nil)
+	prSpec := getSinglePullSpec()
+	claSigners := config.ClaSigners{
+		External: &config.ExternalClaSigners{
+			ManagedBy: "EasyCLA",
+			People:    []config.Account{john},
+		},
+	}
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.External)
+	assert.Equal(t, "EasyCLA", pullRequestStatus.ManagedBy)
+}
 
-	ghc.GetIssueClaLabelStatus = mockGhc.Api.GetIssueClaLabelStatus
-	mockGhc.Api.EXPECT().GetIssueClaLabelStatus(ghc, orgName, repoName, pullNumber).Return(params.IssueClaLabelStatus)
+func TestCheckPullRequestCompliance_OneCompliantOneNot(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
 
-	if params.UpdateRepo {
-		for _, label := range params.LabelsToAdd {
-			mockGhc.Issues.EXPECT().AddLabelsToIssue(any, orgName, repoName, pullNumber, []string{label}).Return(nil, nil, nil)
-		}
+	john, jane := createUserAccounts()
 
-		for _, label := range params.LabelsToRemove {
-			mockGhc.Issues.EXPECT().RemoveLabelForIssue(any, orgName, repoName, pullNumber, label).Return(nil, nil)
-		}
+	commits := []*github.RepositoryCommit{
+		createCommit(john, john),
+		createCommit(jane, jane),
 	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
 
-	err := ghc.ProcessPullRequest(ghc, prSpec, claSigners, params.RepoClaLabelStatus)
+	prSpec := getSinglePullSpec()
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "Committer of one or more commits is not listed as a CLA signer, either individual or as a member of an organization.", pullRequestStatus.NonComplianceReason)
 	assert.Nil(t, err)
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_Compliant_Update(t *testing.T) {
+func TestCheckPullRequestCompliance_ReasonsOfAllCommits(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
-		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
-			HasYes: true,
-			HasNo:  true,
-		},
-		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
-		PullRequestStatus: ghutil.PullRequestStatus{
-			Compliant: true,
-		},
-		UpdateRepo:  true,
-		LabelsToAdd: []string{ghutil.LabelClaYes},
-	})
+	john, jane := createUserAccounts()
+
+	authorCommit := createCommit(jane, john)
+	authorSHA := "1111111aaaa"
+	authorCommit.SHA = &authorSHA
+	committerCommit := createCommit(john, jane)
+	committerSHA := "2222222bbbb"
+	committerCommit.SHA = &committerSHA
+	commits := []*github.RepositoryCommit{
+		authorCommit,
+		createCommit(john, john),
+		committerCommit,
+	}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	prSpec := getSinglePullSpec()
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonCommitterNotSigner, pullRequestStatus.NonComplianceReason)
+	assert.Equal(t, []ghutil.CommitReason{
+		{SHA: authorSHA, Reason: ghutil.ReasonAuthorNotSigner},
+		{SHA: committerSHA, Reason: ghutil.ReasonCommitterNotSigner},
+	}, pullRequestStatus.Reasons)
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_NonCompliant_Update(t *testing.T) {
+func TestCheckPullRequestCompliance_MaintainerRebasedExternalCommits(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	// When adding a "cla: no" label, we will also add a comment to the
-	// effect of why this PR got that label.
-	nonComplianceReason := "Your PR is not compliant"
-	issueComment := github.IssueComment{
-		Body: &nonComplianceReason,
+	maintainer, contributor := createUserAccounts()
+
+	// The maintainer rebased the contributor's commit and added one of
+	// their own; only the contributor should be reported as unsigned.
+	rebased := createCommit(contributor, maintainer)
+	rebasedSHA := "1111111aaaa"
+	rebased.SHA = &rebasedSHA
+	own := createCommit(maintainer, maintainer)
+	ownSHA := "2222222bbbb"
+	own.SHA = &ownSHA
+
+	commits := []*github.RepositoryCommit{rebased, own}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	prSpec := getSinglePullSpec()
+	claSigners := config.ClaSigners{
+		People: []config.Account{maintainer},
 	}
-	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, []ghutil.IdentityStatus{
+		{
+			Account: contributor,
+			Roles:   []string{ghutil.RoleAuthor},
+			Commits: []string{rebasedSHA},
+		},
+	}, pullRequestStatus.Unsigned)
+}
+
+func TestCheckPullRequestCompliance_TrivialDocsChangeExempted(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	_, contributor := createUserAccounts()
+	commit := createCommit(contributor, contributor)
+	commits := []*github.RepositoryCommit{commit}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil)
+
+	// The files are only fetched because the commit is non-compliant.
+	withFiles := github.RepositoryCommit{
+		SHA: commit.SHA,
+		Files: []github.CommitFile{
+			{Filename: github.String("README.md"), Additions: github.Int(1), Deletions: github.Int(1)},
+			{Filename: github.String("docs/guide/install.txt"), Additions: github.Int(2)},
+		},
+	}
+	mockGhc.Repositories.EXPECT().GetCommit(any, orgName, repoName, commit.GetSHA()).Return(&withFiles, nil, nil)
+
+	prSpec := getSinglePullSpec()
+	prSpec.Trivial = config.TrivialPolicy{MaxLines: 10}
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Compliant)
+	assert.Equal(t, "trivial documentation change (4 line(s) in 2 file(s))", pullRequestStatus.Commits[0].Exemption)
+	assert.Empty(t, pullRequestStatus.Unsigned)
+}
+
+func TestIsTrivialCommit(t *testing.T) {
+	policy := config.TrivialPolicy{MaxLines: 5}
+	docs := github.CommitFile{Filename: github.String("docs/index.rst"), Additions: github.Int(4)}
+	code := github.CommitFile{Filename: github.String("main.go"), Additions: github.Int(1)}
+	renamed := github.CommitFile{Filename: github.String("NOTES.md"), PreviousFilename: github.String("notes.go"), Additions: github.Int(1)}
+
+	_, trivial := ghutil.IsTrivialCommit(&github.RepositoryCommit{Files: []github.CommitFile{docs}}, policy)
+	assert.True(t, trivial)
+	_, trivial = ghutil.IsTrivialCommit(&github.RepositoryCommit{Files: []github.CommitFile{docs, code}}, policy)
+	assert.False(t, trivial, "non-documentation files are never trivial")
+	_, trivial = ghutil.IsTrivialCommit(&github.RepositoryCommit{Files: []github.CommitFile{renamed}}, policy)
+	assert.False(t, trivial, "renames from non-documentation files are never trivial")
+	_, trivial = ghutil.IsTrivialCommit(&github.RepositoryCommit{Files: []github.CommitFile{docs, docs}}, policy)
+	assert.False(t, trivial, "8 lines exceeds the threshold")
+	_, trivial = ghutil.IsTrivialCommit(&github.RepositoryCommit{Files: []github.CommitFile{docs}}, config.TrivialPolicy{})
+	assert.False(t, trivial, "policy is disabled")
+}
+
+func TestMatchTrivialPath(t *testing.T) {
+	assert.True(t, ghutil.MatchTrivialPath("pkg/README.md", nil))
+	assert.True(t, ghutil.MatchTrivialPath("docs/img/logo.svg", nil))
+	assert.False(t, ghutil.MatchTrivialPath("pkg/docs/img/logo.svg", nil))
+	assert.False(t, ghutil.MatchTrivialPath("main.go", nil))
+
+	patterns := []string{"site/*.html", "CHANGELOG"}
+	assert.True(t, ghutil.MatchTrivialPath("site/index.html", patterns))
+	assert.False(t, ghutil.MatchTrivialPath("other/site/index.html", patterns))
+	assert.True(t, ghutil.MatchTrivialPath("pkg/CHANGELOG", patterns))
+	assert.False(t, ghutil.MatchTrivialPath("README.md", patterns))
+}
+
+func TestIdentityStatus_String(t *testing.T) {
+	assert.Equal(t, "Jane Doe <jane@example.com> (@jane)", ghutil.IdentityStatus{
+		Account: config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane"},
+	}.String())
+	assert.Equal(t, "Jane Doe <jane@example.com>", ghutil.IdentityStatus{
+		Account: config.Account{Name: "Jane Doe", Email: "jane@example.com"},
+	}.String())
+	assert.Equal(t, "(unknown)", ghutil.IdentityStatus{}.String())
+}
+
+// issueLabels returns the default CLA labels an issue has per its status, as
+// listed by `GetIssueClaLabelStatus`.
+func issueLabels(issueClaLabelStatus ghutil.IssueClaLabelStatus) []string {
+	labels := []string{}
+	if issueClaLabelStatus.HasYes {
+		labels = append(labels, ghutil.LabelClaYes)
+	}
+	if issueClaLabelStatus.HasNo {
+		labels = append(labels, ghutil.LabelClaNo)
+	}
+	if issueClaLabelStatus.HasExternal {
+		labels = append(labels, ghutil.LabelClaExternal)
+	}
+	return labels
+}
+
+// expectListIssueLabels expects the labels of the PR to be listed, as they
+// are right before they are replaced, returning the given labels.
+func expectListIssueLabels(labels ...string) {
+	var issueLabels []*github.Label
+	for _, label := range labels {
+		issueLabels = append(issueLabels, &github.Label{Name: github.String(label)})
+	}
+	mockGhc.Issues.EXPECT().ListLabelsByIssue(any, orgName, repoName, pullNumber, any).Return(issueLabels, nil, nil)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type ProcessPullRequest_TestParams struct {
+	RepoClaLabelStatus  ghutil.RepoClaLabelStatus
+	IssueClaLabelStatus ghutil.IssueClaLabelStatus
+	PullRequestStatus   ghutil.PullRequestStatus
+	UpdateRepo          bool
+	ClaURL              string
+	RequestChanges      bool
+	SkipLabels          bool
+	NoComments          bool
+	MaxCommentsPerSHA   int
+	HeadSHA             string
+	Reminders           config.Reminders
+	Reviewers           config.Reviewers
+	CommitStatus        config.CommitStatus
+	MinimizeComments    bool
+	Author              string
+	LabelsToAdd         []string
+	LabelsToRemove      []string
+}
+
+func runProcessPullRequestTestScenario(t *testing.T, params ProcessPullRequest_TestParams) {
+	// Dummy CLA signers data as we don't actually need to use it here,
+	// since we're mocking out the functions that would otherwise process
+	// this data.
+	claSigners := config.ClaSigners{}
+
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = params.UpdateRepo
+	prSpec.ClaURL = params.ClaURL
+	prSpec.RequestChanges = params.RequestChanges
+	prSpec.SkipLabels = params.SkipLabels
+	prSpec.NoComments = params.NoComments
+	prSpec.MaxCommentsPerSHA = params.MaxCommentsPerSHA
+	if params.HeadSHA != "" {
+		prSpec.Pull.Head = &github.PullRequestBranch{SHA: &params.HeadSHA}
+	}
+	prSpec.Reminders = params.Reminders
+	prSpec.Reviewers = params.Reviewers
+	prSpec.CommitStatus = params.CommitStatus
+	prSpec.MinimizeComments = params.MinimizeComments
+	if params.Author != "" {
+		prSpec.Pull.User = &github.User{Login: &params.Author}
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(params.PullRequestStatus, nil)
+
+	issueClaLabelStatus := params.IssueClaLabelStatus
+	if issueClaLabelStatus.Labels == nil {
+		issueClaLabelStatus.Labels = issueLabels(issueClaLabelStatus)
+	}
+	if !params.SkipLabels {
+		mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{}).Return(issueClaLabelStatus)
+	}
+
+	if params.UpdateRepo && (len(params.LabelsToAdd) > 0 || len(params.LabelsToRemove) > 0) {
+		newLabels := []string{}
+		for _, label := range issueClaLabelStatus.Labels {
+			if !containsString(params.LabelsToRemove, label) {
+				newLabels = append(newLabels, label)
+			}
+		}
+		newLabels = append(newLabels, params.LabelsToAdd...)
+		expectListIssueLabels(issueClaLabelStatus.Labels...)
+		mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, newLabels).Return(nil, nil, nil)
+	}
+
+	err := ghc.ProcessPullRequest(prSpec, claSigners, params.RepoClaLabelStatus)
+	assert.Nil(t, err)
+}
+
+func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_Compliant_Update(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
 
 	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
 		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
@@ -554,34 +1399,47 @@ func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_NonCompliant_Update(
 		},
 		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
 		PullRequestStatus: ghutil.PullRequestStatus{
-			Compliant:           false,
-			NonComplianceReason: nonComplianceReason,
+			Compliant: true,
 		},
 		UpdateRepo:  true,
-		LabelsToAdd: []string{ghutil.LabelClaNo},
+		LabelsToAdd: []string{ghutil.LabelClaYes},
 	})
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_External_Update(t *testing.T) {
+func TestProcessPullRequest_BecomesCompliant_RequestsReviewers(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
+	// The author can't review their own PR, so they're left out.
+	reviewers := github.ReviewersRequest{
+		Reviewers:     []string{"reviewer"},
+		TeamReviewers: []string{"maintainers"},
+	}
+	mockGhc.PullRequests.EXPECT().RequestReviewers(any, orgName, repoName, pullNumber, reviewers).Return(nil, nil, nil)
+
 	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
 		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
-			HasYes:      true,
-			HasNo:       true,
-			HasExternal: true,
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
 		},
-		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
 		PullRequestStatus: ghutil.PullRequestStatus{
-			External: true,
+			Compliant: true,
 		},
-		UpdateRepo:  true,
-		LabelsToAdd: []string{ghutil.LabelClaExternal},
+		UpdateRepo: true,
+		Reviewers: config.Reviewers{
+			Users: []string{"Author", "reviewer"},
+			Teams: []string{"maintainers"},
+		},
+		Author:         "author",
+		LabelsToAdd:    []string{ghutil.LabelClaYes},
+		LabelsToRemove: []string{ghutil.LabelClaNo},
 	})
 }
 
-func TestProcessPullRequest_RepoHasHabels_PullHasYesLabel_Compliant(t *testing.T) {
+func TestProcessPullRequest_AlreadyCompliant_DoesNotRequestReviewers(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
@@ -597,11 +1455,186 @@ func TestProcessPullRequest_RepoHasHabels_PullHasYesLabel_Compliant(t *testing.T
 			Compliant: true,
 		},
 		UpdateRepo: true,
-		// No labels to be added or removed in this case.
+		Reviewers: config.Reviewers{
+			Users: []string{"reviewer"},
+		},
 	})
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasYesLabel_NonCompliant(t *testing.T) {
+func TestProcessPullRequest_LabelUpdateFails_DoesNotRequestReviewers(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	claSigners := config.ClaSigners{}
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.Reviewers = config.Reviewers{Users: []string{"reviewer"}}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(ghutil.PullRequestStatus{Compliant: true}, nil)
+	mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{}).Return(ghutil.IssueClaLabelStatus{HasNo: true, Labels: []string{ghutil.LabelClaNo}})
+	expectListIssueLabels(ghutil.LabelClaNo)
+	mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, []string{ghutil.LabelClaYes}).Return(nil, nil, errors.New("server error"))
+
+	err := ghc.ProcessPullRequest(prSpec, claSigners, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true})
+	assert.Nil(t, err)
+}
+
+func TestProcessPullRequest_SkipLabels_BecomesCompliant_RequestsReviewersOnce(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The transition is recorded in the state store, so reviews are only
+	// requested the first time the PR is compliant.
+	ghc.State = state.NewMemoryStore()
+	reviewers := github.ReviewersRequest{Reviewers: []string{"reviewer"}}
+	mockGhc.PullRequests.EXPECT().RequestReviewers(any, orgName, repoName, pullNumber, reviewers).Return(nil, nil, nil)
+
+	for run := 0; run < 2; run++ {
+		runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+			PullRequestStatus: ghutil.PullRequestStatus{
+				Compliant: true,
+			},
+			UpdateRepo: true,
+			SkipLabels: true,
+			Reviewers: config.Reviewers{
+				Users: []string{"reviewer"},
+			},
+		})
+	}
+}
+
+func TestProcessPullRequest_SkipsUnchangedPullRequest(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	claSigners := config.ClaSigners{}
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.ClaSignersDigest = ghutil.ClaSignersDigest(claSigners)
+	prSpec.Pull.Head = &github.PullRequestBranch{SHA: github.String("abc123")}
+	prSpec.Pull.Labels = []*github.Label{{Name: github.String("CLA: no")}}
+
+	ghc.Api = mockGhc.Api
+	expectProcessing := func() {
+		mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(ghutil.PullRequestStatus{Compliant: true}, nil)
+		mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{}).Return(ghutil.IssueClaLabelStatus{HasNo: true, Labels: []string{"CLA: no"}})
+		expectListIssueLabels("CLA: no")
+		mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, []string{ghutil.LabelClaYes}).Return(nil, nil, nil)
+	}
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true}
+
+	expectProcessing()
+	assert.Nil(t, ghc.ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus))
+	pullState, _ := store.Get(state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber})
+	assert.Equal(t, "abc123", pullState.HeadSHA)
+	assert.Equal(t, []string{"cla: yes"}, pullState.Labels)
+
+	// Once the labels reflect the previous run, nothing is checked again.
+	prSpec.Pull.Labels = []*github.Label{{Name: github.String("cla: yes")}}
+	assert.Nil(t, ghc.ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus))
+
+	// A new commit requires checking the PR again.
+	prSpec.Pull.Head.SHA = github.String("def456")
+	prSpec.Pull.Labels = []*github.Label{{Name: github.String("cla: no")}}
+	expectProcessing()
+	assert.Nil(t, ghc.ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus))
+}
+
+func TestProcessPullRequest_SkipLabel(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Report = report.New()
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.Labels = config.Labels{Skip: "cla: skip"}
+	prSpec.Pull.Labels = []*github.Label{{Name: github.String("CLA: Skip")}}
+
+	// Neither the compliance nor the labels of the PR are checked.
+	err := ghc.ProcessPullRequest(prSpec, config.ClaSigners{}, ghutil.RepoClaLabelStatus{})
+	assert.Nil(t, err)
+	assert.Equal(t, []report.PullRequest{
+		{
+			Org:     orgName,
+			Repo:    repoName,
+			Number:  pullNumber,
+			Title:   "no title",
+			Skipped: true,
+			Reason:  "PR has [cla: skip] label",
+		},
+	}, ghc.Report.PullRequests)
+}
+
+func TestProcessPullRequest_BaseBranchNotChecked(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Report = report.New()
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.BaseBranches = []string{"main", "release-*"}
+	prSpec.Pull.Base = &github.PullRequestBranch{Ref: github.String("feature/cleanup")}
+
+	// Neither the compliance nor the labels of the PR are checked.
+	err := ghc.ProcessPullRequest(prSpec, config.ClaSigners{}, ghutil.RepoClaLabelStatus{})
+	assert.Nil(t, err)
+	assert.Equal(t, []report.PullRequest{
+		{
+			Org:     orgName,
+			Repo:    repoName,
+			Number:  pullNumber,
+			Title:   "no title",
+			Skipped: true,
+			Reason:  "PR targets branch feature/cleanup",
+		},
+	}, ghc.Report.PullRequests)
+}
+
+func TestMatchBranch(t *testing.T) {
+	patterns := []string{"main", "release-*"}
+	assert.True(t, ghutil.MatchBranch(patterns, "main"))
+	assert.True(t, ghutil.MatchBranch(patterns, "release-1.2"))
+	assert.False(t, ghutil.MatchBranch(patterns, "Main"))
+	assert.False(t, ghutil.MatchBranch(patterns, "feature/main"))
+}
+
+func TestParsePullURL(t *testing.T) {
+	for _, pullURL := range []string{
+		"https://github.com/org/repo/pull/123",
+		"https://github.com/org/repo/pull/123/files",
+		"https://github.example.com/org/repo/pull/123#issuecomment-1",
+	} {
+		org, repo, number, err := ghutil.ParsePullURL(pullURL)
+		assert.Nil(t, err, pullURL)
+		assert.Equal(t, "org", org, pullURL)
+		assert.Equal(t, "repo", repo, pullURL)
+		assert.Equal(t, 123, number, pullURL)
+	}
+
+	for _, pullURL := range []string{
+		"github.com/org/repo/pull/123",
+		"https://github.com/org/repo/issues/123",
+		"https://github.com/org/repo/pull/abc",
+		"https://github.com/org/repo/pull/0",
+		"https://github.com/org/repo",
+	} {
+		_, _, _, err := ghutil.ParsePullURL(pullURL)
+		assert.NotNil(t, err, pullURL)
+	}
+}
+
+func TestHasLabel(t *testing.T) {
+	pull := github.PullRequest{
+		Labels: []*github.Label{{Name: github.String("cla: skip")}},
+	}
+	assert.True(t, ghutil.HasLabel(&pull, "CLA: skip"))
+	assert.False(t, ghutil.HasLabel(&pull, "cla: yes"))
+}
+
+func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_NonCompliant_Update(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
@@ -618,23 +1651,68 @@ func TestProcessPullRequest_RepoHasLabels_PullHasYesLabel_NonCompliant(t *testin
 			HasYes: true,
 			HasNo:  true,
 		},
-		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasYes: true,
-		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
 		PullRequestStatus: ghutil.PullRequestStatus{
 			Compliant:           false,
 			NonComplianceReason: nonComplianceReason,
 		},
-		UpdateRepo:     true,
-		LabelsToAdd:    []string{ghutil.LabelClaNo},
-		LabelsToRemove: []string{ghutil.LabelClaYes},
+		UpdateRepo:  true,
+		LabelsToAdd: []string{ghutil.LabelClaNo},
 	})
 }
 
-func TestProcessPullRequest_RepoHasYesNoExternalHabels_PullHasYesLabel_External(t *testing.T) {
+func TestProcessPullRequest_RepoHasLabels_PullHasZeroLabels_External_Update(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External: true,
+		},
+		UpdateRepo:  true,
+		LabelsToAdd: []string{ghutil.LabelClaExternal},
+	})
+}
+
+func TestProcessPullRequest_External_CommentsManagedBy(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	comment := "The CLA of the contributors to this pull request is managed by EasyCLA; please check its status there."
+	issueComment := github.IssueComment{
+		Body: &comment,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External:  true,
+			ManagedBy: "EasyCLA",
+		},
+		UpdateRepo:  true,
+		LabelsToAdd: []string{ghutil.LabelClaExternal},
+	})
+}
+
+func TestProcessPullRequest_External_CountsLabelsAndComments(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
+	ghc.Counts = ghutil.NewRunCounts()
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, any).Return(nil, nil, nil)
+
 	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
 		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
 			HasYes:      true,
@@ -642,40 +1720,42 @@ func TestProcessPullRequest_RepoHasYesNoExternalHabels_PullHasYesLabel_External(
 			HasExternal: true,
 		},
 		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasYes: true,
+			HasNo: true,
 		},
 		PullRequestStatus: ghutil.PullRequestStatus{
-			External: true,
+			External:  true,
+			ManagedBy: "EasyCLA",
 		},
 		UpdateRepo:     true,
 		LabelsToAdd:    []string{ghutil.LabelClaExternal},
-		LabelsToRemove: []string{ghutil.LabelClaYes},
+		LabelsToRemove: []string{ghutil.LabelClaNo},
 	})
+
+	assert.Equal(t, report.RunSummary{LabelsAdded: 1, LabelsRemoved: 1, Comments: 1}, ghc.Counts.Summary())
 }
 
-func TestProcessPullRequest_RepoHasYesNoHabels_PullHasYesLabel_External(t *testing.T) {
+func TestProcessPullRequest_External_ManagedByNoCommentOnceLabeled(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
 	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
 		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
-			HasYes: true,
-			HasNo:  true,
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
 		},
 		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasYes: true,
+			HasExternal: true,
 		},
 		PullRequestStatus: ghutil.PullRequestStatus{
-			External: true,
+			External:  true,
+			ManagedBy: "EasyCLA",
 		},
 		UpdateRepo: true,
-		// The external label wouldn't be added in this case, since the
-		// repo doesn't have it.
-		LabelsToRemove: []string{ghutil.LabelClaYes},
 	})
 }
 
-func TestProcessPullRequest_RepoHasLabels_HasNoLabel_Compliant(t *testing.T) {
+func TestProcessPullRequest_RepoHasHabels_PullHasYesLabel_Compliant(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
@@ -685,63 +1765,1654 @@ func TestProcessPullRequest_RepoHasLabels_HasNoLabel_Compliant(t *testing.T) {
 			HasNo:  true,
 		},
 		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasNo: true,
+			HasYes: true,
 		},
 		PullRequestStatus: ghutil.PullRequestStatus{
 			Compliant: true,
 		},
-		UpdateRepo:     true,
-		LabelsToAdd:    []string{ghutil.LabelClaYes},
-		LabelsToRemove: []string{ghutil.LabelClaNo},
+		UpdateRepo: true,
+		// No labels to be added or removed in this case.
 	})
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasNoLabel_NonCompliant(t *testing.T) {
+func TestProcessPullRequest_RepoHasLabels_PullHasYesLabel_NonCompliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// When adding a "cla: no" label, we will also add a comment to the
+	// effect of why this PR got that label.
+	nonComplianceReason := "Your PR is not compliant"
+	issueComment := github.IssueComment{
+		Body: &nonComplianceReason,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaNo},
+		LabelsToRemove: []string{ghutil.LabelClaYes},
+	})
+}
+
+func TestProcessPullRequest_WritesDiffWithoutUpdatingRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	var buf bytes.Buffer
+	ghc.Diff = ghutil.NewDiffWriter(&buf)
+
+	// Without `UpdateRepo`, no changes are made, but they are all listed.
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+	})
+
+	assert.Equal(t, `org/repo#42 +label "cla: no"
+org/repo#42 -label "cla: yes"
+org/repo#42 +comment "Your PR is not compliant"
+`, buf.String())
+}
+
+func TestProcessPullRequest_RequestChanges_NonCompliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The explanation is posted as the body of the review rather than as a
+	// separate comment.
+	body := "Your PR is not compliant\n\n" + ghutil.ReviewMarker
+	event := "REQUEST_CHANGES"
+	review := github.PullRequestReviewRequest{
+		Body:  &body,
+		Event: &event,
+	}
+	mockGhc.PullRequests.EXPECT().ListReviews(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100}).Return(nil, nil, nil)
+	mockGhc.PullRequests.EXPECT().CreateReview(any, orgName, repoName, pullNumber, &review).Return(nil, nil, nil)
+	ghc.Counts = ghutil.NewRunCounts()
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:     true,
+		RequestChanges: true,
+		LabelsToAdd:    []string{ghutil.LabelClaNo},
+	})
+	// The review counts as the comment.
+	assert.Equal(t, 1, ghc.Counts.Summary().Comments)
+}
+
+func TestProcessPullRequest_RequestChanges_NonCompliant_AlreadyRequested(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	reviews := []*github.PullRequestReview{
+		{
+			ID:    github.Int64(7),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("Your PR is not compliant\n\n" + ghutil.ReviewMarker),
+		},
+	}
+	mockGhc.PullRequests.EXPECT().ListReviews(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100}).Return(reviews, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:     true,
+		RequestChanges: true,
+		SkipLabels:     true,
+	})
+}
+
+func TestProcessPullRequest_RequestChanges_NonCompliant_AlreadyRequestedOnLaterPage(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// Our active review is found even if it isn't on the first page.
+	others := []*github.PullRequestReview{
+		{
+			ID:    github.Int64(7),
+			State: github.String("COMMENTED"),
+			Body:  github.String("Looks good."),
+		},
+	}
+	ours := []*github.PullRequestReview{
+		{
+			ID:    github.Int64(8),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("Your PR is not compliant\n\n" + ghutil.ReviewMarker),
+		},
+	}
+	gomock.InOrder(
+		mockGhc.PullRequests.EXPECT().ListReviews(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100}).Return(others, &github.Response{NextPage: 2}, nil),
+		mockGhc.PullRequests.EXPECT().ListReviews(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100, Page: 2}).Return(ours, &github.Response{}, nil),
+	)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:     true,
+		RequestChanges: true,
+		SkipLabels:     true,
+	})
+}
+
+func TestProcessPullRequest_RequestChanges_Compliant_DismissesReview(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// Only our own reviews which still request changes are dismissed.
+	reviews := []*github.PullRequestReview{
+		{
+			ID:    github.Int64(7),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("Your PR is not compliant\n\n" + ghutil.ReviewMarker),
+		},
+		{
+			ID:    github.Int64(8),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("Please fix the typo."),
+		},
+		{
+			ID:    github.Int64(9),
+			State: github.String("DISMISSED"),
+			Body:  github.String("Your PR is not compliant\n\n" + ghutil.ReviewMarker),
+		},
+	}
+	message := "CLA requirements are now satisfied."
+	dismissal := github.PullRequestReviewDismissalRequest{
+		Message: &message,
+	}
+	mockGhc.PullRequests.EXPECT().ListReviews(any, orgName, repoName, pullNumber, &github.ListOptions{PerPage: 100}).Return(reviews, nil, nil)
+	mockGhc.PullRequests.EXPECT().DismissReview(any, orgName, repoName, pullNumber, int64(7), &dismissal).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo:     true,
+		RequestChanges: true,
+		SkipLabels:     true,
+	})
+}
+
+func TestProcessPullRequest_MinimizeComments(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The new comment is marked as ours, and our earlier comments, but no
+	// others, are minimized, even if they quote the marker.
+	bot := &github.User{Login: github.String("crbot")}
+	contributor := &github.User{Login: github.String("jane")}
+	body := "Your PR is not compliant\n\n" + ghutil.CommentMarker
+	latest := &github.IssueComment{ID: github.Int64(4), NodeID: github.String("IC_4"), Body: &body, User: bot}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &github.IssueComment{Body: &body}).Return(latest, nil, nil)
+	comments := []*github.IssueComment{
+		{ID: github.Int64(1), NodeID: github.String("IC_1"), Body: github.String("Please sign the CLA.\n\n" + ghutil.CommentMarker), User: bot},
+		{ID: github.Int64(2), NodeID: github.String("IC_2"), Body: github.String("I signed it!"), User: contributor},
+		{ID: github.Int64(3), NodeID: github.String("IC_3"), Body: github.String("> Please sign the CLA.\n> " + ghutil.CommentMarker + "\n\nDone."), User: contributor},
+		latest,
+	}
+	mockGhc.Issues.EXPECT().ListComments(any, orgName, repoName, pullNumber, any).Return(comments, nil, nil)
+	mockGhc.GraphQL.EXPECT().MinimizeComment(any, "IC_1", "OUTDATED").Return(nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:       true,
+		MinimizeComments: true,
+		LabelsToAdd:      []string{ghutil.LabelClaNo},
+		LabelsToRemove:   []string{ghutil.LabelClaYes},
+	})
+}
+
+// expectCommitStatus expects the commit status to be set on the head commit.
+func expectCommitStatus(headSHA string, state string, description string, targetURL string) *gomock.Call {
+	status := &github.RepoStatus{
+		State:       &state,
+		Description: &description,
+		Context:     github.String(ghutil.DefaultStatusContext),
+	}
+	if targetURL != "" {
+		status.TargetURL = &targetURL
+	}
+	return mockGhc.Repositories.EXPECT().CreateStatus(any, orgName, repoName, headSHA, status).Return(status, nil, nil)
+}
+
+func TestProcessPullRequest_CommitStatus_Compliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	gomock.InOrder(
+		expectCommitStatus("abc123", "pending", "Checking CLA compliance...", ""),
+		expectCommitStatus("abc123", "success", "All commits are covered by a CLA", ""),
+	)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo:   true,
+		HeadSHA:      "abc123",
+		CommitStatus: config.CommitStatus{Enabled: true},
+	})
+}
+
+func TestProcessPullRequest_CommitStatus_NonCompliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The description is truncated to the length accepted by GitHub, and
+	// the status links to the CLA.
+	reason := strings.Repeat("x", 200)
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, any).Return(nil, nil, nil)
+	gomock.InOrder(
+		expectCommitStatus("abc123", "pending", "Checking CLA compliance...", ""),
+		expectCommitStatus("abc123", "failure", strings.Repeat("x", 137)+"...", "https://cla.example.com"),
+	)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			NonComplianceReason: reason,
+		},
+		UpdateRepo:   true,
+		ClaURL:       "https://cla.example.com",
+		HeadSHA:      "abc123",
+		CommitStatus: config.CommitStatus{Enabled: true},
+		LabelsToAdd:  []string{ghutil.LabelClaNo},
+	})
+}
+
+func TestProcessPullRequest_CommitStatus_WritesDiffWithoutUpdatingRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	var buf bytes.Buffer
+	ghc.Diff = ghutil.NewDiffWriter(&buf)
+
+	// Only the final status is listed, as the pending one is transient.
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasExternal: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External: true,
+		},
+		HeadSHA:      "abc123",
+		CommitStatus: config.CommitStatus{Enabled: true},
+	})
+
+	assert.Equal(t, `org/repo#42 +status "success: CLA compliance is managed externally"
+`, buf.String())
+}
+
+func TestProcessPullRequest_CommitStatus_CheckRun(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// No pending status is set; the check run annotates each non-compliant
+	// commit with the identities which failed to match.
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, any).Return(nil, nil, nil)
+	var created github.CreateCheckRunOptions
+	mockGhc.Checks.EXPECT().CreateCheckRun(any, orgName, repoName, any).DoAndReturn(
+		func(ctx context.Context, owner string, repo string, opt github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+			created = opt
+			return &github.CheckRun{}, nil, nil
+		})
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			NonComplianceReason: "Committer of one or more commits is not listed as a CLA signer",
+			Commits: []ghutil.CommitStatus{
+				{SHA: "abc123", Compliant: true},
+				{
+					SHA:                 "def456",
+					NonComplianceReason: "Committer is not listed as a CLA signer",
+					Unmatched: []ghutil.UnmatchedIdentity{
+						{Role: ghutil.RoleCommitter, Account: config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"}},
+					},
+				},
+			},
+		},
+		UpdateRepo:   true,
+		ClaURL:       "https://cla.example.com",
+		HeadSHA:      "def456",
+		CommitStatus: config.CommitStatus{Enabled: true, CheckRun: true},
+		LabelsToAdd:  []string{ghutil.LabelClaNo},
+	})
+
+	assert.Equal(t, ghutil.DefaultStatusContext, created.Name)
+	assert.Equal(t, "def456", created.HeadSHA)
+	assert.Equal(t, "failure", created.GetConclusion())
+	assert.Equal(t, "https://cla.example.com", created.GetDetailsURL())
+	assert.Equal(t, []*github.CheckRunAnnotation{
+		{
+			Path:            github.String(".github"),
+			StartLine:       github.Int(1),
+			EndLine:         github.Int(1),
+			AnnotationLevel: github.String("failure"),
+			Title:           github.String("Commit def456"),
+			Message:         github.String("Committer is not listed as a CLA signer (unmatched: committer Jane Doe <jane@example.com>, GitHub: jane-doe)"),
+		},
+	}, created.Output.Annotations)
+}
+
+func TestProcessPullRequest_CommitStatus_ComplianceError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.Pull.Head = &github.PullRequestBranch{SHA: github.String("abc123")}
+	prSpec.CommitStatus = config.CommitStatus{Enabled: true, Context: "cla"}
+
+	// The status doesn't stay pending when the PR can't be checked.
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, config.ClaSigners{}).Return(ghutil.PullRequestStatus{}, errors.New("API error"))
+	var states []string
+	mockGhc.Repositories.EXPECT().CreateStatus(any, orgName, repoName, "abc123", any).Times(2).DoAndReturn(
+		func(ctx context.Context, owner string, repo string, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+			assert.Equal(t, "cla", status.GetContext())
+			states = append(states, status.GetState())
+			return status, nil, nil
+		})
+
+	err := ghc.ProcessPullRequest(prSpec, config.ClaSigners{}, ghutil.RepoClaLabelStatus{})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"pending", "error"}, states)
+}
+
+func TestProcessPullRequest_NonCompliant_CommentAlreadyClaimed(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// Both runs share the state store, as would two instances handling
+	// the same event; only the first one posts the comment.
+	ghc.State = state.NewMemoryStore()
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, gomock.Any()).Return(nil, nil, nil).Times(1)
+
+	params := ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:  true,
+		LabelsToAdd: []string{ghutil.LabelClaNo},
+	}
+	runProcessPullRequestTestScenario(t, params)
+	runProcessPullRequestTestScenario(t, params)
+}
+
+func TestProcessPullRequest_NonCompliant_SendsReminder(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	commented := time.Now().UTC().Add(-8 * 24 * time.Hour)
+	store.Put(key, state.PullState{Commented: commented})
+
+	reminder := "Hi, this is a friendly reminder that this pull request is still waiting on the CLA.\n\nYour PR is not compliant"
+	issueComment := github.IssueComment{
+		Body: &reminder,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo: true,
+		Reminders:  config.Reminders{IntervalDays: 7, Max: 3},
+	})
+
+	pullState, _ := store.Get(key)
+	assert.Equal(t, commented, pullState.Commented)
+	assert.Equal(t, 1, pullState.Reminders)
+	assert.False(t, pullState.LastReminder.IsZero())
+}
+
+func TestProcessPullRequest_NonCompliant_NoReminderBeforeInterval(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{
+		Commented:    time.Now().UTC().Add(-30 * 24 * time.Hour),
+		Reminders:    1,
+		LastReminder: time.Now().UTC().Add(-2 * 24 * time.Hour),
+	})
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo: true,
+		Reminders:  config.Reminders{IntervalDays: 7},
+	})
+}
+
+func TestProcessPullRequest_NonCompliant_NoReminderAfterMax(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{
+		Commented:    time.Now().UTC().Add(-60 * 24 * time.Hour),
+		Reminders:    3,
+		LastReminder: time.Now().UTC().Add(-30 * 24 * time.Hour),
+	})
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo: true,
+		Reminders:  config.Reminders{IntervalDays: 7, Max: 3},
+	})
+}
+
+func TestProcessPullRequest_NonCompliant_CommentCappedPerSHA(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{CommentedSHA: "abc123", CommentsAtSHA: 1})
+
+	// The [cla: no] label was removed by hand, so it is added again, but
+	// the comment was already posted on the same head commit.
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:        true,
+		MaxCommentsPerSHA: 1,
+		HeadSHA:           "abc123",
+		LabelsToAdd:       []string{ghutil.LabelClaNo},
+	})
+
+	pullState, _ := store.Get(key)
+	assert.Equal(t, "abc123", pullState.CommentedSHA)
+	assert.Equal(t, 2, pullState.CommentsAtSHA)
+}
+
+func TestProcessPullRequest_NonCompliant_CommentOnNewSHA(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{CommentedSHA: "abc123", CommentsAtSHA: 3})
+
+	nonComplianceReason := "Your PR is not compliant"
+	issueComment := github.IssueComment{
+		Body: &nonComplianceReason,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo:        true,
+		MaxCommentsPerSHA: 1,
+		HeadSHA:           "def456",
+		LabelsToAdd:       []string{ghutil.LabelClaNo},
+	})
+
+	pullState, _ := store.Get(key)
+	assert.Equal(t, "def456", pullState.CommentedSHA)
+	assert.Equal(t, 1, pullState.CommentsAtSHA)
+}
+
+func TestProcessPullRequest_Compliant_ForgetsReminders(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{Commented: time.Now().UTC(), Reminders: 1})
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo: true,
+	})
+
+	// Only the compliance status of the PR is remembered.
+	pullState, _ := store.Get(key)
+	assert.Equal(t, state.PullState{Status: report.StatusCompliant}, pullState)
+}
+
+// recordingPublisher records the events published to it.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestProcessPullRequest_BecomesCompliant_PublishesLabelsChanged(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaYes},
+		LabelsToRemove: []string{ghutil.LabelClaNo},
+	})
+
+	assert.Equal(t, 1, len(publisher.events))
+	event := publisher.events[0]
+	assert.Equal(t, events.TypeLabelsChanged, event.Type)
+	assert.Equal(t, orgName, event.Org)
+	assert.Equal(t, repoName, event.Repo)
+	assert.Equal(t, pullNumber, event.Number)
+	assert.Equal(t, report.StatusCompliant, event.Status)
+	assert.Equal(t, report.StatusNonCompliant, event.PreviousStatus)
+	assert.Equal(t, []string{ghutil.LabelClaYes}, event.Added)
+	assert.Equal(t, []string{ghutil.LabelClaNo}, event.Removed)
+}
+
+func TestProcessPullRequest_NoUpdateRepo_DoesNotPublish(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+	})
+
+	assert.Equal(t, 0, len(publisher.events))
+}
+
+func TestProcessPullRequest_PublishChecks(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+	ghc.PublishChecks = true
+
+	// The decision is published even though nothing changes.
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+	})
+
+	if assert.Equal(t, 1, len(publisher.events)) {
+		event := publisher.events[0]
+		assert.Equal(t, events.TypeChecked, event.Type)
+		assert.Equal(t, pullNumber, event.Number)
+		assert.Equal(t, report.StatusCompliant, event.Status)
+		assert.Empty(t, event.Added)
+	}
+}
+
+func TestProcessPullRequest_Regression_PublishesAlert(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{Status: report.StatusCompliant})
+	nonComplianceReason := "Your PR is not compliant"
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo: true,
+	})
+
+	var regressions []events.Event
+	for _, event := range publisher.events {
+		if event.Type == events.TypeRegressed {
+			regressions = append(regressions, event)
+		}
+	}
+	if assert.Equal(t, 1, len(regressions)) {
+		event := regressions[0]
+		assert.Equal(t, pullNumber, event.Number)
+		assert.Equal(t, report.StatusNonCompliant, event.Status)
+		assert.Equal(t, report.StatusCompliant, event.PreviousStatus)
+		assert.Equal(t, nonComplianceReason, event.Reason)
+		assert.Equal(t, events.SeverityHigh, event.Severity)
+	}
+	pullState, _ := store.Get(key)
+	assert.Equal(t, report.StatusNonCompliant, pullState.Status)
+}
+
+func TestProcessPullRequest_Regression_FromCompliantLabel(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+	nonComplianceReason := "Your PR is not compliant"
+	issueComment := github.IssueComment{
+		Body: &nonComplianceReason,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaNo},
+		LabelsToRemove: []string{ghutil.LabelClaYes},
+	})
+
+	var types []string
+	for _, event := range publisher.events {
+		types = append(types, event.Type)
+	}
+	assert.Contains(t, types, events.TypeRegressed)
+}
+
+func TestProcessPullRequest_NonCompliant_NoRegression(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	publisher := &recordingPublisher{}
+	ghc.Events = publisher
+	store := state.NewMemoryStore()
+	ghc.State = store
+	key := state.PullKey{Org: orgName, Repo: repoName, Number: pullNumber}
+	store.Put(key, state.PullState{Status: report.StatusNonCompliant})
+	nonComplianceReason := "Your PR is not compliant"
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo: true,
+	})
+
+	assert.Equal(t, 0, len(publisher.events))
+}
+
+func TestProcessPullRequest_NonCompliant_CommentIncludesClaURL(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	nonComplianceReason := "Your PR is not compliant"
+	claURL := "https://cla.example.com/sign"
+	expectedComment := nonComplianceReason + "\n\nPlease sign the Contributor License Agreement (CLA) at " + claURL +
+		" before we can accept your contribution. Once you've signed it (or fixed any problems above), the CLA status of this pull request will be updated automatically."
+	issueComment := github.IssueComment{
+		Body: &expectedComment,
+	}
+	mockGhc.Issues.EXPECT().CreateComment(any, orgName, repoName, pullNumber, &issueComment).Return(nil, nil, nil)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: nonComplianceReason,
+		},
+		UpdateRepo:  true,
+		ClaURL:      claURL,
+		LabelsToAdd: []string{ghutil.LabelClaNo},
+	})
+}
+
+func TestProcessPullRequest_NonCompliant_NoComments(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	// The PR is only labeled; no CreateComment call is expected.
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+		},
+		UpdateRepo:  true,
+		NoComments:  true,
+		LabelsToAdd: []string{ghutil.LabelClaNo},
+	})
+}
+
+func TestProcessPullRequest_RepoHasYesNoExternalHabels_PullHasYesLabel_External(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External: true,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaExternal},
+		LabelsToRemove: []string{ghutil.LabelClaYes},
+	})
+}
+
+func TestProcessPullRequest_RepoHasYesNoHabels_PullHasYesLabel_External(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasYes: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External: true,
+		},
+		UpdateRepo: true,
+		// The external label wouldn't be added in this case, since the
+		// repo doesn't have it.
+		LabelsToRemove: []string{ghutil.LabelClaYes},
+	})
+}
+
+func TestProcessPullRequest_RepoHasLabels_HasNoLabel_Compliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaYes},
+		LabelsToRemove: []string{ghutil.LabelClaNo},
+	})
+}
+
+func TestProcessPullRequest_RepoHasLabels_PullHasNoLabel_NonCompliant(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: false,
+		},
+		UpdateRepo: true,
+		// No labels to be added or removed in this case.
+	})
+}
+
+func TestProcessPullRequest_RepoHasLabels_PullHasNoLabel_External(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes:      true,
+			HasNo:       true,
+			HasExternal: true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			External: true,
+		},
+		UpdateRepo:     true,
+		LabelsToAdd:    []string{ghutil.LabelClaExternal},
+		LabelsToRemove: []string{ghutil.LabelClaNo},
+	})
+}
+
+func TestProcessPullRequest_RecordsReport(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Report = report.New()
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant:           false,
+			NonComplianceReason: "Your PR is not compliant",
+			Commits: []ghutil.CommitStatus{
+				{
+					SHA:                 "abc123def456",
+					Compliant:           false,
+					NonComplianceReason: "Your PR is not compliant",
+				},
+			},
+		},
+		UpdateRepo: true,
+	})
+
+	assert.Equal(t, 1, len(ghc.Report.PullRequests))
+	reportPull := ghc.Report.PullRequests[0]
+	assert.Equal(t, orgName, reportPull.Org)
+	assert.Equal(t, repoName, reportPull.Repo)
+	assert.Equal(t, pullNumber, reportPull.Number)
+	assert.False(t, reportPull.Compliant)
+	assert.Equal(t, []report.Commit{
+		{
+			SHA:       "abc123def456",
+			Compliant: false,
+			Reason:    "Your PR is not compliant",
+		},
+	}, reportPull.Commits)
+}
+
+func TestProcessPullRequest_RecordsLabelChangesAndErrors(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Report = report.New()
+
+	expectListIssueLabels(ghutil.LabelClaNo)
+	mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, []string{ghutil.LabelClaYes}).Return(nil, nil, errors.New("403 Forbidden"))
+
+	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
+		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
+			HasYes: true,
+			HasNo:  true,
+		},
+		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
+			HasNo: true,
+		},
+		PullRequestStatus: ghutil.PullRequestStatus{
+			Compliant: true,
+		},
+		UpdateRepo: true,
+	})
+
+	assert.Equal(t, 1, len(ghc.Report.PullRequests))
+	reportPull := ghc.Report.PullRequests[0]
+	assert.Equal(t, []string{ghutil.LabelClaYes}, reportPull.LabelsAdded)
+	assert.Equal(t, []string{ghutil.LabelClaNo}, reportPull.LabelsRemoved)
+	assert.Equal(t, []string{"Error updating labels of repo 'org/repo' PR 42: 403 Forbidden"}, ghc.Report.Errors)
+}
+
+func TestProcessOrgRepo_SpecifiedPrs(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	pullNumber1 := 42
+	pullTitle1 := "pull 42 title"
+	pullRequest1 := github.PullRequest{
+		Number: &pullNumber1,
+		Title:  &pullTitle1,
+	}
+	pullNumber2 := 43
+	pullTitle2 := "pull 43 title"
+	pullRequest2 := github.PullRequest{
+		Number: &pullNumber2,
+		Title:  &pullTitle2,
+	}
+	mockGhc.PullRequests.EXPECT().Get(any, orgName, repoName, pullNumber1).Return(&pullRequest1, nil, nil)
+	mockGhc.PullRequests.EXPECT().Get(any, orgName, repoName, pullNumber2).Return(&pullRequest2, nil, nil)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+
+	prSpec1 := ghutil.GitHubProcessSinglePullSpec{
+		Org:  orgName,
+		Repo: repoName,
+		Pull: &pullRequest1,
+	}
+	prSpec2 := ghutil.GitHubProcessSinglePullSpec{
+		Org:  orgName,
+		Repo: repoName,
+		Pull: &pullRequest2,
+	}
+	mockGhc.Api.EXPECT().ProcessPullRequest(prSpec1, claSigners, repoClaLabelStatus)
+	mockGhc.Api.EXPECT().ProcessPullRequest(prSpec2, claSigners, repoClaLabelStatus)
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:   orgName,
+		Repo:  repoName,
+		Pulls: []int{pullNumber1, pullNumber2},
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+}
+
+func TestProcessOrgRepo_AllPrs(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	pullNumber1 := 42
+	pullTitle1 := "pull 42 title"
+	pullNumber2 := 43
+	pullTitle2 := "pull 43 title"
+	pullRequests := []*github.PullRequest{
+		{
+			Number: &pullNumber1,
+			Title:  &pullTitle1,
+		},
+		{
+			Number: &pullNumber2,
+			Title:  &pullTitle2,
+		},
+	}
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests, nil, nil)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+
+	for _, pull := range pullRequests {
+		prSpec := ghutil.GitHubProcessSinglePullSpec{
+			Org:  orgName,
+			Repo: repoName,
+			Pull: pull,
+		}
+		mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+	}
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:  orgName,
+		Repo: repoName,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+}
+
+func TestGetRepoConfig_NotFound(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, repoName, config.RepoConfigPath, nil).Return(nil, nil, nil, notFoundError())
+
+	repoConfig, err := ghc.GetRepoConfig(orgName, repoName)
+	assert.Nil(t, err)
+	assert.Equal(t, config.RepoConfig{}, repoConfig)
+}
+
+func TestGetRepoConfig_Found(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	content := "skip: true\nlabels:\n  compliant: \"cla: signed\"\n"
+	fileContent := github.RepositoryContent{
+		Content: &content,
+	}
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, repoName, config.RepoConfigPath, nil).Return(&fileContent, nil, nil, nil)
+
+	repoConfig, err := ghc.GetRepoConfig(orgName, repoName)
+	assert.Nil(t, err)
+	assert.True(t, repoConfig.Skip)
+	assert.Equal(t, "cla: signed", repoConfig.Labels.Compliant)
+}
+
+func TestFetchFile_AtRef(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	content := "people: []\n"
+	fileContent := github.RepositoryContent{
+		Content: &content,
+	}
+	opt := &github.RepositoryContentGetOptions{Ref: "main"}
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, "cla-config", "signers.yaml", opt).Return(&fileContent, nil, nil, nil)
+
+	data, err := ghc.FetchFile(orgName, "cla-config", "signers.yaml", "main")
+	assert.Nil(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestFetchFile_NotFound(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, "cla-config", "signers.yaml", nil).Return(nil, nil, nil, notFoundError())
+
+	_, err := ghc.FetchFile(orgName, "cla-config", "signers.yaml", "")
+	assert.EqualError(t, err, "no file signers.yaml in repo 'org/cla-config'")
+}
+
+func TestApplyRepoConfig(t *testing.T) {
+	unknownAsExternal := false
+	prSpec := ghutil.GitHubProcessSinglePullSpec{
+		UnknownAsExternal: true,
+		ClaURL:            "https://global.example.com",
+		Locale:            "fr",
+		Labels: config.Labels{
+			Compliant: "global: yes",
+			External:  "global: external",
+		},
+	}
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{
+		UnknownAsExternal: &unknownAsExternal,
+		ClaURL:            "https://repo.example.com",
+		Labels: config.Labels{
+			Compliant:    "repo: yes",
+			NonCompliant: "repo: no",
+		},
+	})
+
+	assert.False(t, prSpec.UnknownAsExternal)
+	assert.Equal(t, "https://repo.example.com", prSpec.ClaURL)
+	assert.Equal(t, "fr", prSpec.Locale)
+	assert.Equal(t, config.Labels{
+		Compliant:    "repo: yes",
+		NonCompliant: "repo: no",
+		External:     "global: external",
+	}, prSpec.Labels)
+}
+
+func TestApplyRepoConfig_Reviewers(t *testing.T) {
+	prSpec := ghutil.GitHubProcessSinglePullSpec{
+		Reviewers: config.Reviewers{Users: []string{"global-reviewer"}},
+	}
+
+	// Empty reviewers in the repo config keep the global ones.
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{})
+	assert.Equal(t, config.Reviewers{Users: []string{"global-reviewer"}}, prSpec.Reviewers)
+
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{
+		Reviewers: config.Reviewers{Teams: []string{"repo-team"}},
+	})
+	assert.Equal(t, config.Reviewers{Teams: []string{"repo-team"}}, prSpec.Reviewers)
+}
+
+func TestApplyRepoConfig_NoComments(t *testing.T) {
+	prSpec := ghutil.GitHubProcessSinglePullSpec{NoComments: true}
+
+	// Unset in the repo config keeps the global setting.
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{})
+	assert.True(t, prSpec.NoComments)
+
+	noComments := false
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{NoComments: &noComments})
+	assert.False(t, prSpec.NoComments)
+}
+
+func TestApplyRepoConfig_TrustLevel(t *testing.T) {
+	prSpec := ghutil.GitHubProcessSinglePullSpec{}
+
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{TrustLevel: ghutil.TrustVerified})
+	assert.Equal(t, ghutil.TrustVerified, prSpec.TrustLevel)
+
+	// Invalid trust levels are ignored.
+	ghutil.ApplyRepoConfig(&prSpec, config.RepoConfig{TrustLevel: "signed"})
+	assert.Equal(t, ghutil.TrustVerified, prSpec.TrustLevel)
+}
+
+func TestProcessOrgRepo_SkippedByRepoConfig(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{Skip: true}, nil)
+
+	// No pull requests should be listed or processed.
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:  orgName,
+		Repo: repoName,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+}
+
+func TestGetOrgConfig_NotFound(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, config.OrgConfigRepo, config.OrgConfigPath, nil).Return(nil, nil, nil, notFoundError())
+
+	orgConfig, err := ghc.GetOrgConfig(orgName)
+	assert.Nil(t, err)
+	assert.Equal(t, config.OrgConfig{}, orgConfig)
+}
+
+func TestGetOrgConfig_Found(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	content := "cla_url: https://cla.example.com\nexclude_repos:\n  - website\nlabels:\n  external: \"cla: external\"\n"
+	fileContent := github.RepositoryContent{
+		Content: &content,
+	}
+	mockGhc.Repositories.EXPECT().GetContents(any, orgName, config.OrgConfigRepo, config.OrgConfigPath, nil).Return(&fileContent, nil, nil, nil)
+
+	orgConfig, err := ghc.GetOrgConfig(orgName)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://cla.example.com", orgConfig.ClaURL)
+	assert.Equal(t, []string{"website"}, orgConfig.ExcludeRepos)
+	assert.Equal(t, "cla: external", orgConfig.Labels.External)
+}
+
+func TestProcessOrgRepo_ExcludedByOrgConfig(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{ExcludeRepos: []string{repoName}}, nil)
+
+	// Neither the repo config nor any pull requests should be read.
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:  orgName,
+		Repo: repoName,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+}
+
+func TestProcessOrgRepo_OrgConfigOverriddenByRepoConfig(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	orgConfig := config.OrgConfig{
+		RepoConfig: config.RepoConfig{
+			ClaURL: "https://org.example.com",
+			Labels: config.Labels{
+				Compliant:    "org: yes",
+				NonCompliant: "org: no",
+			},
+		},
+	}
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(orgConfig, nil)
+
+	repoConfig := config.RepoConfig{
+		Labels: config.Labels{
+			NonCompliant: "repo: no",
+		},
+	}
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(repoConfig, nil)
+
+	pullNumber := 42
+	pullRequest := github.PullRequest{
+		Number: &pullNumber,
+	}
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return([]*github.PullRequest{&pullRequest}, nil, nil)
+
+	labels := config.Labels{
+		Compliant:    "org: yes",
+		NonCompliant: "repo: no",
+	}
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, labels).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+	prSpec := ghutil.GitHubProcessSinglePullSpec{
+		Org:    orgName,
+		Repo:   repoName,
+		Pull:   &pullRequest,
+		ClaURL: "https://org.example.com",
+		Labels: labels,
+	}
+	mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:  orgName,
+		Repo: repoName,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+}
+
+func TestRepoSkipReason(t *testing.T) {
+	archived := true
+	fork := true
+	assert.Equal(t, "", ghutil.RepoSkipReason(&github.Repository{}, true))
+	assert.Equal(t, "repo is archived", ghutil.RepoSkipReason(&github.Repository{Archived: &archived}, false))
+	assert.Equal(t, "", ghutil.RepoSkipReason(&github.Repository{Fork: &fork}, false))
+	assert.Equal(t, "repo is a fork", ghutil.RepoSkipReason(&github.Repository{Fork: &fork}, true))
+}
+
+func TestProcessOrgRepo_SkipsArchivedAndForkedRepos(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	archivedRepoName := "archived-repo"
+	forkRepoName := "forked-repo"
+	archived := true
+	fork := true
+	repos := []*github.Repository{
+		{
+			Name:     &archivedRepoName,
+			Archived: &archived,
+		},
+		{
+			Name: &forkRepoName,
+			Fork: &fork,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, "").Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	// Neither repo should have its config or pull requests read.
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:       orgName,
+		SkipForks: true,
+	}
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+}
+
+// usageWithCalls returns a usage tracker which has already counted the given
+// number of API calls.
+func usageWithCalls(calls int) *ghutil.UsageTransport {
+	usage := ghutil.NewUsageTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+	}))
+	for i := 0; i < calls; i++ {
+		req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+		usage.RoundTrip(req)
+	}
+	return usage
+}
+
+func TestProcessOrgRepo_StopsWhenBudgetExhausted(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	// No further calls should be made once the budget is exhausted.
+	ghc.Usage = usageWithCalls(2)
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:         orgName,
+		Repo:        repoName,
+		MaxAPICalls: 2,
+	}
+	checkpoint, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+	assert.Equal(t, &ghutil.Checkpoint{Org: orgName, Repo: repoName}, checkpoint)
+}
+
+func TestProcessOrgRepo_StopsAtPullLimit(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
-		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
-			HasYes: true,
-			HasNo:  true,
-		},
-		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasNo: true,
-		},
-		PullRequestStatus: ghutil.PullRequestStatus{
-			Compliant: false,
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
 		},
-		UpdateRepo: true,
-		// No labels to be added or removed in this case.
-	})
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	pullNumbers := []int{44, 43, 42}
+	pullRequests := make([]*github.PullRequest, len(pullNumbers))
+	for idx := range pullNumbers {
+		pullRequests[idx] = &github.PullRequest{Number: &pullNumbers[idx]}
+	}
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests, nil, nil)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	// Only the first two PRs are processed.
+	claSigners := config.ClaSigners{}
+	for _, pull := range pullRequests[:2] {
+		prSpec := ghutil.GitHubProcessSinglePullSpec{
+			Org:  orgName,
+			Repo: repoName,
+			Pull: pull,
+		}
+		mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+	}
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:      orgName,
+		Repo:     repoName,
+		MaxPulls: 2,
+	}
+	checkpoint, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+	assert.Equal(t, &ghutil.Checkpoint{Org: orgName, Repo: repoName, Pull: 42}, checkpoint)
 }
 
-func TestProcessPullRequest_RepoHasLabels_PullHasNoLabel_External(t *testing.T) {
+func TestProcessOrgRepo_ListsAllPagesOfPulls(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
-	runProcessPullRequestTestScenario(t, ProcessPullRequest_TestParams{
-		RepoClaLabelStatus: ghutil.RepoClaLabelStatus{
-			HasYes:      true,
-			HasNo:       true,
-			HasExternal: true,
-		},
-		IssueClaLabelStatus: ghutil.IssueClaLabelStatus{
-			HasNo: true,
-		},
-		PullRequestStatus: ghutil.PullRequestStatus{
-			External: true,
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &localRepoName,
 		},
-		UpdateRepo:     true,
-		LabelsToAdd:    []string{ghutil.LabelClaExternal},
-		LabelsToRemove: []string{ghutil.LabelClaNo},
-	})
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	pullNumbers := []int{44, 43, 42}
+	pullRequests := make([]*github.PullRequest, len(pullNumbers))
+	for idx := range pullNumbers {
+		pullRequests[idx] = &github.PullRequest{Number: &pullNumbers[idx]}
+	}
+	firstPage := &github.PullRequestListOptions{
+		Sort:        "updated",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	secondPage := *firstPage
+	secondPage.Page = 2
+	gomock.InOrder(
+		mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, firstPage).Return(pullRequests[:2], &github.Response{NextPage: 2}, nil),
+		mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, &secondPage).Return(pullRequests[2:], &github.Response{}, nil),
+	)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+	for _, pull := range pullRequests {
+		prSpec := ghutil.GitHubProcessSinglePullSpec{
+			Org:  orgName,
+			Repo: repoName,
+			Pull: pull,
+		}
+		mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+	}
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:       orgName,
+		Repo:      repoName,
+		PullOrder: ghutil.OrderUpdatedAsc,
+	}
+	checkpoint, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+	assert.Nil(t, checkpoint)
 }
 
-func TestProcessOrgRepo_SpecifiedPrs(t *testing.T) {
+func TestProcessOrgRepo_TracksProgressAndCounts(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
+	now := time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)
+	ghc.Progress = ghutil.NewProgress(now.Add(-time.Minute))
+	ghc.Counts = ghutil.NewRunCounts()
+
 	localRepoName := repoName
 	repos := []*github.Repository{
 		{
@@ -749,54 +3420,110 @@ func TestProcessOrgRepo_SpecifiedPrs(t *testing.T) {
 		},
 	}
 
-	ghc.GetAllRepos = mockGhc.Api.GetAllRepos
-	mockGhc.Api.EXPECT().GetAllRepos(ghc, orgName, repoName).Return(repos)
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
 
-	pullNumber1 := 42
-	pullTitle1 := "pull 42 title"
-	pullRequest1 := github.PullRequest{
-		Number: &pullNumber1,
-		Title:  &pullTitle1,
-	}
-	pullNumber2 := 43
-	pullTitle2 := "pull 43 title"
-	pullRequest2 := github.PullRequest{
-		Number: &pullNumber2,
-		Title:  &pullTitle2,
+	pullNumbers := []int{42, 43, 44}
+	pullRequests := make([]*github.PullRequest, len(pullNumbers))
+	for idx := range pullNumbers {
+		pullRequests[idx] = &github.PullRequest{Number: &pullNumbers[idx]}
 	}
-	mockGhc.PullRequests.EXPECT().Get(any, orgName, repoName, pullNumber1).Return(&pullRequest1, nil, nil)
-	mockGhc.PullRequests.EXPECT().Get(any, orgName, repoName, pullNumber2).Return(&pullRequest2, nil, nil)
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests, nil, nil)
 
 	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
 
-	ghc.GetRepoClaLabelStatus = mockGhc.Api.GetRepoClaLabelStatus
-	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(ghc, orgName, repoName).Return(repoClaLabelStatus)
-
+	// The progress is checked as the second PR is processed.
+	var snapshot ghutil.ProgressSnapshot
 	claSigners := config.ClaSigners{}
+	for idx, pull := range pullRequests {
+		prSpec := ghutil.GitHubProcessSinglePullSpec{
+			Org:  orgName,
+			Repo: repoName,
+			Pull: pull,
+		}
+		call := mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+		if idx == 1 {
+			call.Do(func(ghutil.GitHubProcessSinglePullSpec, config.ClaSigners, ghutil.RepoClaLabelStatus) {
+				snapshot = ghc.Progress.Snapshot(now)
+			})
+		}
+	}
 
-	prSpec1 := ghutil.GitHubProcessSinglePullSpec{
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
 		Org:  orgName,
 		Repo: repoName,
-		Pull: &pullRequest1,
 	}
-	prSpec2 := ghutil.GitHubProcessSinglePullSpec{
-		Org:  orgName,
-		Repo: repoName,
-		Pull: &pullRequest2,
+	_, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, snapshot.Repos)
+	assert.Equal(t, 0, snapshot.ReposDone)
+	assert.Equal(t, 1, snapshot.PullsDone)
+	assert.Equal(t, time.Minute, snapshot.Elapsed)
+	assert.InDelta(t, float64(2*time.Minute), float64(snapshot.ETA), float64(time.Second))
+
+	snapshot = ghc.Progress.Snapshot(now)
+	assert.Equal(t, ghutil.ProgressSnapshot{Repos: 1, ReposDone: 1, PullsDone: 3, Elapsed: time.Minute}, snapshot)
+	assert.Equal(t, 3, ghc.Counts.Summary().PullRequests)
+}
+
+func TestProcessOrgRepo_ResumesFromCheckpoint(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	doneRepoName := "done-repo"
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &doneRepoName,
+		},
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, "").Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	// PRs are listed newest-first; the checkpoint is at PR 43, which has
+	// since been closed, so processing resumes at the next older PR.
+	pullNumbers := []int{44, 42, 41}
+	pullRequests := make([]*github.PullRequest, len(pullNumbers))
+	for idx := range pullNumbers {
+		pullRequests[idx] = &github.PullRequest{Number: &pullNumbers[idx]}
+	}
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests, nil, nil)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+	for _, pull := range pullRequests[1:] {
+		prSpec := ghutil.GitHubProcessSinglePullSpec{
+			Org:  orgName,
+			Repo: repoName,
+			Pull: pull,
+		}
+		mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
 	}
-	ghc.ProcessPullRequest = mockGhc.Api.ProcessPullRequest
-	mockGhc.Api.EXPECT().ProcessPullRequest(ghc, prSpec1, claSigners, repoClaLabelStatus)
-	mockGhc.Api.EXPECT().ProcessPullRequest(ghc, prSpec2, claSigners, repoClaLabelStatus)
 
 	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
-		Org:   orgName,
-		Repo:  repoName,
-		Pulls: []int{pullNumber1, pullNumber2},
+		Org:        orgName,
+		ResumeFrom: &ghutil.Checkpoint{Org: orgName, Repo: repoName, Pull: 43},
 	}
-	ghc.ProcessOrgRepo(ghc, repoSpec, claSigners)
+	checkpoint, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+	assert.Nil(t, checkpoint)
 }
 
-func TestProcessOrgRepo_AllPrs(t *testing.T) {
+func TestProcessOrgRepo_PullLimitCheckpointPastFirstPage(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
 
@@ -807,47 +3534,165 @@ func TestProcessOrgRepo_AllPrs(t *testing.T) {
 		},
 	}
 
-	ghc.GetAllRepos = mockGhc.Api.GetAllRepos
-	mockGhc.Api.EXPECT().GetAllRepos(ghc, orgName, repoName).Return(repos)
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, repoName).Return(repos, nil)
 
-	pullNumber1 := 42
-	pullTitle1 := "pull 42 title"
-	pullNumber2 := 43
-	pullTitle2 := "pull 43 title"
-	pullRequests := []*github.PullRequest{
-		{
-			Number: &pullNumber1,
-			Title:  &pullTitle1,
-		},
-		{
-			Number: &pullNumber2,
-			Title:  &pullTitle2,
-		},
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	// The PRs span two pages; resuming at PR 44, the last one on the first
+	// page, the limit is reached on the second page.
+	pullNumbers := []int{46, 45, 44, 43, 42}
+	pullRequests := make([]*github.PullRequest, len(pullNumbers))
+	for idx := range pullNumbers {
+		pullRequests[idx] = &github.PullRequest{Number: &pullNumbers[idx]}
 	}
-	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, nil).Return(pullRequests, nil, nil)
+	gomock.InOrder(
+		mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests[:3], &github.Response{NextPage: 2}, nil),
+		mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests[3:], &github.Response{}, nil),
+	)
 
 	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
-
-	ghc.GetRepoClaLabelStatus = mockGhc.Api.GetRepoClaLabelStatus
-	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(ghc, orgName, repoName).Return(repoClaLabelStatus)
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
 
 	claSigners := config.ClaSigners{}
-
-	ghc.ProcessPullRequest = mockGhc.Api.ProcessPullRequest
-	for _, pull := range pullRequests {
+	for _, pull := range pullRequests[2:4] {
 		prSpec := ghutil.GitHubProcessSinglePullSpec{
 			Org:  orgName,
 			Repo: repoName,
 			Pull: pull,
 		}
-		mockGhc.Api.EXPECT().ProcessPullRequest(ghc, prSpec, claSigners, repoClaLabelStatus)
+		mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
 	}
 
 	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:        orgName,
+		Repo:       repoName,
+		MaxPulls:   2,
+		ResumeFrom: &ghutil.Checkpoint{Org: orgName, Repo: repoName, Pull: 44},
+	}
+	checkpoint, err := ghc.ProcessOrgRepo(repoSpec, claSigners)
+	assert.Nil(t, err)
+	assert.Equal(t, &ghutil.Checkpoint{Org: orgName, Repo: repoName, Pull: 42}, checkpoint)
+}
+
+func TestProcessOrgRepo_GetAllReposError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, "").Return(nil, errors.New("server error"))
+
+	checkpoint, err := ghc.ProcessOrgRepo(ghutil.GitHubProcessOrgRepoSpec{Org: orgName}, config.ClaSigners{})
+	assert.Nil(t, checkpoint)
+	assert.EqualError(t, err, "server error")
+}
+
+func TestProcessOrgRepo_ListPullsErrorSkipsRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	brokenRepoName := "broken-repo"
+	localRepoName := repoName
+	repos := []*github.Repository{
+		{
+			Name: &brokenRepoName,
+		},
+		{
+			Name: &localRepoName,
+		},
+	}
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().GetAllRepos(orgName, "").Return(repos, nil)
+
+	mockGhc.Api.EXPECT().GetOrgConfig(orgName).Return(config.OrgConfig{}, nil)
+
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, brokenRepoName).Return(config.RepoConfig{}, nil)
+	mockGhc.Api.EXPECT().GetRepoConfig(orgName, repoName).Return(config.RepoConfig{}, nil)
+
+	mockGhc.PullRequests.EXPECT().List(any, orgName, brokenRepoName, any).Return(nil, nil, errors.New("server error"))
+	pullRequests := []*github.PullRequest{{Number: github.Int(pullNumber)}}
+	mockGhc.PullRequests.EXPECT().List(any, orgName, repoName, any).Return(pullRequests, nil, nil)
+
+	repoClaLabelStatus := ghutil.RepoClaLabelStatus{}
+	mockGhc.Api.EXPECT().GetRepoClaLabelStatus(orgName, repoName, config.Labels{}).Return(repoClaLabelStatus)
+
+	claSigners := config.ClaSigners{}
+	prSpec := ghutil.GitHubProcessSinglePullSpec{
 		Org:  orgName,
 		Repo: repoName,
+		Pull: pullRequests[0],
+	}
+	mockGhc.Api.EXPECT().ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus)
+
+	checkpoint, err := ghc.ProcessOrgRepo(ghutil.GitHubProcessOrgRepoSpec{Org: orgName}, claSigners)
+	assert.Nil(t, checkpoint)
+	assert.EqualError(t, err, "error listing pull requests for 1 repo(s) in org org: broken-repo")
+}
+
+func TestProcessPullRequest_CustomLabelNames(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	labels := config.Labels{
+		Compliant:    "cla: signed",
+		NonCompliant: "cla: missing",
 	}
-	ghc.ProcessOrgRepo(ghc, repoSpec, claSigners)
+	claSigners := config.ClaSigners{}
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+	prSpec.Labels = labels
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(ghutil.PullRequestStatus{Compliant: true}, nil)
+
+	mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, labels).Return(ghutil.IssueClaLabelStatus{HasNo: true, Labels: []string{"bug", "cla: missing"}})
+
+	expectListIssueLabels("bug", "cla: missing")
+	mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, []string{"bug", "cla: signed"}).Return(nil, nil, nil)
+
+	err := ghc.ProcessPullRequest(prSpec, claSigners, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true})
+	assert.Nil(t, err)
+}
+
+func TestProcessPullRequest_KeepsLabelsAddedMeanwhile(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	claSigners := config.ClaSigners{}
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(ghutil.PullRequestStatus{Compliant: true}, nil)
+	mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{}).Return(ghutil.IssueClaLabelStatus{HasNo: true, Labels: []string{ghutil.LabelClaNo}})
+	// A maintainer labeled the PR after its labels were first listed.
+	expectListIssueLabels(ghutil.LabelClaNo, "bug")
+	mockGhc.Issues.EXPECT().ReplaceLabelsForIssue(any, orgName, repoName, pullNumber, []string{"bug", ghutil.LabelClaYes}).Return(nil, nil, nil)
+
+	err := ghc.ProcessPullRequest(prSpec, claSigners, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true})
+	assert.Nil(t, err)
+}
+
+func TestProcessPullRequest_UnknownLabelsNotReplaced(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	ghc.Report = report.New()
+	claSigners := config.ClaSigners{}
+	prSpec := getSinglePullSpec()
+	prSpec.UpdateRepo = true
+
+	ghc.Api = mockGhc.Api
+	mockGhc.Api.EXPECT().CheckPullRequestCompliance(prSpec, claSigners).Return(ghutil.PullRequestStatus{Compliant: true}, nil)
+	// Listing the labels failed, so replacing them could drop some.
+	mockGhc.Api.EXPECT().GetIssueClaLabelStatus(orgName, repoName, pullNumber, config.Labels{}).Return(ghutil.IssueClaLabelStatus{})
+
+	err := ghc.ProcessPullRequest(prSpec, claSigners, ghutil.RepoClaLabelStatus{HasYes: true, HasNo: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Error updating labels of repo 'org/repo' PR 42: its current labels are unknown"}, ghc.Report.Errors)
 }
 
 func createUserAccounts() (config.Account, config.Account) {
@@ -886,6 +3731,25 @@ func TestIsExternal_JustJohnInPeople(t *testing.T) {
 	}
 }
 
+func TestIsExternal_ExternalMatchedByAlias(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	renamedJohn := john
+	renamedJohn.Login = "john-renamed"
+	renamedJohn.Aliases = []string{john.Login}
+
+	claSigners := config.ClaSigners{
+		External: &config.ExternalClaSigners{
+			People: []config.Account{renamedJohn},
+		},
+	}
+
+	commit := createCommit(john, john)
+	assert.True(t, ghutil.IsExternal(commit, claSigners, false))
+}
+
 func TestIsExternal_JohnAndJaneInPeople(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
@@ -940,6 +3804,25 @@ func TestIsExternal_JaneIsABot(t *testing.T) {
 	}
 }
 
+func TestIsExternal_JaneMatchesBotPattern(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+
+	claSigners := config.ClaSigners{
+		People: []config.Account{
+			john,
+		},
+		Bots: []config.Account{
+			{Login: "/jane.*/"},
+		},
+	}
+
+	// Jane is covered by the bot pattern, so isn't an unknown contributor.
+	assert.False(t, ghutil.IsExternal(createCommit(john, jane), claSigners, true))
+}
+
 func TestIsExternal_JaneIsExternalPerson(t *testing.T) {
 	setUp(t)
 	defer tearDown(t)
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+)
+
+// graphQLClient calls the GitHub GraphQL API via the connection of the REST
+// client, as the version of the API client in use has no support for it.
+type graphQLClient struct {
+	client *github.Client
+}
+
+// graphQLRequest and graphQLResponse are the envelopes of GraphQL queries and
+// their results; errors are reported with a successful HTTP status.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// minimizeCommentMutation minimizes a comment, given its node ID, for the
+// reason given by the classifier, e.g., "OUTDATED".
+const minimizeCommentMutation = `mutation($subjectId: ID!, $classifier: ReportedContentClassifiers!) {
+  minimizeComment(input: {subjectId: $subjectId, classifier: $classifier}) {
+    minimizedComment { isMinimized }
+  }
+}`
+
+// MinimizeComment minimizes the comment with the given node ID.
+func (c *graphQLClient) MinimizeComment(ctx context.Context, subjectID string, classifier string) error {
+	return c.do(ctx, minimizeCommentMutation, map[string]interface{}{
+		"subjectId":  subjectID,
+		"classifier": classifier,
+	})
+}
+
+// pinIssueMutation pins an issue to its repo, given its node ID.
+const pinIssueMutation = `mutation($issueId: ID!) {
+  pinIssue(input: {issueId: $issueId}) {
+    issue { id }
+  }
+}`
+
+// PinIssue pins the issue with the given node ID to its repo.
+func (c *graphQLClient) PinIssue(ctx context.Context, issueID string) error {
+	return c.do(ctx, pinIssueMutation, map[string]interface{}{
+		"issueId": issueID,
+	})
+}
+
+// do runs the query, discarding its data.
+func (c *graphQLClient) do(ctx context.Context, query string, variables map[string]interface{}) error {
+	req, err := c.client.NewRequest("POST", "graphql", &graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	var resp graphQLResponse
+	if _, err := c.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
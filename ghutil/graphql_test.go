@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+type graphQLTransport func(query string, variables map[string]interface{}) string
+
+func (t graphQLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if req.Method != "POST" || req.URL.String() != "https://api.github.com/graphql" {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(t(body.Query, body.Variables))),
+		Request:    req,
+	}, nil
+}
+
+func TestGraphQL_MinimizeComment(t *testing.T) {
+	var variables map[string]interface{}
+	transport := graphQLTransport(func(query string, vars map[string]interface{}) string {
+		assert.Contains(t, query, "minimizeComment")
+		variables = vars
+		return `{"data": {"minimizeComment": {"minimizedComment": {"isMinimized": true}}}}`
+	})
+	ghc := ghutil.NewClient(&http.Client{Transport: transport}, "")
+
+	err := ghc.GraphQL.MinimizeComment(context.Background(), "IC_1", "OUTDATED")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"subjectId": "IC_1", "classifier": "OUTDATED"}, variables)
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	transport := graphQLTransport(func(query string, vars map[string]interface{}) string {
+		return `{"data": null, "errors": [{"message": "Resource not accessible by integration"}]}`
+	})
+	ghc := ghutil.NewClient(&http.Client{Transport: transport}, "")
+
+	err := ghc.GraphQL.MinimizeComment(context.Background(), "IC_1", "OUTDATED")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Resource not accessible by integration", err.Error())
+	}
+}
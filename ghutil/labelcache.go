@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// RepoLabelCache caches the CLA label status of repos for a fixed TTL, as
+// label definitions almost never change, but take an API call per label to
+// look up. It is safe for concurrent use, e.g., by `crbot serve`, which
+// processes the PRs of the same repos over and over.
+type RepoLabelCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]repoLabelEntry
+}
+
+type repoLabelEntry struct {
+	status  RepoClaLabelStatus
+	expires time.Time
+}
+
+// NewRepoLabelCache returns an empty cache whose entries expire after `ttl`.
+func NewRepoLabelCache(ttl time.Duration) *RepoLabelCache {
+	return &RepoLabelCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]repoLabelEntry),
+	}
+}
+
+// repoLabelKey identifies the repo and the names of the labels looked up in
+// it, as repos may be configured with different label names.
+func repoLabelKey(orgName string, repoName string, labels config.Labels) string {
+	return strings.ToLower(strings.Join([]string{orgName, repoName, labels.Compliant, labels.NonCompliant, labels.External}, "\x00"))
+}
+
+// Get returns the cached label status of the repo, if it has not expired.
+func (c *RepoLabelCache) Get(orgName string, repoName string, labels config.Labels) (RepoClaLabelStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[repoLabelKey(orgName, repoName, labels)]
+	if !ok || !c.now().Before(entry.expires) {
+		return RepoClaLabelStatus{}, false
+	}
+	return entry.status, true
+}
+
+// Put caches the label status of the repo.
+func (c *RepoLabelCache) Put(orgName string, repoName string, labels config.Labels, status RepoClaLabelStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[repoLabelKey(orgName, repoName, labels)] = repoLabelEntry{status: status, expires: c.now().Add(c.ttl)}
+}
+
+// Invalidate drops the cached label status of the repo, for all label names,
+// e.g., after its labels were created or edited.
+func (c *RepoLabelCache) Invalidate(orgName string, repoName string) {
+	prefix := strings.ToLower(orgName + "\x00" + repoName + "\x00")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// LabelSpec is the definition of a CLA-related label as it should exist on
+// each repository.
+type LabelSpec struct {
+	Name        string
+	Color       string
+	Description string
+}
+
+// LabelSpecs returns the definitions of the CLA-related labels, with the
+// given names (or the default names, if unset), to be created and kept
+// consistent across repos by `SyncLabels`; the override label is included
+// only if configured.
+func LabelSpecs(labels config.Labels) []LabelSpec {
+	labels = ResolveLabels(labels)
+	specs := []LabelSpec{
+		{
+			Name:        labels.Compliant,
+			Color:       "0e8a16",
+			Description: "All commit authors and committers are covered by a CLA",
+		},
+		{
+			Name:        labels.NonCompliant,
+			Color:       "b60205",
+			Description: "One or more commit authors or committers are not covered by a CLA",
+		},
+		{
+			Name:        labels.External,
+			Color:       "c5def5",
+			Description: "CLA status is managed by an external tool",
+		},
+	}
+	if labels.Skip != "" {
+		specs = append(specs, LabelSpec{
+			Name:        labels.Skip,
+			Color:       "ededed",
+			Description: "Excluded from CLA checks by maintainers",
+		})
+	}
+	return specs
+}
+
+// isNotFound returns whether the error is a GitHub API "404 Not Found" error.
+func isNotFound(err error) bool {
+	if errResp, ok := err.(*github.ErrorResponse); ok && errResp.Response != nil {
+		return errResp.Response.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// SyncLabels ensures that the given repo has each of the labels in `specs`
+// with the specified color and description, creating or updating them as
+// needed. If `updateRepo` is false, it only reports the changes that it would
+// have made.
+func SyncLabels(ghc *GitHubClient, orgName string, repoName string, specs []LabelSpec, updateRepo bool) error {
+	ctx := context.Background()
+	if ghc.RepoLabels != nil && updateRepo {
+		defer ghc.RepoLabels.Invalidate(orgName, repoName)
+	}
+	for _, spec := range specs {
+		spec := spec
+		wanted := github.Label{
+			Name:        &spec.Name,
+			Color:       &spec.Color,
+			Description: &spec.Description,
+		}
+
+		label, _, err := ghc.Issues.GetLabel(ctx, orgName, repoName, spec.Name)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("error looking up label [%s] in repo '%s/%s': %v", spec.Name, orgName, repoName, err)
+		}
+
+		if label == nil {
+			logger.Infof("  Creating label [%s] in repo '%s/%s'...", spec.Name, orgName, repoName)
+			if !updateRepo {
+				logger.Info("  ... but -update-repo flag is disabled; skipping")
+				continue
+			}
+			if _, _, err := ghc.Issues.CreateLabel(ctx, orgName, repoName, &wanted); err != nil {
+				return fmt.Errorf("error creating label [%s] in repo '%s/%s': %v", spec.Name, orgName, repoName, err)
+			}
+			continue
+		}
+
+		if label.GetName() == spec.Name &&
+			strings.EqualFold(label.GetColor(), spec.Color) &&
+			label.GetDescription() == spec.Description {
+			logger.Infof("  No action needed: label [%s] in repo '%s/%s' is up to date", spec.Name, orgName, repoName)
+			continue
+		}
+
+		logger.Infof("  Updating label [%s] in repo '%s/%s' (color: %s -> %s, description: %q -> %q)...",
+			spec.Name, orgName, repoName, label.GetColor(), spec.Color, label.GetDescription(), spec.Description)
+		if !updateRepo {
+			logger.Info("  ... but -update-repo flag is disabled; skipping")
+			continue
+		}
+		if _, _, err := ghc.Issues.EditLabel(ctx, orgName, repoName, label.GetName(), &wanted); err != nil {
+			return fmt.Errorf("error updating label [%s] in repo '%s/%s': %v", spec.Name, orgName, repoName, err)
+		}
+	}
+	return nil
+}
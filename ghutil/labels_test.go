@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func notFoundError() error {
+	return &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+		Message:  "Not Found",
+	}
+}
+
+func labelFromSpec(spec ghutil.LabelSpec) *github.Label {
+	return &github.Label{
+		Name:        &spec.Name,
+		Color:       &spec.Color,
+		Description: &spec.Description,
+	}
+}
+
+var testLabelSpec = ghutil.LabelSpec{
+	Name:        ghutil.LabelClaYes,
+	Color:       "0e8a16",
+	Description: "CLA signed",
+}
+
+func TestSyncLabels_CreatesMissingLabel(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, testLabelSpec.Name).Return(nil, nil, notFoundError())
+	mockGhc.Issues.EXPECT().CreateLabel(any, orgName, repoName, labelFromSpec(testLabelSpec)).Return(nil, nil, nil)
+
+	err := ghutil.SyncLabels(ghc, orgName, repoName, []ghutil.LabelSpec{testLabelSpec}, true)
+	assert.Nil(t, err)
+}
+
+func TestSyncLabels_UpdatesDifferentColor(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	existing := testLabelSpec
+	existing.Color = "ffffff"
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, testLabelSpec.Name).Return(labelFromSpec(existing), nil, nil)
+	mockGhc.Issues.EXPECT().EditLabel(any, orgName, repoName, testLabelSpec.Name, labelFromSpec(testLabelSpec)).Return(nil, nil, nil)
+
+	err := ghutil.SyncLabels(ghc, orgName, repoName, []ghutil.LabelSpec{testLabelSpec}, true)
+	assert.Nil(t, err)
+}
+
+func TestSyncLabels_UpToDate(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	existing := testLabelSpec
+	existing.Color = "0E8A16"
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, testLabelSpec.Name).Return(labelFromSpec(existing), nil, nil)
+
+	err := ghutil.SyncLabels(ghc, orgName, repoName, []ghutil.LabelSpec{testLabelSpec}, true)
+	assert.Nil(t, err)
+}
+
+func TestSyncLabels_NoUpdateRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, testLabelSpec.Name).Return(nil, nil, notFoundError())
+
+	err := ghutil.SyncLabels(ghc, orgName, repoName, []ghutil.LabelSpec{testLabelSpec}, false)
+	assert.Nil(t, err)
+}
+
+func TestSyncLabels_LookupError(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Issues.EXPECT().GetLabel(any, orgName, repoName, testLabelSpec.Name).Return(nil, nil, errors.New("server error"))
+
+	err := ghutil.SyncLabels(ghc, orgName, repoName, []ghutil.LabelSpec{testLabelSpec}, true)
+	assert.NotNil(t, err)
+}
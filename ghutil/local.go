@@ -0,0 +1,221 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// LocalCommit is a commit read from a local Git checkout, which, unlike the
+// commits listed via the GitHub API, carries no GitHub logins.
+type LocalCommit struct {
+	SHA            string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+	Message        string
+}
+
+// localLogFormat prints the fields of `LocalCommit` separated by the ASCII
+// unit separator; with `git log -z`, commits are separated by NUL bytes.
+const localLogFormat = "--format=%H%x1f%an%x1f%ae%x1f%cn%x1f%ce%x1f%B"
+
+// ReadLocalCommits reads the commits in the revision range, e.g.,
+// "origin/main..HEAD", from the Git checkout at `repoPath`, oldest first.
+func ReadLocalCommits(repoPath string, revRange string) ([]LocalCommit, error) {
+	if revRange == "" || strings.HasPrefix(revRange, "-") {
+		return nil, fmt.Errorf("invalid revision range '%s'", revRange)
+	}
+	return readLocalCommits(repoPath, revRange)
+}
+
+// readLocalCommits runs `git log` with the given revision arguments.
+func readLocalCommits(repoPath string, revisions ...string) ([]LocalCommit, error) {
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"-C", repoPath, "log", "-z", "--reverse", localLogFormat}, revisions...)
+	cmd := exec.Command("git", append(args, "--")...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running git log %s in '%s': %s: %s", strings.Join(revisions, " "), repoPath, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []LocalCommit
+	for _, record := range strings.Split(stdout.String(), "\x00") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 6)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("unexpected output of git log: %q", record)
+		}
+		commits = append(commits, LocalCommit{
+			SHA:            fields[0],
+			AuthorName:     fields[1],
+			AuthorEmail:    fields[2],
+			CommitterName:  fields[3],
+			CommitterEmail: fields[4],
+			Message:        fields[5],
+		})
+	}
+	return commits, nil
+}
+
+// PushUpdate is a ref updated by a push, as passed to pre-receive hooks on
+// stdin, one per line.
+type PushUpdate struct {
+	OldSHA string
+	NewSHA string
+	Ref    string
+}
+
+// zeroSHA is the SHA of a missing ref, as passed to pre-receive hooks.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// ParsePushUpdates parses the ref updates passed to a pre-receive hook, e.g.,
+// on GitHub Enterprise, on stdin.
+func ParsePushUpdates(r io.Reader) ([]PushUpdate, error) {
+	var updates []PushUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid ref update: %q", line)
+		}
+		updates = append(updates, PushUpdate{OldSHA: fields[0], NewSHA: fields[1], Ref: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// ReadPushedCommits reads the commits which the ref update adds to the repo at
+// `repoPath`, oldest first, from within a pre-receive hook: for a new ref,
+// those not reachable from any existing ref, and none for a deleted ref.
+func ReadPushedCommits(repoPath string, update PushUpdate) ([]LocalCommit, error) {
+	switch {
+	case update.NewSHA == zeroSHA:
+		return nil, nil
+	case update.OldSHA == zeroSHA:
+		return readLocalCommits(repoPath, update.NewSHA, "--not", "--all")
+	}
+	return readLocalCommits(repoPath, update.OldSHA+".."+update.NewSHA)
+}
+
+// ParsePrePushUpdates parses the refs being pushed, as passed to Git pre-push
+// hooks on stdin, one per line, as the updates they make to the remote.
+func ParsePrePushUpdates(r io.Reader) ([]PushUpdate, error) {
+	var updates []PushUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		// Each line is: <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid ref being pushed: %q", line)
+		}
+		updates = append(updates, PushUpdate{OldSHA: fields[3], NewSHA: fields[1], Ref: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// ReadUnpushedCommits reads the commits which the ref update pushes from the
+// Git checkout at `repoPath` to the named remote, oldest first, from within a
+// pre-push hook: for a new ref, those not in any of the remote's branches
+// fetched so far, and none for a deleted ref.
+func ReadUnpushedCommits(repoPath string, remoteName string, update PushUpdate) ([]LocalCommit, error) {
+	switch {
+	case update.NewSHA == zeroSHA:
+		return nil, nil
+	case update.OldSHA == zeroSHA:
+		return readLocalCommits(repoPath, update.NewSHA, "--not", "--remotes="+remoteName)
+	}
+	return readLocalCommits(repoPath, update.OldSHA+".."+update.NewSHA)
+}
+
+// LocalLogin infers the GitHub login of a local commit's author or committer
+// from their email, as GitHub would when the commit is pushed: the login
+// embedded in a GitHub noreply address, or else that of the CLA signer,
+// external CLA signer, or maintainer with the same email, if any.
+func LocalLogin(email string, claSigners config.ClaSigners) string {
+	if login, ok := NoreplyLogin(email); ok {
+		return login
+	}
+	accountLists := [][]config.Account{claSigners.People, claSigners.Bots, claSigners.Maintainers}
+	for _, company := range claSigners.Companies {
+		accountLists = append(accountLists, company.People)
+	}
+	if ext := claSigners.External; ext != nil {
+		accountLists = append(accountLists, ext.People, ext.Bots)
+		for _, company := range ext.Companies {
+			accountLists = append(accountLists, company.People)
+		}
+	}
+	for _, accounts := range accountLists {
+		for _, account := range accounts {
+			if account.Email != "" && matchEmail(email, account) {
+				return account.Login
+			}
+		}
+	}
+	return ""
+}
+
+// RepositoryCommit converts the local commit to the form listed via the GitHub
+// API, with the logins inferred via `LocalLogin`.
+func (c LocalCommit) RepositoryCommit(claSigners config.ClaSigners) *github.RepositoryCommit {
+	commit := &github.RepositoryCommit{
+		SHA: github.String(c.SHA),
+		Commit: &github.Commit{
+			Author:    &github.CommitAuthor{Name: github.String(c.AuthorName), Email: github.String(c.AuthorEmail)},
+			Committer: &github.CommitAuthor{Name: github.String(c.CommitterName), Email: github.String(c.CommitterEmail)},
+			Message:   github.String(c.Message),
+		},
+	}
+	if login := LocalLogin(c.AuthorEmail, claSigners); login != "" {
+		commit.Author = &github.User{Login: github.String(login)}
+	}
+	if login := LocalLogin(c.CommitterEmail, claSigners); login != "" {
+		commit.Committer = &github.User{Login: github.String(login)}
+	}
+	return commit
+}
+
+// CheckLocalCommit checks the CLA compliance of a local commit, e.g., before
+// it is pushed, as `ProcessCommit` does for the commits of a PR. Commits whose
+// author or committer is covered by an external CLA tool are reported as
+// external, and compliant.
+func CheckLocalCommit(c LocalCommit, claSigners config.ClaSigners, unknownAsExternal bool) CommitStatus {
+	commit := c.RepositoryCommit(claSigners)
+	if IsExternal(commit, claSigners, unknownAsExternal) {
+		return CommitStatus{SHA: c.SHA, Compliant: true, External: true}
+	}
+	return ProcessCommit(commit, claSigners)
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+var localClaSigners = config.ClaSigners{
+	People: []config.Account{
+		{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+	},
+	External: &config.ExternalClaSigners{
+		People: []config.Account{
+			{Name: "Jane Doe", Email: "jane@example.org", Login: "janedoe"},
+		},
+	},
+}
+
+func TestLocalLogin(t *testing.T) {
+	assert.Equal(t, "johndoe", ghutil.LocalLogin("John@Example.com", localClaSigners))
+	assert.Equal(t, "janedoe", ghutil.LocalLogin("jane@example.org", localClaSigners))
+	assert.Equal(t, "someone", ghutil.LocalLogin("123+someone@users.noreply.github.com", localClaSigners))
+	assert.Equal(t, "", ghutil.LocalLogin("unknown@example.com", localClaSigners))
+}
+
+func TestCheckLocalCommit(t *testing.T) {
+	commit := ghutil.LocalCommit{
+		SHA:            "abc123def456",
+		AuthorName:     "John Doe",
+		AuthorEmail:    "john@example.com",
+		CommitterName:  "John Doe",
+		CommitterEmail: "john@example.com",
+		Message:        "Fix typo",
+	}
+	commitStatus := ghutil.CheckLocalCommit(commit, localClaSigners, false)
+	assert.True(t, commitStatus.Compliant)
+	assert.False(t, commitStatus.External)
+
+	commit.AuthorName, commit.AuthorEmail = "Jane Doe", "jane@example.org"
+	commitStatus = ghutil.CheckLocalCommit(commit, localClaSigners, false)
+	assert.True(t, commitStatus.External)
+
+	commit.AuthorName, commit.AuthorEmail = "Unknown", "unknown@example.com"
+	commitStatus = ghutil.CheckLocalCommit(commit, localClaSigners, false)
+	assert.False(t, commitStatus.Compliant)
+	assert.Equal(t, ghutil.ReasonAuthorIdentity, commitStatus.NonComplianceReason)
+}
+
+// johnGitEnv sets John as the author and committer of Git commits.
+var johnGitEnv = []string{
+	"GIT_AUTHOR_NAME=John Doe", "GIT_AUTHOR_EMAIL=john@example.com",
+	"GIT_COMMITTER_NAME=John Doe", "GIT_COMMITTER_EMAIL=john@example.com",
+}
+
+// initGitRepo creates a Git repo in a temporary directory, which the caller
+// must remove, and returns it along with a function running Git in it with
+// the given extra environment variables and returning its output.
+func initGitRepo(t *testing.T) (string, func(env []string, args ...string) string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "local")
+	assert.Nil(t, err)
+
+	git := func(env []string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git(nil, "init", "-q")
+	return dir, git
+}
+
+func TestReadLocalCommits(t *testing.T) {
+	dir, git := initGitRepo(t)
+	defer os.RemoveAll(dir)
+	john := johnGitEnv
+	git(john, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	git(append(john, "GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.org"),
+		"commit", "-q", "--allow-empty", "-m", "First change\n\nWith details.")
+	git(john, "commit", "-q", "--allow-empty", "-m", "Second change")
+
+	commits, err := ghutil.ReadLocalCommits(dir, "HEAD~2..HEAD")
+	assert.Nil(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Len(t, commits[0].SHA, 40)
+		commits[0].SHA, commits[1].SHA = "", ""
+		assert.Equal(t, []ghutil.LocalCommit{
+			{
+				AuthorName:     "Jane Doe",
+				AuthorEmail:    "jane@example.org",
+				CommitterName:  "John Doe",
+				CommitterEmail: "john@example.com",
+				Message:        "First change\n\nWith details.\n",
+			},
+			{
+				AuthorName:     "John Doe",
+				AuthorEmail:    "john@example.com",
+				CommitterName:  "John Doe",
+				CommitterEmail: "john@example.com",
+				Message:        "Second change\n",
+			},
+		}, commits)
+	}
+
+	_, err = ghutil.ReadLocalCommits(dir, "no-such-branch..HEAD")
+	assert.NotNil(t, err)
+	_, err = ghutil.ReadLocalCommits(dir, "--all")
+	assert.NotNil(t, err)
+}
+
+func TestParsePushUpdates(t *testing.T) {
+	updates, err := ghutil.ParsePushUpdates(strings.NewReader("aaa bbb refs/heads/main\n\nccc ddd refs/tags/v1\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []ghutil.PushUpdate{
+		{OldSHA: "aaa", NewSHA: "bbb", Ref: "refs/heads/main"},
+		{OldSHA: "ccc", NewSHA: "ddd", Ref: "refs/tags/v1"},
+	}, updates)
+
+	_, err = ghutil.ParsePushUpdates(strings.NewReader("aaa bbb\n"))
+	assert.NotNil(t, err)
+}
+
+func TestReadPushedCommits(t *testing.T) {
+	dir, git := initGitRepo(t)
+	defer os.RemoveAll(dir)
+	const zeroSHA = "0000000000000000000000000000000000000000"
+
+	git(johnGitEnv, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	base := git(nil, "rev-parse", "HEAD")
+	git(johnGitEnv, "commit", "-q", "--allow-empty", "-m", "Pushed change")
+	head := git(nil, "rev-parse", "HEAD")
+	// As in a pre-receive hook, no ref points at the pushed commit yet.
+	git(nil, "reset", "-q", "--hard", base)
+
+	// An update of an existing ref, and a new ref.
+	for _, update := range []ghutil.PushUpdate{
+		{OldSHA: base, NewSHA: head, Ref: "refs/heads/main"},
+		{OldSHA: zeroSHA, NewSHA: head, Ref: "refs/heads/feature"},
+	} {
+		commits, err := ghutil.ReadPushedCommits(dir, update)
+		assert.Nil(t, err)
+		if assert.Len(t, commits, 1) {
+			assert.Equal(t, head, commits[0].SHA)
+		}
+	}
+
+	// A deleted ref.
+	commits, err := ghutil.ReadPushedCommits(dir, ghutil.PushUpdate{OldSHA: base, NewSHA: zeroSHA, Ref: "refs/heads/main"})
+	assert.Nil(t, err)
+	assert.Empty(t, commits)
+}
+
+func TestParsePrePushUpdates(t *testing.T) {
+	updates, err := ghutil.ParsePrePushUpdates(strings.NewReader("refs/heads/feature bbb refs/heads/main aaa\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []ghutil.PushUpdate{{OldSHA: "aaa", NewSHA: "bbb", Ref: "refs/heads/main"}}, updates)
+
+	_, err = ghutil.ParsePrePushUpdates(strings.NewReader("aaa bbb refs/heads/main\n"))
+	assert.NotNil(t, err)
+}
+
+func TestReadUnpushedCommits(t *testing.T) {
+	dir, git := initGitRepo(t)
+	defer os.RemoveAll(dir)
+	const zeroSHA = "0000000000000000000000000000000000000000"
+
+	git(johnGitEnv, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	git(nil, "update-ref", "refs/remotes/origin/main", "HEAD")
+	git(johnGitEnv, "commit", "-q", "--allow-empty", "-m", "Unpushed change")
+	head := git(nil, "rev-parse", "HEAD")
+
+	// A new branch only pushes the commits not on any branch of the remote.
+	commits, err := ghutil.ReadUnpushedCommits(dir, "origin", ghutil.PushUpdate{OldSHA: zeroSHA, NewSHA: head, Ref: "refs/heads/feature"})
+	assert.Nil(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, head, commits[0].SHA)
+	}
+
+	commits, err = ghutil.ReadUnpushedCommits(dir, "origin", ghutil.PushUpdate{OldSHA: head, NewSHA: zeroSHA, Ref: "refs/heads/feature"})
+	assert.Nil(t, err)
+	assert.Empty(t, commits)
+}
@@ -0,0 +1,260 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale of the built-in messages and comment template.
+const DefaultLocale = "en"
+
+// localeMessages holds the translations of contributor-facing text for a
+// single locale: the default comment template, the comment on external PRs,
+// and the built-in non-compliance reasons, keyed by their English text.
+type localeMessages struct {
+	commentTemplate string
+	externalComment string
+	reasons         map[string]string
+}
+
+var locales = map[string]localeMessages{
+	"de": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+Die folgenden Mitwirkenden müssen das CLA unterzeichnen oder ihre Commit-Identität korrigieren; alle anderen Mitwirkenden an diesem Pull-Request sind bereits abgedeckt:` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `Falls diese Commits mit falschem Namen oder falscher E-Mail-Adresse erstellt wurden, können Sie sie mit den folgenden Befehlen in Ihrem Branch auf die Identität umschreiben, mit der Sie das CLA unterzeichnet haben:` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+Bitte unterzeichnen Sie das Contributor License Agreement (CLA) unter {{.ClaURL}}, bevor wir Ihren Beitrag annehmen können. Sobald Sie es unterzeichnet (oder die oben genannten Probleme behoben) haben, wird der CLA-Status dieses Pull-Requests automatisch aktualisiert.
+{{- end}}`,
+		externalComment: "Das CLA der Mitwirkenden an diesem Pull-Request wird von %s verwaltet; bitte prüfen Sie den Status dort.",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "Bitte überprüfen Sie, ob die Zuordnung von Name, E-Mail-Adresse und GitHub-Benutzername des Autors korrekt ist und mit den CLA-Unterlagen übereinstimmt.",
+			ReasonCommitterIdentity:      "Bitte überprüfen Sie, ob die Zuordnung von Name, E-Mail-Adresse und GitHub-Benutzername des Committers korrekt ist und mit den CLA-Unterlagen übereinstimmt.",
+			ReasonAuthorNotSigner:        "Der Autor eines oder mehrerer Commits ist nicht als CLA-Unterzeichner eingetragen, weder als Einzelperson noch als Mitglied einer Organisation.",
+			ReasonCommitterNotSigner:     "Der Committer eines oder mehrerer Commits ist nicht als CLA-Unterzeichner eingetragen, weder als Einzelperson noch als Mitglied einer Organisation.",
+			ReasonCoAuthorNotSigner:      "Ein Co-Autor eines oder mehrerer Commits, der in einem Co-authored-by-Trailer genannt wird, ist nicht als CLA-Unterzeichner eingetragen, weder als Einzelperson noch als Mitglied einer Organisation.",
+			ReasonAuthorEmailUnverified:  "Die Autor-E-Mail-Adresse eines oder mehrerer Commits konnte nicht als zum GitHub-Konto des Autors gehörend bestätigt werden; bitte signieren Sie die Commits mit einem Schlüssel, der diesem Konto hinzugefügt wurde.",
+			ReasonAuthorNameMismatch:     "Der Name des Autors eines oder mehrerer Commits stimmt nicht mit dem Namen im CLA-Eintrag mit derselben E-Mail-Adresse oder demselben GitHub-Benutzernamen überein.",
+			ReasonAuthorEmailMismatch:    "Die E-Mail-Adresse des Autors eines oder mehrerer Commits stimmt nicht mit der E-Mail-Adresse im CLA-Eintrag mit demselben GitHub-Benutzernamen überein.",
+			ReasonAuthorLoginMismatch:    "Der GitHub-Benutzername des Autors eines oder mehrerer Commits stimmt nicht mit dem Benutzernamen im CLA-Eintrag mit derselben E-Mail-Adresse überein.",
+			ReasonCommitterNameMismatch:  "Der Name des Committers eines oder mehrerer Commits stimmt nicht mit dem Namen im CLA-Eintrag mit derselben E-Mail-Adresse oder demselben GitHub-Benutzernamen überein.",
+			ReasonCommitterEmailMismatch: "Die E-Mail-Adresse des Committers eines oder mehrerer Commits stimmt nicht mit der E-Mail-Adresse im CLA-Eintrag mit demselben GitHub-Benutzernamen überein.",
+			ReasonCommitterLoginMismatch: "Der GitHub-Benutzername des Committers eines oder mehrerer Commits stimmt nicht mit dem Benutzernamen im CLA-Eintrag mit derselben E-Mail-Adresse überein.",
+		},
+	},
+	"es": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+Los siguientes colaboradores deben firmar el CLA o corregir su identidad en los commits; todos los demás colaboradores de esta pull request ya están cubiertos:` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `Si estos commits se crearon con un nombre o correo electrónico incorrectos, puede reescribirlos con la identidad con la que firmó el CLA ejecutando los siguientes comandos en su rama:` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+Firme el Acuerdo de Licencia de Colaborador (CLA) en {{.ClaURL}} antes de que podamos aceptar su contribución. Una vez que lo haya firmado (o haya corregido los problemas indicados arriba), el estado del CLA de esta pull request se actualizará automáticamente.
+{{- end}}`,
+		externalComment: "El CLA de los colaboradores de esta pull request lo gestiona %s; consulte allí su estado.",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "Verifique que la asociación entre el nombre, el correo electrónico y el nombre de usuario de GitHub del autor sea correcta y coincida con los registros del CLA.",
+			ReasonCommitterIdentity:      "Verifique que la asociación entre el nombre, el correo electrónico y el nombre de usuario de GitHub del committer sea correcta y coincida con los registros del CLA.",
+			ReasonAuthorNotSigner:        "El autor de uno o más commits no figura como firmante del CLA, ni de forma individual ni como miembro de una organización.",
+			ReasonCommitterNotSigner:     "El committer de uno o más commits no figura como firmante del CLA, ni de forma individual ni como miembro de una organización.",
+			ReasonCoAuthorNotSigner:      "Un coautor de uno o más commits, indicado en un trailer Co-authored-by, no figura como firmante del CLA, ni de forma individual ni como miembro de una organización.",
+			ReasonAuthorEmailUnverified:  "No se pudo verificar que el correo electrónico del autor de uno o más commits pertenezca a su cuenta de GitHub; firme los commits con una clave añadida a esa cuenta.",
+			ReasonAuthorNameMismatch:     "El nombre del autor de uno o más commits no coincide con el nombre del registro del CLA con el mismo correo electrónico o nombre de usuario de GitHub.",
+			ReasonAuthorEmailMismatch:    "El correo electrónico del autor de uno o más commits no coincide con el del registro del CLA con el mismo nombre de usuario de GitHub.",
+			ReasonAuthorLoginMismatch:    "El nombre de usuario de GitHub del autor de uno o más commits no coincide con el del registro del CLA con el mismo correo electrónico.",
+			ReasonCommitterNameMismatch:  "El nombre del committer de uno o más commits no coincide con el nombre del registro del CLA con el mismo correo electrónico o nombre de usuario de GitHub.",
+			ReasonCommitterEmailMismatch: "El correo electrónico del committer de uno o más commits no coincide con el del registro del CLA con el mismo nombre de usuario de GitHub.",
+			ReasonCommitterLoginMismatch: "El nombre de usuario de GitHub del committer de uno o más commits no coincide con el del registro del CLA con el mismo correo electrónico.",
+		},
+	},
+	"fr": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+Les contributeurs suivants doivent signer le CLA ou corriger leur identité de commit ; tous les autres contributeurs de cette pull request sont déjà couverts :` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `Si ces commits ont été créés avec un nom ou une adresse e-mail incorrects, vous pouvez les réécrire avec l'identité utilisée pour signer le CLA en exécutant les commandes suivantes sur votre branche :` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+Veuillez signer le contrat de licence de contributeur (CLA) à l'adresse {{.ClaURL}} avant que nous puissions accepter votre contribution. Une fois le CLA signé (ou les problèmes ci-dessus corrigés), le statut CLA de cette pull request sera mis à jour automatiquement.
+{{- end}}`,
+		externalComment: "Le CLA des contributeurs à cette pull request est géré par %s ; veuillez vérifier son statut à cet endroit.",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "Veuillez vérifier que l'association entre le nom, l'adresse e-mail et le nom d'utilisateur GitHub de l'auteur est correcte et correspond aux enregistrements du CLA.",
+			ReasonCommitterIdentity:      "Veuillez vérifier que l'association entre le nom, l'adresse e-mail et le nom d'utilisateur GitHub du committer est correcte et correspond aux enregistrements du CLA.",
+			ReasonAuthorNotSigner:        "L'auteur d'un ou plusieurs commits ne figure pas parmi les signataires du CLA, ni à titre individuel, ni en tant que membre d'une organisation.",
+			ReasonCommitterNotSigner:     "Le committer d'un ou plusieurs commits ne figure pas parmi les signataires du CLA, ni à titre individuel, ni en tant que membre d'une organisation.",
+			ReasonCoAuthorNotSigner:      "Un co-auteur d'un ou plusieurs commits, mentionné dans un trailer Co-authored-by, ne figure pas parmi les signataires du CLA, ni à titre individuel, ni en tant que membre d'une organisation.",
+			ReasonAuthorEmailUnverified:  "L'adresse e-mail de l'auteur d'un ou plusieurs commits n'a pas pu être vérifiée comme appartenant à son compte GitHub ; veuillez signer les commits avec une clé ajoutée à ce compte.",
+			ReasonAuthorNameMismatch:     "Le nom de l'auteur d'un ou plusieurs commits ne correspond pas au nom de l'enregistrement du CLA ayant la même adresse e-mail ou le même nom d'utilisateur GitHub.",
+			ReasonAuthorEmailMismatch:    "L'adresse e-mail de l'auteur d'un ou plusieurs commits ne correspond pas à celle de l'enregistrement du CLA ayant le même nom d'utilisateur GitHub.",
+			ReasonAuthorLoginMismatch:    "Le nom d'utilisateur GitHub de l'auteur d'un ou plusieurs commits ne correspond pas à celui de l'enregistrement du CLA ayant la même adresse e-mail.",
+			ReasonCommitterNameMismatch:  "Le nom du committer d'un ou plusieurs commits ne correspond pas au nom de l'enregistrement du CLA ayant la même adresse e-mail ou le même nom d'utilisateur GitHub.",
+			ReasonCommitterEmailMismatch: "L'adresse e-mail du committer d'un ou plusieurs commits ne correspond pas à celle de l'enregistrement du CLA ayant le même nom d'utilisateur GitHub.",
+			ReasonCommitterLoginMismatch: "Le nom d'utilisateur GitHub du committer d'un ou plusieurs commits ne correspond pas à celui de l'enregistrement du CLA ayant la même adresse e-mail.",
+		},
+	},
+	"ja": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+以下のコントリビューターは、CLA に署名するか、コミットの ID 情報を修正する必要があります。このプルリクエストのその他のコントリビューターは既に対象となっています:` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `これらのコミットが誤った名前またはメールアドレスで作成された場合は、ブランチで次のコマンドを実行すると、CLA に署名したときの ID でコミットを書き換えることができます:` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+コントリビューションを受け付ける前に、{{.ClaURL}} でコントリビューター ライセンス契約 (CLA) に署名してください。署名が完了する (または上記の問題が修正される) と、このプルリクエストの CLA ステータスは自動的に更新されます。
+{{- end}}`,
+		externalComment: "このプルリクエストのコントリビューターの CLA は %s によって管理されています。ステータスはそちらでご確認ください。",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "作成者 (author) の名前、メールアドレス、GitHub ユーザー名の関連付けがすべて正しく、CLA の記録と一致していることを確認してください。",
+			ReasonCommitterIdentity:      "コミッター (committer) の名前、メールアドレス、GitHub ユーザー名の関連付けがすべて正しく、CLA の記録と一致していることを確認してください。",
+			ReasonAuthorNotSigner:        "1 つ以上のコミットの作成者が、個人としても組織のメンバーとしても CLA 署名者として登録されていません。",
+			ReasonCommitterNotSigner:     "1 つ以上のコミットのコミッターが、個人としても組織のメンバーとしても CLA 署名者として登録されていません。",
+			ReasonCoAuthorNotSigner:      "1 つ以上のコミットの Co-authored-by トレーラーに記載された共同作成者が、個人としても組織のメンバーとしても CLA 署名者として登録されていません。",
+			ReasonAuthorEmailUnverified:  "1 つ以上のコミットの作成者のメールアドレスが、作成者の GitHub アカウントに属することを確認できませんでした。そのアカウントに追加した鍵でコミットに署名してください。",
+			ReasonAuthorNameMismatch:     "1 つ以上のコミットの作成者の名前が、同じメールアドレスまたは GitHub ユーザー名を持つ CLA の記録の名前と一致しません。",
+			ReasonAuthorEmailMismatch:    "1 つ以上のコミットの作成者のメールアドレスが、同じ GitHub ユーザー名を持つ CLA の記録のメールアドレスと一致しません。",
+			ReasonAuthorLoginMismatch:    "1 つ以上のコミットの作成者の GitHub ユーザー名が、同じメールアドレスを持つ CLA の記録のユーザー名と一致しません。",
+			ReasonCommitterNameMismatch:  "1 つ以上のコミットのコミッターの名前が、同じメールアドレスまたは GitHub ユーザー名を持つ CLA の記録の名前と一致しません。",
+			ReasonCommitterEmailMismatch: "1 つ以上のコミットのコミッターのメールアドレスが、同じ GitHub ユーザー名を持つ CLA の記録のメールアドレスと一致しません。",
+			ReasonCommitterLoginMismatch: "1 つ以上のコミットのコミッターの GitHub ユーザー名が、同じメールアドレスを持つ CLA の記録のユーザー名と一致しません。",
+		},
+	},
+	"pt-br": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+Os seguintes colaboradores precisam assinar o CLA ou corrigir sua identidade nos commits; todos os demais colaboradores deste pull request já estão cobertos:` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `Se esses commits foram feitos com o nome ou e-mail errado, você pode reescrevê-los com a identidade usada para assinar o CLA executando os seguintes comandos no seu branch:` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+Assine o Contrato de Licença de Colaborador (CLA) em {{.ClaURL}} antes que possamos aceitar sua contribuição. Depois de assiná-lo (ou corrigir os problemas acima), o status do CLA deste pull request será atualizado automaticamente.
+{{- end}}`,
+		externalComment: "O CLA dos colaboradores deste pull request é gerenciado por %s; verifique o status lá.",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "Verifique se a associação entre o nome, o e-mail e o nome de usuário do GitHub do autor está correta e corresponde aos registros do CLA.",
+			ReasonCommitterIdentity:      "Verifique se a associação entre o nome, o e-mail e o nome de usuário do GitHub do committer está correta e corresponde aos registros do CLA.",
+			ReasonAuthorNotSigner:        "O autor de um ou mais commits não está listado como signatário do CLA, seja individualmente ou como membro de uma organização.",
+			ReasonCommitterNotSigner:     "O committer de um ou mais commits não está listado como signatário do CLA, seja individualmente ou como membro de uma organização.",
+			ReasonCoAuthorNotSigner:      "Um coautor de um ou mais commits, indicado em um trailer Co-authored-by, não está listado como signatário do CLA, seja individualmente ou como membro de uma organização.",
+			ReasonAuthorEmailUnverified:  "Não foi possível verificar se o e-mail do autor de um ou mais commits pertence à conta do GitHub do autor; assine os commits com uma chave adicionada a essa conta.",
+			ReasonAuthorNameMismatch:     "O nome do autor de um ou mais commits não corresponde ao nome no registro do CLA com o mesmo e-mail ou nome de usuário do GitHub.",
+			ReasonAuthorEmailMismatch:    "O e-mail do autor de um ou mais commits não corresponde ao e-mail no registro do CLA com o mesmo nome de usuário do GitHub.",
+			ReasonAuthorLoginMismatch:    "O nome de usuário do GitHub do autor de um ou mais commits não corresponde ao nome de usuário no registro do CLA com o mesmo e-mail.",
+			ReasonCommitterNameMismatch:  "O nome do committer de um ou mais commits não corresponde ao nome no registro do CLA com o mesmo e-mail ou nome de usuário do GitHub.",
+			ReasonCommitterEmailMismatch: "O e-mail do committer de um ou mais commits não corresponde ao e-mail no registro do CLA com o mesmo nome de usuário do GitHub.",
+			ReasonCommitterLoginMismatch: "O nome de usuário do GitHub do committer de um ou mais commits não corresponde ao nome de usuário no registro do CLA com o mesmo e-mail.",
+		},
+	},
+	"zh-cn": {
+		commentTemplate: reasonsTemplate + `
+{{- if .Unsigned}}
+
+以下贡献者需要签署 CLA 或修正其提交身份信息；此拉取请求的其他贡献者均已涵盖：` + unsignedListTemplate + `
+{{- end}}` + fixCommandsStart + `如果这些提交使用了错误的姓名或电子邮件地址，您可以在您的分支上运行以下命令，将其改写为您签署 CLA 时使用的身份：` + fixCommandsEnd + `
+{{- if .ClaURL}}
+
+在我们接受您的贡献之前，请前往 {{.ClaURL}} 签署贡献者许可协议（CLA）。签署完成（或修复上述问题）后，此拉取请求的 CLA 状态将自动更新。
+{{- end}}`,
+		externalComment: "此拉取请求贡献者的 CLA 由 %s 管理；请在那里查看其状态。",
+		reasons: map[string]string{
+			ReasonAuthorIdentity:         "请确认作者的姓名、电子邮件地址和 GitHub 用户名之间的关联均正确无误，并与 CLA 记录一致。",
+			ReasonCommitterIdentity:      "请确认提交者的姓名、电子邮件地址和 GitHub 用户名之间的关联均正确无误，并与 CLA 记录一致。",
+			ReasonAuthorNotSigner:        "一个或多个提交的作者未被列为 CLA 签署人（无论是以个人身份还是作为组织成员）。",
+			ReasonCommitterNotSigner:     "一个或多个提交的提交者未被列为 CLA 签署人（无论是以个人身份还是作为组织成员）。",
+			ReasonCoAuthorNotSigner:      "一个或多个提交的 Co-authored-by 尾注中列出的共同作者未被列为 CLA 签署人（无论是以个人身份还是作为组织成员）。",
+			ReasonAuthorEmailUnverified:  "无法验证一个或多个提交的作者电子邮件地址属于作者的 GitHub 帐户；请使用已添加到该帐户的密钥为提交签名。",
+			ReasonAuthorNameMismatch:     "一个或多个提交的作者姓名与具有相同电子邮件地址或 GitHub 用户名的 CLA 记录中的姓名不一致。",
+			ReasonAuthorEmailMismatch:    "一个或多个提交的作者电子邮件地址与具有相同 GitHub 用户名的 CLA 记录中的电子邮件地址不一致。",
+			ReasonAuthorLoginMismatch:    "一个或多个提交的作者 GitHub 用户名与具有相同电子邮件地址的 CLA 记录中的用户名不一致。",
+			ReasonCommitterNameMismatch:  "一个或多个提交的提交者姓名与具有相同电子邮件地址或 GitHub 用户名的 CLA 记录中的姓名不一致。",
+			ReasonCommitterEmailMismatch: "一个或多个提交的提交者电子邮件地址与具有相同 GitHub 用户名的 CLA 记录中的电子邮件地址不一致。",
+			ReasonCommitterLoginMismatch: "一个或多个提交的提交者 GitHub 用户名与具有相同电子邮件地址的 CLA 记录中的用户名不一致。",
+		},
+	},
+}
+
+// canonicalLocale normalizes a locale name such as "pt_BR" to the form used
+// in the catalog ("pt-br"), falling back to the base language (e.g., "de-AT"
+// to "de") if there is no exact match. It returns an empty string if there is
+// no translation for the locale.
+func canonicalLocale(locale string) string {
+	locale = strings.ToLower(strings.Replace(locale, "_", "-", -1))
+	if _, ok := locales[locale]; ok {
+		return locale
+	}
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		if _, ok := locales[locale[:idx]]; ok {
+			return locale[:idx]
+		}
+	}
+	return ""
+}
+
+// IsSupportedLocale returns whether there are built-in translations for the
+// given locale; the empty string denotes the `DefaultLocale`.
+func IsSupportedLocale(locale string) bool {
+	if locale == "" || strings.EqualFold(locale, DefaultLocale) {
+		return true
+	}
+	return canonicalLocale(locale) != ""
+}
+
+// SupportedLocales returns the sorted list of locales with built-in
+// translations, including the `DefaultLocale`.
+func SupportedLocales() []string {
+	names := []string{DefaultLocale}
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LocalizedCommentTemplate returns the default comment template for the given
+// locale, or `DefaultCommentTemplate` if there is no translation.
+func LocalizedCommentTemplate(locale string) string {
+	if messages, ok := locales[canonicalLocale(locale)]; ok {
+		return messages.commentTemplate
+	}
+	return DefaultCommentTemplate
+}
+
+// ExternalComment returns the comment posted when a PR is labeled as external,
+// naming the system managing its CLA, in the given locale.
+func ExternalComment(locale string, managedBy string) string {
+	text := DefaultExternalComment
+	if messages, ok := locales[canonicalLocale(locale)]; ok && messages.externalComment != "" {
+		text = messages.externalComment
+	}
+	return fmt.Sprintf(text, managedBy)
+}
+
+// LocalizeReason translates a built-in non-compliance reason into the given
+// locale; reasons without a translation (such as those reported by custom
+// compliance checkers) are returned unchanged.
+func LocalizeReason(locale string, reason string) string {
+	if messages, ok := locales[canonicalLocale(locale)]; ok {
+		if translated, ok := messages.reasons[reason]; ok {
+			return translated
+		}
+	}
+	return reason
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestIsSupportedLocale(t *testing.T) {
+	for _, locale := range []string{"", "en", "EN", "de", "de-AT", "pt-BR", "pt_BR", "zh-CN", "ja"} {
+		assert.True(t, ghutil.IsSupportedLocale(locale), "locale: %s", locale)
+	}
+	for _, locale := range []string{"xx", "pt", "zh-TW"} {
+		assert.False(t, ghutil.IsSupportedLocale(locale), "locale: %s", locale)
+	}
+	assert.Contains(t, ghutil.SupportedLocales(), ghutil.DefaultLocale)
+}
+
+func TestLocalizeReason(t *testing.T) {
+	assert.Equal(t, ghutil.ReasonAuthorNotSigner, ghutil.LocalizeReason("", ghutil.ReasonAuthorNotSigner))
+	assert.Equal(t, ghutil.ReasonAuthorNotSigner, ghutil.LocalizeReason("en", ghutil.ReasonAuthorNotSigner))
+	assert.NotEqual(t, ghutil.ReasonAuthorNotSigner, ghutil.LocalizeReason("de", ghutil.ReasonAuthorNotSigner))
+
+	// Reasons from custom checkers have no translations.
+	assert.Equal(t, "Custom reason.", ghutil.LocalizeReason("de", "Custom reason."))
+}
+
+func TestRenderComment_Localized(t *testing.T) {
+	comment, err := ghutil.RenderComment("", ghutil.CommentData{
+		Reason: ghutil.ReasonCommitterNotSigner,
+		ClaURL: "https://cla.example.com",
+		Locale: "es",
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, comment, ghutil.LocalizeReason("es", ghutil.ReasonCommitterNotSigner))
+	assert.Contains(t, comment, "Firme el Acuerdo de Licencia de Colaborador (CLA) en https://cla.example.com")
+}
+
+func TestAllLocalesTranslateAllReasons(t *testing.T) {
+	reasons := []string{
+		ghutil.ReasonAuthorIdentity,
+		ghutil.ReasonCommitterIdentity,
+		ghutil.ReasonAuthorNotSigner,
+		ghutil.ReasonCommitterNotSigner,
+		ghutil.ReasonCoAuthorNotSigner,
+		ghutil.ReasonAuthorEmailUnverified,
+		ghutil.ReasonAuthorNameMismatch,
+		ghutil.ReasonAuthorEmailMismatch,
+		ghutil.ReasonAuthorLoginMismatch,
+		ghutil.ReasonCommitterNameMismatch,
+		ghutil.ReasonCommitterEmailMismatch,
+		ghutil.ReasonCommitterLoginMismatch,
+	}
+	for _, locale := range ghutil.SupportedLocales() {
+		if locale == ghutil.DefaultLocale {
+			continue
+		}
+		assert.NotEqual(t, ghutil.ExternalComment(ghutil.DefaultLocale, "EasyCLA"), ghutil.ExternalComment(locale, "EasyCLA"), "locale %s is missing translation for the external comment", locale)
+		assert.Contains(t, ghutil.ExternalComment(locale, "EasyCLA"), "EasyCLA")
+		for _, reason := range reasons {
+			assert.NotEqual(t, reason, ghutil.LocalizeReason(locale, reason), "locale %s is missing translation for: %s", locale, reason)
+		}
+		_, err := ghutil.ParseCommentTemplate(ghutil.LocalizedCommentTemplate(locale))
+		assert.Nil(t, err, "locale %s has invalid comment template", locale)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+)
+
+// CommentMarker is a hidden marker included in the body of the comments posted
+// with `MinimizeComments`, identifying them as ours so they can be minimized
+// later; since anyone can include it in their comments, only those also
+// posted by the bot's account are.
+const CommentMarker = "<!-- crbot:cla-comment -->"
+
+// minimizeClassifierOutdated is the reason given for minimizing comments, as
+// defined by the GitHub GraphQL API.
+const minimizeClassifierOutdated = "OUTDATED"
+
+// minimizeOutdatedComments minimizes our comments on the PR other than the one
+// just posted, `latest`, i.e., those with the marker posted by the same
+// account. Comments minimized before are minimized again, which is a no-op,
+// as the REST API doesn't tell which ones are.
+func minimizeOutdatedComments(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, latest *github.IssueComment) {
+	orgName := prSpec.Org
+	repoName := prSpec.Repo
+	pullNumber := prSpec.Pull.GetNumber()
+	if ghc.GraphQL == nil {
+		logger.Errorf("  Error minimizing comments on repo '%s/%s' PR %d: the GraphQL API is not available", orgName, repoName, pullNumber)
+		return
+	}
+	botLogin := latest.GetUser().GetLogin()
+	if botLogin == "" {
+		logger.Errorf("  Error minimizing comments on repo '%s/%s' PR %d: the author of the new comment is unknown", orgName, repoName, pullNumber)
+		return
+	}
+
+	var outdated []*github.IssueComment
+	opt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := ghc.Issues.ListComments(ctx, orgName, repoName, pullNumber, opt)
+		if err != nil {
+			logger.Errorf("  Error listing comments on repo '%s/%s' PR %d: %v", orgName, repoName, pullNumber, err)
+			return
+		}
+		for _, comment := range comments {
+			if comment.GetID() != latest.GetID() && comment.GetUser().GetLogin() == botLogin && strings.Contains(comment.GetBody(), CommentMarker) {
+				outdated = append(outdated, comment)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	for _, comment := range outdated {
+		logger.Infof("  Minimizing outdated comment %d on repo '%s/%s' PR %d...", comment.GetID(), orgName, repoName, pullNumber)
+		if err := ghc.GraphQL.MinimizeComment(ctx, comment.GetNodeID(), minimizeClassifierOutdated); err != nil {
+			logger.Errorf("  Error minimizing comment %d on repo '%s/%s' PR %d: %v", comment.GetID(), orgName, repoName, pullNumber, err)
+		}
+	}
+}
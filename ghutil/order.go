@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v21/github"
+)
+
+// Supported orders in which the PRs of each repo are processed; the default,
+// `OrderNumberDesc`, processes the newest PRs first, as GitHub lists them.
+const (
+	OrderNumberDesc  = "number-desc"
+	OrderNumberAsc   = "number-asc"
+	OrderCreatedDesc = "created-desc"
+	OrderCreatedAsc  = "created-asc"
+	OrderUpdatedDesc = "updated-desc"
+	OrderUpdatedAsc  = "updated-asc"
+)
+
+// PullOrders lists the supported PR processing orders.
+var PullOrders = []string{OrderNumberDesc, OrderNumberAsc, OrderCreatedDesc, OrderCreatedAsc, OrderUpdatedDesc, OrderUpdatedAsc}
+
+// IsSupportedPullOrder returns whether `order` is empty (i.e., the default) or
+// one of the supported `PullOrders`.
+func IsSupportedPullOrder(order string) bool {
+	if order == "" {
+		return true
+	}
+	for _, o := range PullOrders {
+		if o == order {
+			return true
+		}
+	}
+	return false
+}
+
+// SortPulls sorts the PRs in the given order, in place; ties, e.g., PRs
+// updated at the same time, are broken by number, newest first.
+func SortPulls(pulls []*github.PullRequest, order string) {
+	less := func(a, b *github.PullRequest) bool {
+		return a.GetNumber() > b.GetNumber()
+	}
+	switch order {
+	case OrderNumberAsc:
+		less = func(a, b *github.PullRequest) bool {
+			return a.GetNumber() < b.GetNumber()
+		}
+	case OrderCreatedDesc, OrderCreatedAsc:
+		less = timeOrder(order == OrderCreatedAsc, (*github.PullRequest).GetCreatedAt)
+	case OrderUpdatedDesc, OrderUpdatedAsc:
+		less = timeOrder(order == OrderUpdatedAsc, (*github.PullRequest).GetUpdatedAt)
+	}
+	sort.SliceStable(pulls, func(i, j int) bool {
+		return less(pulls[i], pulls[j])
+	})
+}
+
+// pullListOptions returns the options to list the open PRs of a repo from the
+// API in the given order, 100 per page; orders by number use the creation
+// time, which matches them.
+func pullListOptions(order string) *github.PullRequestListOptions {
+	opt := &github.PullRequestListOptions{
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	switch order {
+	case OrderUpdatedDesc, OrderUpdatedAsc:
+		opt.Sort = "updated"
+	}
+	switch order {
+	case OrderNumberAsc, OrderCreatedAsc, OrderUpdatedAsc:
+		opt.Direction = "asc"
+	}
+	return opt
+}
+
+// timeOrder returns an ordering of PRs by the time returned by `getTime`,
+// ascending or descending, breaking ties by number, newest first.
+func timeOrder(ascending bool, getTime func(*github.PullRequest) time.Time) func(a, b *github.PullRequest) bool {
+	return func(a, b *github.PullRequest) bool {
+		timeA, timeB := getTime(a), getTime(b)
+		if timeA.Equal(timeB) {
+			return a.GetNumber() > b.GetNumber()
+		}
+		if ascending {
+			return timeA.Before(timeB)
+		}
+		return timeA.After(timeB)
+	}
+}
+
+// resumeIndex returns the index in `pulls`, sorted in the given order, of the
+// PR numbered `pullNumber` at which to resume processing; a zero `pullNumber`
+// starts from the beginning. If that PR is no longer listed (e.g., it has since
+// been closed), processing resumes at the next PR in the order for orders by
+// number or creation time, which match each other, or from the beginning for
+// orders by update time, as PRs may since have moved within them.
+func resumeIndex(pulls []*github.PullRequest, pullNumber int, order string) int {
+	if pullNumber == 0 {
+		return 0
+	}
+	for idx, pull := range pulls {
+		if pull.GetNumber() == pullNumber {
+			return idx
+		}
+	}
+	switch order {
+	case OrderUpdatedDesc, OrderUpdatedAsc:
+		return 0
+	case OrderNumberAsc, OrderCreatedAsc:
+		for idx, pull := range pulls {
+			if pull.GetNumber() > pullNumber {
+				return idx
+			}
+		}
+	default:
+		for idx, pull := range pulls {
+			if pull.GetNumber() < pullNumber {
+				return idx
+			}
+		}
+	}
+	return len(pulls)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/go-github/v21/github"
+)
+
+func newPull(number int, created time.Time, updated time.Time) *github.PullRequest {
+	return &github.PullRequest{Number: &number, CreatedAt: &created, UpdatedAt: &updated}
+}
+
+func pullNumbers(pulls []*github.PullRequest) []int {
+	var numbers []int
+	for _, pull := range pulls {
+		numbers = append(numbers, pull.GetNumber())
+	}
+	return numbers
+}
+
+func TestSortPulls(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		order    string
+		expected []int
+	}{
+		{"", []int{4, 3, 2, 1}},
+		{ghutil.OrderNumberDesc, []int{4, 3, 2, 1}},
+		{ghutil.OrderNumberAsc, []int{1, 2, 3, 4}},
+		{ghutil.OrderCreatedDesc, []int{4, 3, 2, 1}},
+		{ghutil.OrderCreatedAsc, []int{1, 2, 3, 4}},
+		// PRs 2 and 4 were updated at the same time, so the newer comes
+		// first in either direction.
+		{ghutil.OrderUpdatedDesc, []int{1, 4, 2, 3}},
+		{ghutil.OrderUpdatedAsc, []int{3, 4, 2, 1}},
+	}
+	for _, testCase := range testCases {
+		pulls := []*github.PullRequest{
+			newPull(2, day(2), day(8)),
+			newPull(4, day(4), day(8)),
+			newPull(1, day(1), day(9)),
+			newPull(3, day(3), day(5)),
+		}
+		ghutil.SortPulls(pulls, testCase.order)
+		assert.Equal(t, testCase.expected, pullNumbers(pulls), "order: %q", testCase.order)
+	}
+}
+
+func TestIsSupportedPullOrder(t *testing.T) {
+	assert.True(t, ghutil.IsSupportedPullOrder(""))
+	for _, order := range ghutil.PullOrders {
+		assert.True(t, ghutil.IsSupportedPullOrder(order), order)
+	}
+	assert.False(t, ghutil.IsSupportedPullOrder("updated"))
+}
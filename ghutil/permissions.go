@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+)
+
+// oauthScopesHeader lists the scopes of a classic token in each response; it
+// is absent for fine-grained tokens and GitHub App installation tokens.
+const oauthScopesHeader = "X-OAuth-Scopes"
+
+// writeRepoPermissions are the permissions on a repo, as reported by GitHub,
+// any of which allows labeling and commenting on its PRs.
+var writeRepoPermissions = []string{"triage", "push", "maintain", "admin"}
+
+// CheckPermissions verifies, before any PRs are processed, that the token can
+// list the repos in the org and, if they are to be updated, label and comment
+// on their PRs, so that a token lacking permissions fails fast with an
+// actionable message rather than with "403 Forbidden" errors on each PR.
+// Classic tokens are checked by their scopes, and other tokens by their
+// permissions on the first target repo, if GitHub reports them.
+func CheckPermissions(ghc *GitHubClient, orgName string, repoName string, updateRepo bool) error {
+	ctx := context.Background()
+	repos, resp, err := ghc.Repositories.List(ctx, orgName, &github.RepositoryListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		switch responseStatus(err) {
+		case http.StatusUnauthorized:
+			return errors.New("the GitHub token is invalid or has expired; generate a new one and update the secrets file")
+		case http.StatusForbidden, http.StatusNotFound:
+			return fmt.Errorf("the GitHub token can't list the repos in %s; grant it the `repo` scope or, for a fine-grained token or GitHub App, read access to repository metadata in %s", orgName, orgName)
+		}
+		return fmt.Errorf("error listing repos in %s: %s", orgName, err)
+	}
+	if !updateRepo {
+		return nil
+	}
+
+	if resp != nil && resp.Response != nil {
+		if values, ok := resp.Header[textproto.CanonicalMIMEHeaderKey(oauthScopesHeader)]; ok {
+			var scopes []string
+			for _, value := range values {
+				for _, scope := range strings.Split(value, ",") {
+					if scope = strings.TrimSpace(scope); scope != "" {
+						scopes = append(scopes, scope)
+					}
+				}
+			}
+			for _, scope := range scopes {
+				if scope == "repo" || scope == "public_repo" {
+					return nil
+				}
+			}
+			return fmt.Errorf("the GitHub token has scopes [%s], but needs the `repo` scope (or `public_repo` for public repos only) to label and comment on PRs with -update-repo", strings.Join(scopes, ", "))
+		}
+	}
+
+	var repo *github.Repository
+	if selectors := ParseRepoSelector(repoName); len(selectors) > 0 && !hasRepoPattern(selectors) {
+		repo, _, err = ghc.Repositories.Get(ctx, orgName, selectors[0])
+		if err != nil {
+			return fmt.Errorf("error looking up %s/%s: %s", orgName, selectors[0], err)
+		}
+	} else if len(repos) > 0 {
+		repo = repos[0]
+	}
+	if repo == nil || repo.Permissions == nil {
+		return nil
+	}
+	for _, permission := range writeRepoPermissions {
+		if (*repo.Permissions)[permission] {
+			return nil
+		}
+	}
+	return fmt.Errorf("the GitHub token has read-only access to %s/%s, but needs triage access or, for a fine-grained token or GitHub App, write access to issues and pull requests, to label and comment on PRs with -update-repo", orgName, repo.GetName())
+}
+
+// responseStatus returns the HTTP status of a GitHub API error, or 0 if the
+// error didn't come from a response.
+func responseStatus(err error) int {
+	if errResp, ok := err.(*github.ErrorResponse); ok && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+// scopesResponse is a response to a request authenticated with a classic token
+// with the given scopes.
+func scopesResponse(scopes string) *github.Response {
+	return &github.Response{Response: &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"X-Oauth-Scopes": {scopes}},
+	}}
+}
+
+func TestCheckPermissions_InvalidToken(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	err401 := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnauthorized}, Message: "Bad credentials"}
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, nil, err401)
+
+	err := ghutil.CheckPermissions(ghc, orgName, "", false)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid or has expired")
+	}
+}
+
+func TestCheckPermissions_CannotListRepos(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, nil, notFoundError())
+
+	err := ghutil.CheckPermissions(ghc, orgName, "", false)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't list the repos in "+orgName)
+	}
+}
+
+func TestCheckPermissions_ClassicTokenScopes(t *testing.T) {
+	for _, test := range []struct {
+		scopes string
+		ok     bool
+	}{
+		{"repo, read:org", true},
+		{"public_repo", true},
+		{"read:org", false},
+		{"", false},
+	} {
+		t.Run(test.scopes, func(t *testing.T) {
+			setUp(t)
+			defer tearDown(t)
+
+			mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, scopesResponse(test.scopes), nil)
+
+			err := ghutil.CheckPermissions(ghc, orgName, "", true)
+			if test.ok {
+				assert.Nil(t, err)
+			} else if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), "needs the `repo` scope")
+			}
+		})
+	}
+}
+
+func TestCheckPermissions_ClassicTokenScopes_NoUpdateRepo(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, scopesResponse("read:org"), nil)
+
+	assert.Nil(t, ghutil.CheckPermissions(ghc, orgName, "", false))
+}
+
+func TestCheckPermissions_RepoPermissions(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	readOnly := map[string]bool{"pull": true}
+	repo := github.Repository{Name: github.String(repoName), Permissions: &readOnly}
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return(nil, nil, nil)
+	mockGhc.Repositories.EXPECT().Get(any, orgName, repoName).Return(&repo, nil, nil)
+
+	err := ghutil.CheckPermissions(ghc, orgName, repoName, true)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "read-only access to "+orgName+"/"+repoName)
+	}
+}
+
+func TestCheckPermissions_RepoPermissions_Triage(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	triage := map[string]bool{"pull": true, "triage": true}
+	repo := github.Repository{Name: github.String("repo1"), Permissions: &triage}
+	mockGhc.Repositories.EXPECT().List(any, orgName, any).Return([]*github.Repository{&repo}, nil, nil)
+
+	assert.Nil(t, ghutil.CheckPermissions(ghc, orgName, "repo*", true))
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress tracks how far `ProcessOrgRepo` has got through the repos of the
+// org and the PRs of each, e.g., for a progress indicator; it is safe for
+// concurrent use. Its methods do nothing on a nil `Progress`.
+type Progress struct {
+	mu      sync.Mutex
+	started time.Time
+
+	repos     int
+	reposDone int
+	inRepo    bool
+	pullsDone int
+
+	// repoPulls and repoPullsDone are the number of PRs to process in the
+	// current repo, and of those processed.
+	repoPulls     int
+	repoPullsDone int
+}
+
+// ProgressSnapshot is the progress as of a point in time. `ETA` is the
+// estimated time until all repos are processed, or zero if unknown.
+type ProgressSnapshot struct {
+	Repos     int
+	ReposDone int
+	PullsDone int
+	Elapsed   time.Duration
+	ETA       time.Duration
+}
+
+// NewProgress returns a `Progress` for a run started at the given time.
+func NewProgress(started time.Time) *Progress {
+	return &Progress{started: started}
+}
+
+// setRepos records the number of repos to process.
+func (p *Progress) setRepos(repos int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.repos = repos
+}
+
+// startRepo marks the previous repo, if any, as processed, and starts the next.
+func (p *Progress) startRepo() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endRepoLocked()
+	p.inRepo = true
+}
+
+// setRepoPulls records the number of PRs to process in the current repo.
+func (p *Progress) setRepoPulls(pulls int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.repoPulls = pulls
+}
+
+// pullDone counts a processed PR of the current repo.
+func (p *Progress) pullDone() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pullsDone++
+	p.repoPullsDone++
+}
+
+// finish marks the last repo, if any, as processed.
+func (p *Progress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endRepoLocked()
+}
+
+func (p *Progress) endRepoLocked() {
+	if p.inRepo {
+		p.reposDone++
+		p.inRepo = false
+	}
+	p.repoPulls, p.repoPullsDone = 0, 0
+}
+
+// Snapshot returns the progress as of `now`, estimating the time remaining
+// from the fraction of repos processed so far, counting the PRs processed in
+// the current repo towards it.
+func (p *Progress) Snapshot(now time.Time) ProgressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	snapshot := ProgressSnapshot{
+		Repos:     p.repos,
+		ReposDone: p.reposDone,
+		PullsDone: p.pullsDone,
+		Elapsed:   now.Sub(p.started),
+	}
+	if p.repos == 0 {
+		return snapshot
+	}
+	done := float64(p.reposDone)
+	if p.repoPulls > 0 {
+		done += float64(p.repoPullsDone) / float64(p.repoPulls)
+	}
+	if done > 0 {
+		fraction := done / float64(p.repos)
+		snapshot.ETA = time.Duration(float64(snapshot.Elapsed) * (1 - fraction) / fraction)
+	}
+	return snapshot
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/state"
+)
+
+// pullKey returns the key of the PR in the state store.
+func pullKey(prSpec GitHubProcessSinglePullSpec) state.PullKey {
+	return state.PullKey{
+		Org:    prSpec.Org,
+		Repo:   prSpec.Repo,
+		Number: prSpec.Pull.GetNumber(),
+	}
+}
+
+// updatePullState applies the update to the stored state of the PR, if the
+// client has a state store, logging any errors.
+func updatePullState(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, update func(*state.PullState)) {
+	if ghc.State == nil {
+		return
+	}
+	key := pullKey(prSpec)
+	pullState, err := ghc.State.Get(key)
+	if err != nil {
+		logger.Errorf("  Error reading state of PR %d: %v", key.Number, err)
+		return
+	}
+	update(&pullState)
+	if err := ghc.State.Put(key, pullState); err != nil {
+		logger.Errorf("  Error saving state of PR %d: %v", key.Number, err)
+	}
+}
+
+// claimTTL is how long a claim on an action on a PR, taken via `claimAction`,
+// prevents other instances of the bot from taking the same action; it only
+// needs to cover instances processing the same event concurrently.
+const claimTTL = 10 * time.Minute
+
+// claimAction claims the action on the PR in the state store, if any,
+// returning false if another instance of the bot sharing the store already
+// took it. If the claim fails, the action is taken anyway, as a missing
+// comment is worse than a duplicate one.
+func claimAction(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, action string) bool {
+	if ghc.State == nil {
+		return true
+	}
+	claimed, err := ghc.State.Claim(pullKey(prSpec).String()+":"+action, claimTTL)
+	if err != nil {
+		logger.Errorf("  Error claiming %s on PR %d: %v", action, prSpec.Pull.GetNumber(), err)
+		return true
+	}
+	return claimed
+}
+
+// digestJSON returns a hex-encoded SHA-256 digest of the JSON encoding of v.
+func digestJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+// ClaSignersDigest returns a digest identifying the contents of the CLA
+// signers config, for `GitHubProcessSinglePullSpec.ClaSignersDigest`.
+func ClaSignersDigest(claSigners config.ClaSigners) string {
+	return digestJSON(claSigners)
+}
+
+// pullFingerprint identifies everything other than the PR itself which its
+// processing depends on: the CLA signers and the settings in the spec. It is
+// empty, disabling skipping unchanged PRs, if the digest of the CLA signers is
+// unknown.
+func pullFingerprint(prSpec GitHubProcessSinglePullSpec) string {
+	if prSpec.ClaSignersDigest == "" {
+		return ""
+	}
+	prSpec.Pull = nil
+	return digestJSON(prSpec)
+}
+
+// pullLabels returns the sorted, lowercased names of the labels of the PR.
+func pullLabels(pull *github.PullRequest) []string {
+	var labels []string
+	for _, label := range pull.Labels {
+		labels = append(labels, strings.ToLower(label.GetName()))
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// isUnchanged returns whether the PR has the same head commit and labels as
+// when it was last processed, against the same CLA signers and settings, so
+// that processing it again would neither change its compliance nor lead to
+// any updates.
+func isUnchanged(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, fingerprint string) bool {
+	if ghc.State == nil || fingerprint == "" {
+		return false
+	}
+	pullState, err := ghc.State.Get(pullKey(prSpec))
+	if err != nil {
+		logger.Errorf("  Error reading state of PR %d: %v", prSpec.Pull.GetNumber(), err)
+		return false
+	}
+	return pullState.HeadSHA != "" &&
+		pullState.HeadSHA == prSpec.Pull.GetHead().GetSHA() &&
+		pullState.Fingerprint == fingerprint &&
+		strings.Join(pullState.Labels, "\n") == strings.Join(pullLabels(prSpec.Pull), "\n")
+}
+
+// recordProcessed records the head commit of the PR and the labels it has
+// after processing, for `isUnchanged` to compare against on the next run.
+func recordProcessed(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, fingerprint string, labels map[string]bool) {
+	if fingerprint == "" || !prSpec.UpdateRepo {
+		return
+	}
+	var labelNames []string
+	for label := range labels {
+		labelNames = append(labelNames, label)
+	}
+	sort.Strings(labelNames)
+	updatePullState(ghc, prSpec, func(pullState *state.PullState) {
+		pullState.HeadSHA = prSpec.Pull.GetHead().GetSHA()
+		pullState.Labels = labelNames
+		pullState.Fingerprint = fingerprint
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/code-review-bot/events"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/code-review-bot/state"
+)
+
+// checkRegression alerts maintainers if the PR was compliant, as recorded in
+// the state store or shown by its CLA label, but no longer is, e.g., after a
+// force-push added commits by an author without a CLA, publishing a distinct,
+// high-severity event. The status of the PR is then recorded for the next run.
+func checkRegression(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus, hadCompliantLabel bool) {
+	status := newReportPullRequest(prSpec, pullRequestStatus).Status()
+	wasCompliant := hadCompliantLabel
+	if ghc.State != nil {
+		pullState, err := ghc.State.Get(pullKey(prSpec))
+		if err != nil {
+			logger.Errorf("  Error reading state of PR %d: %v", prSpec.Pull.GetNumber(), err)
+		}
+		wasCompliant = wasCompliant || pullState.Status == report.StatusCompliant
+		if prSpec.UpdateRepo && pullState.Status != status {
+			updatePullState(ghc, prSpec, func(pullState *state.PullState) {
+				pullState.Status = status
+			})
+		}
+	}
+	if !wasCompliant || status != report.StatusNonCompliant {
+		return
+	}
+
+	pull := prSpec.Pull
+	logger.Errorf("  Regression: repo '%s/%s' PR %d was compliant, but no longer is: %s", prSpec.Org, prSpec.Repo, pull.GetNumber(), pullRequestStatus.NonComplianceReason)
+	if ghc.Events == nil {
+		return
+	}
+	if !prSpec.UpdateRepo {
+		logger.Info("  ... but -update-repo flag is disabled; skipping regression alert")
+		return
+	}
+	event := events.Event{
+		Type:           events.TypeRegressed,
+		Timestamp:      time.Now().UTC(),
+		Org:            prSpec.Org,
+		Repo:           prSpec.Repo,
+		Number:         pull.GetNumber(),
+		URL:            pull.GetHTMLURL(),
+		Status:         status,
+		Reason:         pullRequestStatus.NonComplianceReason,
+		PreviousStatus: report.StatusCompliant,
+		Severity:       events.SeverityHigh,
+	}
+	if err := ghc.Events.Publish(ctx, event); err != nil {
+		logger.Errorf("  Error publishing regression of PR %d: %v", pull.GetNumber(), err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"time"
+
+	"github.com/google/code-review-bot/state"
+)
+
+// day is the unit of `config.Reminders.IntervalDays`.
+const day = 24 * time.Hour
+
+// reminderComment prefixes the comment explaining why the PR is not compliant
+// with a reminder mentioning its author.
+func reminderComment(author string, comment string) string {
+	greeting := "Hi"
+	if author != "" {
+		greeting += " @" + author
+	}
+	return greeting + ", this is a friendly reminder that this pull request is still waiting on the CLA.\n\n" + comment
+}
+
+// recordComment records that the comment explaining why the PR is not
+// compliant was just posted, restarting the reminder cadence, and counts it
+// against the head commit of the PR for `commentCapped`.
+func recordComment(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	if !prSpec.UpdateRepo {
+		return
+	}
+	headSHA := prSpec.Pull.GetHead().GetSHA()
+	updatePullState(ghc, prSpec, func(pullState *state.PullState) {
+		pullState.Commented = time.Now().UTC()
+		pullState.Reminders = 0
+		pullState.LastReminder = time.Time{}
+		if pullState.CommentedSHA != headSHA {
+			pullState.CommentedSHA = headSHA
+			pullState.CommentsAtSHA = 0
+		}
+		pullState.CommentsAtSHA++
+	})
+}
+
+// commentCapped returns whether the comment explaining why the PR is not
+// compliant was already posted `MaxCommentsPerSHA` times on its current head
+// commit, e.g., because its labels kept being changed by hand or by another
+// bot, in which case it isn't posted again until new commits are pushed.
+func commentCapped(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) bool {
+	if ghc.State == nil || prSpec.MaxCommentsPerSHA <= 0 {
+		return false
+	}
+	pullState, err := ghc.State.Get(pullKey(prSpec))
+	if err != nil {
+		logger.Errorf("  Error reading state of PR %d: %v", prSpec.Pull.GetNumber(), err)
+		return false
+	}
+	headSHA := prSpec.Pull.GetHead().GetSHA()
+	if pullState.CommentedSHA != headSHA || pullState.CommentsAtSHA < prSpec.MaxCommentsPerSHA {
+		return false
+	}
+	logger.Infof("  Not commenting: already commented %d time(s) on head commit %s", pullState.CommentsAtSHA, headSHA)
+	return true
+}
+
+// reminderDue returns whether a reminder should be posted on the PR, which
+// already carries the non-compliant label: reminders must be enabled, and the
+// configured interval must have passed since the comment or the last reminder,
+// without reaching the maximum number of reminders. A PR which was commented
+// on before its state was tracked starts being tracked now.
+func reminderDue(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) bool {
+	reminders := prSpec.Reminders
+	if ghc.State == nil || reminders.IntervalDays <= 0 {
+		return false
+	}
+	pullState, err := ghc.State.Get(pullKey(prSpec))
+	if err != nil {
+		logger.Errorf("  Error reading state of PR %d: %v", prSpec.Pull.GetNumber(), err)
+		return false
+	}
+
+	last := pullState.LastReminder
+	if last.IsZero() {
+		last = pullState.Commented
+	}
+	if last.IsZero() {
+		logger.Info("  No record of the comment on this PR; starting reminders from now")
+		recordComment(ghc, prSpec)
+		return false
+	}
+	if reminders.Max > 0 && pullState.Reminders >= reminders.Max {
+		logger.Infof("  No action needed: already sent %d reminder(s)", pullState.Reminders)
+		return false
+	}
+	interval := time.Duration(reminders.IntervalDays) * day
+	if time.Since(last) < interval {
+		logger.Infof("  No action needed: next reminder due at %s", last.Add(interval).Format(time.RFC3339))
+		return false
+	}
+	return true
+}
+
+// recordReminder records that a reminder was just posted on the PR.
+func recordReminder(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	if !prSpec.UpdateRepo {
+		return
+	}
+	updatePullState(ghc, prSpec, func(pullState *state.PullState) {
+		pullState.Reminders++
+		pullState.LastReminder = time.Now().UTC()
+	})
+}
+
+// forgetReminders clears the reminder state of a PR which is no longer
+// non-compliant, so that reminders start over if it becomes non-compliant
+// again; the comments counted against its head commit are kept, so that
+// compliance flapping back and forth doesn't escape `commentCapped`.
+func forgetReminders(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	if !prSpec.UpdateRepo {
+		return
+	}
+	updatePullState(ghc, prSpec, func(pullState *state.PullState) {
+		pullState.Commented = time.Time{}
+		pullState.Reminders = 0
+		pullState.LastReminder = time.Time{}
+	})
+}
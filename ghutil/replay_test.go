@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/replay"
+)
+
+// Recordings made via `crbot -record` can be replayed against the real
+// client, as shown here with an inline recording.
+func TestReplay_GetRepoConfig(t *testing.T) {
+	cassette := &replay.Cassette{
+		Interactions: []replay.Interaction{
+			{
+				Request: replay.Request{
+					Method: "GET",
+					URL:    "https://api.github.com/repos/org/repo/contents/.github/crbot.yaml",
+				},
+				Response: replay.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       `{"type": "file", "encoding": "base64", "content": "c2tpcDogdHJ1ZQo="}`,
+				},
+			},
+		},
+	}
+	client := ghutil.NewClient(&http.Client{Transport: replay.NewReplayer(cassette)}, "")
+
+	repoConfig, err := client.GetRepoConfig(orgName, repoName)
+	assert.Nil(t, err)
+	assert.True(t, repoConfig.Skip)
+}
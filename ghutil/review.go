@@ -0,0 +1,177 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/report"
+)
+
+// ReviewMarker is a hidden marker included in the body of the reviews which
+// request changes on non-compliant PRs, identifying them as ours so they can
+// be dismissed later without having to know the bot's own login.
+const ReviewMarker = "<!-- crbot:cla-review -->"
+
+// Review states and events, as defined by the GitHub API.
+const (
+	reviewStateChangesRequested = "CHANGES_REQUESTED"
+	reviewEventRequestChanges   = "REQUEST_CHANGES"
+)
+
+// reviewDismissalMessage is the message recorded when dismissing a review
+// once the PR has become compliant.
+const reviewDismissalMessage = "CLA requirements are now satisfied."
+
+// listReviews returns all reviews on the PR, across all pages, as long-lived
+// PRs may have more than fit in one.
+func listReviews(ctx context.Context, ghc *GitHubClient, orgName string, repoName string, pullNumber int) ([]*github.PullRequestReview, error) {
+	var reviews []*github.PullRequestReview
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := ghc.PullRequests.ListReviews(ctx, orgName, repoName, pullNumber, opt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, page...)
+		if resp == nil || resp.NextPage == 0 {
+			return reviews, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// syncReview submits a review requesting changes on a non-compliant PR, unless
+// one is already active, or dismisses our active reviews on a compliant (or
+// externally-managed) PR.
+func syncReview(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus, renderComment func() string) {
+	orgName := prSpec.Org
+	repoName := prSpec.Repo
+	pullNumber := prSpec.Pull.GetNumber()
+
+	reviews, err := listReviews(ctx, ghc, orgName, repoName, pullNumber)
+	if err != nil {
+		logger.Errorf("  Error listing reviews on repo '%s/%s' PR %d: %v", orgName, repoName, pullNumber, err)
+		return
+	}
+	var activeReviews []*github.PullRequestReview
+	for _, review := range reviews {
+		if review.GetState() == reviewStateChangesRequested && strings.Contains(review.GetBody(), ReviewMarker) {
+			activeReviews = append(activeReviews, review)
+		}
+	}
+
+	if !pullRequestStatus.Compliant && !pullRequestStatus.External {
+		if len(activeReviews) > 0 {
+			logger.Info("  No action needed: changes already requested")
+			return
+		}
+		body := renderComment()
+		logger.Infof("  Requesting changes on repo '%s/%s' PR %d: %s", orgName, repoName, pullNumber, body)
+		ghc.Diff.Write(orgName, repoName, pullNumber, ChangeRequestChanges, body)
+		if !prSpec.UpdateRepo {
+			logger.Info("  ... but -update-repo flag is disabled; skipping")
+			return
+		}
+		if !claimAction(ghc, prSpec, "review") {
+			logger.Info("  ... but another instance already requested them; skipping")
+			return
+		}
+		body = body + "\n\n" + ReviewMarker
+		event := reviewEventRequestChanges
+		review := github.PullRequestReviewRequest{
+			Body:  &body,
+			Event: &event,
+		}
+		if _, _, err := ghc.PullRequests.CreateReview(ctx, orgName, repoName, pullNumber, &review); err != nil {
+			logger.Errorf("  Error requesting changes on PR %d: %v", pullNumber, err)
+			return
+		}
+		// The review carries the comment in place of an issue comment.
+		ghc.Counts.commentPosted()
+		return
+	}
+
+	for _, review := range activeReviews {
+		logger.Infof("  Dismissing review %d on repo '%s/%s' PR %d", review.GetID(), orgName, repoName, pullNumber)
+		ghc.Diff.Write(orgName, repoName, pullNumber, ChangeDismissReview, reviewDismissalMessage)
+		if !prSpec.UpdateRepo {
+			logger.Info("  ... but -update-repo flag is disabled; skipping")
+			continue
+		}
+		message := reviewDismissalMessage
+		dismissal := github.PullRequestReviewDismissalRequest{
+			Message: &message,
+		}
+		if _, _, err := ghc.PullRequests.DismissReview(ctx, orgName, repoName, pullNumber, review.GetID(), &dismissal); err != nil {
+			logger.Errorf("  Error dismissing review %d on PR %d: %v", review.GetID(), pullNumber, err)
+		}
+	}
+}
+
+// becameCompliant returns whether the compliant PR just became compliant, so
+// that reviews of it should be requested. With a state store, it did unless it
+// already had the [cla: yes] label or was compliant when last processed; even
+// a PR which isn't labeled, e.g., with `SkipLabels`, is then covered. Without
+// one, it did only if the [cla: yes] label was just added, as otherwise the
+// reviews of an unlabeled PR would be requested on every run.
+func becameCompliant(ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, hadCompliantLabel bool, addedCompliantLabel bool) bool {
+	if ghc.State == nil {
+		return addedCompliantLabel
+	}
+	if hadCompliantLabel {
+		return false
+	}
+	pullState, err := ghc.State.Get(pullKey(prSpec))
+	if err != nil {
+		logger.Errorf("  Error reading state of PR %d: %v", prSpec.Pull.GetNumber(), err)
+		return addedCompliantLabel
+	}
+	return pullState.Status != report.StatusCompliant
+}
+
+// requestReviewers requests reviews of a PR which just became compliant from
+// the configured reviewers, except for its author, who cannot review it.
+func requestReviewers(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	orgName := prSpec.Org
+	repoName := prSpec.Repo
+	pullNumber := prSpec.Pull.GetNumber()
+	author := prSpec.Pull.GetUser().GetLogin()
+
+	var request github.ReviewersRequest
+	for _, user := range prSpec.Reviewers.Users {
+		if !strings.EqualFold(user, author) {
+			request.Reviewers = append(request.Reviewers, user)
+		}
+	}
+	request.TeamReviewers = prSpec.Reviewers.Teams
+	if len(request.Reviewers) == 0 && len(request.TeamReviewers) == 0 {
+		return
+	}
+
+	reviewers := append(append([]string{}, request.Reviewers...), request.TeamReviewers...)
+	logger.Infof("  Requesting reviews from %s on repo '%s/%s' PR %d...", strings.Join(reviewers, ", "), orgName, repoName, pullNumber)
+	ghc.Diff.Write(orgName, repoName, pullNumber, ChangeRequestReviewers, strings.Join(reviewers, ", "))
+	if !prSpec.UpdateRepo {
+		logger.Info("  ... but -update-repo flag is disabled; skipping")
+		return
+	}
+	if _, _, err := ghc.PullRequests.RequestReviewers(ctx, orgName, repoName, pullNumber, request); err != nil {
+		logger.Errorf("  Error requesting reviews on PR %d: %v", pullNumber, err)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/report"
+)
+
+// DefaultStatusContext is the context of the commit status set on pull
+// requests unless overridden by `config.CommitStatus`.
+const DefaultStatusContext = "cla/crbot"
+
+// Commit states, as defined by the GitHub API.
+const (
+	statusStatePending = "pending"
+	statusStateSuccess = "success"
+	statusStateFailure = "failure"
+	statusStateError   = "error"
+)
+
+// Descriptions of the commit statuses which don't come with a reason.
+const (
+	statusDescriptionPending   = "Checking CLA compliance..."
+	statusDescriptionCompliant = "All commits are covered by a CLA"
+	statusDescriptionExternal  = "CLA compliance is managed externally"
+	statusDescriptionError     = "Error checking CLA compliance"
+)
+
+// maxStatusDescriptionLength is the length beyond which the descriptions of
+// commit statuses are truncated, as GitHub rejects longer ones.
+const maxStatusDescriptionLength = 140
+
+// Conclusions of check runs, as defined by the GitHub API, by commit state.
+var checkRunConclusions = map[string]string{
+	statusStateSuccess: "success",
+	statusStateFailure: "failure",
+	statusStateError:   "neutral",
+}
+
+// maxCheckRunAnnotations is the number of annotations GitHub accepts when
+// creating a check run; those of further commits are left out.
+const maxCheckRunAnnotations = 50
+
+// setPendingStatus marks the head commit of the PR as being checked.
+func setPendingStatus(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	setCommitStatus(ctx, ghc, prSpec, statusStatePending, statusDescriptionPending, "", nil)
+}
+
+// setErrorStatus marks the head commit of the PR as not checked due to the
+// error, so that it doesn't stay pending.
+func setErrorStatus(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec) {
+	setCommitStatus(ctx, ghc, prSpec, statusStateError, statusDescriptionError, "", nil)
+}
+
+// setFinalStatus sets the status of the head commit of the PR to its
+// compliance, linking non-compliant PRs to the CLA.
+func setFinalStatus(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, pullRequestStatus PullRequestStatus) {
+	switch {
+	case pullRequestStatus.Compliant:
+		setCommitStatus(ctx, ghc, prSpec, statusStateSuccess, statusDescriptionCompliant, "", nil)
+	case pullRequestStatus.External:
+		setCommitStatus(ctx, ghc, prSpec, statusStateSuccess, statusDescriptionExternal, "", nil)
+	default:
+		setCommitStatus(ctx, ghc, prSpec, statusStateFailure, pullRequestStatus.NonComplianceReason, prSpec.ClaURL, pullRequestStatus.Commits)
+	}
+}
+
+// setCommitStatus sets the commit status of the head commit of the PR, if
+// enabled, or creates a check run annotating the non-compliant commits, if
+// configured; statuses are only written to the diff once final.
+func setCommitStatus(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, state string, description string, targetURL string, commits []CommitStatus) {
+	if !prSpec.CommitStatus.Enabled {
+		return
+	}
+	// A check run is only created once its conclusion is known.
+	if prSpec.CommitStatus.CheckRun && state == statusStatePending {
+		return
+	}
+	orgName := prSpec.Org
+	repoName := prSpec.Repo
+	pullNumber := prSpec.Pull.GetNumber()
+	sha := prSpec.Pull.GetHead().GetSHA()
+	if sha == "" {
+		logger.Errorf("  Error setting commit status of repo '%s/%s' PR %d: its head commit is unknown", orgName, repoName, pullNumber)
+		return
+	}
+
+	description = truncateStatusDescription(description)
+	logger.Infof("  Setting commit status of repo '%s/%s' PR %d to %s: %s", orgName, repoName, pullNumber, state, description)
+	if state != statusStatePending {
+		ghc.Diff.Write(orgName, repoName, pullNumber, ChangeSetStatus, fmt.Sprintf("%s: %s", state, description))
+	}
+	if !prSpec.UpdateRepo {
+		logger.Info("  ... but -update-repo flag is disabled; skipping")
+		return
+	}
+	statusContext := prSpec.CommitStatus.Context
+	if statusContext == "" {
+		statusContext = DefaultStatusContext
+	}
+	if prSpec.CommitStatus.CheckRun {
+		createCheckRun(ctx, ghc, prSpec, statusContext, checkRunConclusions[state], description, targetURL, commits)
+		return
+	}
+	status := &github.RepoStatus{
+		State:       &state,
+		Description: &description,
+		Context:     &statusContext,
+	}
+	if targetURL != "" {
+		status.TargetURL = &targetURL
+	}
+	if _, _, err := ghc.Repositories.CreateStatus(ctx, orgName, repoName, sha, status); err != nil {
+		logger.Errorf("  Error setting commit status of repo '%s/%s' PR %d: %v", orgName, repoName, pullNumber, err)
+	}
+}
+
+// createCheckRun creates a completed check run on the head commit of the PR,
+// with an annotation per non-compliant commit.
+func createCheckRun(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, name string, conclusion string, title string, detailsURL string, commits []CommitStatus) {
+	orgName := prSpec.Org
+	repoName := prSpec.Repo
+	pullNumber := prSpec.Pull.GetNumber()
+
+	var annotations []*github.CheckRunAnnotation
+	for _, commit := range commits {
+		if commit.Compliant || commit.External {
+			continue
+		}
+		if len(annotations) == maxCheckRunAnnotations {
+			logger.Infof("  Only annotating the first %d non-compliant commits of repo '%s/%s' PR %d", maxCheckRunAnnotations, orgName, repoName, pullNumber)
+			break
+		}
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(report.CommitResultPath),
+			StartLine:       github.Int(1),
+			EndLine:         github.Int(1),
+			AnnotationLevel: github.String("failure"),
+			Title:           github.String(fmt.Sprintf("Commit %s", commit.SHA)),
+			Message:         github.String(checkRunAnnotationMessage(commit)),
+		})
+	}
+
+	opt := github.CreateCheckRunOptions{
+		Name:        name,
+		HeadBranch:  prSpec.Pull.GetHead().GetRef(),
+		HeadSHA:     prSpec.Pull.GetHead().GetSHA(),
+		Status:      github.String("completed"),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:       github.String(title),
+			Summary:     github.String(title),
+			Annotations: annotations,
+		},
+	}
+	if detailsURL != "" {
+		opt.DetailsURL = github.String(detailsURL)
+	}
+	if _, _, err := ghc.Checks.CreateCheckRun(ctx, orgName, repoName, opt); err != nil {
+		logger.Errorf("  Error creating check run on repo '%s/%s' PR %d: %v", orgName, repoName, pullNumber, err)
+	}
+}
+
+// checkRunAnnotationMessage describes why the commit is not compliant, naming
+// each identity which failed to match, e.g., "author Jane Doe
+// <jane@example.com>, GitHub: janedoe".
+func checkRunAnnotationMessage(commit CommitStatus) string {
+	message := commit.NonComplianceReason
+	if len(commit.Unmatched) > 0 {
+		var identities []string
+		for _, unmatched := range commit.Unmatched {
+			identity := report.Identity{
+				Role:  unmatched.Role,
+				Name:  unmatched.Account.Name,
+				Email: unmatched.Account.Email,
+				Login: unmatched.Account.Login,
+			}
+			identities = append(identities, identity.String())
+		}
+		message += fmt.Sprintf(" (unmatched: %s)", strings.Join(identities, "; "))
+	}
+	return message
+}
+
+// truncateStatusDescription truncates the description of a commit status to
+// the maximum length accepted by GitHub, without splitting characters.
+func truncateStatusDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxStatusDescriptionLength {
+		return description
+	}
+	return string(runes[:maxStatusDescriptionLength-3]) + "..."
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/report"
+)
+
+// TrackingIssueMarker is a hidden marker included in the body of tracking
+// issues, identifying them as ours so they can be found again; since anyone
+// can include it in the issues they open, only those also opened by the bot's
+// account are.
+const TrackingIssueMarker = "<!-- crbot:tracking-issue -->"
+
+// DefaultTrackingIssueTitle is the title of tracking issues unless overridden
+// by `config.TrackingIssue`.
+const DefaultTrackingIssueTitle = "Non-compliant pull requests (CLA)"
+
+// trackingIssueIntro introduces the checklist of a tracking issue.
+const trackingIssueIntro = "The following pull requests are not CLA-compliant. This issue is updated automatically; please don't edit it.\n\n"
+
+// reportedPulls returns the pull requests of the run's report in the repo, or
+// in all repos of the org if `repoName` is empty.
+func reportedPulls(ghc *GitHubClient, orgName string, repoName string) []report.PullRequest {
+	var prs []report.PullRequest
+	for _, pr := range ghc.Report.PullRequests {
+		if pr.Org == orgName && (repoName == "" || pr.Repo == repoName) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// updateTrackingIssue updates the tracking issue in the repo with a checklist
+// of the non-compliant PRs among those given, creating it if needed, unless
+// there are none.
+func updateTrackingIssue(ctx context.Context, ghc *GitHubClient, orgName string, repoName string, tracking config.TrackingIssue, prs []report.PullRequest, updateRepo bool) {
+	var buf bytes.Buffer
+	buf.WriteString(trackingIssueIntro)
+	if err := report.WriteChecklist(&buf, prs); err != nil {
+		logger.Errorf("Error rendering tracking issue of repo '%s/%s': %v", orgName, repoName, err)
+		return
+	}
+	buf.WriteString("\n" + TrackingIssueMarker)
+	body := buf.String()
+
+	issue, err := findTrackingIssue(ctx, ghc, orgName, repoName)
+	if err != nil {
+		logError(ghc, "Error finding tracking issue of repo '%s/%s': %v", orgName, repoName, err)
+		return
+	}
+	if issue == nil {
+		hasNonCompliant := false
+		for _, pr := range prs {
+			hasNonCompliant = hasNonCompliant || pr.Status() == report.StatusNonCompliant
+		}
+		if !hasNonCompliant {
+			logger.Infof("No action needed: no tracking issue in repo '%s/%s', and no non-compliant PRs", orgName, repoName)
+			return
+		}
+		title := tracking.Title
+		if title == "" {
+			title = DefaultTrackingIssueTitle
+		}
+		logger.Infof("Creating tracking issue in repo '%s/%s'...", orgName, repoName)
+		ghc.Diff.Write(orgName, repoName, 0, ChangeCreateIssue, title)
+		if tracking.Pin {
+			ghc.Diff.Write(orgName, repoName, 0, ChangePinIssue, title)
+		}
+		if !updateRepo {
+			logger.Info("  ... but -update-repo flag is disabled; skipping")
+			return
+		}
+		created, _, err := ghc.Issues.Create(ctx, orgName, repoName, &github.IssueRequest{Title: &title, Body: &body})
+		if err != nil {
+			logError(ghc, "Error creating tracking issue in repo '%s/%s': %v", orgName, repoName, err)
+			return
+		}
+		if tracking.Pin {
+			pinTrackingIssue(ctx, ghc, orgName, repoName, created)
+		}
+		return
+	}
+
+	if issue.GetBody() == body {
+		logger.Infof("No action needed: tracking issue %d of repo '%s/%s' is up to date", issue.GetNumber(), orgName, repoName)
+		return
+	}
+	logger.Infof("Updating tracking issue %d of repo '%s/%s'...", issue.GetNumber(), orgName, repoName)
+	ghc.Diff.Write(orgName, repoName, issue.GetNumber(), ChangeEditIssue, issue.GetTitle())
+	if !updateRepo {
+		logger.Info("  ... but -update-repo flag is disabled; skipping")
+		return
+	}
+	if _, _, err := ghc.Issues.Edit(ctx, orgName, repoName, issue.GetNumber(), &github.IssueRequest{Body: &body}); err != nil {
+		logError(ghc, "Error updating tracking issue %d of repo '%s/%s': %v", issue.GetNumber(), orgName, repoName, err)
+	}
+}
+
+// findTrackingIssue returns the open tracking issue of the repo, if any, i.e.,
+// an issue with the marker opened by the authenticated user.
+func findTrackingIssue(ctx context.Context, ghc *GitHubClient, orgName string, repoName string) (*github.Issue, error) {
+	user, _, err := ghc.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("error identifying the authenticated user: %v", err)
+	} else if user.GetLogin() == "" {
+		return nil, fmt.Errorf("error identifying the authenticated user: no login")
+	}
+	opt := &github.IssueListByRepoOptions{
+		State:       "open",
+		Creator:     user.GetLogin(),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := ghc.Issues.ListByRepo(ctx, orgName, repoName, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() && strings.Contains(issue.GetBody(), TrackingIssueMarker) {
+				return issue, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// pinTrackingIssue pins the newly-created tracking issue to its repo.
+func pinTrackingIssue(ctx context.Context, ghc *GitHubClient, orgName string, repoName string, issue *github.Issue) {
+	if ghc.GraphQL == nil {
+		logger.Errorf("Error pinning tracking issue %d of repo '%s/%s': the GraphQL API is not available", issue.GetNumber(), orgName, repoName)
+		return
+	}
+	logger.Infof("Pinning tracking issue %d of repo '%s/%s'...", issue.GetNumber(), orgName, repoName)
+	if err := ghc.GraphQL.PinIssue(ctx, issue.GetNodeID()); err != nil {
+		logError(ghc, "Error pinning tracking issue %d of repo '%s/%s': %v", issue.GetNumber(), orgName, repoName, err)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/report"
+)
+
+// TransportOptions configures the network connection to GitHub.
+type TransportOptions struct {
+	// ProxyURL is the URL of the HTTP(S) proxy to connect through (e.g.,
+	// "http://proxy.example.com:3128"); if empty, the proxy is taken from
+	// the standard `HTTPS_PROXY`, `HTTP_PROXY`, and `NO_PROXY` environment
+	// variables (or their lowercase versions).
+	ProxyURL string
+
+	// CABundle is the path to a PEM file with additional CA certificates to
+	// trust, on top of the system's, e.g., for a GitHub Enterprise Server
+	// instance with an internally-issued certificate.
+	CABundle string
+}
+
+// NewTransport returns an HTTP transport for connecting to GitHub with the
+// given options.
+func NewTransport(options TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.ProxyURL == "" {
+		transport.Proxy = http.ProxyFromEnvironment
+	} else {
+		parsedURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL '%s': %s", options.ProxyURL, err)
+		}
+		if parsedURL.Scheme == "" || parsedURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL '%s': scheme and host are required", options.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(parsedURL)
+	}
+
+	if options.CABundle != "" {
+		rootCAs, err := loadCABundle(options.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	return transport, nil
+}
+
+// loadCABundle returns the system's certificate pool, extended with the CA
+// certificates from the given PEM file.
+func loadCABundle(filename string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA bundle '%s': %s", filename, err)
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		// The system pool is unavailable on some platforms (e.g., Windows
+		// before Go 1.18), so only the bundle's certificates are trusted.
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid PEM certificates found in CA bundle '%s'", filename)
+	}
+	return rootCAs, nil
+}
+
+// headerTransport adds a fixed set of headers to every request.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// NewHeaderTransport returns a transport which adds the given headers to each
+// request before sending it via `base`, e.g., so that enterprise proxies can
+// attribute traffic to a particular deployment.
+func NewHeaderTransport(base http.RoundTripper, headers map[string]string) http.RoundTripper {
+	if len(headers) == 0 {
+		return base
+	}
+	return &headerTransport{base: base, headers: headers}
+}
+
+// RoundTrip implements `http.RoundTripper`; as required by its contract, the
+// original request is left unmodified.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// UsageTransport counts the requests sent via its base transport, and tracks
+// the GitHub rate limit reported in the headers of the responses.
+type UsageTransport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	usage report.APIUsage
+}
+
+// NewUsageTransport returns a transport tracking the API usage of requests
+// sent via `base`.
+func NewUsageTransport(base http.RoundTripper) *UsageTransport {
+	return &UsageTransport{base: base}
+}
+
+// RoundTrip implements `http.RoundTripper`.
+func (t *UsageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.Calls++
+	if resp != nil {
+		if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+			t.usage.Limit = limit
+		}
+		if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+			t.usage.Remaining = remaining
+		}
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			t.usage.Reset = time.Unix(reset, 0).UTC()
+		}
+	}
+	return resp, err
+}
+
+// Usage returns the API usage so far.
+func (t *UsageTransport) Usage() report.APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
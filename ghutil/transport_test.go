@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestNewTransport_ExplicitProxy(t *testing.T) {
+	transport, err := ghutil.NewTransport(ghutil.TransportOptions{ProxyURL: "http://proxy.example.com:3128"})
+	assert.Nil(t, err)
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+	proxyURL, err := transport.Proxy(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+}
+
+func TestNewTransport_Environment(t *testing.T) {
+	transport, err := ghutil.NewTransport(ghutil.TransportOptions{})
+	assert.Nil(t, err)
+	assert.NotNil(t, transport.Proxy)
+}
+
+func TestNewTransport_InvalidProxyURL(t *testing.T) {
+	_, err := ghutil.NewTransport(ghutil.TransportOptions{ProxyURL: "proxy.example.com:3128"})
+	assert.NotNil(t, err)
+
+	_, err = ghutil.NewTransport(ghutil.TransportOptions{ProxyURL: "http://%zz"})
+	assert.NotNil(t, err)
+}
+
+// writeTestCABundle writes a self-signed CA certificate to a temporary file.
+func writeTestCABundle(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "crbot test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := ioutil.TempFile("", "ca-bundle-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestNewTransport_CABundle(t *testing.T) {
+	bundle := writeTestCABundle(t)
+	defer os.Remove(bundle)
+
+	transport, err := ghutil.NewTransport(ghutil.TransportOptions{CABundle: bundle})
+	assert.Nil(t, err)
+	assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+}
+
+func TestNewTransport_InvalidCABundle(t *testing.T) {
+	_, err := ghutil.NewTransport(ghutil.TransportOptions{CABundle: "/no/such/file.pem"})
+	assert.NotNil(t, err)
+
+	file, err := ioutil.TempFile("", "ca-bundle-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("not a certificate")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	_, err = ghutil.NewTransport(ghutil.TransportOptions{CABundle: file.Name()})
+	assert.NotNil(t, err)
+}
+
+// roundTripFunc adapts a function to `http.RoundTripper`.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewHeaderTransport(t *testing.T) {
+	var sent *http.Request
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	transport := ghutil.NewHeaderTransport(base, map[string]string{"X-Deployment": "ci-east"})
+	req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+	_, err := transport.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "ci-east", sent.Header.Get("X-Deployment"))
+	assert.Equal(t, "", req.Header.Get("X-Deployment"), "original request should be unmodified")
+}
+
+func TestNewHeaderTransport_NoHeaders(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	transport := ghutil.NewHeaderTransport(base, nil)
+	_, ok := transport.(roundTripFunc)
+	assert.True(t, ok)
+}
+
+func TestUsageTransport(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("X-RateLimit-Limit", "5000")
+		header.Set("X-RateLimit-Remaining", "4998")
+		header.Set("X-RateLimit-Reset", "1767323045")
+		return &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+	})
+
+	transport := ghutil.NewUsageTransport(base)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "https://api.github.com/", nil)
+		_, err := transport.RoundTrip(req)
+		assert.Nil(t, err)
+	}
+
+	usage := transport.Usage()
+	assert.Equal(t, 2, usage.Calls)
+	assert.Equal(t, 5000, usage.Limit)
+	assert.Equal(t, 4998, usage.Remaining)
+	assert.Equal(t, time.Unix(1767323045, 0).UTC(), usage.Reset)
+}
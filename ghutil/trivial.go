@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// DefaultTrivialPaths are the documentation paths used by the trivial-change
+// policy if none are configured.
+var DefaultTrivialPaths = []string{"*.md", "*.rst", "*.txt", "doc/", "docs/"}
+
+// MatchTrivialPath returns whether the file path matches any of the patterns,
+// as documented on `config.TrivialPolicy`.
+func MatchTrivialPath(filename string, patterns []string) bool {
+	if len(patterns) == 0 {
+		patterns = DefaultTrivialPaths
+	}
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(filename, pattern) {
+				return true
+			}
+			continue
+		}
+		name := filename
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(filename)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrivialCommit returns whether the commit, as retrieved with its files,
+// qualifies for the trivial-change policy; if so, it also returns a summary
+// of the change to record as the reason for the exemption.
+func IsTrivialCommit(commit *github.RepositoryCommit, policy config.TrivialPolicy) (string, bool) {
+	if policy.MaxLines <= 0 || len(commit.Files) == 0 {
+		return "", false
+	}
+	lines := 0
+	for _, file := range commit.Files {
+		if !MatchTrivialPath(file.GetFilename(), policy.Paths) {
+			return "", false
+		}
+		if file.PreviousFilename != nil && !MatchTrivialPath(file.GetPreviousFilename(), policy.Paths) {
+			return "", false
+		}
+		lines += file.GetAdditions() + file.GetDeletions()
+	}
+	if lines >= policy.MaxLines {
+		return "", false
+	}
+	return fmt.Sprintf("trivial documentation change (%d line(s) in %d file(s))", lines, len(commit.Files)), true
+}
+
+// checkTrivialCommit exempts the otherwise non-compliant commit if it
+// qualifies for the trivial-change policy, fetching its list of files, which
+// is not included when listing the commits of a PR.
+func checkTrivialCommit(ctx context.Context, ghc *GitHubClient, prSpec GitHubProcessSinglePullSpec, commitStatus CommitStatus) CommitStatus {
+	commit, _, err := ghc.Repositories.GetCommit(ctx, prSpec.Org, prSpec.Repo, commitStatus.SHA)
+	if err != nil {
+		logger.Errorf("  Error retrieving files of commit %s in repo '%s/%s': %v", commitStatus.SHA, prSpec.Org, prSpec.Repo, err)
+		return commitStatus
+	}
+	reason, trivial := IsTrivialCommit(commit, prSpec.Trivial)
+	if !trivial {
+		return commitStatus
+	}
+	logger.Debugf("    exempted: %s", reason)
+	return CommitStatus{
+		SHA:       commitStatus.SHA,
+		Compliant: true,
+		Company:   commitStatus.Company,
+		Agreement: commitStatus.Agreement,
+		Exemption: reason,
+	}
+}
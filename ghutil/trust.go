@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil
+
+import (
+	"github.com/google/go-github/v21/github"
+)
+
+// Trust levels select how much evidence a commit needs for its identities to
+// be trusted: with `TrustMatched`, the default, a commit is compliant if its
+// identities match CLA signers; with `TrustVerified`, its author email must
+// also be verified as belonging to the author's account, i.e., GitHub must
+// have verified its signature, and the author must be the committer, whose
+// email the signature vouches for (see `CheckVerifiedEmail`).
+const (
+	TrustMatched  = "matched"
+	TrustVerified = "verified"
+)
+
+// TrustLevels lists the supported trust levels.
+var TrustLevels = []string{TrustMatched, TrustVerified}
+
+// IsSupportedTrustLevel returns whether `level` is empty (i.e., the default)
+// or one of the supported `TrustLevels`.
+func IsSupportedTrustLevel(level string) bool {
+	if level == "" {
+		return true
+	}
+	for _, l := range TrustLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTrustLevel checks a commit which is otherwise compliant against the
+// trust level; exempt commits need no further evidence.
+func applyTrustLevel(commit *github.RepositoryCommit, commitStatus CommitStatus, level string) CommitStatus {
+	if level != TrustVerified || !commitStatus.Compliant || commitStatus.Exemption != "" {
+		return commitStatus
+	}
+	verifiedStatus := CheckVerifiedEmail(commit)
+	if verifiedStatus.Compliant {
+		return commitStatus
+	}
+	logger.Debugf("    author email of commit %s not verified; signature: %s", commit.GetSHA(), commit.GetCommit().GetVerification().GetReason())
+	commitStatus.Compliant = false
+	commitStatus.NonComplianceReason = verifiedStatus.NonComplianceReason
+	commitStatus.Unmatched = verifiedStatus.Unmatched
+	return commitStatus
+}
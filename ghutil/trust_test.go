@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutil_test
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v21/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestIsSupportedTrustLevel(t *testing.T) {
+	assert.True(t, ghutil.IsSupportedTrustLevel(""))
+	assert.True(t, ghutil.IsSupportedTrustLevel(ghutil.TrustMatched))
+	assert.True(t, ghutil.IsSupportedTrustLevel(ghutil.TrustVerified))
+	assert.False(t, ghutil.IsSupportedTrustLevel("signed"))
+}
+
+func TestCheckPullRequestCompliance_TrustVerified(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, _ := createUserAccounts()
+	signed := createCommit(john, john)
+	signed.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+	unsigned := createCommit(john, john)
+	unsigned.SHA = github.String("def456abc123")
+	commits := []*github.RepositoryCommit{signed, unsigned}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return(commits, nil, nil).Times(2)
+
+	claSigners := config.ClaSigners{
+		People: []config.Account{john},
+	}
+	prSpec := getSinglePullSpec()
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.True(t, pullRequestStatus.Compliant)
+
+	// With the stricter trust level, the unsigned commit is not compliant,
+	// even though John is a CLA signer.
+	prSpec.TrustLevel = ghutil.TrustVerified
+	pullRequestStatus, err = ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, []ghutil.CommitReason{
+		{SHA: "def456abc123", Reason: ghutil.ReasonAuthorEmailUnverified},
+	}, pullRequestStatus.Reasons)
+}
+
+func TestCheckPullRequestCompliance_TrustVerified_AuthorNotCommitter(t *testing.T) {
+	setUp(t)
+	defer tearDown(t)
+
+	john, jane := createUserAccounts()
+	// Jane signed the commit, but claims John as its author.
+	spoofed := createCommit(john, jane)
+	spoofed.Commit.Verification = &github.SignatureVerification{Verified: github.Bool(true)}
+	mockGhc.PullRequests.EXPECT().ListCommits(any, orgName, repoName, pullNumber, nil).Return([]*github.RepositoryCommit{spoofed}, nil, nil)
+
+	claSigners := config.ClaSigners{
+		People: []config.Account{john, jane},
+	}
+	prSpec := getSinglePullSpec()
+	prSpec.TrustLevel = ghutil.TrustVerified
+	pullRequestStatus, err := ghc.CheckPullRequestCompliance(prSpec, claSigners)
+	assert.Nil(t, err)
+	assert.False(t, pullRequestStatus.Compliant)
+	assert.Equal(t, []ghutil.CommitReason{
+		{SHA: "abc123def456", Reason: ghutil.ReasonAuthorEmailUnverified},
+	}, pullRequestStatus.Reasons)
+}
@@ -0,0 +1,906 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutiltest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+// FakeGitHub is an in-memory implementation of the GitHub services used by
+// `ghutil`, holding repos with their labels, files, commits, commit statuses,
+// check runs, issues and pull requests, and the labels, comments (some of which may have been
+// minimized), reviews and review requests of each pull request.
+// Tests set up the state via its `Add*` and `Set*` methods, run the code under
+// test against a client bound to it, and then inspect the resulting state,
+// rather than spelling out each API call as with the mocks.
+//
+// Missing repos, pull requests, labels, files and commits are reported as
+// "404 Not Found" errors, as by the GitHub API. Options for pagination are
+// ignored: all results are returned in a single page.
+type FakeGitHub struct {
+	mu     sync.Mutex
+	repos  map[string]*fakeRepo
+	nextID int64
+}
+
+type fakeRepo struct {
+	repo     *github.Repository
+	labels   map[string]*github.Label
+	files    map[string]string
+	commits  map[string]*github.RepositoryCommit
+	statuses map[string][]*github.RepoStatus
+	checks   map[string][]*github.CheckRun
+	issues   map[int]*github.Issue
+	pinned   map[int]bool
+	pulls    map[int]*fakePull
+	orgIndex int
+}
+
+type fakePull struct {
+	pull      *github.PullRequest
+	commits   []*github.RepositoryCommit
+	labels    []string
+	comments  []*github.IssueComment
+	minimized map[int64]bool
+	reviews   []*github.PullRequestReview
+	reviewers []string
+}
+
+// FakeLogin is the login of the account which clients bound to the fake act
+// as; the comments and issues they create are attributed to it.
+const FakeLogin = "crbot"
+
+// NewFakeGitHub returns a fake without any repos.
+func NewFakeGitHub() *FakeGitHub {
+	return &FakeGitHub{
+		repos: make(map[string]*fakeRepo),
+	}
+}
+
+// Client returns a new client bound to the fake.
+func (f *FakeGitHub) Client() *ghutil.GitHubClient {
+	ghc := ghutil.NewBasicClient()
+	f.Bind(ghc)
+	return ghc
+}
+
+// Bind patches the services of the client with the fake, as
+// `NewMockGitHubClient` does with mocks.
+func (f *FakeGitHub) Bind(ghc *ghutil.GitHubClient) {
+	ghc.Organizations = fakeOrganizations{f}
+	ghc.Repositories = fakeRepositories{f}
+	ghc.Issues = fakeIssues{f}
+	ghc.PullRequests = fakePullRequests{f}
+	ghc.Checks = fakeChecks{f}
+	ghc.Users = fakeUsers{f}
+	ghc.GraphQL = fakeGraphQL{f}
+}
+
+// AddRepo adds a repo to the org, if it doesn't exist yet, and returns it.
+func (f *FakeGitHub) AddRepo(org string, repo string) *github.Repository {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addRepo(org, repo).repo
+}
+
+// AddLabel adds a label to the repo, adding the repo if needed.
+func (f *FakeGitHub) AddLabel(org string, repo string, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addRepo(org, repo).labels[strings.ToLower(name)] = &github.Label{
+		ID:   f.newID(),
+		Name: github.String(name),
+	}
+}
+
+// SetFile sets the contents of a file in the repo, adding the repo if needed.
+// Files have the same contents at all refs.
+func (f *FakeGitHub) SetFile(org string, repo string, path string, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addRepo(org, repo).files[path] = content
+}
+
+// NewCommit returns a commit with the given SHA, authored and committed by the
+// account, e.g., for `AddPullRequest`.
+func NewCommit(sha string, author config.Account) *github.RepositoryCommit {
+	return &github.RepositoryCommit{
+		SHA: github.String(sha),
+		Commit: &github.Commit{
+			Author:    &github.CommitAuthor{Name: github.String(author.Name), Email: github.String(author.Email)},
+			Committer: &github.CommitAuthor{Name: github.String(author.Name), Email: github.String(author.Email)},
+		},
+		Author:    &github.User{Login: github.String(author.Login)},
+		Committer: &github.User{Login: github.String(author.Login)},
+	}
+}
+
+// AddPullRequest adds a pull request with the given commits to the repo,
+// adding the repo if needed. The pull request must have a number and a title;
+// its labels, if any, are those initially applied to it, and are kept in sync
+// as they are replaced. The commits can also be retrieved individually by
+// their SHAs.
+func (f *FakeGitHub) AddPullRequest(org string, repo string, pull *github.PullRequest, commits ...*github.RepositoryCommit) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.addRepo(org, repo)
+	p := &fakePull{pull: pull, commits: commits}
+	for _, label := range pull.Labels {
+		p.labels = append(p.labels, label.GetName())
+	}
+	r.pulls[pull.GetNumber()] = p
+	for _, commit := range commits {
+		r.commits[commit.GetSHA()] = commit
+	}
+}
+
+// AddIssue adds an issue, other than a pull request, to the repo, adding the
+// repo if needed; it is open unless its state is set.
+func (f *FakeGitHub) AddIssue(org string, repo string, issue *github.Issue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if issue.State == nil {
+		issue.State = github.String("open")
+	}
+	f.addRepo(org, repo).issues[issue.GetNumber()] = issue
+}
+
+// Labels returns the names of the labels of the repo, sorted by name.
+func (f *FakeGitHub) Labels(org string, repo string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, err := f.repo(org, repo)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, label := range r.labels {
+		names = append(names, label.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PullLabels returns the names of the labels of the pull request.
+func (f *FakeGitHub) PullLabels(org string, repo string, number int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, err := f.pull(org, repo, number); err == nil {
+		return append([]string(nil), p.labels...)
+	}
+	return nil
+}
+
+// Comments returns the comments on the pull request, in the order in which
+// they were created.
+func (f *FakeGitHub) Comments(org string, repo string, number int) []*github.IssueComment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, err := f.pull(org, repo, number); err == nil {
+		return append([]*github.IssueComment(nil), p.comments...)
+	}
+	return nil
+}
+
+// MinimizedComments returns the comments on the pull request which were
+// minimized, in the order in which they were created.
+func (f *FakeGitHub) MinimizedComments(org string, repo string, number int) []*github.IssueComment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, err := f.pull(org, repo, number)
+	if err != nil {
+		return nil
+	}
+	var minimized []*github.IssueComment
+	for _, comment := range p.comments {
+		if p.minimized[comment.GetID()] {
+			minimized = append(minimized, comment)
+		}
+	}
+	return minimized
+}
+
+// Reviews returns the reviews of the pull request, in the order in which they
+// were created, including dismissed ones.
+func (f *FakeGitHub) Reviews(org string, repo string, number int) []*github.PullRequestReview {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, err := f.pull(org, repo, number); err == nil {
+		return append([]*github.PullRequestReview(nil), p.reviews...)
+	}
+	return nil
+}
+
+// Reviewers returns the logins whose review of the pull request was
+// requested.
+func (f *FakeGitHub) Reviewers(org string, repo string, number int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, err := f.pull(org, repo, number); err == nil {
+		return append([]string(nil), p.reviewers...)
+	}
+	return nil
+}
+
+// Issues returns the issues of the repo, other than pull requests, sorted by
+// number.
+func (f *FakeGitHub) Issues(org string, repo string) []*github.Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, err := f.repo(org, repo)
+	if err != nil {
+		return nil
+	}
+	var issues []*github.Issue
+	for _, issue := range r.issues {
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].GetNumber() < issues[j].GetNumber() })
+	return issues
+}
+
+// IsPinned returns whether the issue of the repo was pinned.
+func (f *FakeGitHub) IsPinned(org string, repo string, number int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, err := f.repo(org, repo)
+	return err == nil && r.pinned[number]
+}
+
+// Statuses returns the commit statuses set on the commit, in the order in
+// which they were set.
+func (f *FakeGitHub) Statuses(org string, repo string, sha string) []*github.RepoStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r, err := f.repo(org, repo); err == nil {
+		return append([]*github.RepoStatus(nil), r.statuses[sha]...)
+	}
+	return nil
+}
+
+// CheckRuns returns the check runs created on the commit, in the order in
+// which they were created.
+func (f *FakeGitHub) CheckRuns(org string, repo string, sha string) []*github.CheckRun {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r, err := f.repo(org, repo); err == nil {
+		return append([]*github.CheckRun(nil), r.checks[sha]...)
+	}
+	return nil
+}
+
+func (f *FakeGitHub) newID() *int64 {
+	f.nextID++
+	id := f.nextID
+	return &id
+}
+
+func (f *FakeGitHub) addRepo(org string, repo string) *fakeRepo {
+	key := org + "/" + repo
+	if r, ok := f.repos[key]; ok {
+		return r
+	}
+	r := &fakeRepo{
+		repo: &github.Repository{
+			ID:       f.newID(),
+			Name:     github.String(repo),
+			FullName: github.String(key),
+			Owner:    &github.User{Login: github.String(org)},
+		},
+		labels:   make(map[string]*github.Label),
+		files:    make(map[string]string),
+		commits:  make(map[string]*github.RepositoryCommit),
+		statuses: make(map[string][]*github.RepoStatus),
+		checks:   make(map[string][]*github.CheckRun),
+		issues:   make(map[int]*github.Issue),
+		pinned:   make(map[int]bool),
+		pulls:    make(map[int]*fakePull),
+		orgIndex: len(f.repos),
+	}
+	f.repos[key] = r
+	return r
+}
+
+func (f *FakeGitHub) repo(org string, repo string) (*fakeRepo, error) {
+	if r, ok := f.repos[org+"/"+repo]; ok {
+		return r, nil
+	}
+	return nil, notFound("repos/%s/%s", org, repo)
+}
+
+func (f *FakeGitHub) pull(org string, repo string, number int) (*fakePull, error) {
+	r, err := f.repo(org, repo)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := r.pulls[number]; ok {
+		return p, nil
+	}
+	return nil, notFound("repos/%s/%s/pulls/%d", org, repo, number)
+}
+
+// label returns the repo's label with the name, if any, or a label with only
+// the name otherwise.
+func (r *fakeRepo) label(name string) *github.Label {
+	if label, ok := r.labels[strings.ToLower(name)]; ok {
+		return label
+	}
+	return &github.Label{Name: github.String(name)}
+}
+
+// okResponse is the response of successful API calls.
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+// page returns the bounds of the page of a list of `n` items requested by
+// `opt`, and the response pointing to the next page, if any, as the GitHub API
+// paginates lists; a zero `PerPage` returns 30 items per page, as the API does.
+func page(n int, opt github.ListOptions) (int, int, *github.Response) {
+	perPage := opt.PerPage
+	if perPage <= 0 {
+		perPage = 30
+	}
+	pageNumber := opt.Page
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+	resp := okResponse()
+	start := (pageNumber - 1) * perPage
+	if start > n {
+		start = n
+	}
+	end := start + perPage
+	if end < n {
+		resp.NextPage = pageNumber + 1
+	} else {
+		end = n
+	}
+	return start, end, resp
+}
+
+// notFound returns an error as returned by the GitHub API for missing
+// resources, so that callers checking the status code behave as in
+// production.
+func notFound(format string, args ...interface{}) error {
+	return errorResponse(http.StatusNotFound, "Not Found", format, args...)
+}
+
+func errorResponse(status int, message string, format string, args ...interface{}) error {
+	req, _ := http.NewRequest("GET", "https://api.github.com/"+fmt.Sprintf(format, args...), nil)
+	return &github.ErrorResponse{
+		Response: &http.Response{StatusCode: status, Request: req},
+		Message:  message,
+	}
+}
+
+type fakeOrganizations struct{ f *FakeGitHub }
+
+type fakeRepositories struct{ f *FakeGitHub }
+
+// CompareCommits compares the base ref with the head SHA of a pull request
+// targeting it, whose commits are all considered to be ahead of the base.
+func (s fakeRepositories) CompareCommits(ctx context.Context, owner string, repo string, base string, head string) (*github.CommitsComparison, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range r.pulls {
+		if p.pull.GetBase().GetRef() == base && p.pull.GetHead().GetSHA() == head {
+			var commits []github.RepositoryCommit
+			for _, commit := range p.commits {
+				commits = append(commits, *commit)
+			}
+			return &github.CommitsComparison{
+				Status:       github.String("ahead"),
+				AheadBy:      github.Int(len(commits)),
+				TotalCommits: github.Int(len(commits)),
+				Commits:      commits,
+			}, okResponse(), nil
+		}
+	}
+	return nil, nil, notFound("repos/%s/%s/compare/%s...%s", owner, repo, base, head)
+}
+
+// CreateStatus sets a commit status on any SHA of the repo, whether or not
+// the fake knows of the commit.
+func (s fakeRepositories) CreateStatus(ctx context.Context, owner string, repo string, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	created := *status
+	created.ID = s.f.newID()
+	r.statuses[ref] = append(r.statuses[ref], &created)
+	return &created, okResponse(), nil
+}
+
+func (s fakeRepositories) Get(ctx context.Context, owner string, repo string) (*github.Repository, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.repo, okResponse(), nil
+}
+
+func (s fakeRepositories) GetCommit(ctx context.Context, owner string, repo string, sha string) (*github.RepositoryCommit, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if commit, ok := r.commits[sha]; ok {
+		return commit, okResponse(), nil
+	}
+	return nil, nil, notFound("repos/%s/%s/commits/%s", owner, repo, sha)
+}
+
+func (s fakeRepositories) GetContents(ctx context.Context, owner string, repo string, path string, opt *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	content, ok := r.files[path]
+	if !ok {
+		return nil, nil, nil, notFound("repos/%s/%s/contents/%s", owner, repo, path)
+	}
+	return &github.RepositoryContent{
+		Type:    github.String("file"),
+		Path:    github.String(path),
+		Size:    github.Int(len(content)),
+		Content: github.String(content),
+	}, nil, okResponse(), nil
+}
+
+// List returns the repos of the org, in the order in which they were added.
+func (s fakeRepositories) List(ctx context.Context, user string, opt *github.RepositoryListOptions) ([]*github.Repository, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	var repos []*fakeRepo
+	for _, r := range s.f.repos {
+		if r.repo.GetOwner().GetLogin() == user {
+			repos = append(repos, r)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].orgIndex < repos[j].orgIndex })
+	var result []*github.Repository
+	for _, r := range repos {
+		result = append(result, r.repo)
+	}
+	var listOpt github.ListOptions
+	if opt != nil {
+		listOpt = opt.ListOptions
+	}
+	start, end, resp := page(len(result), listOpt)
+	return result[start:end], resp, nil
+}
+
+type fakeIssues struct{ f *FakeGitHub }
+
+// Create creates an open issue, numbered after the existing issues and pull
+// requests of the repo.
+func (s fakeIssues) Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	number := 1
+	for n := range r.issues {
+		if n >= number {
+			number = n + 1
+		}
+	}
+	for n := range r.pulls {
+		if n >= number {
+			number = n + 1
+		}
+	}
+	created := &github.Issue{
+		ID:     s.f.newID(),
+		Number: github.Int(number),
+		State:  github.String("open"),
+		Title:  issue.Title,
+		Body:   issue.Body,
+		User:   &github.User{Login: github.String(FakeLogin)},
+	}
+	created.NodeID = github.String(fmt.Sprintf("I_%d", created.GetID()))
+	r.issues[number] = created
+	return created, okResponse(), nil
+}
+
+func (s fakeIssues) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	created := *comment
+	created.ID = s.f.newID()
+	created.NodeID = github.String(fmt.Sprintf("IC_%d", created.GetID()))
+	created.User = &github.User{Login: github.String(FakeLogin)}
+	p.comments = append(p.comments, &created)
+	return &created, okResponse(), nil
+}
+
+// ListByRepo lists the issues of the repo in the state selected by the
+// options, "open" by default, and by their creator, if given, sorted by
+// number; pull requests are left out.
+func (s fakeIssues) ListByRepo(ctx context.Context, owner string, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := "open"
+	if opt != nil && opt.State != "" {
+		state = opt.State
+	}
+	var issues []*github.Issue
+	for _, issue := range r.issues {
+		if (state == "all" || issue.GetState() == state) && (opt == nil || opt.Creator == "" || strings.EqualFold(issue.GetUser().GetLogin(), opt.Creator)) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].GetNumber() < issues[j].GetNumber() })
+	return issues, okResponse(), nil
+}
+
+func (s fakeIssues) ListComments(ctx context.Context, owner string, repo string, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]*github.IssueComment(nil), p.comments...), okResponse(), nil
+}
+
+func (s fakeIssues) CreateLabel(ctx context.Context, owner string, repo string, label *github.Label) (*github.Label, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := strings.ToLower(label.GetName())
+	if _, ok := r.labels[key]; ok {
+		return nil, nil, errorResponse(http.StatusUnprocessableEntity, "Validation Failed", "repos/%s/%s/labels", owner, repo)
+	}
+	created := *label
+	created.ID = s.f.newID()
+	r.labels[key] = &created
+	return &created, okResponse(), nil
+}
+
+func (s fakeIssues) Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing, ok := r.issues[number]
+	if !ok {
+		return nil, nil, notFound("repos/%s/%s/issues/%d", owner, repo, number)
+	}
+	edited := *existing
+	if issue.Title != nil {
+		edited.Title = issue.Title
+	}
+	if issue.Body != nil {
+		edited.Body = issue.Body
+	}
+	if issue.State != nil {
+		edited.State = issue.State
+	}
+	r.issues[number] = &edited
+	return &edited, okResponse(), nil
+}
+
+func (s fakeIssues) EditLabel(ctx context.Context, owner string, repo string, name string, label *github.Label) (*github.Label, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing, ok := r.labels[strings.ToLower(name)]
+	if !ok {
+		return nil, nil, notFound("repos/%s/%s/labels/%s", owner, repo, name)
+	}
+	edited := *existing
+	if label.Name != nil {
+		edited.Name = label.Name
+	}
+	if label.Color != nil {
+		edited.Color = label.Color
+	}
+	if label.Description != nil {
+		edited.Description = label.Description
+	}
+	delete(r.labels, strings.ToLower(name))
+	r.labels[strings.ToLower(edited.GetName())] = &edited
+	return &edited, okResponse(), nil
+}
+
+func (s fakeIssues) GetLabel(ctx context.Context, owner string, repo string, name string) (*github.Label, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if label, ok := r.labels[strings.ToLower(name)]; ok {
+		return label, okResponse(), nil
+	}
+	return nil, nil, notFound("repos/%s/%s/labels/%s", owner, repo, name)
+}
+
+func (s fakeIssues) ListLabelsByIssue(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, _ := s.f.repo(owner, repo)
+	var labels []*github.Label
+	for _, name := range p.labels {
+		labels = append(labels, r.label(name))
+	}
+	return labels, okResponse(), nil
+}
+
+func (s fakeIssues) ReplaceLabelsForIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, _ := s.f.repo(owner, repo)
+	p.labels = append([]string(nil), labels...)
+	var replaced []*github.Label
+	for _, name := range labels {
+		replaced = append(replaced, r.label(name))
+	}
+	p.pull.Labels = replaced
+	return replaced, okResponse(), nil
+}
+
+type fakePullRequests struct{ f *FakeGitHub }
+
+// List returns the open pull requests of the repo, newest first, as the
+// GitHub API does by default.
+func (s fakePullRequests) List(ctx context.Context, owner string, repo string, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pulls []*github.PullRequest
+	for _, p := range r.pulls {
+		if state := p.pull.GetState(); state == "" || state == "open" {
+			pulls = append(pulls, p.pull)
+		}
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].GetNumber() > pulls[j].GetNumber() })
+	var listOpt github.ListOptions
+	if opt != nil {
+		listOpt = opt.ListOptions
+	}
+	start, end, resp := page(len(pulls), listOpt)
+	return pulls[start:end], resp, nil
+}
+
+func (s fakePullRequests) ListCommits(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.commits, okResponse(), nil
+}
+
+func (s fakePullRequests) Get(ctx context.Context, owner string, repo string, number int) (*github.PullRequest, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.pull, okResponse(), nil
+}
+
+func (s fakePullRequests) ListReviews(ctx context.Context, owner string, repo string, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.reviews, okResponse(), nil
+}
+
+// reviewStates maps the events of review requests to the states of the
+// resulting reviews.
+var reviewStates = map[string]string{
+	"APPROVE":         "APPROVED",
+	"REQUEST_CHANGES": "CHANGES_REQUESTED",
+	"COMMENT":         "COMMENTED",
+}
+
+func (s fakePullRequests) CreateReview(ctx context.Context, owner string, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	state, ok := reviewStates[review.GetEvent()]
+	if !ok {
+		state = "PENDING"
+	}
+	created := &github.PullRequestReview{
+		ID:       s.f.newID(),
+		Body:     review.Body,
+		CommitID: review.CommitID,
+		State:    github.String(state),
+	}
+	p.reviews = append(p.reviews, created)
+	return created, okResponse(), nil
+}
+
+func (s fakePullRequests) DismissReview(ctx context.Context, owner string, repo string, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, existing := range p.reviews {
+		if existing.GetID() == reviewID {
+			existing.State = github.String("DISMISSED")
+			return existing, okResponse(), nil
+		}
+	}
+	return nil, nil, notFound("repos/%s/%s/pulls/%d/reviews/%d", owner, repo, number, reviewID)
+}
+
+func (s fakePullRequests) RequestReviewers(ctx context.Context, owner string, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	p, err := s.f.pull(owner, repo, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, reviewer := range reviewers.Reviewers {
+		found := false
+		for _, existing := range p.reviewers {
+			found = found || strings.EqualFold(existing, reviewer)
+		}
+		if !found {
+			p.reviewers = append(p.reviewers, reviewer)
+		}
+	}
+	return p.pull, okResponse(), nil
+}
+
+type fakeChecks struct{ f *FakeGitHub }
+
+// CreateCheckRun creates a check run on any SHA of the repo, whether or not
+// the fake knows of the commit.
+func (s fakeChecks) CreateCheckRun(ctx context.Context, owner string, repo string, opt github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	r, err := s.f.repo(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	created := &github.CheckRun{
+		ID:          s.f.newID(),
+		Name:        github.String(opt.Name),
+		HeadSHA:     github.String(opt.HeadSHA),
+		DetailsURL:  opt.DetailsURL,
+		Status:      opt.Status,
+		Conclusion:  opt.Conclusion,
+		CompletedAt: opt.CompletedAt,
+		Output:      opt.Output,
+	}
+	r.checks[opt.HeadSHA] = append(r.checks[opt.HeadSHA], created)
+	return created, okResponse(), nil
+}
+
+type fakeUsers struct{ f *FakeGitHub }
+
+// Get returns the user with the given login, or the authenticated user, i.e.,
+// `FakeLogin`, if it is empty.
+func (s fakeUsers) Get(ctx context.Context, user string) (*github.User, *github.Response, error) {
+	if user == "" {
+		user = FakeLogin
+	}
+	return &github.User{Login: github.String(user)}, okResponse(), nil
+}
+
+type fakeGraphQL struct{ f *FakeGitHub }
+
+// MinimizeComment minimizes the comment with the node ID, in any repo.
+func (s fakeGraphQL) MinimizeComment(ctx context.Context, subjectID string, classifier string) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	for _, r := range s.f.repos {
+		for _, p := range r.pulls {
+			for _, comment := range p.comments {
+				if comment.GetNodeID() == subjectID {
+					if p.minimized == nil {
+						p.minimized = make(map[int64]bool)
+					}
+					p.minimized[comment.GetID()] = true
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("could not resolve to a node with the global id of '%s'", subjectID)
+}
+
+// PinIssue pins the issue with the node ID, in any repo.
+func (s fakeGraphQL) PinIssue(ctx context.Context, issueID string) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+	for _, r := range s.f.repos {
+		for number, issue := range r.issues {
+			if issue.GetNodeID() == issueID {
+				r.pinned[number] = true
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("could not resolve to a node with the global id of '%s'", issueID)
+}
+
+// Verify that the fake implements each of the services.
+var (
+	_ ghutil.OrganizationsService = fakeOrganizations{}
+	_ ghutil.RepositoriesService  = fakeRepositories{}
+	_ ghutil.IssuesService        = fakeIssues{}
+	_ ghutil.PullRequestsService  = fakePullRequests{}
+	_ ghutil.GraphQLService       = fakeGraphQL{}
+)
@@ -0,0 +1,312 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ghutiltest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/ghutiltest"
+	"github.com/google/code-review-bot/report"
+	"github.com/google/go-github/v21/github"
+)
+
+const (
+	orgName  = "org"
+	repoName = "repo"
+)
+
+var (
+	john = config.Account{Name: "John Doe", Email: "john@example.com", Login: "john-doe"}
+	jane = config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane-doe"}
+)
+
+func TestNewMockGitHubClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ghc := ghutil.NewBasicClient()
+	mockGhc := ghutiltest.NewMockGitHubClient(ghc, ctrl)
+	repo := &github.Repository{Name: github.String(repoName)}
+	mockGhc.Repositories.EXPECT().Get(gomock.Any(), orgName, repoName).Return(repo, nil, nil)
+
+	repos, err := ghc.GetAllRepos(orgName, repoName)
+	assert.Nil(t, err)
+	assert.Equal(t, []*github.Repository{repo}, repos)
+}
+
+func TestFakeGitHub_ProcessOrgRepo(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	for _, label := range []string{ghutil.LabelClaYes, ghutil.LabelClaNo} {
+		fake.AddLabel(orgName, repoName, label)
+	}
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number: github.Int(1),
+		Title:  github.String("Signed"),
+	}, ghutiltest.NewCommit("abc123", john))
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number: github.Int(2),
+		Title:  github.String("Not signed"),
+		Labels: []*github.Label{{Name: github.String(ghutil.LabelClaYes)}},
+	}, ghutiltest.NewCommit("def456", jane))
+
+	claSigners := config.ClaSigners{People: []config.Account{john}}
+	_, err := fake.Client().ProcessOrgRepo(ghutil.GitHubProcessOrgRepoSpec{
+		Org:        orgName,
+		Repo:       repoName,
+		UpdateRepo: true,
+	}, claSigners)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{ghutil.LabelClaYes}, fake.PullLabels(orgName, repoName, 1))
+	assert.Empty(t, fake.Comments(orgName, repoName, 1))
+	assert.Equal(t, []string{ghutil.LabelClaNo}, fake.PullLabels(orgName, repoName, 2))
+	comments := fake.Comments(orgName, repoName, 2)
+	if assert.Len(t, comments, 1) {
+		assert.Contains(t, comments[0].GetBody(), jane.Email)
+	}
+}
+
+func TestFakeGitHub_MinimizeComments(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddLabel(orgName, repoName, ghutil.LabelClaNo)
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number: github.Int(1),
+		Title:  github.String("Not signed"),
+	}, ghutiltest.NewCommit("abc123", jane))
+
+	// Each run comments anew, as the PR is labeled as compliant in between.
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:              orgName,
+		Repo:             repoName,
+		UpdateRepo:       true,
+		MinimizeComments: true,
+	}
+	ghc := fake.Client()
+	for run := 0; run < 3; run++ {
+		_, _, err := ghc.Issues.ReplaceLabelsForIssue(context.Background(), orgName, repoName, 1, []string{ghutil.LabelClaYes})
+		assert.Nil(t, err)
+		_, err = ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+		assert.Nil(t, err)
+	}
+
+	comments := fake.Comments(orgName, repoName, 1)
+	if assert.Len(t, comments, 3) {
+		assert.Equal(t, comments[:2], fake.MinimizedComments(orgName, repoName, 1))
+	}
+}
+
+func TestFakeGitHub_TrackingIssue(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number:  github.Int(1),
+		Title:   github.String("Not signed"),
+		HTMLURL: github.String("https://github.com/org/repo/pull/1"),
+	}, ghutiltest.NewCommit("abc123", jane))
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:           orgName,
+		Repo:          repoName,
+		UpdateRepo:    true,
+		TrackingIssue: config.TrackingIssue{Enabled: true, Pin: true},
+	}
+	ghc := fake.Client()
+	ghc.Report = report.New()
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+
+	issues := fake.Issues(orgName, repoName)
+	if !assert.Len(t, issues, 1) {
+		return
+	}
+	assert.Equal(t, 2, issues[0].GetNumber())
+	assert.Equal(t, ghutil.DefaultTrackingIssueTitle, issues[0].GetTitle())
+	assert.Contains(t, issues[0].GetBody(), "- [ ] [org/repo#1](https://github.com/org/repo/pull/1) Not signed: ")
+	assert.True(t, fake.IsPinned(orgName, repoName, 2))
+
+	// Once the PR is compliant, the same issue is updated.
+	ghc.Report = report.New()
+	_, err = ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{People: []config.Account{jane}})
+	assert.Nil(t, err)
+	issues = fake.Issues(orgName, repoName)
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].GetBody(), "No pull requests are currently non-compliant.")
+		assert.Contains(t, issues[0].GetBody(), ghutil.TrackingIssueMarker)
+	}
+}
+
+func TestFakeGitHub_TrackingIssue_ListsAllPagesOfPulls(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	for number := 1; number <= 101; number++ {
+		fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+			Number:  github.Int(number),
+			Title:   github.String(fmt.Sprintf("Not signed %d", number)),
+			HTMLURL: github.String(fmt.Sprintf("https://github.com/org/repo/pull/%d", number)),
+		}, ghutiltest.NewCommit(fmt.Sprintf("abc%03d", number), jane))
+	}
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:           orgName,
+		Repo:          repoName,
+		UpdateRepo:    true,
+		TrackingIssue: config.TrackingIssue{Enabled: true},
+	}
+	ghc := fake.Client()
+	ghc.Report = report.New()
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+
+	// The oldest PR is only listed on the second page.
+	issues := fake.Issues(orgName, repoName)
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].GetBody(), "- [ ] [org/repo#101](https://github.com/org/repo/pull/101) Not signed 101: ")
+		assert.Contains(t, issues[0].GetBody(), "- [ ] [org/repo#1](https://github.com/org/repo/pull/1) Not signed 1: ")
+	}
+}
+
+func TestFakeGitHub_TrackingIssue_IgnoresOthersIssues(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number: github.Int(1),
+		Title:  github.String("Not signed"),
+	}, ghutiltest.NewCommit("abc123", jane))
+	// Someone else opened an issue with the marker.
+	fake.AddIssue(orgName, repoName, &github.Issue{
+		Number: github.Int(2),
+		Title:  github.String("Not a tracking issue"),
+		Body:   github.String(ghutil.TrackingIssueMarker),
+		User:   &github.User{Login: github.String("mallory")},
+	})
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:           orgName,
+		Repo:          repoName,
+		UpdateRepo:    true,
+		TrackingIssue: config.TrackingIssue{Enabled: true},
+	}
+	ghc := fake.Client()
+	ghc.Report = report.New()
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+
+	issues := fake.Issues(orgName, repoName)
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, ghutil.TrackingIssueMarker, issues[0].GetBody())
+		assert.Equal(t, ghutil.DefaultTrackingIssueTitle, issues[1].GetTitle())
+		assert.Equal(t, ghutiltest.FakeLogin, issues[1].GetUser().GetLogin())
+	}
+}
+
+func TestFakeGitHub_TrackingIssue_DryRun(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{
+		Number: github.Int(1),
+		Title:  github.String("Not signed"),
+	}, ghutiltest.NewCommit("abc123", jane))
+
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{
+		Org:           orgName,
+		Repo:          repoName,
+		TrackingIssue: config.TrackingIssue{Enabled: true, Pin: true},
+	}
+	ghc := fake.Client()
+	ghc.Report = report.New()
+	ghc.Diff = ghutil.NewDiffWriter(nil)
+	_, err := ghc.ProcessOrgRepo(repoSpec, config.ClaSigners{})
+	assert.Nil(t, err)
+
+	assert.Empty(t, fake.Issues(orgName, repoName))
+	var issueChanges []ghutil.Change
+	for _, change := range ghc.Diff.Changes() {
+		if change.Kind == ghutil.ChangeCreateIssue || change.Kind == ghutil.ChangePinIssue {
+			issueChanges = append(issueChanges, change)
+		}
+	}
+	assert.Equal(t, []ghutil.Change{
+		{Org: orgName, Repo: repoName, Kind: ghutil.ChangeCreateIssue, Text: ghutil.DefaultTrackingIssueTitle},
+		{Org: orgName, Repo: repoName, Kind: ghutil.ChangePinIssue, Text: ghutil.DefaultTrackingIssueTitle},
+	}, issueChanges)
+}
+
+func TestFakeGitHub_NotFound(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddRepo(orgName, repoName)
+	ghc := fake.Client()
+	ctx := context.Background()
+
+	_, _, err := ghc.Repositories.Get(ctx, orgName, "missing")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "404")
+	}
+	_, _, err = ghc.PullRequests.Get(ctx, orgName, repoName, 1)
+	assert.NotNil(t, err)
+
+	// Missing files and labels are treated as such by the client.
+	repoConfig, err := ghc.GetRepoConfig(orgName, repoName)
+	assert.Nil(t, err)
+	assert.Equal(t, config.RepoConfig{}, repoConfig)
+	assert.Equal(t, ghutil.RepoClaLabelStatus{}, ghc.GetRepoClaLabelStatus(orgName, repoName, config.Labels{}))
+}
+
+func TestFakeGitHub_Labels(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddLabel(orgName, repoName, "cla: yes")
+	ghc := fake.Client()
+	ctx := context.Background()
+
+	_, _, err := ghc.Issues.CreateLabel(ctx, orgName, repoName, &github.Label{Name: github.String("CLA: Yes")})
+	assert.NotNil(t, err)
+	_, _, err = ghc.Issues.CreateLabel(ctx, orgName, repoName, &github.Label{Name: github.String("cla: no"), Color: github.String("ff0000")})
+	assert.Nil(t, err)
+	label, _, err := ghc.Issues.EditLabel(ctx, orgName, repoName, "cla: yes", &github.Label{Name: github.String("cla: ok")})
+	assert.Nil(t, err)
+	assert.Equal(t, "cla: ok", label.GetName())
+	assert.Equal(t, []string{"cla: no", "cla: ok"}, fake.Labels(orgName, repoName))
+
+	label, _, err = ghc.Issues.GetLabel(ctx, orgName, repoName, "cla: no")
+	assert.Nil(t, err)
+	assert.Equal(t, "ff0000", label.GetColor())
+}
+
+func TestFakeGitHub_Reviews(t *testing.T) {
+	fake := ghutiltest.NewFakeGitHub()
+	fake.AddPullRequest(orgName, repoName, &github.PullRequest{Number: github.Int(1)})
+	ghc := fake.Client()
+	ctx := context.Background()
+
+	review, _, err := ghc.PullRequests.CreateReview(ctx, orgName, repoName, 1, &github.PullRequestReviewRequest{
+		Body:  github.String("Please sign the CLA."),
+		Event: github.String("REQUEST_CHANGES"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "CHANGES_REQUESTED", review.GetState())
+
+	_, _, err = ghc.PullRequests.DismissReview(ctx, orgName, repoName, 1, review.GetID(), &github.PullRequestReviewDismissalRequest{})
+	assert.Nil(t, err)
+	reviews := fake.Reviews(orgName, repoName, 1)
+	if assert.Len(t, reviews, 1) {
+		assert.Equal(t, "DISMISSED", reviews[0].GetState())
+	}
+
+	_, _, err = ghc.PullRequests.RequestReviewers(ctx, orgName, repoName, 1, github.ReviewersRequest{Reviewers: []string{"jane-doe", "Jane-Doe"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"jane-doe"}, fake.Reviewers(orgName, repoName, 1))
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The mocks are checked in, unlike those of other packages, so that the
+// package can be imported without generating them first.
+//
+//go:generate mockgen -source ../ghutil/ghutil.go -destination mock_ghutil.go -package ghutiltest
+
+// Package ghutiltest provides test doubles for the GitHub services used by
+// `ghutil`, for testing code which embeds it: mocks of each service, which
+// verify the exact calls made, and `FakeGitHub`, an in-memory implementation
+// of repos, pull requests, labels and comments, which tests can set up and
+// inspect instead.
+package ghutiltest
+
+import (
+	"github.com/golang/mock/gomock"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+// MockGitHubClient holds the mock services bound to a `ghutil.GitHubClient`,
+// on which tests set their expectations.
+type MockGitHubClient struct {
+	Organizations *MockOrganizationsService
+	PullRequests  *MockPullRequestsService
+	Issues        *MockIssuesService
+	Repositories  *MockRepositoriesService
+	Checks        *MockChecksService
+	Users         *MockUsersService
+	GraphQL       *MockGraphQLService
+	Api           *MockGitHubUtilApi
+}
+
+// NewMockGitHubClient creates mocks of each service and binds them to the
+// client, e.g., one created via `ghutil.NewBasicClient`. The mock of
+// `GitHubUtilApi` is not bound, as most tests exercise the client's own
+// methods; assign it to `ghc.Api` to mock calls between them.
+func NewMockGitHubClient(ghc *ghutil.GitHubClient, ctrl *gomock.Controller) *MockGitHubClient {
+	mockGhc := &MockGitHubClient{
+		Organizations: NewMockOrganizationsService(ctrl),
+		PullRequests:  NewMockPullRequestsService(ctrl),
+		Issues:        NewMockIssuesService(ctrl),
+		Repositories:  NewMockRepositoriesService(ctrl),
+		Checks:        NewMockChecksService(ctrl),
+		Users:         NewMockUsersService(ctrl),
+		GraphQL:       NewMockGraphQLService(ctrl),
+		Api:           NewMockGitHubUtilApi(ctrl),
+	}
+
+	// Patch the original GitHubClient with our mock services.
+	ghc.Organizations = mockGhc.Organizations
+	ghc.PullRequests = mockGhc.PullRequests
+	ghc.Issues = mockGhc.Issues
+	ghc.Repositories = mockGhc.Repositories
+	ghc.Checks = mockGhc.Checks
+	ghc.Users = mockGhc.Users
+	ghc.GraphQL = mockGhc.GraphQL
+
+	return mockGhc
+}
@@ -0,0 +1,745 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../ghutil/ghutil.go
+
+// Package ghutiltest is a generated GoMock package.
+package ghutiltest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	config "github.com/google/code-review-bot/config"
+	ghutil "github.com/google/code-review-bot/ghutil"
+	github "github.com/google/go-github/v21/github"
+)
+
+// MockOrganizationsService is a mock of OrganizationsService interface.
+type MockOrganizationsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationsServiceMockRecorder
+}
+
+// MockOrganizationsServiceMockRecorder is the mock recorder for MockOrganizationsService.
+type MockOrganizationsServiceMockRecorder struct {
+	mock *MockOrganizationsService
+}
+
+// NewMockOrganizationsService creates a new mock instance.
+func NewMockOrganizationsService(ctrl *gomock.Controller) *MockOrganizationsService {
+	mock := &MockOrganizationsService{ctrl: ctrl}
+	mock.recorder = &MockOrganizationsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationsService) EXPECT() *MockOrganizationsServiceMockRecorder {
+	return m.recorder
+}
+
+// MockRepositoriesService is a mock of RepositoriesService interface.
+type MockRepositoriesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoriesServiceMockRecorder
+}
+
+// MockRepositoriesServiceMockRecorder is the mock recorder for MockRepositoriesService.
+type MockRepositoriesServiceMockRecorder struct {
+	mock *MockRepositoriesService
+}
+
+// NewMockRepositoriesService creates a new mock instance.
+func NewMockRepositoriesService(ctrl *gomock.Controller) *MockRepositoriesService {
+	mock := &MockRepositoriesService{ctrl: ctrl}
+	mock.recorder = &MockRepositoriesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoriesService) EXPECT() *MockRepositoriesServiceMockRecorder {
+	return m.recorder
+}
+
+// CompareCommits mocks base method.
+func (m *MockRepositoriesService) CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareCommits", ctx, owner, repo, base, head)
+	ret0, _ := ret[0].(*github.CommitsComparison)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompareCommits indicates an expected call of CompareCommits.
+func (mr *MockRepositoriesServiceMockRecorder) CompareCommits(ctx, owner, repo, base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareCommits", reflect.TypeOf((*MockRepositoriesService)(nil).CompareCommits), ctx, owner, repo, base, head)
+}
+
+// CreateStatus mocks base method.
+func (m *MockRepositoriesService) CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatus", ctx, owner, repo, ref, status)
+	ret0, _ := ret[0].(*github.RepoStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateStatus indicates an expected call of CreateStatus.
+func (mr *MockRepositoriesServiceMockRecorder) CreateStatus(ctx, owner, repo, ref, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatus", reflect.TypeOf((*MockRepositoriesService)(nil).CreateStatus), ctx, owner, repo, ref, status)
+}
+
+// Get mocks base method.
+func (m *MockRepositoriesService) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoriesServiceMockRecorder) Get(ctx, owner, repo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepositoriesService)(nil).Get), ctx, owner, repo)
+}
+
+// GetCommit mocks base method.
+func (m *MockRepositoriesService) GetCommit(ctx context.Context, owner, repo, sha string) (*github.RepositoryCommit, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommit", ctx, owner, repo, sha)
+	ret0, _ := ret[0].(*github.RepositoryCommit)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommit indicates an expected call of GetCommit.
+func (mr *MockRepositoriesServiceMockRecorder) GetCommit(ctx, owner, repo, sha interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockRepositoriesService)(nil).GetCommit), ctx, owner, repo, sha)
+}
+
+// GetContents mocks base method.
+func (m *MockRepositoriesService) GetContents(ctx context.Context, owner, repo, path string, opt *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContents", ctx, owner, repo, path, opt)
+	ret0, _ := ret[0].(*github.RepositoryContent)
+	ret1, _ := ret[1].([]*github.RepositoryContent)
+	ret2, _ := ret[2].(*github.Response)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetContents indicates an expected call of GetContents.
+func (mr *MockRepositoriesServiceMockRecorder) GetContents(ctx, owner, repo, path, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContents", reflect.TypeOf((*MockRepositoriesService)(nil).GetContents), ctx, owner, repo, path, opt)
+}
+
+// List mocks base method.
+func (m *MockRepositoriesService) List(ctx context.Context, user string, opt *github.RepositoryListOptions) ([]*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, user, opt)
+	ret0, _ := ret[0].([]*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoriesServiceMockRecorder) List(ctx, user, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepositoriesService)(nil).List), ctx, user, opt)
+}
+
+// MockIssuesService is a mock of IssuesService interface.
+type MockIssuesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssuesServiceMockRecorder
+}
+
+// MockIssuesServiceMockRecorder is the mock recorder for MockIssuesService.
+type MockIssuesServiceMockRecorder struct {
+	mock *MockIssuesService
+}
+
+// NewMockIssuesService creates a new mock instance.
+func NewMockIssuesService(ctrl *gomock.Controller) *MockIssuesService {
+	mock := &MockIssuesService{ctrl: ctrl}
+	mock.recorder = &MockIssuesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIssuesService) EXPECT() *MockIssuesServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIssuesService) Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, owner, repo, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIssuesServiceMockRecorder) Create(ctx, owner, repo, issue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIssuesService)(nil).Create), ctx, owner, repo, issue)
+}
+
+// CreateComment mocks base method.
+func (m *MockIssuesService) CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, owner, repo, number, comment)
+	ret0, _ := ret[0].(*github.IssueComment)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockIssuesServiceMockRecorder) CreateComment(ctx, owner, repo, number, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockIssuesService)(nil).CreateComment), ctx, owner, repo, number, comment)
+}
+
+// CreateLabel mocks base method.
+func (m *MockIssuesService) CreateLabel(ctx context.Context, owner, repo string, label *github.Label) (*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", ctx, owner, repo, label)
+	ret0, _ := ret[0].(*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockIssuesServiceMockRecorder) CreateLabel(ctx, owner, repo, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockIssuesService)(nil).CreateLabel), ctx, owner, repo, label)
+}
+
+// Edit mocks base method.
+func (m *MockIssuesService) Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, owner, repo, number, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockIssuesServiceMockRecorder) Edit(ctx, owner, repo, number, issue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockIssuesService)(nil).Edit), ctx, owner, repo, number, issue)
+}
+
+// EditLabel mocks base method.
+func (m *MockIssuesService) EditLabel(ctx context.Context, owner, repo, name string, label *github.Label) (*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditLabel", ctx, owner, repo, name, label)
+	ret0, _ := ret[0].(*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditLabel indicates an expected call of EditLabel.
+func (mr *MockIssuesServiceMockRecorder) EditLabel(ctx, owner, repo, name, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditLabel", reflect.TypeOf((*MockIssuesService)(nil).EditLabel), ctx, owner, repo, name, label)
+}
+
+// GetLabel mocks base method.
+func (m *MockIssuesService) GetLabel(ctx context.Context, owner, repo, name string) (*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabel", ctx, owner, repo, name)
+	ret0, _ := ret[0].(*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLabel indicates an expected call of GetLabel.
+func (mr *MockIssuesServiceMockRecorder) GetLabel(ctx, owner, repo, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockIssuesService)(nil).GetLabel), ctx, owner, repo, name)
+}
+
+// ListByRepo mocks base method.
+func (m *MockIssuesService) ListByRepo(ctx context.Context, owner, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRepo", ctx, owner, repo, opt)
+	ret0, _ := ret[0].([]*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByRepo indicates an expected call of ListByRepo.
+func (mr *MockIssuesServiceMockRecorder) ListByRepo(ctx, owner, repo, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRepo", reflect.TypeOf((*MockIssuesService)(nil).ListByRepo), ctx, owner, repo, opt)
+}
+
+// ListComments mocks base method.
+func (m *MockIssuesService) ListComments(ctx context.Context, owner, repo string, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", ctx, owner, repo, number, opt)
+	ret0, _ := ret[0].([]*github.IssueComment)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockIssuesServiceMockRecorder) ListComments(ctx, owner, repo, number, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockIssuesService)(nil).ListComments), ctx, owner, repo, number, opt)
+}
+
+// ListLabelsByIssue mocks base method.
+func (m *MockIssuesService) ListLabelsByIssue(ctx context.Context, owner, repo string, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLabelsByIssue", ctx, owner, repo, number, opt)
+	ret0, _ := ret[0].([]*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListLabelsByIssue indicates an expected call of ListLabelsByIssue.
+func (mr *MockIssuesServiceMockRecorder) ListLabelsByIssue(ctx, owner, repo, number, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLabelsByIssue", reflect.TypeOf((*MockIssuesService)(nil).ListLabelsByIssue), ctx, owner, repo, number, opt)
+}
+
+// ReplaceLabelsForIssue mocks base method.
+func (m *MockIssuesService) ReplaceLabelsForIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceLabelsForIssue", ctx, owner, repo, number, labels)
+	ret0, _ := ret[0].([]*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReplaceLabelsForIssue indicates an expected call of ReplaceLabelsForIssue.
+func (mr *MockIssuesServiceMockRecorder) ReplaceLabelsForIssue(ctx, owner, repo, number, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceLabelsForIssue", reflect.TypeOf((*MockIssuesService)(nil).ReplaceLabelsForIssue), ctx, owner, repo, number, labels)
+}
+
+// MockChecksService is a mock of ChecksService interface.
+type MockChecksService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChecksServiceMockRecorder
+}
+
+// MockChecksServiceMockRecorder is the mock recorder for MockChecksService.
+type MockChecksServiceMockRecorder struct {
+	mock *MockChecksService
+}
+
+// NewMockChecksService creates a new mock instance.
+func NewMockChecksService(ctrl *gomock.Controller) *MockChecksService {
+	mock := &MockChecksService{ctrl: ctrl}
+	mock.recorder = &MockChecksServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChecksService) EXPECT() *MockChecksServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCheckRun mocks base method.
+func (m *MockChecksService) CreateCheckRun(ctx context.Context, owner, repo string, opt github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckRun", ctx, owner, repo, opt)
+	ret0, _ := ret[0].(*github.CheckRun)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateCheckRun indicates an expected call of CreateCheckRun.
+func (mr *MockChecksServiceMockRecorder) CreateCheckRun(ctx, owner, repo, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckRun", reflect.TypeOf((*MockChecksService)(nil).CreateCheckRun), ctx, owner, repo, opt)
+}
+
+// MockUsersService is a mock of UsersService interface.
+type MockUsersService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersServiceMockRecorder
+}
+
+// MockUsersServiceMockRecorder is the mock recorder for MockUsersService.
+type MockUsersServiceMockRecorder struct {
+	mock *MockUsersService
+}
+
+// NewMockUsersService creates a new mock instance.
+func NewMockUsersService(ctrl *gomock.Controller) *MockUsersService {
+	mock := &MockUsersService{ctrl: ctrl}
+	mock.recorder = &MockUsersServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsersService) EXPECT() *MockUsersServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockUsersService) Get(ctx context.Context, user string) (*github.User, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, user)
+	ret0, _ := ret[0].(*github.User)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUsersServiceMockRecorder) Get(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUsersService)(nil).Get), ctx, user)
+}
+
+// MockGraphQLService is a mock of GraphQLService interface.
+type MockGraphQLService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLServiceMockRecorder
+}
+
+// MockGraphQLServiceMockRecorder is the mock recorder for MockGraphQLService.
+type MockGraphQLServiceMockRecorder struct {
+	mock *MockGraphQLService
+}
+
+// NewMockGraphQLService creates a new mock instance.
+func NewMockGraphQLService(ctrl *gomock.Controller) *MockGraphQLService {
+	mock := &MockGraphQLService{ctrl: ctrl}
+	mock.recorder = &MockGraphQLServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQLService) EXPECT() *MockGraphQLServiceMockRecorder {
+	return m.recorder
+}
+
+// MinimizeComment mocks base method.
+func (m *MockGraphQLService) MinimizeComment(ctx context.Context, subjectID, classifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinimizeComment", ctx, subjectID, classifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MinimizeComment indicates an expected call of MinimizeComment.
+func (mr *MockGraphQLServiceMockRecorder) MinimizeComment(ctx, subjectID, classifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinimizeComment", reflect.TypeOf((*MockGraphQLService)(nil).MinimizeComment), ctx, subjectID, classifier)
+}
+
+// PinIssue mocks base method.
+func (m *MockGraphQLService) PinIssue(ctx context.Context, issueID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinIssue", ctx, issueID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinIssue indicates an expected call of PinIssue.
+func (mr *MockGraphQLServiceMockRecorder) PinIssue(ctx, issueID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinIssue", reflect.TypeOf((*MockGraphQLService)(nil).PinIssue), ctx, issueID)
+}
+
+// MockPullRequestsService is a mock of PullRequestsService interface.
+type MockPullRequestsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestsServiceMockRecorder
+}
+
+// MockPullRequestsServiceMockRecorder is the mock recorder for MockPullRequestsService.
+type MockPullRequestsServiceMockRecorder struct {
+	mock *MockPullRequestsService
+}
+
+// NewMockPullRequestsService creates a new mock instance.
+func NewMockPullRequestsService(ctrl *gomock.Controller) *MockPullRequestsService {
+	mock := &MockPullRequestsService{ctrl: ctrl}
+	mock.recorder = &MockPullRequestsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPullRequestsService) EXPECT() *MockPullRequestsServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockPullRequestsService) CreateReview(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", ctx, owner, repo, number, review)
+	ret0, _ := ret[0].(*github.PullRequestReview)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockPullRequestsServiceMockRecorder) CreateReview(ctx, owner, repo, number, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockPullRequestsService)(nil).CreateReview), ctx, owner, repo, number, review)
+}
+
+// DismissReview mocks base method.
+func (m *MockPullRequestsService) DismissReview(ctx context.Context, owner, repo string, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissReview", ctx, owner, repo, number, reviewID, review)
+	ret0, _ := ret[0].(*github.PullRequestReview)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DismissReview indicates an expected call of DismissReview.
+func (mr *MockPullRequestsServiceMockRecorder) DismissReview(ctx, owner, repo, number, reviewID, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissReview", reflect.TypeOf((*MockPullRequestsService)(nil).DismissReview), ctx, owner, repo, number, reviewID, review)
+}
+
+// Get mocks base method.
+func (m *MockPullRequestsService) Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, owner, repo, number)
+	ret0, _ := ret[0].(*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPullRequestsServiceMockRecorder) Get(ctx, owner, repo, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPullRequestsService)(nil).Get), ctx, owner, repo, number)
+}
+
+// List mocks base method.
+func (m *MockPullRequestsService) List(ctx context.Context, owner, repo string, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, owner, repo, opt)
+	ret0, _ := ret[0].([]*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPullRequestsServiceMockRecorder) List(ctx, owner, repo, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPullRequestsService)(nil).List), ctx, owner, repo, opt)
+}
+
+// ListCommits mocks base method.
+func (m *MockPullRequestsService) ListCommits(ctx context.Context, owner, repo string, number int, opt *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", ctx, owner, repo, number, opt)
+	ret0, _ := ret[0].([]*github.RepositoryCommit)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockPullRequestsServiceMockRecorder) ListCommits(ctx, owner, repo, number, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockPullRequestsService)(nil).ListCommits), ctx, owner, repo, number, opt)
+}
+
+// ListReviews mocks base method.
+func (m *MockPullRequestsService) ListReviews(ctx context.Context, owner, repo string, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviews", ctx, owner, repo, number, opt)
+	ret0, _ := ret[0].([]*github.PullRequestReview)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListReviews indicates an expected call of ListReviews.
+func (mr *MockPullRequestsServiceMockRecorder) ListReviews(ctx, owner, repo, number, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockPullRequestsService)(nil).ListReviews), ctx, owner, repo, number, opt)
+}
+
+// RequestReviewers mocks base method.
+func (m *MockPullRequestsService) RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReviewers", ctx, owner, repo, number, reviewers)
+	ret0, _ := ret[0].(*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RequestReviewers indicates an expected call of RequestReviewers.
+func (mr *MockPullRequestsServiceMockRecorder) RequestReviewers(ctx, owner, repo, number, reviewers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReviewers", reflect.TypeOf((*MockPullRequestsService)(nil).RequestReviewers), ctx, owner, repo, number, reviewers)
+}
+
+// MockGitHubUtilApi is a mock of GitHubUtilApi interface.
+type MockGitHubUtilApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitHubUtilApiMockRecorder
+}
+
+// MockGitHubUtilApiMockRecorder is the mock recorder for MockGitHubUtilApi.
+type MockGitHubUtilApiMockRecorder struct {
+	mock *MockGitHubUtilApi
+}
+
+// NewMockGitHubUtilApi creates a new mock instance.
+func NewMockGitHubUtilApi(ctrl *gomock.Controller) *MockGitHubUtilApi {
+	mock := &MockGitHubUtilApi{ctrl: ctrl}
+	mock.recorder = &MockGitHubUtilApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitHubUtilApi) EXPECT() *MockGitHubUtilApiMockRecorder {
+	return m.recorder
+}
+
+// CheckPullRequestCompliance mocks base method.
+func (m *MockGitHubUtilApi) CheckPullRequestCompliance(prSpec ghutil.GitHubProcessSinglePullSpec, claSigners config.ClaSigners) (ghutil.PullRequestStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPullRequestCompliance", prSpec, claSigners)
+	ret0, _ := ret[0].(ghutil.PullRequestStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPullRequestCompliance indicates an expected call of CheckPullRequestCompliance.
+func (mr *MockGitHubUtilApiMockRecorder) CheckPullRequestCompliance(prSpec, claSigners interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPullRequestCompliance", reflect.TypeOf((*MockGitHubUtilApi)(nil).CheckPullRequestCompliance), prSpec, claSigners)
+}
+
+// GetAllRepos mocks base method.
+func (m *MockGitHubUtilApi) GetAllRepos(orgName, repoName string) ([]*github.Repository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllRepos", orgName, repoName)
+	ret0, _ := ret[0].([]*github.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllRepos indicates an expected call of GetAllRepos.
+func (mr *MockGitHubUtilApiMockRecorder) GetAllRepos(orgName, repoName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRepos", reflect.TypeOf((*MockGitHubUtilApi)(nil).GetAllRepos), orgName, repoName)
+}
+
+// GetIssueClaLabelStatus mocks base method.
+func (m *MockGitHubUtilApi) GetIssueClaLabelStatus(orgName, repoName string, pullNumber int, labels config.Labels) ghutil.IssueClaLabelStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssueClaLabelStatus", orgName, repoName, pullNumber, labels)
+	ret0, _ := ret[0].(ghutil.IssueClaLabelStatus)
+	return ret0
+}
+
+// GetIssueClaLabelStatus indicates an expected call of GetIssueClaLabelStatus.
+func (mr *MockGitHubUtilApiMockRecorder) GetIssueClaLabelStatus(orgName, repoName, pullNumber, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssueClaLabelStatus", reflect.TypeOf((*MockGitHubUtilApi)(nil).GetIssueClaLabelStatus), orgName, repoName, pullNumber, labels)
+}
+
+// GetOrgConfig mocks base method.
+func (m *MockGitHubUtilApi) GetOrgConfig(orgName string) (config.OrgConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrgConfig", orgName)
+	ret0, _ := ret[0].(config.OrgConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrgConfig indicates an expected call of GetOrgConfig.
+func (mr *MockGitHubUtilApiMockRecorder) GetOrgConfig(orgName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrgConfig", reflect.TypeOf((*MockGitHubUtilApi)(nil).GetOrgConfig), orgName)
+}
+
+// GetRepoClaLabelStatus mocks base method.
+func (m *MockGitHubUtilApi) GetRepoClaLabelStatus(orgName, repoName string, labels config.Labels) ghutil.RepoClaLabelStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepoClaLabelStatus", orgName, repoName, labels)
+	ret0, _ := ret[0].(ghutil.RepoClaLabelStatus)
+	return ret0
+}
+
+// GetRepoClaLabelStatus indicates an expected call of GetRepoClaLabelStatus.
+func (mr *MockGitHubUtilApiMockRecorder) GetRepoClaLabelStatus(orgName, repoName, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepoClaLabelStatus", reflect.TypeOf((*MockGitHubUtilApi)(nil).GetRepoClaLabelStatus), orgName, repoName, labels)
+}
+
+// GetRepoConfig mocks base method.
+func (m *MockGitHubUtilApi) GetRepoConfig(orgName, repoName string) (config.RepoConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepoConfig", orgName, repoName)
+	ret0, _ := ret[0].(config.RepoConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepoConfig indicates an expected call of GetRepoConfig.
+func (mr *MockGitHubUtilApiMockRecorder) GetRepoConfig(orgName, repoName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepoConfig", reflect.TypeOf((*MockGitHubUtilApi)(nil).GetRepoConfig), orgName, repoName)
+}
+
+// ProcessOrgRepo mocks base method.
+func (m *MockGitHubUtilApi) ProcessOrgRepo(repoSpec ghutil.GitHubProcessOrgRepoSpec, claSigners config.ClaSigners) (*ghutil.Checkpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessOrgRepo", repoSpec, claSigners)
+	ret0, _ := ret[0].(*ghutil.Checkpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessOrgRepo indicates an expected call of ProcessOrgRepo.
+func (mr *MockGitHubUtilApiMockRecorder) ProcessOrgRepo(repoSpec, claSigners interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessOrgRepo", reflect.TypeOf((*MockGitHubUtilApi)(nil).ProcessOrgRepo), repoSpec, claSigners)
+}
+
+// ProcessPullRequest mocks base method.
+func (m *MockGitHubUtilApi) ProcessPullRequest(prSpec ghutil.GitHubProcessSinglePullSpec, claSigners config.ClaSigners, repoClaLabelStatus ghutil.RepoClaLabelStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPullRequest", prSpec, claSigners, repoClaLabelStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessPullRequest indicates an expected call of ProcessPullRequest.
+func (mr *MockGitHubUtilApiMockRecorder) ProcessPullRequest(prSpec, claSigners, repoClaLabelStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPullRequest", reflect.TypeOf((*MockGitHubUtilApi)(nil).ProcessPullRequest), prSpec, claSigners, repoClaLabelStatus)
+}
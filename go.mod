@@ -6,6 +6,7 @@ require (
 	github.com/go-yaml/yaml v2.1.0+incompatible
 	github.com/golang/mock v1.6.0
 	github.com/google/go-github/v21 v21.0.0
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/oauth2 v0.0.0-20190115181402-5dab4167f31c
 )
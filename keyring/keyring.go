@@ -0,0 +1,167 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keyring reads secrets from the operating system's credential store
+// (macOS Keychain, the Secret Service on Linux, or the Windows Credential
+// Manager) via the command-line tools each platform provides, so that
+// maintainers running the tool from a workstation need not keep tokens in
+// plain-text files.
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+)
+
+// Prefix marks a secrets location as referring to the keyring rather than to a
+// file, e.g., "keyring:crbot/github-token".
+const Prefix = "keyring:"
+
+// Default service and account names under which the GitHub token is stored.
+const (
+	DefaultService = "crbot"
+	DefaultAccount = "github-token"
+)
+
+// IsSpec returns whether the secrets location refers to the keyring.
+func IsSpec(location string) bool {
+	return strings.HasPrefix(location, Prefix)
+}
+
+// ParseSpec parses a keyring location of the form "keyring:[SERVICE[/ACCOUNT]]"
+// into its service and account names, using the defaults for missing parts.
+func ParseSpec(location string) (service string, account string, err error) {
+	if !IsSpec(location) {
+		return "", "", fmt.Errorf("keyring location must start with '%s': %s", Prefix, location)
+	}
+	service, account = DefaultService, DefaultAccount
+	spec := strings.TrimPrefix(location, Prefix)
+	if spec == "" {
+		return service, account, nil
+	}
+	parts := strings.SplitN(spec, "/", 2)
+	if parts[0] != "" {
+		service = parts[0]
+	}
+	if len(parts) == 2 && parts[1] != "" {
+		account = parts[1]
+	}
+	return service, account, nil
+}
+
+// windowsCredReadScript reads a generic credential from the Windows Credential
+// Manager, whose target name is "SERVICE/ACCOUNT"; PowerShell has no built-in
+// cmdlet for this, so it calls `CredRead` directly. The target is appended by
+// `windowsCredReadCommand`.
+const windowsCredReadScript = `
+$ErrorActionPreference = 'Stop'
+Add-Type -TypeDefinition @'
+using System;
+using System.Runtime.InteropServices;
+public static class CrbotCred {
+  [StructLayout(LayoutKind.Sequential, CharSet = CharSet.Unicode)]
+  public struct CREDENTIAL {
+    public int Flags; public int Type; public string TargetName; public string Comment;
+    public long LastWritten; public int CredentialBlobSize; public IntPtr CredentialBlob;
+    public int Persist; public int AttributeCount; public IntPtr Attributes;
+    public string TargetAlias; public string UserName;
+  }
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  public static extern bool CredRead(string target, int type, int flags, out IntPtr cred);
+  [DllImport("advapi32.dll")]
+  public static extern void CredFree(IntPtr cred);
+  public static string Read(string target) {
+    IntPtr ptr;
+    if (!CredRead(target, 1, 0, out ptr)) { throw new Exception("credential not found: " + target); }
+    try {
+      CREDENTIAL cred = (CREDENTIAL)Marshal.PtrToStructure(ptr, typeof(CREDENTIAL));
+      return Marshal.PtrToStringUni(cred.CredentialBlob, cred.CredentialBlobSize / 2);
+    } finally { CredFree(ptr); }
+  }
+}
+'@
+`
+
+// windowsCredReadCommand returns the PowerShell script which reads the
+// credential with the given target name and writes it to stdout. The target
+// is embedded as a single-quoted string literal, in which only single quotes
+// need escaping, by doubling them; with `-Command`, PowerShell would join any
+// arguments following the script into its text rather than pass them in
+// `$args`.
+func windowsCredReadCommand(target string) string {
+	literal := "'" + strings.Replace(target, "'", "''", -1) + "'"
+	return windowsCredReadScript + "[Console]::Out.Write([CrbotCred]::Read(" + literal + "))\n"
+}
+
+// encodePowerShellCommand encodes a script for `powershell -EncodedCommand`,
+// i.e., as base64 of its UTF-16LE encoding, so that no quoting of the command
+// line can alter it.
+func encodePowerShellCommand(script string) string {
+	units := utf16.Encode([]rune(script))
+	data := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(data[2*i:], unit)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// command returns the command line which reads the secret on the given OS.
+func command(goos string, service string, account string) ([]string, error) {
+	switch goos {
+	case "darwin":
+		return []string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"secret-tool", "lookup", "service", service, "account", account}, nil
+	case "windows":
+		return []string{"powershell", "-NoProfile", "-NonInteractive", "-EncodedCommand", encodePowerShellCommand(windowsCredReadCommand(service + "/" + account))}, nil
+	}
+	return nil, fmt.Errorf("reading from the keyring is not supported on %s", goos)
+}
+
+// runCommand runs the command and returns its standard output; it is a
+// variable so that tests can replace it.
+var runCommand = func(args []string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, err
+}
+
+// Get reads the secret stored under the given service and account names.
+func Get(service string, account string) (string, error) {
+	args, err := command(runtime.GOOS, service, account)
+	if err != nil {
+		return "", err
+	}
+	out, err := runCommand(args)
+	if err != nil {
+		return "", fmt.Errorf("error reading '%s/%s' from the keyring via %s: %s", service, account, args[0], err)
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", errors.New("empty secret for '" + service + "/" + account + "' in the keyring")
+	}
+	return secret, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyring
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpec(t *testing.T) {
+	for location, expected := range map[string][2]string{
+		"keyring:":                {DefaultService, DefaultAccount},
+		"keyring:my-service":      {"my-service", DefaultAccount},
+		"keyring:my-service/bot":  {"my-service", "bot"},
+		"keyring:/bot":            {DefaultService, "bot"},
+		"keyring:my-service/a/b/": {"my-service", "a/b/"},
+	} {
+		service, account, err := ParseSpec(location)
+		assert.Nil(t, err, location)
+		assert.Equal(t, expected, [2]string{service, account}, location)
+	}
+
+	_, _, err := ParseSpec("secrets.yaml")
+	assert.NotNil(t, err)
+}
+
+func TestCommand(t *testing.T) {
+	args, err := command("darwin", "svc", "acct")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"security", "find-generic-password", "-s", "svc", "-a", "acct", "-w"}, args)
+
+	args, err = command("linux", "svc", "acct")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"secret-tool", "lookup", "service", "svc", "account", "acct"}, args)
+
+	args, err = command("windows", "svc", "it's")
+	assert.Nil(t, err)
+	script := windowsCredReadScript + "[Console]::Out.Write([CrbotCred]::Read('svc/it''s'))\n"
+	assert.Equal(t, []string{"powershell", "-NoProfile", "-NonInteractive", "-EncodedCommand", encodePowerShellCommand(script)}, args)
+
+	_, err = command("plan9", "svc", "acct")
+	assert.NotNil(t, err)
+}
+
+func TestEncodePowerShellCommand(t *testing.T) {
+	// As encoded by `[Convert]::ToBase64String([Text.Encoding]::Unicode.GetBytes(...))`.
+	assert.Equal(t, "JwBhACcAJwBiACcA", encodePowerShellCommand("'a''b'"))
+	assert.Equal(t, "rCA=", encodePowerShellCommand("€"))
+}
+
+func TestGet(t *testing.T) {
+	if _, err := command(runtime.GOOS, "", ""); err != nil {
+		t.Skip(err)
+	}
+	defer func(saved func([]string) ([]byte, error)) { runCommand = saved }(runCommand)
+
+	runCommand = func(args []string) ([]byte, error) {
+		return []byte("s3cr3t\n"), nil
+	}
+	secret, err := Get("svc", "acct")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", secret)
+
+	runCommand = func(args []string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}
+	_, err = Get("svc", "acct")
+	assert.NotNil(t, err)
+
+	runCommand = func(args []string) ([]byte, error) {
+		return []byte("\n"), nil
+	}
+	_, err = Get("svc", "acct")
+	assert.NotNil(t, err)
+}
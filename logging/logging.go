@@ -15,37 +15,512 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// Names of the built-in logging sinks.
+const (
+	// SinkStd writes debug and info lines to stdout and errors to stderr.
+	SinkStd = "stdout"
+	// SinkSyslog writes to the local syslog daemon.
+	SinkSyslog = "syslog"
+	// SinkJournald writes to stdout and stderr, prefixing each line with
+	// its priority, as understood by the systemd journal.
+	SinkJournald = "journald"
+	// SinkStackdriver writes to stdout as JSON, one entry per line, as
+	// understood by Google Cloud Logging (formerly Stackdriver) on Cloud
+	// Run, Cloud Functions, and GKE.
+	SinkStackdriver = "stackdriver"
+	// SinkFilePrefix, followed by a path, appends to the file at the path.
+	SinkFilePrefix = "file:"
+)
+
+// Sinks lists the names of all the supported logging sinks.
+var Sinks = []string{SinkStd, SinkSyslog, SinkJournald, SinkStackdriver, SinkFilePrefix + "PATH"}
+
+// Level is the severity of a log entry.
+type Level int
+
+// Levels of log entries, by increasing severity.
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo
+	LevelError
+	LevelFatal
+)
+
+// Levels lists the names of the levels which can be selected via `SetLevel`.
+var Levels = []string{"debug", "info", "error"}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// ParseLevel returns the level with the given name, e.g., "debug".
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range Levels {
+		if strings.EqualFold(name, levelName) {
+			return LevelDebug + Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level '%s'; accepted: %s", name, strings.Join(Levels, ", "))
+}
+
+// Names of the formats of log lines.
+const (
+	// FormatText writes each entry as plain text.
+	FormatText = "text"
+	// FormatJSON writes each entry as a single line of JSON with its
+	// level, module, and message, e.g., for log aggregators.
+	FormatJSON = "json"
+)
+
+// Formats lists the names of all the supported formats of log lines.
+var Formats = []string{FormatText, FormatJSON}
+
+// jsonEntry is a log entry as written in `FormatJSON`.
+type jsonEntry struct {
+	Level   string `json:"level"`
+	Module  string `json:"module,omitempty"`
+	Message string `json:"message"`
+}
+
+// Entry is a single log entry.
+type Entry struct {
+	Level Level
+	// Module identifies the package which logged the entry, e.g., "ghutil",
+	// or is empty for entries logged via the package-level functions.
+	Module string
+	// Message is the text of the entry, which is newline-terminated and may
+	// span multiple lines.
+	Message string
+}
+
+// Line returns the message of the entry prefixed with its module, if any, or
+// the entry as JSON if selected via `SetFormat`.
+func (e Entry) Line() string {
+	if lineFormat == FormatJSON {
+		line, err := json.Marshal(jsonEntry{
+			Level:   e.Level.String(),
+			Module:  e.Module,
+			Message: strings.TrimSuffix(e.Message, "\n"),
+		})
+		if err != nil {
+			return e.Message
+		}
+		return string(line) + "\n"
+	}
+	if e.Module == "" || !modulePrefixes {
+		return e.Message
+	}
+	return "[" + e.Module + "] " + e.Message
+}
+
+// Sink outputs log entries. Applications embedding the bot's packages can
+// implement it to route (or filter) their output, e.g., by module.
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as a `Sink`.
+type SinkFunc func(entry Entry) error
+
+// Write calls `f(entry)`.
+func (f SinkFunc) Write(entry Entry) error {
+	return f(entry)
+}
+
+// Destinations of the stdout and journald sinks; variables for testing.
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// quiet suppresses info lines when set.
+var quiet = false
+
+// SetQuiet suppresses (or restores) info lines, e.g., when stdout is used for
+// other output; errors are still written.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// minLevel is the level below which entries are dropped.
+var minLevel = LevelInfo
+
+// SetLevel drops all further entries below the given level; the default is
+// `LevelInfo`, so that debug entries, e.g., the details of each commit, are
+// dropped.
+func SetLevel(l Level) {
+	minLevel = l
+}
+
+// lineFormat is the format of log lines written by the line-oriented sinks.
+var lineFormat = FormatText
+
+// SetFormat selects the format of log lines written by the line-oriented
+// sinks, i.e., all but the Stackdriver sink, which always writes JSON, and
+// custom sinks. It returns an error for unknown formats.
+func SetFormat(name string) error {
+	for _, f := range Formats {
+		if name == f {
+			lineFormat = name
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log format '%s'; accepted: %s", name, strings.Join(Formats, ", "))
+}
+
+// modulePrefixes makes the line-oriented sinks prefix each line with the
+// module which logged it, if any.
+var modulePrefixes = false
+
+// SetModulePrefixes enables (or disables) prefixing each line with the module
+// which logged it, e.g., "[ghutil] ".
+func SetModulePrefixes(enabled bool) {
+	modulePrefixes = enabled
+}
+
+// sinkName is the name of the current sink, and sink outputs entries.
+var (
+	sinkName      = SinkStd
+	sink     Sink = SinkFunc(writeStd)
+)
+
+// sinkFactories create the sinks which can be selected by name via `SetSink`;
+// `tag` identifies the program.
+var (
+	sinkFactoriesMu sync.Mutex
+	sinkFactories   = map[string]func(tag string) (Sink, error){
+		SinkStd:         func(string) (Sink, error) { return SinkFunc(writeStd), nil },
+		SinkJournald:    func(string) (Sink, error) { return SinkFunc(writeJournald), nil },
+		SinkStackdriver: func(string) (Sink, error) { return SinkFunc(writeStackdriver), nil },
+		SinkSyslog:      newSyslogSink,
+	}
+)
+
+// RegisterSink makes a custom sink available under the given name, e.g., for
+// the -log-sink flag. It panics if a sink with the same name is already
+// registered; it is intended to be called from `init` functions.
+func RegisterSink(name string, factory func(tag string) (Sink, error)) {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+	if _, dup := sinkFactories[name]; dup {
+		panic("logging: RegisterSink called twice for sink " + name)
+	}
+	sinkFactories[name] = factory
+	Sinks = append(Sinks, name)
+}
+
+// SetSink selects where log lines are written; `tag` identifies the program
+// in syslog. It returns an error for unknown sinks, or if the sink is not
+// available.
+func SetSink(name string, tag string) error {
+	var newSink Sink
+	if strings.HasPrefix(name, SinkFilePrefix) {
+		fileSink, err := newFileSink(strings.TrimPrefix(name, SinkFilePrefix))
+		if err != nil {
+			return err
+		}
+		newSink = fileSink
+	} else {
+		sinkFactoriesMu.Lock()
+		factory, ok := sinkFactories[name]
+		sinkFactoriesMu.Unlock()
+		if !ok {
+			return fmt.Errorf("unknown logging sink '%s'; accepted: %s", name, strings.Join(Sinks, ", "))
+		}
+		var err error
+		if newSink, err = factory(tag); err != nil {
+			return err
+		}
+	}
+	sinkName = name
+	sink = newSink
+	return nil
+}
+
+// Use makes all further logging go to the given sink, e.g., one implemented
+// by an application embedding the bot's packages.
+func Use(s Sink) {
+	sinkName = ""
+	sink = s
+}
+
+// CurrentSink returns the sink which entries are currently written to, e.g.,
+// to wrap it in another passed to `Use`.
+func CurrentSink() Sink {
+	return sink
+}
+
+// writeStd writes debug and info lines to stdout, and all other lines to stderr.
+func writeStd(entry Entry) error {
+	if entry.Level <= LevelInfo {
+		_, err := io.WriteString(stdout, entry.Line())
+		return err
+	}
+	_, err := io.WriteString(stderr, entry.Line())
+	return err
+}
+
+// journaldPriorities maps levels to the syslog priority prefixes which the
+// systemd journal recognizes on the output of the services it runs.
+var journaldPriorities = map[Level]string{
+	LevelDebug: "<7>",
+	LevelInfo:  "<6>",
+	LevelError: "<3>",
+	LevelFatal: "<2>",
+}
+
+// writeJournald prefixes each line of the message with its priority, as the
+// journal treats each line as a separate entry.
+func writeJournald(entry Entry) error {
+	prefix := journaldPriorities[entry.Level]
+	lines := strings.Split(strings.TrimSuffix(entry.Line(), "\n"), "\n")
+	entry.Module = ""
+	entry.Message = prefix + strings.Join(lines, "\n"+prefix) + "\n"
+	return writeStd(entry)
+}
+
+// repeatKey identifies error entries which repeat each other: those logged by
+// the same module with the same format string, e.g., the same error for each of
+// many repos.
+type repeatKey struct {
+	module string
+	format string
+}
+
+// repeatCount is the number of error entries with the same `repeatKey` since
+// the last `FlushRepeats`, and the first of their messages.
+type repeatCount struct {
+	first string
+	count int
+}
+
+var (
+	repeatMu    sync.Mutex
+	repeatLimit = 0
+	repeats     = make(map[repeatKey]*repeatCount)
+	repeatOrder []repeatKey
+)
+
+// SetRepeatLimit suppresses error entries which repeat others, i.e., which are
+// logged by the same module with the same format string, once `limit` of them
+// have been written, until `FlushRepeats` writes a single entry with the
+// number suppressed; 0, the default, disables the limit.
+func SetRepeatLimit(limit int) {
+	repeatMu.Lock()
+	defer repeatMu.Unlock()
+	repeatLimit = limit
+}
+
+// countRepeat counts the error entry, returning whether to suppress it, along
+// with a notice to write first for the first one suppressed.
+func (lg *Logger) countRepeat(format string, message string) (bool, string) {
+	repeatMu.Lock()
+	defer repeatMu.Unlock()
+	if repeatLimit <= 0 {
+		return false, ""
+	}
+	key := repeatKey{module: lg.module, format: format}
+	c := repeats[key]
+	if c == nil {
+		c = &repeatCount{first: message}
+		repeats[key] = c
+		repeatOrder = append(repeatOrder, key)
+	}
+	c.count++
+	if c.count <= repeatLimit {
+		return false, ""
+	} else if c.count == repeatLimit+1 {
+		return true, fmt.Sprintf("Suppressing further errors like: %s", c.first)
+	}
+	return true, ""
+}
+
+// FlushRepeats writes an entry with the number of errors suppressed by
+// `SetRepeatLimit` for each format, e.g., at the end of a run, and resets the
+// counts.
+func FlushRepeats() {
+	repeatMu.Lock()
+	limit := repeatLimit
+	counts := repeats
+	order := repeatOrder
+	repeats = make(map[repeatKey]*repeatCount)
+	repeatOrder = nil
+	repeatMu.Unlock()
+
+	for _, key := range order {
+		if c := counts[key]; c.count > limit {
+			(&Logger{module: key.module}).write(LevelError, fmt.Sprintf("Suppressed %d more error(s) like: %s", c.count-limit, c.first))
+		}
+	}
+}
+
+// Logger logs entries on behalf of a single module.
+type Logger struct {
+	module string
+}
+
+// New returns a logger for the given module, whose entries sinks can tell
+// apart from those of other modules.
+func New(module string) *Logger {
+	return &Logger{module: module}
+}
+
+// root logs the entries of the package-level functions.
+var root = &Logger{}
+
+func (lg *Logger) write(l Level, message string) (int, error) {
+	if l < minLevel {
+		return 0, nil
+	}
+	if err := sink.Write(Entry{Level: l, Module: lg.module, Message: message}); err != nil {
+		return 0, err
+	}
+	return len(message), nil
+}
+
+func (lg *Logger) fatal(message string) {
+	FlushRepeats()
+	if sinkName != SinkStd || lineFormat == FormatJSON {
+		lg.write(LevelFatal, message)
+		os.Exit(1)
+	}
+	log.Fatal(Entry{Level: LevelFatal, Module: lg.module, Message: message}.Line())
+}
+
+// Errorf outputs an error log line with a formatting string.
+func (lg *Logger) Errorf(format string, a ...interface{}) (int, error) {
+	return lg.writeError(format, fmt.Sprintf(format+"\n", a...))
+}
+
+// Error outputs an error log line without a formatting string.
+func (lg *Logger) Error(a ...interface{}) (int, error) {
+	message := fmt.Sprintln(a...)
+	return lg.writeError(message, message)
+}
+
+// errorCount is the number of error entries logged, including those
+// suppressed as repeats.
+var errorCount int64
+
+// ErrorCount returns the number of error entries logged so far by any module,
+// including those suppressed by `SetRepeatLimit`, e.g., for a summary of the
+// run.
+func ErrorCount() int {
+	return int(atomic.LoadInt64(&errorCount))
+}
+
+// writeError outputs an error log line, unless it is suppressed as a repeat of
+// others with the same format; see `SetRepeatLimit`.
+func (lg *Logger) writeError(format string, message string) (int, error) {
+	atomic.AddInt64(&errorCount, 1)
+	suppressed, notice := lg.countRepeat(format, message)
+	if notice != "" {
+		lg.write(LevelError, notice)
+	}
+	if suppressed {
+		return 0, nil
+	}
+	return lg.write(LevelError, message)
+}
+
+// Debugf outputs a debug log line with a formatting string.
+func (lg *Logger) Debugf(format string, a ...interface{}) (int, error) {
+	if quiet {
+		return 0, nil
+	}
+	return lg.write(LevelDebug, fmt.Sprintf(format+"\n", a...))
+}
+
+// Debug outputs a debug log line without a formatting string.
+func (lg *Logger) Debug(a ...interface{}) (int, error) {
+	if quiet {
+		return 0, nil
+	}
+	return lg.write(LevelDebug, fmt.Sprintln(a...))
+}
+
+// Infof outputs an info log line with a formatting string.
+func (lg *Logger) Infof(format string, a ...interface{}) (int, error) {
+	if quiet {
+		return 0, nil
+	}
+	return lg.write(LevelInfo, fmt.Sprintf(format+"\n", a...))
+}
+
+// Info outputs an info log line without a formatting string.
+func (lg *Logger) Info(a ...interface{}) (int, error) {
+	if quiet {
+		return 0, nil
+	}
+	return lg.write(LevelInfo, fmt.Sprintln(a...))
+}
+
+// Fatalf outputs a fatal log line with a formatting string, and exits.
+func (lg *Logger) Fatalf(format string, a ...interface{}) {
+	lg.fatal(fmt.Sprintf(format+"\n", a...))
+}
+
+// Fatal outputs a fatal log line without a formatting string, and exits.
+func (lg *Logger) Fatal(a ...interface{}) {
+	lg.fatal(fmt.Sprintln(a...))
+}
+
 // Errorf outputs an error log line with a formatting string.
 func Errorf(format string, a ...interface{}) (int, error) {
-	return fmt.Fprintf(os.Stderr, format+"\n", a...)
+	return root.Errorf(format, a...)
 }
 
 // Error outputs an error log line without a formatting string.
 func Error(a ...interface{}) (int, error) {
-	return fmt.Fprintln(os.Stderr, a...)
+	return root.Error(a...)
+}
+
+// Debugf outputs a debug log line with a formatting string.
+func Debugf(format string, a ...interface{}) (int, error) {
+	return root.Debugf(format, a...)
+}
+
+// Debug outputs a debug log line without a formatting string.
+func Debug(a ...interface{}) (int, error) {
+	return root.Debug(a...)
 }
 
 // Infof outputs an info log line with a formatting string.
 func Infof(format string, a ...interface{}) (int, error) {
-	return fmt.Printf(format+"\n", a...)
+	return root.Infof(format, a...)
 }
 
 // Info outputs an info log line without a formatting string.
 func Info(a ...interface{}) (int, error) {
-	return fmt.Println(a...)
+	return root.Info(a...)
 }
 
 // Fatalf outputs a fatal log line with a formatting string.
 func Fatalf(format string, a ...interface{}) {
-	log.Fatalf(format+"\n", a...)
+	root.Fatalf(format, a...)
 }
 
 // Fatal outputs a fatal log line without a formatting string.
 func Fatal(a ...interface{}) {
-	log.Fatal(a...)
+	root.Fatal(a...)
 }
@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureOutput(t *testing.T, sink string) (*bytes.Buffer, *bytes.Buffer, func()) {
+	var out, errOut bytes.Buffer
+	savedStdout, savedStderr := stdout, stderr
+	stdout, stderr = &out, &errOut
+	if err := SetSink(sink, "test"); err != nil {
+		t.Fatal(err)
+	}
+	return &out, &errOut, func() {
+		stdout, stderr = savedStdout, savedStderr
+		SetSink(SinkStd, "")
+	}
+}
+
+func TestStdSink(t *testing.T) {
+	out, errOut, restore := captureOutput(t, SinkStd)
+	defer restore()
+
+	Infof("info %d", 1)
+	Errorf("error %d", 2)
+	assert.Equal(t, "info 1\n", out.String())
+	assert.Equal(t, "error 2\n", errOut.String())
+}
+
+func TestJournaldSink(t *testing.T) {
+	out, errOut, restore := captureOutput(t, SinkJournald)
+	defer restore()
+
+	Info("info", "line")
+	Errorf("first\nsecond")
+	assert.Equal(t, "<6>info line\n", out.String())
+	assert.Equal(t, "<3>first\n<3>second\n", errOut.String())
+}
+
+func TestSetSink_Unknown(t *testing.T) {
+	assert.NotNil(t, SetSink("nowhere", "test"))
+	assert.Equal(t, SinkStd, sinkName)
+}
+
+func TestSetQuiet(t *testing.T) {
+	out, errOut, restore := captureOutput(t, SinkStd)
+	defer restore()
+	SetQuiet(true)
+	defer SetQuiet(false)
+
+	Infof("info")
+	Errorf("error")
+	assert.Equal(t, "", out.String())
+	assert.Equal(t, "error\n", errOut.String())
+}
+
+func TestStackdriverSink(t *testing.T) {
+	out, _, restore := captureOutput(t, SinkStackdriver)
+	defer restore()
+
+	Infof("info %d", 1)
+	New("ghutil").Errorf("error")
+	assert.Equal(t, `{"severity":"INFO","message":"info 1"}`+"\n"+
+		`{"severity":"ERROR","message":"error","logging.googleapis.com/labels":{"module":"ghutil"}}`+"\n", out.String())
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "crbot.log")
+
+	sink, err := newFileSink(filename)
+	assert.Nil(t, err)
+	sink.now = func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC) }
+	assert.Nil(t, sink.Write(Entry{Level: LevelInfo, Message: "first\nsecond\n"}))
+	assert.Nil(t, sink.Write(Entry{Level: LevelError, Message: "error\n"}))
+
+	contents, err := ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "2026-03-04T05:06:07Z INFO  first\n2026-03-04T05:06:07Z INFO  second\n"+
+		"2026-03-04T05:06:07Z ERROR error\n", string(contents))
+
+	assert.NotNil(t, SetSink(SinkFilePrefix, "test"))
+}
+
+func TestUse_CustomSinkByModule(t *testing.T) {
+	var entries []Entry
+	Use(SinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	defer SetSink(SinkStd, "")
+
+	New("ghutil").Infof("processing %d", 42)
+	Error("failed")
+	assert.Equal(t, []Entry{
+		{Level: LevelInfo, Module: "ghutil", Message: "processing 42\n"},
+		{Level: LevelError, Message: "failed\n"},
+	}, entries)
+}
+
+func TestCurrentSink_Wrapped(t *testing.T) {
+	out, _, restore := captureOutput(t, SinkStd)
+	defer restore()
+
+	wrapped := CurrentSink()
+	Use(SinkFunc(func(entry Entry) error {
+		entry.Message = "> " + entry.Message
+		return wrapped.Write(entry)
+	}))
+	Infof("info")
+	assert.Equal(t, "> info\n", out.String())
+}
+
+func TestSetModulePrefixes(t *testing.T) {
+	out, _, restore := captureOutput(t, SinkStd)
+	defer restore()
+	SetModulePrefixes(true)
+	defer SetModulePrefixes(false)
+
+	New("ghutil").Info("info")
+	Info("root")
+	assert.Equal(t, "[ghutil] info\nroot\n", out.String())
+}
+
+func TestRegisterSink(t *testing.T) {
+	var messages []string
+	RegisterSink("test-sink", func(tag string) (Sink, error) {
+		return SinkFunc(func(entry Entry) error {
+			messages = append(messages, tag+": "+entry.Message)
+			return nil
+		}), nil
+	})
+	assert.Contains(t, Sinks, "test-sink")
+	assert.Panics(t, func() { RegisterSink("test-sink", nil) })
+
+	assert.Nil(t, SetSink("test-sink", "crbot"))
+	defer SetSink(SinkStd, "")
+	Infof("hello")
+	assert.Equal(t, []string{"crbot: hello\n"}, messages)
+}
+
+func TestSetLevel(t *testing.T) {
+	out, errOut, restore := captureOutput(t, SinkStd)
+	defer restore()
+
+	Debugf("hidden")
+	SetLevel(LevelDebug)
+	Debugf("debug %d", 1)
+	SetLevel(LevelError)
+	Infof("info")
+	Errorf("error")
+	SetLevel(LevelInfo)
+	assert.Equal(t, "debug 1\n", out.String())
+	assert.Equal(t, "error\n", errOut.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "error": LevelError} {
+		level, err := ParseLevel(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, level)
+	}
+	_, err := ParseLevel("verbose")
+	assert.NotNil(t, err)
+}
+
+func TestSetFormat_JSON(t *testing.T) {
+	out, errOut, restore := captureOutput(t, SinkStd)
+	defer restore()
+	assert.Nil(t, SetFormat(FormatJSON))
+	defer SetFormat(FormatText)
+
+	Infof("info %d", 1)
+	New("ghutil").Errorf("first\nsecond")
+	assert.Equal(t, `{"level":"INFO","message":"info 1"}`+"\n", out.String())
+	assert.Equal(t, `{"level":"ERROR","module":"ghutil","message":"first\nsecond"}`+"\n", errOut.String())
+
+	assert.NotNil(t, SetFormat("xml"))
+	assert.Equal(t, FormatJSON, lineFormat)
+}
+
+func TestSetRepeatLimit(t *testing.T) {
+	_, errOut, restore := captureOutput(t, SinkStd)
+	defer restore()
+	SetRepeatLimit(2)
+	defer SetRepeatLimit(0)
+
+	logger := New("ghutil")
+	for i := 1; i <= 5; i++ {
+		logger.Errorf("Repo 'org/repo%d' is missing label [%s]", i, "cla: yes")
+	}
+	logger.Errorf("Other error")
+	// Errors with the same format from other modules are counted separately.
+	Errorf("Repo 'org/repo%d' is missing label [%s]", 6, "cla: yes")
+	FlushRepeats()
+
+	assert.Equal(t, "Repo 'org/repo1' is missing label [cla: yes]\n"+
+		"Repo 'org/repo2' is missing label [cla: yes]\n"+
+		"Suppressing further errors like: Repo 'org/repo1' is missing label [cla: yes]\n"+
+		"Other error\n"+
+		"Repo 'org/repo6' is missing label [cla: yes]\n"+
+		"Suppressed 3 more error(s) like: Repo 'org/repo1' is missing label [cla: yes]\n", errOut.String())
+
+	// The counts are reset once flushed.
+	errOut.Reset()
+	logger.Errorf("Repo 'org/repo%d' is missing label [%s]", 7, "cla: yes")
+	FlushRepeats()
+	assert.Equal(t, "Repo 'org/repo7' is missing label [cla: yes]\n", errOut.String())
+}
+
+func TestErrorCount(t *testing.T) {
+	_, _, restore := captureOutput(t, SinkStd)
+	defer restore()
+	SetRepeatLimit(1)
+	defer SetRepeatLimit(0)
+
+	before := ErrorCount()
+	logger := New("ghutil")
+	for i := 1; i <= 3; i++ {
+		logger.Errorf("Error processing repo 'org/repo%d'", i)
+	}
+	Infof("Not an error")
+	FlushRepeats()
+	// Suppressed errors are counted, but not the line reporting them.
+	assert.Equal(t, 3, ErrorCount()-before)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stackdriverEntry is a log entry in the structured format which the logging
+// agents of Google Cloud parse from the output of the workloads they run.
+type stackdriverEntry struct {
+	Severity string            `json:"severity"`
+	Message  string            `json:"message"`
+	Labels   map[string]string `json:"logging.googleapis.com/labels,omitempty"`
+}
+
+// writeStackdriver writes the entry to stdout as a single line of JSON, with
+// its module, if any, as a label.
+func writeStackdriver(entry Entry) error {
+	structured := stackdriverEntry{
+		Severity: entry.Level.String(),
+		Message:  strings.TrimSuffix(entry.Message, "\n"),
+	}
+	if entry.Module != "" {
+		structured.Labels = map[string]string{"module": entry.Module}
+	}
+	line, err := json.Marshal(structured)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(append(line, '\n'))
+	return err
+}
+
+// fileSink appends entries to a file, each line prefixed with its time and
+// level.
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	now  func() time.Time
+}
+
+func newFileSink(filename string) (*fileSink, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("logging sink '%s' requires a path", SinkFilePrefix)
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file, now: time.Now}, nil
+}
+
+// Write appends the entry to the file.
+func (s *fileSink) Write(entry Entry) error {
+	prefix := fmt.Sprintf("%s %-5s ", s.now().UTC().Format(time.RFC3339), entry.Level)
+	lines := strings.Split(strings.TrimSuffix(entry.Line(), "\n"), "\n")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.file.WriteString(prefix + strings.Join(lines, "\n"+prefix) + "\n")
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logging
+
+import (
+	"log/syslog"
+	"strings"
+)
+
+// newSyslogSink connects to the local syslog daemon and returns a `Sink`
+// writing log lines to it with the priority matching their level.
+func newSyslogSink(tag string) (Sink, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return SinkFunc(func(entry Entry) error {
+		message := strings.TrimSuffix(entry.Line(), "\n")
+		switch entry.Level {
+		case LevelDebug:
+			return writer.Debug(message)
+		case LevelInfo:
+			return writer.Info(message)
+		case LevelError:
+			return writer.Err(message)
+		default:
+			return writer.Crit(message)
+		}
+	}), nil
+}
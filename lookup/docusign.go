@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jws"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// ServiceDocuSign is the name of the DocuSign provider in the `service`
+// setting of the signer lookup config, which confirms individual CLAs from
+// the envelopes they were signed in.
+const ServiceDocuSign = "docusign"
+
+// DefaultDocuSignTokenURL is the token endpoint of DocuSign's production
+// environment; that of the developer environment is
+// "https://account-d.docusign.com/oauth/token".
+const DefaultDocuSignTokenURL = "https://account.docusign.com/oauth/token"
+
+// docuSignFromDate is the start of the period searched for envelopes, which
+// the API requires; it predates any CLA signed via DocuSign.
+const docuSignFromDate = "2000-01-01"
+
+// DocuSignLookup considers identities to have signed the individual CLA if
+// their email is that of a signer of a completed DocuSign envelope, for
+// organizations whose CLA intake happens via DocuSign. Only envelopes whose
+// email subject contains the configured text count, so that other documents
+// sent from the same account don't.
+type DocuSignLookup struct {
+	client     *http.Client
+	accountURL string
+	subject    string
+}
+
+// NewDocuSignLookup returns a lookup searching the envelopes of the DocuSign
+// account at `accountURL`, its base URI in the eSignature REST API (e.g.,
+// "https://na3.docusign.net/restapi/v2.1/accounts/ACCOUNT_ID"), via the
+// client, which must authenticate requests (see `NewDocuSignClient`).
+func NewDocuSignLookup(client *http.Client, accountURL string, envelopeSubject string) *DocuSignLookup {
+	return &DocuSignLookup{
+		client:     client,
+		accountURL: strings.TrimSuffix(accountURL, "/"),
+		subject:    envelopeSubject,
+	}
+}
+
+type docuSignSigner struct {
+	Email  string `json:"email"`
+	Status string `json:"status"`
+}
+
+type docuSignEnvelope struct {
+	Status       string `json:"status"`
+	EmailSubject string `json:"emailSubject"`
+	Recipients   struct {
+		Signers []docuSignSigner `json:"signers"`
+	} `json:"recipients"`
+}
+
+type docuSignEnvelopesResponse struct {
+	Envelopes    []docuSignEnvelope `json:"envelopes"`
+	TotalSetSize string             `json:"totalSetSize"`
+}
+
+// Lookup searches the completed envelopes for one the account's email signed.
+func (l *DocuSignLookup) Lookup(ctx context.Context, account config.Account) (Result, error) {
+	if account.Email == "" {
+		return Result{}, nil
+	}
+	start := 0
+	for {
+		query := url.Values{}
+		query.Set("from_date", docuSignFromDate)
+		query.Set("status", "completed")
+		query.Set("search_text", account.Email)
+		query.Set("include", "recipients")
+		query.Set("start_position", strconv.Itoa(start))
+		response, err := l.listEnvelopes(ctx, query)
+		if err != nil {
+			return Result{}, fmt.Errorf("error looking up signer <%s> in DocuSign: %s", account.Email, err)
+		}
+		for _, envelope := range response.Envelopes {
+			if l.signedBy(envelope, account.Email) {
+				return Result{Signed: true}, nil
+			}
+		}
+		start += len(response.Envelopes)
+		if total, _ := strconv.Atoi(response.TotalSetSize); len(response.Envelopes) == 0 || start >= total {
+			return Result{}, nil
+		}
+	}
+}
+
+// signedBy returns whether the envelope is a completed CLA which was signed by
+// the email.
+func (l *DocuSignLookup) signedBy(envelope docuSignEnvelope, email string) bool {
+	if envelope.Status != "completed" || !strings.Contains(strings.ToLower(envelope.EmailSubject), strings.ToLower(l.subject)) {
+		return false
+	}
+	for _, signer := range envelope.Recipients.Signers {
+		if strings.EqualFold(signer.Email, email) && signer.Status == "completed" {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *DocuSignLookup) listEnvelopes(ctx context.Context, query url.Values) (docuSignEnvelopesResponse, error) {
+	var response docuSignEnvelopesResponse
+	req, err := http.NewRequest("GET", l.accountURL+"/envelopes?"+query.Encode(), nil)
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := l.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return response, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return response, fmt.Errorf("error parsing response: %s", err)
+	}
+	return response, nil
+}
+
+// docuSignKey is the credentials file of a DocuSign integration using JWT
+// grant authentication: the integration key of the app, the ID of the user it
+// impersonates, who must have consented to it, and the app's RSA private key
+// in PEM form. `TokenURL` defaults to `DefaultDocuSignTokenURL`.
+type docuSignKey struct {
+	IntegrationKey string `json:"integration_key"`
+	UserID         string `json:"user_id"`
+	PrivateKey     string `json:"private_key"`
+	TokenURL       string `json:"token_url"`
+}
+
+// NewDocuSignClient returns an HTTP client authenticating its requests with
+// tokens obtained via JWT grant with the credentials file (see `docuSignKey`).
+func NewDocuSignClient(ctx context.Context, credentialsFile string) (*http.Client, error) {
+	data, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	var key docuSignKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("error parsing credentials file '%s': %s", credentialsFile, err)
+	}
+	if key.IntegrationKey == "" || key.UserID == "" {
+		return nil, fmt.Errorf("credentials file '%s' requires integration_key and user_id", credentialsFile)
+	}
+	privateKey, err := parsePrivateKey([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key in '%s': %s", credentialsFile, err)
+	}
+	if key.TokenURL == "" {
+		key.TokenURL = DefaultDocuSignTokenURL
+	}
+	tokenURL, err := url.Parse(key.TokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token_url in '%s': %s", credentialsFile, err)
+	}
+	source := &docuSignTokenSource{ctx: ctx, key: key, privateKey: privateKey, audience: tokenURL.Host}
+	return oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, source)), nil
+}
+
+// parsePrivateKey parses an RSA private key in PKCS #1 or PKCS #8 PEM form.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return key, nil
+}
+
+// docuSignTokenSource obtains tokens via DocuSign's JWT grant, whose audience
+// is the host of the token endpoint rather than its URL, as the generic JWT
+// flow of the oauth2 package assumes.
+type docuSignTokenSource struct {
+	ctx        context.Context
+	key        docuSignKey
+	privateKey *rsa.PrivateKey
+	audience   string
+}
+
+// Token fetches a new token from the token endpoint.
+func (s *docuSignTokenSource) Token() (*oauth2.Token, error) {
+	now := time.Now()
+	assertion, err := jws.Encode(&jws.Header{Algorithm: "RS256", Typ: "JWT"}, &jws.ClaimSet{
+		Iss:   s.key.IntegrationKey,
+		Sub:   s.key.UserID,
+		Aud:   s.audience,
+		Scope: "signature impersonation",
+		Iat:   now.Unix(),
+		Exp:   now.Add(time.Hour).Unix(),
+	}, s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequest("POST", s.key.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req.WithContext(s.ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching DocuSign token: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching DocuSign token: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("error parsing DocuSign token: %s", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      now.Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
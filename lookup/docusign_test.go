@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+)
+
+func TestDocuSignLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/accounts/123/envelopes", req.URL.Path)
+		assert.Equal(t, "completed", req.URL.Query().Get("status"))
+		assert.Equal(t, "recipients", req.URL.Query().Get("include"))
+		switch req.URL.Query().Get("search_text") + "@" + req.URL.Query().Get("start_position") {
+		case "john@example.com@0":
+			// The first page only has other documents John signed.
+			w.Write([]byte(`{"totalSetSize": "2", "envelopes": [
+				{"status": "completed", "emailSubject": "NDA", "recipients": {"signers": [{"email": "john@example.com", "status": "completed"}]}}
+			]}`))
+		case "john@example.com@1":
+			w.Write([]byte(`{"totalSetSize": "2", "envelopes": [
+				{"status": "completed", "emailSubject": "Please sign the Individual CLA", "recipients": {"signers": [{"email": "John@Example.com", "status": "completed"}]}}
+			]}`))
+		case "jane@example.com@0":
+			// Jane was only CC'd on John's CLA.
+			w.Write([]byte(`{"totalSetSize": "1", "envelopes": [
+				{"status": "completed", "emailSubject": "Individual CLA", "recipients": {"signers": [{"email": "john@example.com", "status": "completed"}]}}
+			]}`))
+		default:
+			w.Write([]byte(`{"totalSetSize": "0"}`))
+		}
+	}))
+	defer server.Close()
+
+	docuSignLookup := NewDocuSignLookup(server.Client(), server.URL+"/accounts/123/", "individual cla")
+	ctx := context.Background()
+
+	result, err := docuSignLookup.Lookup(ctx, john)
+	assert.Nil(t, err)
+	assert.Equal(t, Result{Signed: true}, result)
+
+	result, err = docuSignLookup.Lookup(ctx, config.Account{Name: "Jane Doe", Email: "jane@example.com", Login: "jane"})
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+
+	result, err = docuSignLookup.Lookup(ctx, config.Account{Name: "Nobody", Email: "nobody@example.com", Login: "nobody"})
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+}
+
+func TestDocuSignLookup_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errorCode": "USER_AUTHENTICATION_FAILED"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewDocuSignLookup(server.Client(), server.URL, "CLA").Lookup(context.Background(), john)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "USER_AUTHENTICATION_FAILED")
+}
+
+func TestNewDocuSignClient(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/oauth/token" {
+			assert.Nil(t, req.ParseForm())
+			// The audience is the host of the token endpoint.
+			parts := strings.Split(req.Form.Get("assertion"), ".")
+			if assert.Len(t, parts, 3) {
+				claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+				assert.Nil(t, err)
+				assert.Contains(t, string(claims), `"aud":"`+strings.TrimPrefix(server.URL, "http://")+`"`)
+				assert.Contains(t, string(claims), `"sub":"user-id"`)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "docusign-token", "token_type": "Bearer", "expires_in": 3600}`))
+			return
+		}
+		assert.Equal(t, "Bearer docusign-token", req.Header.Get("Authorization"))
+		w.Write([]byte(`{"totalSetSize": "0"}`))
+	}))
+	defer server.Close()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	keyFile, err := json.Marshal(docuSignKey{
+		IntegrationKey: "integration-key",
+		UserID:         "user-id",
+		PrivateKey:     string(keyPEM),
+		TokenURL:       server.URL + "/oauth/token",
+	})
+	assert.Nil(t, err)
+	dir, err := ioutil.TempDir("", "docusign")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "docusign.json")
+	assert.Nil(t, ioutil.WriteFile(filename, keyFile, 0600))
+
+	client, err := NewDocuSignClient(context.Background(), filename)
+	assert.Nil(t, err)
+	result, err := NewDocuSignLookup(client, server.URL, "CLA").Lookup(context.Background(), john)
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+
+	assert.Nil(t, ioutil.WriteFile(filename, []byte(`{"integration_key": "integration-key", "user_id": "user-id", "private_key": "garbage"}`), 0600))
+	_, err = NewDocuSignClient(context.Background(), filename)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"net/http"
+)
+
+// ServiceGoogle is the name of Google's CLA service at cla.developers.google.com
+// in the `service` setting of the signer lookup config.
+const ServiceGoogle = "google"
+
+// Services lists the names of the CLA services with built-in support; an
+// empty name selects a generic `HTTPLookup`.
+var Services = []string{ServiceGoogle, ServiceOkta, ServiceDocuSign}
+
+// IsSupportedService returns whether `service` names a CLA service with
+// built-in support, or is empty.
+func IsSupportedService(service string) bool {
+	if service == "" {
+		return true
+	}
+	for _, supported := range Services {
+		if service == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// NewGoogleLookup returns a lookup querying an endpoint in front of Google's
+// CLA service at the given URL, which answers using the protocol of
+// `HTTPLookup`; the service has no public API for signer lookups, so there
+// is no default endpoint. The client must authenticate requests as a Google
+// account with access to the CLA records of the project, e.g., a service
+// account via `gcp.NewClient` with `gcp.ScopeUserInfoEmail`.
+func NewGoogleLookup(client *http.Client, url string) *HTTPLookup {
+	return NewHTTPLookup(client, url)
+}
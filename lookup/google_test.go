@@ -0,0 +1,36 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedService(t *testing.T) {
+	assert.True(t, IsSupportedService(""))
+	assert.True(t, IsSupportedService(ServiceGoogle))
+	assert.True(t, IsSupportedService(ServiceOkta))
+	assert.True(t, IsSupportedService(ServiceDocuSign))
+	assert.False(t, IsSupportedService("easycla"))
+}
+
+func TestNewGoogleLookup(t *testing.T) {
+	googleLookup := NewGoogleLookup(http.DefaultClient, "https://cla.example.com/lookup")
+	assert.Equal(t, "https://cla.example.com/lookup", googleLookup.url)
+	assert.Equal(t, "", googleLookup.token)
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lookup queries external CLA services (e.g., EasyCLA or an internal
+// CLA database) for identities which don't match any of the signers listed in
+// the CLA signers config, so that the full signer corpus need not be mirrored
+// into it.
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// Result is the answer of a CLA service about a single identity.
+type Result struct {
+	// Signed is whether the identity is covered by a CLA.
+	Signed bool `json:"signed"`
+	// Company is the name of the company through whose corporate CLA the
+	// identity is covered, if any.
+	Company string `json:"company,omitempty"`
+}
+
+// SignerLookup looks up whether an identity has signed the CLA.
+type SignerLookup interface {
+	Lookup(ctx context.Context, account config.Account) (Result, error)
+}
+
+// DefaultAuthHeader is the header carrying the token of an `HTTPLookup`,
+// which is sent as a bearer token in this header, and as is in any other.
+const DefaultAuthHeader = "Authorization"
+
+// HTTPLookup queries a CLA service over HTTP. For each identity, it sends
+//
+//	GET URL?name=NAME&email=EMAIL&login=LOGIN
+//
+// and expects either a 200 response with a JSON `Result`, or a 404 response if
+// the identity has not signed the CLA; any other response is an error.
+type HTTPLookup struct {
+	client     *http.Client
+	url        string
+	authHeader string
+	token      string
+}
+
+// NewHTTPLookup returns a lookup querying the service at the given URL via the
+// client.
+func NewHTTPLookup(client *http.Client, url string) *HTTPLookup {
+	return &HTTPLookup{
+		client:     client,
+		url:        url,
+		authHeader: DefaultAuthHeader,
+	}
+}
+
+// SetAuth sets the token to authenticate requests with, and the header to send
+// it in; if `header` is empty, `DefaultAuthHeader` is used.
+func (l *HTTPLookup) SetAuth(header string, token string) {
+	if header == "" {
+		header = DefaultAuthHeader
+	}
+	l.authHeader = header
+	l.token = token
+}
+
+// Lookup queries the service about the account.
+func (l *HTTPLookup) Lookup(ctx context.Context, account config.Account) (Result, error) {
+	query := url.Values{}
+	query.Set("name", account.Name)
+	query.Set("email", account.Email)
+	query.Set("login", account.Login)
+	lookupURL := l.url
+	if strings.Contains(lookupURL, "?") {
+		lookupURL += "&" + query.Encode()
+	} else {
+		lookupURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest("GET", lookupURL, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if l.token != "" {
+		if strings.EqualFold(l.authHeader, DefaultAuthHeader) {
+			req.Header.Set(l.authHeader, "Bearer "+l.token)
+		} else {
+			req.Header.Set(l.authHeader, l.token)
+		}
+	}
+	resp, err := l.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var result Result
+		if err := json.Unmarshal(body, &result); err != nil {
+			return Result{}, fmt.Errorf("error parsing signer lookup response for <%s>: %s", account.Email, err)
+		}
+		return result, nil
+	case http.StatusNotFound:
+		return Result{}, nil
+	default:
+		return Result{}, fmt.Errorf("error looking up signer <%s>: %s: %s", account.Email, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// CachedLookup caches the results of another lookup, so that each identity is
+// looked up at most once per TTL, no matter how many commits it authored.
+// Errors are not cached.
+type CachedLookup struct {
+	lookup SignerLookup
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	result  Result
+	expires time.Time
+}
+
+// NewCachedLookup returns a lookup caching the results of `lookup` for `ttl`.
+func NewCachedLookup(lookup SignerLookup, ttl time.Duration) *CachedLookup {
+	return &CachedLookup{
+		lookup:  lookup,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Lookup returns the cached result for the account, if it has not expired, or
+// else looks it up.
+func (c *CachedLookup) Lookup(ctx context.Context, account config.Account) (Result, error) {
+	key := strings.Join([]string{account.Name, strings.ToLower(account.Email), strings.ToLower(account.Login)}, "\x00")
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.result, nil
+	}
+
+	result, err := c.lookup.Lookup(ctx, account)
+	if err != nil {
+		return result, err
+	}
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{result: result, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return result, nil
+}
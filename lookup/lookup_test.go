@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+)
+
+var john = config.Account{
+	Name:  "John Doe",
+	Email: "john@example.com",
+	Login: "john",
+}
+
+func TestHTTPLookup_Signed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/signers", req.URL.Path)
+		assert.Equal(t, "v1", req.URL.Query().Get("api"))
+		assert.Equal(t, "John Doe", req.URL.Query().Get("name"))
+		assert.Equal(t, "john@example.com", req.URL.Query().Get("email"))
+		assert.Equal(t, "john", req.URL.Query().Get("login"))
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		w.Write([]byte(`{"signed": true, "company": "Acme"}`))
+	}))
+	defer server.Close()
+
+	signerLookup := NewHTTPLookup(server.Client(), server.URL+"/signers?api=v1")
+	signerLookup.SetAuth("", "secret")
+	result, err := signerLookup.Lookup(context.Background(), john)
+	assert.Nil(t, err)
+	assert.Equal(t, Result{Signed: true, Company: "Acme"}, result)
+}
+
+func TestHTTPLookup_CustomAuthHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "", req.Header.Get("Authorization"))
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+		w.Write([]byte(`{"signed": false}`))
+	}))
+	defer server.Close()
+
+	signerLookup := NewHTTPLookup(server.Client(), server.URL)
+	signerLookup.SetAuth("X-Api-Key", "secret")
+	result, err := signerLookup.Lookup(context.Background(), john)
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+}
+
+func TestHTTPLookup_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.NotFound(w, req)
+	}))
+	defer server.Close()
+
+	result, err := NewHTTPLookup(server.Client(), server.URL).Lookup(context.Background(), john)
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+}
+
+func TestHTTPLookup_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPLookup(server.Client(), server.URL).Lookup(context.Background(), john)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "<john@example.com>: 503 Service Unavailable: database unavailable")
+}
+
+// countingLookup counts the lookups made through it.
+type countingLookup struct {
+	count int
+	err   error
+}
+
+func (l *countingLookup) Lookup(_ context.Context, _ config.Account) (Result, error) {
+	l.count++
+	return Result{Signed: true}, l.err
+}
+
+func TestCachedLookup(t *testing.T) {
+	counter := &countingLookup{}
+	cached := NewCachedLookup(counter, time.Hour)
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		result, err := cached.Lookup(context.Background(), john)
+		assert.Nil(t, err)
+		assert.True(t, result.Signed)
+	}
+	// Emails and logins are case-insensitive.
+	_, err := cached.Lookup(context.Background(), config.Account{Name: "John Doe", Email: "JOHN@example.com", Login: "John"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, counter.count)
+
+	now = now.Add(2 * time.Hour)
+	_, err = cached.Lookup(context.Background(), john)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, counter.count)
+}
+
+func TestCachedLookup_ErrorsNotCached(t *testing.T) {
+	counter := &countingLookup{err: errors.New("unavailable")}
+	cached := NewCachedLookup(counter, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		_, err := cached.Lookup(context.Background(), john)
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, 2, counter.count)
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/config"
+)
+
+// ServiceOkta is the name of the Okta provider in the `service` setting of the
+// signer lookup config, which resolves company membership from Okta groups.
+const ServiceOkta = "okta"
+
+// OktaLookup considers identities to be covered by the corporate CLA of a
+// company if they are members of the Okta group mapped to it, so that
+// corporate rosters follow the company's identity provider rather than being
+// copied into the CLA signers config. The members of each group are listed
+// via the Okta API at most once per TTL, rather than for each identity.
+type OktaLookup struct {
+	client         *http.Client
+	baseURL        string
+	token          string
+	groups         []config.OktaGroup
+	loginAttribute string
+	ttl            time.Duration
+	now            func() time.Time
+
+	mu      sync.Mutex
+	members map[string]oktaMembers
+}
+
+// oktaMembers are the cached members of a group, keyed by lowercased email,
+// with the GitHub login from their profile, if any.
+type oktaMembers struct {
+	logins  map[string]string
+	expires time.Time
+}
+
+// oktaUser is the subset of an Okta user which is needed for matching.
+type oktaUser struct {
+	Status  string                 `json:"status"`
+	Profile map[string]interface{} `json:"profile"`
+}
+
+// NewOktaLookup returns a lookup querying the Okta org at `baseURL` (e.g.,
+// "https://example.okta.com") via the client, authenticated by the API token,
+// about the members of the groups. If `loginAttribute` is set, members must
+// also have the identity's GitHub login in that attribute of their profile.
+func NewOktaLookup(client *http.Client, baseURL string, token string, groups []config.OktaGroup, loginAttribute string, ttl time.Duration) *OktaLookup {
+	return &OktaLookup{
+		client:         client,
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		token:          token,
+		groups:         groups,
+		loginAttribute: loginAttribute,
+		ttl:            ttl,
+		now:            time.Now,
+		members:        make(map[string]oktaMembers),
+	}
+}
+
+// Lookup returns whether the account is a member of any of the groups, and
+// the company of the first such group.
+func (l *OktaLookup) Lookup(ctx context.Context, account config.Account) (Result, error) {
+	email := strings.ToLower(account.Email)
+	for _, group := range l.groups {
+		logins, err := l.groupMembers(ctx, group.ID)
+		if err != nil {
+			return Result{}, err
+		}
+		login, ok := logins[email]
+		if !ok {
+			continue
+		}
+		if l.loginAttribute != "" && !strings.EqualFold(login, account.Login) {
+			continue
+		}
+		return Result{Signed: true, Company: group.Company}, nil
+	}
+	return Result{}, nil
+}
+
+// groupMembers returns the members of the group, from the cache if they were
+// listed within the TTL.
+func (l *OktaLookup) groupMembers(ctx context.Context, groupID string) (map[string]string, error) {
+	l.mu.Lock()
+	cached, ok := l.members[groupID]
+	l.mu.Unlock()
+	if ok && l.now().Before(cached.expires) {
+		return cached.logins, nil
+	}
+
+	logins := make(map[string]string)
+	nextURL := fmt.Sprintf("%s/api/v1/groups/%s/users?limit=200", l.baseURL, url.PathEscape(groupID))
+	for nextURL != "" {
+		users, next, err := l.listUsers(ctx, nextURL)
+		if err != nil {
+			return nil, fmt.Errorf("error listing members of Okta group %s: %s", groupID, err)
+		}
+		for _, user := range users {
+			// Suspended and deprovisioned users have left the
+			// company, or are about to.
+			if user.Status == "SUSPENDED" || user.Status == "DEPROVISIONED" {
+				continue
+			}
+			email, _ := user.Profile["email"].(string)
+			if email == "" {
+				continue
+			}
+			login, _ := user.Profile[l.loginAttribute].(string)
+			logins[strings.ToLower(email)] = strings.TrimSpace(login)
+		}
+		nextURL = next
+	}
+
+	l.mu.Lock()
+	l.members[groupID] = oktaMembers{logins: logins, expires: l.now().Add(l.ttl)}
+	l.mu.Unlock()
+	return logins, nil
+}
+
+// nextLinkPattern matches the link to the next page of results in the `Link`
+// header of Okta API responses.
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// listUsers fetches a page of users, and returns them along with the URL of
+// the next page, if any.
+func (l *OktaLookup) listUsers(ctx context.Context, pageURL string) ([]oktaUser, string, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "SSWS "+l.token)
+	resp, err := l.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var users []oktaUser
+	if err := json.Unmarshal(body, &users); err != nil {
+		return nil, "", fmt.Errorf("error parsing response: %s", err)
+	}
+	next := ""
+	for _, link := range resp.Header["Link"] {
+		if match := nextLinkPattern.FindStringSubmatch(link); match != nil {
+			next = match[1]
+		}
+	}
+	return users, next, nil
+}
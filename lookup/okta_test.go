@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+)
+
+var oktaGroups = []config.OktaGroup{
+	{ID: "00g1", Company: "Acme"},
+	{ID: "00g2", Company: "Initech"},
+}
+
+// newOktaServer returns a server answering requests for the members of the
+// Okta groups, and counting them.
+func newOktaServer(t *testing.T, requests *int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*requests++
+		assert.Equal(t, "SSWS okta-token", req.Header.Get("Authorization"))
+		switch {
+		case req.URL.Path == "/api/v1/groups/00g1/users" && req.URL.Query().Get("after") == "":
+			w.Header().Set("Link", `<`+server.URL+`/api/v1/groups/00g1/users?limit=200>; rel="self", <`+server.URL+`/api/v1/groups/00g1/users?after=2&limit=200>; rel="next"`)
+			w.Write([]byte(`[
+				{"status": "ACTIVE", "profile": {"email": "alice@acme.com", "githubUsername": "alice"}},
+				{"status": "SUSPENDED", "profile": {"email": "bob@acme.com", "githubUsername": "bob"}}
+			]`))
+		case req.URL.Path == "/api/v1/groups/00g1/users":
+			w.Write([]byte(`[{"status": "ACTIVE", "profile": {"email": "John@Example.com", "githubUsername": "john"}}]`))
+		case req.URL.Path == "/api/v1/groups/00g2/users":
+			w.Write([]byte(`[{"status": "ACTIVE", "profile": {"email": "peter@initech.com"}}]`))
+		default:
+			t.Errorf("unexpected request for %s", req.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestOktaLookup(t *testing.T) {
+	requests := 0
+	server := newOktaServer(t, &requests)
+	defer server.Close()
+
+	oktaLookup := NewOktaLookup(server.Client(), server.URL+"/", "okta-token", oktaGroups, "", time.Hour)
+	ctx := context.Background()
+
+	result, err := oktaLookup.Lookup(ctx, john)
+	assert.Nil(t, err)
+	assert.Equal(t, Result{Signed: true, Company: "Acme"}, result)
+	assert.Equal(t, 2, requests)
+
+	result, err = oktaLookup.Lookup(ctx, config.Account{Name: "Peter", Email: "peter@initech.com", Login: "peter"})
+	assert.Nil(t, err)
+	assert.Equal(t, Result{Signed: true, Company: "Initech"}, result)
+
+	// Suspended members are not covered.
+	result, err = oktaLookup.Lookup(ctx, config.Account{Name: "Bob", Email: "bob@acme.com", Login: "bob"})
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+
+	// Group members are listed once per TTL.
+	assert.Equal(t, 3, requests)
+	now := time.Now().Add(2 * time.Hour)
+	oktaLookup.now = func() time.Time { return now }
+	_, err = oktaLookup.Lookup(ctx, john)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, requests)
+}
+
+func TestOktaLookup_LoginAttribute(t *testing.T) {
+	requests := 0
+	server := newOktaServer(t, &requests)
+	defer server.Close()
+
+	oktaLookup := NewOktaLookup(server.Client(), server.URL, "okta-token", oktaGroups, "githubUsername", time.Hour)
+	ctx := context.Background()
+
+	result, err := oktaLookup.Lookup(ctx, john)
+	assert.Nil(t, err)
+	assert.True(t, result.Signed)
+
+	// The login must match the profile, so that the email alone can't
+	// claim membership.
+	result, err = oktaLookup.Lookup(ctx, config.Account{Name: "John Doe", Email: "john@example.com", Login: "mallory"})
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+	result, err = oktaLookup.Lookup(ctx, config.Account{Name: "Peter", Email: "peter@initech.com", Login: "peter"})
+	assert.Nil(t, err)
+	assert.False(t, result.Signed)
+}
+
+func TestOktaLookup_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errorCode": "E0000011", "errorSummary": "Invalid token provided"}`))
+	}))
+	defer server.Close()
+
+	oktaLookup := NewOktaLookup(server.Client(), server.URL, "bad-token", oktaGroups, "", time.Hour)
+	_, err := oktaLookup.Lookup(context.Background(), john)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Invalid token provided")
+}
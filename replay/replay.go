@@ -0,0 +1,204 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay records GitHub API interactions to a "cassette" file and
+// replays them later without network access, so that real-world behavior
+// can be reproduced and turned into tests against captured data.
+//
+// Request headers (including credentials) are never recorded; requests are
+// matched on their method, URL, and body only.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Request is the recorded part of an HTTP request.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the sequence of interactions recorded during a run.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads a cassette from a file.
+func Load(filename string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("error parsing cassette '%s': %s", filename, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a file.
+func (c *Cassette) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// readBody reads and returns the body, replacing it so it can be read again.
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+// Recorder is an `http.RoundTripper` which sends requests via its base
+// transport and records each interaction.
+type Recorder struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder sending requests via `base`.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	return &Recorder{base: base}
+}
+
+// RoundTrip implements `http.RoundTripper`.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := Request{Method: req.Method, URL: req.URL.String()}
+	if req.Body != nil {
+		req = req.Clone(req.Context())
+		body, err := readBody(&req.Body)
+		if err != nil {
+			return nil, err
+		}
+		recorded.Body = body
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		},
+	})
+	return resp, nil
+}
+
+// Cassette returns the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cassette := Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+	return &cassette
+}
+
+// Replayer is an `http.RoundTripper` which answers requests from a cassette,
+// without any network access. Each recorded interaction is used once, in
+// order, so repeated requests receive their respective recorded responses.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a replayer answering requests from the cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip implements `http.RoundTripper`; requests without a matching
+// unused interaction fail.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		req = req.Clone(req.Context())
+		var err error
+		if body, err = readBody(&req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for idx, interaction := range r.cassette.Interactions {
+		recorded := interaction.Request
+		if r.used[idx] || recorded.Method != req.Method || recorded.URL != req.URL.String() || recorded.Body != body {
+			continue
+		}
+		r.used[idx] = true
+		header := http.Header{}
+		for name, values := range interaction.Response.Header {
+			header[name] = append([]string(nil), values...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+}
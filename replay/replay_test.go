@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		fmt.Fprintf(w, "%s #%d", req.URL.Path, calls)
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(http.DefaultTransport)
+	client := &http.Client{Transport: recorder}
+	get(t, client, server.URL+"/a")
+	get(t, client, server.URL+"/a")
+	resp, err := client.Post(server.URL+"/b", "text/plain", strings.NewReader("payload"))
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	dir, err := ioutil.TempDir("", "replay_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cassette.json")
+	assert.Nil(t, recorder.Cassette().Save(filename))
+
+	cassette, err := Load(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(cassette.Interactions))
+	assert.Equal(t, "payload", cassette.Interactions[2].Request.Body)
+
+	// Replay without the server; repeated requests get their own responses.
+	server.Close()
+	client = &http.Client{Transport: NewReplayer(cassette)}
+	status, body := get(t, client, server.URL+"/a")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/a #1", body)
+	_, body = get(t, client, server.URL+"/a")
+	assert.Equal(t, "/a #2", body)
+
+	_, err = client.Get(server.URL + "/a")
+	assert.NotNil(t, err, "each interaction should only be replayed once")
+	_, err = client.Post(server.URL+"/b", "text/plain", strings.NewReader("other payload"))
+	assert.NotNil(t, err, "request bodies should be matched")
+	resp, err = client.Post(server.URL+"/b", "text/plain", strings.NewReader("payload"))
+	assert.Nil(t, err)
+	assert.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+	resp.Body.Close()
+}
+
+func TestRecorder_DoesNotRecordRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	recorder := NewRecorder(http.DefaultTransport)
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Authorization", "token s3cr3t")
+	resp, err := (&http.Client{Transport: recorder}).Do(req)
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	dir, err := ioutil.TempDir("", "replay_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cassette.json")
+	assert.Nil(t, recorder.Cassette().Save(filename))
+	data, err := ioutil.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Contributor is a distinct identity which failed CLA matching in one or more
+// commits, along with the roles in which it appeared and the pull requests
+// affected, for use by CLA administrators as an outreach list.
+type Contributor struct {
+	Name         string
+	Email        string
+	Login        string
+	Roles        []string
+	PullRequests []string
+}
+
+// NonCompliantContributors aggregates all of the distinct identities which
+// failed matching across the report, sorted by login and then email.
+// Identities are considered the same if their names, emails, and logins match
+// case-insensitively.
+func NonCompliantContributors(r *Report) []Contributor {
+	byKey := make(map[string]*Contributor)
+	var keys []string
+
+	for _, pr := range r.PullRequests {
+		prName := fmt.Sprintf("%s/%s#%d", pr.Org, pr.Repo, pr.Number)
+		for _, commit := range pr.Commits {
+			for _, identity := range commit.Unmatched {
+				key := strings.ToLower(strings.Join([]string{identity.Login, identity.Email, identity.Name}, "\x00"))
+				contributor, ok := byKey[key]
+				if !ok {
+					contributor = &Contributor{
+						Name:  identity.Name,
+						Email: identity.Email,
+						Login: identity.Login,
+					}
+					byKey[key] = contributor
+					keys = append(keys, key)
+				}
+				contributor.Roles = appendUnique(contributor.Roles, identity.Role)
+				contributor.PullRequests = appendUnique(contributor.PullRequests, prName)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	contributors := make([]Contributor, 0, len(keys))
+	for _, key := range keys {
+		contributors = append(contributors, *byKey[key])
+	}
+	return contributors
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// WriteContributorsCSV renders the non-compliant contributors in the report
+// as CSV with a header row; multiple roles and pull requests are separated by
+// spaces within their respective columns.
+func WriteContributorsCSV(w io.Writer, r *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"name", "email", "login", "roles", "pull_requests"}); err != nil {
+		return err
+	}
+	for _, contributor := range NonCompliantContributors(r) {
+		row := []string{
+			contributor.Name,
+			contributor.Email,
+			contributor.Login,
+			strings.Join(contributor.Roles, " "),
+			strings.Join(contributor.PullRequests, " "),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonCompliantContributors_Aggregates(t *testing.T) {
+	jane := Identity{Role: "author", Name: "Jane Doe", Email: "jane@example.com", Login: "jane"}
+	janeCommitter := Identity{Role: "committer", Name: "Jane Doe", Email: "Jane@Example.com", Login: "Jane"}
+	john := Identity{Role: "author", Name: "John Doe", Email: "john@example.com", Login: "john"}
+
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 1, Commits: []Commit{
+		{SHA: "1", Unmatched: []Identity{jane}},
+		{SHA: "2", Unmatched: []Identity{jane, janeCommitter}},
+	}})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 2, Commits: []Commit{
+		{SHA: "3", Unmatched: []Identity{john}},
+		{SHA: "4", Unmatched: []Identity{jane}},
+	}})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 3, Compliant: true, Commits: []Commit{
+		{SHA: "5", Compliant: true},
+	}})
+
+	contributors := NonCompliantContributors(r)
+	assert.Equal(t, []Contributor{
+		{
+			Name:         "Jane Doe",
+			Email:        "jane@example.com",
+			Login:        "jane",
+			Roles:        []string{"author", "committer"},
+			PullRequests: []string{"org/a#1", "org/b#2"},
+		},
+		{
+			Name:         "John Doe",
+			Email:        "john@example.com",
+			Login:        "john",
+			Roles:        []string{"author"},
+			PullRequests: []string{"org/b#2"},
+		},
+	}, contributors)
+}
+
+func TestWriteContributorsCSV(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 1, Commits: []Commit{
+		{SHA: "1", Unmatched: []Identity{{Role: "author", Name: "Jane Doe", Email: "jane@example.com", Login: "jane"}}},
+	}})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteContributorsCSV(&buf, r))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "email", "login", "roles", "pull_requests"},
+		{"Jane Doe", "jane@example.com", "jane", "author", "org/a#1"},
+	}, rows)
+}
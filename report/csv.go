@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{
+	"org", "repo", "pr", "title", "url", "pr_status", "pr_reason",
+	"commit", "commit_status", "commit_reason",
+	"company", "agreement_id", "contact_email", "signed_date", "managed_by",
+}
+
+// WriteCSV renders the report as CSV with a header row, followed by one row
+// per commit. Pull requests without any evaluated commits (e.g., those
+// determined to be external before any commits were checked) are rendered as
+// a single row with empty commit columns.
+func WriteCSV(w io.Writer, r *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, pr := range r.PullRequests {
+		prColumns := []string{
+			pr.Org, pr.Repo, strconv.Itoa(pr.Number), pr.Title, pr.URL, pr.Status(), strings.Join(pr.Reasons(), "; "),
+		}
+		if len(pr.Commits) == 0 {
+			if err := writer.Write(append(prColumns, "", "", "", "", "", "", "", pr.ManagedBy)); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, commit := range pr.Commits {
+			reason := commit.Reason
+			if commit.Exemption != "" {
+				reason = commit.Exemption
+			}
+			row := append(append([]string{}, prColumns...), commit.SHA, commit.Status(), reason,
+				commit.Company, commit.Agreement.ID, commit.Agreement.ContactEmail, commit.Agreement.SignedDate, pr.ManagedBy)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV_OneRowPerCommit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteCSV(&buf, newTestReport()))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(rows), "Expected header and 3 commit rows: %v", rows)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"org", "repo", "42", "Fix all the things", "", StatusNonCompliant,
+		"Author of one or more commits is not listed as a CLA signer",
+		"bbb222", StatusNonCompliant, "Author of one or more commits is not listed as a CLA signer",
+		"", "", "", "", ""}, rows[2])
+	assert.Equal(t, "ccc333", rows[3][7])
+}
+
+func TestWriteCSV_PullWithoutCommits(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "repo", Number: 7, External: true, ManagedBy: "EasyCLA"})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteCSV(&buf, r))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, StatusExternal, rows[1][5])
+	assert.Equal(t, "", rows[1][7])
+	assert.Equal(t, "EasyCLA", rows[1][14])
+}
+
+func TestWriteCSV_ExemptCommit(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{
+		Org: "org", Repo: "repo", Number: 7, Compliant: true,
+		Commits: []Commit{{SHA: "aaa111", Compliant: true, Exemption: "Vendored import"}},
+	})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteCSV(&buf, r))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"aaa111", StatusExempted, "Vendored import"}, rows[1][7:10])
+}
+
+func TestWriteCSV_CompanyAgreement(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{
+		Org: "org", Repo: "repo", Number: 7, Compliant: true,
+		Commits: []Commit{{
+			SHA: "aaa111", Compliant: true, Company: "Acme",
+			Agreement: Agreement{ID: "CCLA-1234", ContactEmail: "legal@acme.com", SignedDate: "2019-05-01"},
+		}},
+	})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteCSV(&buf, r))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Acme", "CCLA-1234", "legal@acme.com", "2019-05-01"}, rows[1][10:14])
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot records the compliance status of each pull request as of a run, so
+// that the next digest can report what changed since; pull requests are keyed
+// by "org/repo#number".
+type Snapshot struct {
+	Timestamp    time.Time         `json:"timestamp"`
+	PullRequests map[string]string `json:"pull_requests"`
+}
+
+// StatusChange is a pull request whose compliance status changed from
+// `Previous` since the last snapshot.
+type StatusChange struct {
+	PullRequest PullRequest
+	Previous    string
+}
+
+// MissingLabels lists the CLA labels which are not defined in a repo, so that
+// the pull requests in it can't be labeled.
+type MissingLabels struct {
+	Repo   string
+	Labels []string
+}
+
+// Digest summarizes the changes in compliance since the last snapshot, if
+// any: the pull requests whose status changed, the external pull requests not
+// seen before, and the repos missing CLA labels.
+type Digest struct {
+	Timestamp     time.Time
+	Since         *time.Time
+	Counts        Counts
+	Changes       []StatusChange
+	NewExternal   []PullRequest
+	MissingLabels []MissingLabels
+}
+
+// pullRequestKey identifies the pull request in a `Snapshot`.
+func pullRequestKey(pr PullRequest) string {
+	return fmt.Sprintf("%s/%s#%d", pr.Org, pr.Repo, pr.Number)
+}
+
+// TakeSnapshot records the status of each pull request in the report.
+func TakeSnapshot(r *Report, now time.Time) Snapshot {
+	snapshot := Snapshot{Timestamp: now, PullRequests: make(map[string]string)}
+	for _, pr := range r.PullRequests {
+		snapshot.PullRequests[pullRequestKey(pr)] = pr.Status()
+	}
+	return snapshot
+}
+
+// ComputeDigest compares the report against the previous snapshot, if
+// non-nil; without one, all external pull requests are considered new.
+func ComputeDigest(r *Report, previous *Snapshot, missingLabels []MissingLabels, now time.Time) Digest {
+	digest := Digest{Timestamp: now, MissingLabels: missingLabels}
+	if previous != nil {
+		since := previous.Timestamp
+		digest.Since = &since
+	}
+	for _, pr := range r.PullRequests {
+		status := pr.Status()
+		digest.Counts.add(status)
+
+		var previousStatus string
+		if previous != nil {
+			previousStatus = previous.PullRequests[pullRequestKey(pr)]
+		}
+		if previousStatus == "" {
+			if status == StatusExternal {
+				digest.NewExternal = append(digest.NewExternal, pr)
+			}
+		} else if previousStatus != status {
+			digest.Changes = append(digest.Changes, StatusChange{PullRequest: pr, Previous: previousStatus})
+		}
+	}
+	sort.Slice(digest.MissingLabels, func(i, j int) bool { return digest.MissingLabels[i].Repo < digest.MissingLabels[j].Repo })
+	return digest
+}
+
+// ReadSnapshot reads a snapshot previously written by `WriteSnapshotJSON`.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// WriteSnapshotJSON renders the snapshot as JSON.
+func WriteSnapshotJSON(w io.Writer, snapshot Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// digestFormatter renders the parts of a digest which differ between markup
+// languages.
+type digestFormatter struct {
+	heading     func(string) string
+	bold        func(string) string
+	code        func(string) string
+	pullRequest func(PullRequest) string
+}
+
+var markdownDigestFormatter = digestFormatter{
+	heading:     func(s string) string { return "## " + s },
+	bold:        func(s string) string { return "**" + s + "**" },
+	code:        func(s string) string { return "`" + strings.ReplaceAll(s, "`", "'") + "`" },
+	pullRequest: markdownPullRequest,
+}
+
+// slackEscaper escapes the only characters with special meaning in Slack
+// message text.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+var slackDigestFormatter = digestFormatter{
+	heading: func(s string) string { return "*" + s + "*" },
+	bold:    func(s string) string { return "*" + s + "*" },
+	code:    func(s string) string { return "`" + slackEscaper.Replace(strings.ReplaceAll(s, "`", "'")) + "`" },
+	pullRequest: func(pr PullRequest) string {
+		ref := slackEscaper.Replace(pullRequestKey(pr))
+		if pr.URL != "" {
+			ref = fmt.Sprintf("<%s|%s>", pr.URL, ref)
+		}
+		if pr.Title != "" {
+			ref += " " + slackEscaper.Replace(pr.Title)
+		}
+		return ref
+	},
+}
+
+// WriteDigestMarkdown renders the digest as GitHub-flavored Markdown, e.g., for
+// an issue comment.
+func WriteDigestMarkdown(w io.Writer, digest Digest) error {
+	return writeDigest(w, digest, markdownDigestFormatter)
+}
+
+// WriteDigestSlack renders the digest in Slack's markup, for the text of a
+// message.
+func WriteDigestSlack(w io.Writer, digest Digest) error {
+	return writeDigest(w, digest, slackDigestFormatter)
+}
+
+func writeDigest(w io.Writer, digest Digest, f digestFormatter) error {
+	bw := bufio.NewWriter(w)
+
+	title := "CLA compliance digest"
+	if digest.Since != nil {
+		title += " since " + digest.Since.Format("2006-01-02")
+	}
+	fmt.Fprintf(bw, "%s\n\n", f.heading(title))
+	c := digest.Counts
+	fmt.Fprintf(bw, "Open pull requests: %d (%d compliant, %d non-compliant, %d external, %d skipped).\n\n",
+		c.Total, c.Compliant, c.NonCompliant, c.External, c.Skipped)
+
+	if len(digest.Changes) > 0 {
+		fmt.Fprintf(bw, "%s\n\n", f.heading("Compliance changes"))
+		for _, change := range digest.Changes {
+			fmt.Fprintf(bw, "- %s: %s → %s\n", f.pullRequest(change.PullRequest), change.Previous, f.bold(change.PullRequest.Status()))
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if len(digest.NewExternal) > 0 {
+		fmt.Fprintf(bw, "%s\n\n", f.heading("New external-CLA pull requests"))
+		for _, pr := range digest.NewExternal {
+			line := f.pullRequest(pr)
+			if pr.ManagedBy != "" {
+				line += fmt.Sprintf(" (managed by %s)", f.code(pr.ManagedBy))
+			}
+			fmt.Fprintf(bw, "- %s\n", line)
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if len(digest.MissingLabels) > 0 {
+		fmt.Fprintf(bw, "%s\n\n", f.heading("Repos missing CLA labels"))
+		for _, missing := range digest.MissingLabels {
+			labels := make([]string, len(missing.Labels))
+			for i, label := range missing.Labels {
+				labels[i] = f.code(label)
+			}
+			fmt.Fprintf(bw, "- %s: %s\n", f.code(missing.Repo), strings.Join(labels, ", "))
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if len(digest.Changes) == 0 && len(digest.NewExternal) == 0 && len(digest.MissingLabels) == 0 {
+		fmt.Fprintf(bw, "Nothing changed.\n")
+	}
+	return bw.Flush()
+}
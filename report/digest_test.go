@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDigest_NoSnapshot(t *testing.T) {
+	now := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+	digest := ComputeDigest(newStatsTestReport(), nil, nil, now)
+
+	assert.Nil(t, digest.Since)
+	assert.Equal(t, Counts{Total: 5, Compliant: 2, NonCompliant: 2, External: 1}, digest.Counts)
+	assert.Empty(t, digest.Changes)
+	if assert.Len(t, digest.NewExternal, 1) {
+		assert.Equal(t, 4, digest.NewExternal[0].Number)
+	}
+}
+
+func TestComputeDigest_Changes(t *testing.T) {
+	then := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := TakeSnapshot(newStatsTestReport(), then)
+	assert.Equal(t, StatusNonCompliant, previous.PullRequests["org/a#2"])
+
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 1, Compliant: true})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 2, Compliant: true})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 4, External: true})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 6, External: true})
+	missing := []MissingLabels{{Repo: "org/c", Labels: []string{"cla: no"}}, {Repo: "org/b", Labels: []string{"cla: external"}}}
+	digest := ComputeDigest(r, &previous, missing, then.Add(7*24*time.Hour))
+
+	assert.Equal(t, then, *digest.Since)
+	if assert.Len(t, digest.Changes, 1) {
+		assert.Equal(t, 2, digest.Changes[0].PullRequest.Number)
+		assert.Equal(t, StatusNonCompliant, digest.Changes[0].Previous)
+	}
+	if assert.Len(t, digest.NewExternal, 1) {
+		assert.Equal(t, 6, digest.NewExternal[0].Number)
+	}
+	assert.Equal(t, "org/b", digest.MissingLabels[0].Repo)
+}
+
+func TestSnapshot_JSONRoundTrip(t *testing.T) {
+	snapshot := TakeSnapshot(newStatsTestReport(), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSnapshotJSON(&buf, snapshot))
+	read, err := ReadSnapshot(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, snapshot, *read)
+}
+
+func newTestDigest() Digest {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Digest{
+		Since:  &since,
+		Counts: Counts{Total: 2, Compliant: 1, External: 1},
+		Changes: []StatusChange{{
+			PullRequest: PullRequest{Org: "org", Repo: "a", Number: 2, Title: "Fix <b>", URL: "https://github.com/org/a/pull/2", Compliant: true},
+			Previous:    StatusNonCompliant,
+		}},
+		NewExternal:   []PullRequest{{Org: "org", Repo: "b", Number: 6, External: true, ManagedBy: "easycla"}},
+		MissingLabels: []MissingLabels{{Repo: "org/c", Labels: []string{"cla: yes", "cla: no"}}},
+	}
+}
+
+func TestWriteDigestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteDigestMarkdown(&buf, newTestDigest()))
+	assert.Equal(t, "## CLA compliance digest since 2026-01-01\n\n"+
+		"Open pull requests: 2 (1 compliant, 0 non-compliant, 1 external, 0 skipped).\n\n"+
+		"## Compliance changes\n\n"+
+		"- [org/a#2](https://github.com/org/a/pull/2) Fix &lt;b&gt;: non-compliant → **compliant**\n\n"+
+		"## New external-CLA pull requests\n\n"+
+		"- org/b#6 (managed by `easycla`)\n\n"+
+		"## Repos missing CLA labels\n\n"+
+		"- `org/c`: `cla: yes`, `cla: no`\n\n", buf.String())
+}
+
+func TestWriteDigestSlack(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteDigestSlack(&buf, newTestDigest()))
+	assert.Equal(t, "*CLA compliance digest since 2026-01-01*\n\n"+
+		"Open pull requests: 2 (1 compliant, 0 non-compliant, 1 external, 0 skipped).\n\n"+
+		"*Compliance changes*\n\n"+
+		"- <https://github.com/org/a/pull/2|org/a#2> Fix &lt;b&gt;: non-compliant → *compliant*\n\n"+
+		"*New external-CLA pull requests*\n\n"+
+		"- org/b#6 (managed by `easycla`)\n\n"+
+		"*Repos missing CLA labels*\n\n"+
+		"- `org/c`: `cla: yes`, `cla: no`\n\n", buf.String())
+}
+
+func TestWriteDigest_NothingChanged(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteDigestMarkdown(&buf, Digest{}))
+	assert.Contains(t, buf.String(), "Nothing changed.\n")
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The types below model the commonly-accepted subset of the JUnit XML format,
+// as rendered natively by most CI systems.
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit renders the report as JUnit XML, with one test suite per repo and
+// one test case per pull request. Non-compliant pull requests are reported as
+// failures listing each of the offending commits, and pull requests with
+// externally-managed CLAs or excluded by maintainers are reported as skipped.
+func WriteJUnit(w io.Writer, r *Report) error {
+	suites := junitTestSuites{
+		Name: "crbot",
+	}
+	suiteIndex := make(map[string]int)
+
+	for _, pr := range r.PullRequests {
+		className := fmt.Sprintf("%s/%s", pr.Org, pr.Repo)
+		idx, ok := suiteIndex[className]
+		if !ok {
+			idx = len(suites.Suites)
+			suiteIndex[className] = idx
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: className})
+		}
+		suite := &suites.Suites[idx]
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("PR %d: %s", pr.Number, pr.Title),
+			ClassName: className,
+		}
+		switch pr.Status() {
+		case StatusExternal:
+			message := "CLA is managed externally"
+			if pr.ManagedBy != "" {
+				message += " by " + pr.ManagedBy
+			}
+			testCase.Skipped = &junitSkipped{Message: message}
+			suite.Skipped++
+			suites.Skipped++
+		case StatusSkipped:
+			testCase.Skipped = &junitSkipped{Message: pr.Reason}
+			suite.Skipped++
+			suites.Skipped++
+		case StatusNonCompliant:
+			var details []string
+			for _, commit := range pr.Commits {
+				if commit.Status() == StatusNonCompliant {
+					details = append(details, fmt.Sprintf("%s: %s", commit.SHA, commit.Reason))
+				}
+			}
+			testCase.Failure = &junitFailure{
+				Message: strings.Join(pr.Reasons(), "; "),
+				Text:    strings.Join(details, "\n"),
+			}
+			suite.Failures++
+			suites.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+		suites.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
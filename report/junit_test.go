@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJUnit_FailuresAndSkips(t *testing.T) {
+	r := newTestReport()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "other", Number: 1, External: true, ManagedBy: "EasyCLA"})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteJUnit(&buf, r))
+
+	var suites junitTestSuites
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, 2, len(suites.Suites))
+
+	repoSuite := suites.Suites[0]
+	assert.Equal(t, "org/repo", repoSuite.Name)
+	assert.Equal(t, 2, len(repoSuite.TestCases))
+	assert.NotNil(t, repoSuite.TestCases[0].Failure)
+	assert.Contains(t, repoSuite.TestCases[0].Failure.Text, "bbb222")
+	assert.Nil(t, repoSuite.TestCases[1].Failure)
+
+	otherSuite := suites.Suites[1]
+	if assert.NotNil(t, otherSuite.TestCases[0].Skipped) {
+		assert.Equal(t, "CLA is managed externally by EasyCLA", otherSuite.TestCases[0].Skipped.Message)
+	}
+}
+
+func TestWriteJUnit_SkippedByLabel(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "repo", Number: 1, Skipped: true, Reason: "PR has [cla: skip] label"})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteJUnit(&buf, r))
+
+	var suites junitTestSuites
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, 0, suites.Failures)
+	assert.Equal(t, "PR has [cla: skip] label", suites.Suites[0].TestCases[0].Skipped.Message)
+}
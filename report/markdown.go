@@ -0,0 +1,134 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// markdownEscaper escapes the characters which would otherwise be interpreted
+// as Markdown (or HTML) in table cells and list items.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`",
+	"[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "\n", " ",
+)
+
+// WriteMarkdown renders a summary of the run as GitHub-flavored Markdown,
+// suitable for a tracking issue or a GitHub Actions job summary: the number of
+// pull requests per repo in each compliance state, the pull requests whose
+// labels changed, the non-compliant pull requests, and any errors.
+func WriteMarkdown(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+	stats := ComputeStats(r, nil, time.Time{})
+
+	fmt.Fprintf(bw, "# CLA compliance summary\n\n")
+	fmt.Fprintf(bw, "Checked %d pull request(s) in %d repo(s).\n\n", stats.Total.Counts.Total, len(stats.Repos))
+	if len(stats.Repos) > 0 {
+		fmt.Fprintf(bw, "| Repo | PRs | Compliant | Non-compliant | External | Skipped |\n")
+		fmt.Fprintf(bw, "| --- | ---: | ---: | ---: | ---: | ---: |\n")
+		writeRow := func(name string, c Counts) {
+			fmt.Fprintf(bw, "| %s | %d | %d | %d | %d | %d |\n", name, c.Total, c.Compliant, c.NonCompliant, c.External, c.Skipped)
+		}
+		for _, group := range stats.Repos {
+			writeRow(markdownEscaper.Replace(group.Name), group.Counts)
+		}
+		writeRow("**Total**", stats.Total.Counts)
+		fmt.Fprintf(bw, "\n")
+	}
+
+	var changed, nonCompliant []PullRequest
+	for _, pr := range r.PullRequests {
+		if len(pr.LabelsAdded) > 0 || len(pr.LabelsRemoved) > 0 {
+			changed = append(changed, pr)
+		}
+		if pr.Status() == StatusNonCompliant {
+			nonCompliant = append(nonCompliant, pr)
+		}
+	}
+
+	if len(changed) > 0 {
+		fmt.Fprintf(bw, "## Label changes\n\n")
+		for _, pr := range changed {
+			var changes []string
+			for _, label := range pr.LabelsAdded {
+				changes = append(changes, fmt.Sprintf("added `%s`", strings.ReplaceAll(label, "`", "'")))
+			}
+			for _, label := range pr.LabelsRemoved {
+				changes = append(changes, fmt.Sprintf("removed `%s`", strings.ReplaceAll(label, "`", "'")))
+			}
+			fmt.Fprintf(bw, "- %s: %s (now %s)\n", markdownPullRequest(pr), strings.Join(changes, ", "), pr.Status())
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if len(nonCompliant) > 0 {
+		fmt.Fprintf(bw, "## Non-compliant pull requests\n\n")
+		for _, pr := range nonCompliant {
+			fmt.Fprintf(bw, "- %s: %s\n", markdownPullRequest(pr), markdownEscaper.Replace(strings.Join(pr.Reasons(), "; ")))
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(bw, "## Errors\n\n")
+		for _, message := range r.Errors {
+			fmt.Fprintf(bw, "- %s\n", markdownEscaper.Replace(message))
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+
+	if r.APIUsage != nil {
+		fmt.Fprintf(bw, "GitHub API calls: %d; rate limit remaining: %d of %d.\n", r.APIUsage.Calls, r.APIUsage.Remaining, r.APIUsage.Limit)
+	}
+	return bw.Flush()
+}
+
+// WriteChecklist renders the non-compliant pull requests among those given as
+// a GitHub-flavored Markdown task list, with the reasons of each, e.g., for the
+// body of a tracking issue.
+func WriteChecklist(w io.Writer, prs []PullRequest) error {
+	bw := bufio.NewWriter(w)
+	count := 0
+	for _, pr := range prs {
+		if pr.Status() != StatusNonCompliant {
+			continue
+		}
+		count++
+		fmt.Fprintf(bw, "- [ ] %s: %s\n", markdownPullRequest(pr), markdownEscaper.Replace(strings.Join(pr.Reasons(), "; ")))
+	}
+	if count == 0 {
+		fmt.Fprintf(bw, "No pull requests are currently non-compliant.\n")
+	}
+	return bw.Flush()
+}
+
+// markdownPullRequest renders a reference to the pull request, linked to it if
+// its URL is known, along with its title.
+func markdownPullRequest(pr PullRequest) string {
+	ref := fmt.Sprintf("%s/%s#%d", pr.Org, pr.Repo, pr.Number)
+	if pr.URL != "" {
+		ref = fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(ref), pr.URL)
+	} else {
+		ref = markdownEscaper.Replace(ref)
+	}
+	if pr.Title != "" {
+		ref += " " + markdownEscaper.Replace(pr.Title)
+	}
+	return ref
+}
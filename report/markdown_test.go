@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	r := newTestReport()
+	r.AddPullRequest(PullRequest{
+		Org:           "org",
+		Repo:          "other",
+		Number:        7,
+		Title:         "Update *all* deps",
+		URL:           "https://github.com/org/other/pull/7",
+		Compliant:     true,
+		LabelsAdded:   []string{"cla: yes"},
+		LabelsRemoved: []string{"cla: no"},
+	})
+	r.AddError("Error adding label [cla: no] to repo 'org/repo' PR 42: 403 Forbidden")
+	r.SetAPIUsage(APIUsage{Calls: 12, Limit: 5000, Remaining: 4988})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteMarkdown(&buf, r))
+	assert.Equal(t, `# CLA compliance summary
+
+Checked 3 pull request(s) in 2 repo(s).
+
+| Repo | PRs | Compliant | Non-compliant | External | Skipped |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| org/other | 1 | 1 | 0 | 0 | 0 |
+| org/repo | 2 | 1 | 1 | 0 | 0 |
+| **Total** | 3 | 2 | 1 | 0 | 0 |
+
+## Label changes
+
+- [org/other#7](https://github.com/org/other/pull/7) Update \*all\* deps: added `+"`cla: yes`"+`, removed `+"`cla: no`"+` (now compliant)
+
+## Non-compliant pull requests
+
+- org/repo#42 Fix all the things: Author of one or more commits is not listed as a CLA signer
+
+## Errors
+
+- Error adding label \[cla: no\] to repo 'org/repo' PR 42: 403 Forbidden
+
+GitHub API calls: 12; rate limit remaining: 4988 of 5000.
+`, buf.String())
+}
+
+func TestWriteMarkdown_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, FormatMarkdown, New()))
+	assert.Equal(t, "# CLA compliance summary\n\nChecked 0 pull request(s) in 0 repo(s).\n\n", buf.String())
+}
+
+func TestWriteChecklist(t *testing.T) {
+	prs := []PullRequest{
+		{Org: "org", Repo: "repo", Number: 1, Title: "Compliant", Compliant: true},
+		{Org: "org", Repo: "repo", Number: 2, Title: "Fix *bug*", URL: "https://github.com/org/repo/pull/2", Reason: "Author has not signed the CLA"},
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, WriteChecklist(&buf, prs))
+	assert.Equal(t, "- [ ] [org/repo#2](https://github.com/org/repo/pull/2) Fix \\*bug\\*: Author has not signed the CLA\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, WriteChecklist(&buf, prs[:1]))
+	assert.Equal(t, "No pull requests are currently non-compliant.\n", buf.String())
+}
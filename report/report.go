@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report collects the compliance results of a single run of the
+// `crbot` tool and renders them in formats suitable for other tools to
+// consume.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported output formats for `Write`.
+const (
+	FormatSARIF    = "sarif"
+	FormatCSV      = "csv"
+	FormatJUnit    = "junit"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists all of the output formats supported by `Write`.
+var Formats = []string{FormatSARIF, FormatCSV, FormatJUnit, FormatMarkdown}
+
+// Compliance statuses of pull requests and commits as rendered in reports.
+const (
+	StatusCompliant    = "compliant"
+	StatusNonCompliant = "non-compliant"
+	StatusExternal     = "external"
+	StatusSkipped      = "skipped"
+	StatusExempted     = "exempted"
+)
+
+// IsSupportedFormat returns whether `format` is one of the supported `Formats`.
+func IsSupportedFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Identity is an author or committer of a commit; `Role` is either "author"
+// or "committer". `Mismatched` lists the fields ("name", "email", or "login")
+// which differ from the closest CLA record, if any.
+type Identity struct {
+	Role       string
+	Name       string
+	Email      string
+	Login      string
+	Mismatched []string
+}
+
+// String describes the identity as it appears in the commit, e.g.,
+// "author Jane Doe <jane@example.com>, GitHub: janedoe", noting missing fields.
+func (id Identity) String() string {
+	name, email, login := id.Name, id.Email, id.Login
+	if name == "" {
+		name = "(no name)"
+	}
+	if email == "" {
+		email = "no email"
+	}
+	if login == "" {
+		login = "(none)"
+	}
+	description := fmt.Sprintf("%s %s <%s>, GitHub: %s", id.Role, name, email, login)
+	if len(id.Mismatched) > 0 {
+		description += fmt.Sprintf(" (mismatched: %s)", strings.Join(id.Mismatched, ", "))
+	}
+	return description
+}
+
+// Commit is the compliance result for a single commit in a pull request;
+// `Unmatched` lists the identities which caused it to be non-compliant, and
+// `Exemption`, if set, is why it was exempt from CLA checks. `Company` is the
+// company whose corporate CLA covers the author, if any, as recorded by
+// `Agreement`.
+type Commit struct {
+	SHA       string
+	Compliant bool
+	External  bool
+	Reason    string
+	Company   string
+	Agreement Agreement
+	Unmatched []Identity
+	Exemption string
+}
+
+// Agreement identifies the record of a corporate CLA, if configured for the
+// company.
+type Agreement struct {
+	ID           string
+	ContactEmail string
+	SignedDate   string
+}
+
+// PullRequest is the compliance result for a single pull request, including
+// the results of each of the commits which were considered. `Skipped` pull
+// requests were excluded from processing by maintainers, so none of their
+// commits were considered.
+type PullRequest struct {
+	Org       string
+	Repo      string
+	Number    int
+	Title     string
+	URL       string
+	Compliant bool
+	External  bool
+	Skipped   bool
+	Reason    string
+	Commits   []Commit
+
+	// ManagedBy names the system managing the CLA of an external pull
+	// request, if known.
+	ManagedBy string
+
+	// LabelsAdded and LabelsRemoved are the labels added to and removed
+	// from the pull request, or which would have been without
+	// `-update-repo`, e.g., when its compliance changed.
+	LabelsAdded   []string
+	LabelsRemoved []string
+}
+
+// Status returns the compliance status of the commit as one of the `Status*`
+// constants.
+func (c Commit) Status() string {
+	if c.Exemption != "" {
+		return StatusExempted
+	}
+	return status(c.Compliant, c.External)
+}
+
+// Status returns the compliance status of the pull request as one of the
+// `Status*` constants.
+func (pr PullRequest) Status() string {
+	if pr.Skipped {
+		return StatusSkipped
+	}
+	return status(pr.Compliant, pr.External)
+}
+
+// Reasons returns the distinct reasons of the non-compliant commits of the
+// pull request, in order, or else its own `Reason`, if any, so that reports
+// explain every problem with it rather than just one.
+func (pr PullRequest) Reasons() []string {
+	var reasons []string
+	seen := make(map[string]bool)
+	for _, commit := range pr.Commits {
+		if commit.Status() != StatusNonCompliant || commit.Reason == "" || seen[commit.Reason] {
+			continue
+		}
+		seen[commit.Reason] = true
+		reasons = append(reasons, commit.Reason)
+	}
+	if len(reasons) == 0 && pr.Reason != "" {
+		reasons = append(reasons, pr.Reason)
+	}
+	return reasons
+}
+
+func status(compliant bool, external bool) string {
+	if external {
+		return StatusExternal
+	} else if compliant {
+		return StatusCompliant
+	}
+	return StatusNonCompliant
+}
+
+// APIUsage summarizes the GitHub API calls made during a run, along with the
+// state of the rate limit as of the last response.
+type APIUsage struct {
+	Calls     int       `json:"calls"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// Report accumulates the results of a run; it is safe for concurrent use.
+type Report struct {
+	mu           sync.Mutex
+	PullRequests []PullRequest
+
+	// APIUsage, if set, is included in formats which support run-level
+	// metadata (currently SARIF and Markdown).
+	APIUsage *APIUsage
+
+	// Errors lists the errors encountered during the run, e.g., failures
+	// to update labels.
+	Errors []string
+}
+
+// New returns an empty report.
+func New() *Report {
+	return &Report{}
+}
+
+// AddPullRequest records the result of processing a single pull request.
+func (r *Report) AddPullRequest(pr PullRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.PullRequests = append(r.PullRequests, pr)
+}
+
+// AddError records an error encountered during the run.
+func (r *Report) AddError(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors = append(r.Errors, message)
+}
+
+// SetAPIUsage records the GitHub API usage of the run.
+func (r *Report) SetAPIUsage(usage APIUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.APIUsage = &usage
+}
+
+// Write renders the report in the requested format.
+func Write(w io.Writer, format string, r *Report) error {
+	switch format {
+	case FormatSARIF:
+		return WriteSARIF(w, r)
+	case FormatCSV:
+		return WriteCSV(w, r)
+	case FormatJUnit:
+		return WriteJUnit(w, r)
+	case FormatMarkdown:
+		return WriteMarkdown(w, r)
+	default:
+		return fmt.Errorf("unsupported report format '%s'; accepted: %s", format, strings.Join(Formats, ", "))
+	}
+}
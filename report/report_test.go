@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	r := New()
+	r.AddPullRequest(PullRequest{
+		Org:       "org",
+		Repo:      "repo",
+		Number:    42,
+		Title:     "Fix all the things",
+		Compliant: false,
+		Reason:    "Author of one or more commits is not listed as a CLA signer",
+		Commits: []Commit{
+			{SHA: "aaa111", Compliant: true},
+			{SHA: "bbb222", Compliant: false, Reason: "Author of one or more commits is not listed as a CLA signer"},
+		},
+	})
+	r.AddPullRequest(PullRequest{
+		Org:       "org",
+		Repo:      "repo",
+		Number:    43,
+		Title:     "Add a feature",
+		Compliant: true,
+		Commits: []Commit{
+			{SHA: "ccc333", Compliant: true},
+		},
+	})
+	return r
+}
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, StatusCompliant, Commit{Compliant: true}.Status())
+	assert.Equal(t, StatusNonCompliant, Commit{Compliant: false}.Status())
+	assert.Equal(t, StatusExternal, PullRequest{External: true}.Status())
+	assert.Equal(t, StatusExternal, PullRequest{Compliant: true, External: true}.Status())
+	assert.Equal(t, StatusSkipped, PullRequest{Skipped: true}.Status())
+	assert.Equal(t, StatusExempted, Commit{Compliant: true, Exemption: "Vendored import"}.Status())
+}
+
+func TestPullRequestReasons(t *testing.T) {
+	pr := PullRequest{
+		Reason: "Committer is not a signer",
+		Commits: []Commit{
+			{SHA: "aaa111", Compliant: false, Reason: "Author is not a signer"},
+			{SHA: "bbb222", Compliant: true},
+			{SHA: "ccc333", Compliant: false, Reason: "Committer is not a signer"},
+			{SHA: "ddd444", Compliant: false, Reason: "Author is not a signer"},
+		},
+	}
+	assert.Equal(t, []string{"Author is not a signer", "Committer is not a signer"}, pr.Reasons())
+	assert.Equal(t, []string{"PR has [cla: skip] label"}, PullRequest{Skipped: true, Reason: "PR has [cla: skip] label"}.Reasons())
+	assert.Nil(t, PullRequest{Compliant: true}.Reasons())
+}
+
+func TestIdentityString(t *testing.T) {
+	id := Identity{Role: "author", Name: "Jane Doe", Email: "jane@personal.example.com", Login: "jane"}
+	assert.Equal(t, "author Jane Doe <jane@personal.example.com>, GitHub: jane", id.String())
+	id.Mismatched = []string{"email"}
+	assert.Equal(t, "author Jane Doe <jane@personal.example.com>, GitHub: jane (mismatched: email)", id.String())
+	assert.Equal(t, "committer (no name) <no email>, GitHub: (none)", Identity{Role: "committer"}.String())
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NotNil(t, Write(&buf, "xml", New()))
+	assert.False(t, IsSupportedFormat("xml"))
+	assert.True(t, IsSupportedFormat(FormatSARIF))
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+	sarifToolName = "crbot"
+	sarifToolURI  = "https://github.com/google/code-review-bot"
+
+	// RuleNonCompliantCommit is the SARIF rule ID reported for each commit
+	// whose author or committer is not covered by a CLA.
+	RuleNonCompliantCommit = "cla/non-compliant-commit"
+)
+
+// CommitResultPath is the path at which results about commits are located,
+// both in SARIF reports and in check run annotations, since GitHub requires
+// each of them to name a path, while non-compliance is a property of the
+// commit rather than of any of its files: the `.github` directory of the repo.
+// GitHub lists such results with the others, but not inline in the diff.
+const CommitResultPath = ".github"
+
+// The types below model the subset of the SARIF 2.1.0 format that we emit;
+// see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html for
+// the full specification.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool           `json:"tool"`
+	Results    []sarifResult       `json:"results"`
+	Properties *sarifRunProperties `json:"properties,omitempty"`
+}
+
+// sarifRunProperties is the property bag of a run, for data which has no
+// dedicated place in the SARIF format.
+type sarifRunProperties struct {
+	APIUsage *APIUsage `json:"apiUsage,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifCommitMessage describes why the commit is non-compliant, including each
+// of the identities which didn't match, so that contributors can tell which
+// field of which commit to fix from the annotation alone.
+func sarifCommitMessage(pr PullRequest, commit Commit) string {
+	message := fmt.Sprintf("Commit %s in PR %d: %s", commit.SHA, pr.Number, commit.Reason)
+	if len(commit.Unmatched) > 0 {
+		var identities []string
+		for _, identity := range commit.Unmatched {
+			identities = append(identities, identity.String())
+		}
+		message += fmt.Sprintf(" (unmatched: %s)", strings.Join(identities, "; "))
+	}
+	return message
+}
+
+// WriteSARIF renders the report in SARIF format, with one result per
+// non-compliant commit, suitable for uploading to GitHub code scanning, which
+// lists each of them as an alert. Code scanning requires a file location, so
+// each result is located at `CommitResultPath`, along with the commit as its
+// logical location.
+func WriteSARIF(w io.Writer, r *Report) error {
+	results := make([]sarifResult, 0)
+	for _, pr := range r.PullRequests {
+		for _, commit := range pr.Commits {
+			if commit.Compliant || commit.External {
+				continue
+			}
+			fqn := fmt.Sprintf("%s/%s#%d@%s", pr.Org, pr.Repo, pr.Number, commit.SHA)
+			results = append(results, sarifResult{
+				RuleID: RuleNonCompliantCommit,
+				Level:  "error",
+				Message: sarifMessage{
+					Text: sarifCommitMessage(pr, commit),
+				},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: CommitResultPath},
+							Region:           sarifRegion{StartLine: 1},
+						},
+						LogicalLocations: []sarifLogicalLocation{
+							{
+								Name:               commit.SHA,
+								FullyQualifiedName: fqn,
+								Kind:               "commit",
+							},
+						},
+					},
+				},
+				PartialFingerprints: map[string]string{
+					"commitSha": commit.SHA,
+				},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           sarifToolName,
+						InformationURI: sarifToolURI,
+						Rules: []sarifRule{
+							{
+								ID: RuleNonCompliantCommit,
+								ShortDescription: sarifMessage{
+									Text: "Commit author or committer is not covered by a CLA",
+								},
+							},
+						},
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	if r.APIUsage != nil {
+		log.Runs[0].Properties = &sarifRunProperties{APIUsage: r.APIUsage}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSARIF_OneResultPerNonCompliantCommit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSARIF(&buf, newTestReport()))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, sarifVersion, log.Version)
+	assert.Equal(t, 1, len(log.Runs))
+
+	results := log.Runs[0].Results
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, RuleNonCompliantCommit, results[0].RuleID)
+	assert.Equal(t, "bbb222", results[0].PartialFingerprints["commitSha"])
+	assert.Equal(t, "org/repo#42@bbb222", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	// Code scanning rejects results without a physical location.
+	assert.Equal(t, CommitResultPath, results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestWriteSARIF_DescribesUnmatchedIdentities(t *testing.T) {
+	r := New()
+	r.AddPullRequest(PullRequest{
+		Org:    "org",
+		Repo:   "repo",
+		Number: 42,
+		Commits: []Commit{
+			{
+				SHA:    "bbb222",
+				Reason: "Committer of one or more commits is not listed as a CLA signer",
+				Unmatched: []Identity{
+					{Role: "author", Name: "Jane Doe", Email: "jane@example.com"},
+					{Role: "committer", Name: "John Doe", Email: "john@example.com", Login: "john-doe"},
+				},
+			},
+		},
+	})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSARIF(&buf, r))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "Commit bbb222 in PR 42: Committer of one or more commits is not listed as a CLA signer "+
+		"(unmatched: author Jane Doe <jane@example.com>, GitHub: (none); committer John Doe <john@example.com>, GitHub: john-doe)",
+		log.Runs[0].Results[0].Message.Text)
+}
+
+func TestWriteSARIF_EmptyReport(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSARIF(&buf, New()))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.NotNil(t, log.Runs[0].Results)
+	assert.Equal(t, 0, len(log.Runs[0].Results))
+}
+
+func TestWriteSARIF_APIUsage(t *testing.T) {
+	r := New()
+	reset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.SetAPIUsage(APIUsage{Calls: 12, Limit: 5000, Remaining: 4988, Reset: reset})
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSARIF(&buf, r))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, &APIUsage{Calls: 12, Limit: 5000, Remaining: 4988, Reset: reset}, log.Runs[0].Properties.APIUsage)
+}
+
+func TestWriteSARIF_NoAPIUsage(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSARIF(&buf, New()))
+	assert.NotContains(t, buf.String(), "properties")
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Pseudo-company names used to group pull requests in `Stats.Companies` whose
+// authors aren't covered by a corporate CLA.
+const (
+	CompanyIndividual = "(individual)"
+	CompanyUnknown    = "(unknown)"
+	CompanyExternal   = "(external)"
+)
+
+// Counts is the number of pull requests in each of the compliance states.
+type Counts struct {
+	Total        int `json:"total"`
+	Compliant    int `json:"compliant"`
+	NonCompliant int `json:"non_compliant"`
+	External     int `json:"external"`
+	Skipped      int `json:"skipped"`
+}
+
+func (c *Counts) add(status string) {
+	c.Total++
+	switch status {
+	case StatusCompliant:
+		c.Compliant++
+	case StatusNonCompliant:
+		c.NonCompliant++
+	case StatusExternal:
+		c.External++
+	case StatusSkipped:
+		c.Skipped++
+	}
+}
+
+func (c Counts) sub(other Counts) Counts {
+	return Counts{
+		Total:        c.Total - other.Total,
+		Compliant:    c.Compliant - other.Compliant,
+		NonCompliant: c.NonCompliant - other.NonCompliant,
+		External:     c.External - other.External,
+		Skipped:      c.Skipped - other.Skipped,
+	}
+}
+
+// GroupStats is the compliance summary for a group of pull requests, such as
+// those in a single repo, or those authored by members of a single company.
+// If previous stats were provided to `ComputeStats`, `Change` holds the
+// difference in counts since then.
+type GroupStats struct {
+	Name   string  `json:"name"`
+	Counts Counts  `json:"counts"`
+	Change *Counts `json:"change,omitempty"`
+}
+
+// ReasonCount is the number of pull requests failing for a particular reason.
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// Stats summarizes the compliance of all pull requests in a report.
+type Stats struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	Since      *time.Time    `json:"since,omitempty"`
+	Total      GroupStats    `json:"total"`
+	Repos      []GroupStats  `json:"repos"`
+	Companies  []GroupStats  `json:"companies"`
+	TopReasons []ReasonCount `json:"top_reasons"`
+}
+
+// companies returns the distinct companies covering the authors of the pull
+// request, or one of the pseudo-company names if there are none.
+func (pr PullRequest) companies() []string {
+	if pr.External {
+		return []string{CompanyExternal}
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, commit := range pr.Commits {
+		name := commit.Company
+		if name == "" {
+			if commit.Compliant {
+				name = CompanyIndividual
+			} else {
+				name = CompanyUnknown
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, CompanyUnknown)
+	}
+	return names
+}
+
+// groupStats converts the counts per group into a list sorted by name, with
+// changes computed against the same groups in `previous`, if non-nil.
+func groupStats(counts map[string]*Counts, previous []GroupStats, hasPrevious bool) []GroupStats {
+	previousCounts := make(map[string]Counts)
+	for _, group := range previous {
+		previousCounts[group.Name] = group.Counts
+	}
+
+	groups := make([]GroupStats, 0, len(counts))
+	for name, c := range counts {
+		group := GroupStats{Name: name, Counts: *c}
+		if hasPrevious {
+			change := c.sub(previousCounts[name])
+			group.Change = &change
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// ComputeStats summarizes the report per repo and per company, along with the
+// most common reasons for non-compliance. If `previous` is non-nil, the
+// resulting stats also include the changes since then.
+func ComputeStats(r *Report, previous *Stats, now time.Time) Stats {
+	var total Counts
+	repoCounts := make(map[string]*Counts)
+	companyCounts := make(map[string]*Counts)
+	reasonCounts := make(map[string]int)
+
+	for _, pr := range r.PullRequests {
+		status := pr.Status()
+		total.add(status)
+
+		repo := fmt.Sprintf("%s/%s", pr.Org, pr.Repo)
+		if repoCounts[repo] == nil {
+			repoCounts[repo] = &Counts{}
+		}
+		repoCounts[repo].add(status)
+
+		for _, company := range pr.companies() {
+			if companyCounts[company] == nil {
+				companyCounts[company] = &Counts{}
+			}
+			companyCounts[company].add(status)
+		}
+
+		if status == StatusNonCompliant {
+			for _, reason := range pr.Reasons() {
+				reasonCounts[reason]++
+			}
+		}
+	}
+
+	stats := Stats{
+		Timestamp: now,
+		Total:     GroupStats{Name: "total", Counts: total},
+	}
+	var previousRepos, previousCompanies []GroupStats
+	if previous != nil {
+		since := previous.Timestamp
+		stats.Since = &since
+		change := total.sub(previous.Total.Counts)
+		stats.Total.Change = &change
+		previousRepos = previous.Repos
+		previousCompanies = previous.Companies
+	}
+	stats.Repos = groupStats(repoCounts, previousRepos, previous != nil)
+	stats.Companies = groupStats(companyCounts, previousCompanies, previous != nil)
+
+	stats.TopReasons = make([]ReasonCount, 0, len(reasonCounts))
+	for reason, count := range reasonCounts {
+		stats.TopReasons = append(stats.TopReasons, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(stats.TopReasons, func(i, j int) bool {
+		if stats.TopReasons[i].Count != stats.TopReasons[j].Count {
+			return stats.TopReasons[i].Count > stats.TopReasons[j].Count
+		}
+		return stats.TopReasons[i].Reason < stats.TopReasons[j].Reason
+	})
+	return stats
+}
+
+// ReadStats reads stats previously written by `WriteStatsJSON`.
+func ReadStats(r io.Reader) (*Stats, error) {
+	var stats Stats
+	if err := json.NewDecoder(r).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// WriteStatsJSON renders the stats as JSON.
+func WriteStatsJSON(w io.Writer, stats Stats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
+
+// formatChange renders the change in a count as a signed number, or an empty
+// string if there are no previous stats to compare against.
+func formatChange(change *Counts, value func(Counts) int) string {
+	if change == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (%+d)", value(*change))
+}
+
+// WriteStatsTable renders the stats as human-readable, aligned tables.
+func WriteStatsTable(w io.Writer, stats Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	writeGroups := func(title string, groups []GroupStats) {
+		fmt.Fprintf(tw, "%s\tTOTAL\tCOMPLIANT\tNON-COMPLIANT\tEXTERNAL\tSKIPPED\n", title)
+		for _, group := range groups {
+			fmt.Fprintf(tw, "%s\t%d%s\t%d%s\t%d%s\t%d%s\t%d%s\n", group.Name,
+				group.Counts.Total, formatChange(group.Change, func(c Counts) int { return c.Total }),
+				group.Counts.Compliant, formatChange(group.Change, func(c Counts) int { return c.Compliant }),
+				group.Counts.NonCompliant, formatChange(group.Change, func(c Counts) int { return c.NonCompliant }),
+				group.Counts.External, formatChange(group.Change, func(c Counts) int { return c.External }),
+				group.Counts.Skipped, formatChange(group.Change, func(c Counts) int { return c.Skipped }))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	if stats.Since != nil {
+		fmt.Fprintf(tw, "Changes since %s are shown in parentheses.\n\n", stats.Since.Format(time.RFC3339))
+	}
+	writeGroups("REPO", append(append([]GroupStats{}, stats.Repos...), stats.Total))
+	writeGroups("COMPANY", stats.Companies)
+
+	fmt.Fprintf(tw, "COUNT\tTOP NON-COMPLIANCE REASONS\n")
+	for _, reason := range stats.TopReasons {
+		fmt.Fprintf(tw, "%d\t%s\n", reason.Count, reason.Reason)
+	}
+	return tw.Flush()
+}
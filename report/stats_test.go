@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStatsTestReport() *Report {
+	r := New()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 1, Compliant: true,
+		Commits: []Commit{{SHA: "1", Compliant: true, Company: "Acme"}}})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "a", Number: 2, Compliant: false, Reason: "no CLA",
+		Commits: []Commit{{SHA: "2", Compliant: false, Reason: "no CLA"}}})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 3, Compliant: true,
+		Commits: []Commit{{SHA: "3", Compliant: true}}})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 4, External: true})
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "b", Number: 5, Compliant: false, Reason: "no CLA",
+		Commits: []Commit{{SHA: "5", Compliant: false, Reason: "no CLA"}}})
+	return r
+}
+
+func TestComputeStats_PerRepoAndCompany(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := ComputeStats(newStatsTestReport(), nil, now)
+
+	assert.Equal(t, Counts{Total: 5, Compliant: 2, NonCompliant: 2, External: 1}, stats.Total.Counts)
+	assert.Nil(t, stats.Total.Change)
+	assert.Nil(t, stats.Since)
+
+	assert.Equal(t, []GroupStats{
+		{Name: "org/a", Counts: Counts{Total: 2, Compliant: 1, NonCompliant: 1}},
+		{Name: "org/b", Counts: Counts{Total: 3, Compliant: 1, NonCompliant: 1, External: 1}},
+	}, stats.Repos)
+
+	assert.Equal(t, []GroupStats{
+		{Name: CompanyExternal, Counts: Counts{Total: 1, External: 1}},
+		{Name: CompanyIndividual, Counts: Counts{Total: 1, Compliant: 1}},
+		{Name: CompanyUnknown, Counts: Counts{Total: 2, NonCompliant: 2}},
+		{Name: "Acme", Counts: Counts{Total: 1, Compliant: 1}},
+	}, stats.Companies)
+
+	assert.Equal(t, []ReasonCount{{Reason: "no CLA", Count: 2}}, stats.TopReasons)
+}
+
+func TestComputeStats_Trends(t *testing.T) {
+	then := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := ComputeStats(newStatsTestReport(), nil, then)
+
+	r := newStatsTestReport()
+	r.AddPullRequest(PullRequest{Org: "org", Repo: "c", Number: 6, Compliant: true,
+		Commits: []Commit{{SHA: "6", Compliant: true}}})
+	stats := ComputeStats(r, &previous, then.Add(24*time.Hour))
+
+	assert.Equal(t, then, *stats.Since)
+	assert.Equal(t, Counts{Total: 1, Compliant: 1}, *stats.Total.Change)
+	assert.Equal(t, "org/c", stats.Repos[2].Name)
+	assert.Equal(t, Counts{Total: 1, Compliant: 1}, *stats.Repos[2].Change)
+	assert.Equal(t, Counts{}, *stats.Repos[0].Change)
+}
+
+func TestStats_JSONRoundTrip(t *testing.T) {
+	stats := ComputeStats(newStatsTestReport(), nil, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteStatsJSON(&buf, stats))
+	parsed, err := ReadStats(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, stats, *parsed)
+}
+
+func TestWriteStatsTable(t *testing.T) {
+	previous := ComputeStats(New(), nil, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats := ComputeStats(newStatsTestReport(), &previous, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteStatsTable(&buf, stats))
+	output := buf.String()
+	assert.Contains(t, output, "Changes since 2026-01-01T00:00:00Z")
+	assert.Contains(t, output, "org/b")
+	assert.Contains(t, output, "5 (+5)")
+	assert.Contains(t, output, "no CLA")
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RunSummary is the outcome of a run as a whole, e.g., for monitoring it
+// without parsing its log.
+type RunSummary struct {
+	PullRequests  int `json:"pull_requests"`
+	LabelsAdded   int `json:"labels_added"`
+	LabelsRemoved int `json:"labels_removed"`
+	Comments      int `json:"comments"`
+	Errors        int `json:"errors"`
+	APICalls      int `json:"api_calls"`
+
+	// Duration is the wall-clock time of the run, encoded in JSON as
+	// `duration_seconds`.
+	Duration time.Duration `json:"-"`
+}
+
+// String renders the summary as a single line, e.g., for the log.
+func (s RunSummary) String() string {
+	return fmt.Sprintf("Processed %d PR(s) in %s: added %d label(s), removed %d label(s), posted %d comment(s); %d error(s), %d API call(s)",
+		s.PullRequests, s.Duration.Round(time.Second), s.LabelsAdded, s.LabelsRemoved, s.Comments, s.Errors, s.APICalls)
+}
+
+// WriteRunSummaryJSON writes the summary as an indented JSON object.
+func WriteRunSummaryJSON(w io.Writer, s RunSummary) error {
+	type jsonSummary RunSummary
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		jsonSummary
+		DurationSeconds float64 `json:"duration_seconds"`
+	}{jsonSummary(s), s.Duration.Seconds()})
+}
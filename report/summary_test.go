@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRunSummary = RunSummary{
+	PullRequests:  120,
+	LabelsAdded:   5,
+	LabelsRemoved: 3,
+	Comments:      2,
+	Errors:        1,
+	APICalls:      340,
+	Duration:      80*time.Second + 400*time.Millisecond,
+}
+
+func TestRunSummary_String(t *testing.T) {
+	assert.Equal(t, "Processed 120 PR(s) in 1m20s: added 5 label(s), removed 3 label(s), posted 2 comment(s); 1 error(s), 340 API call(s)", testRunSummary.String())
+}
+
+func TestWriteRunSummaryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteRunSummaryJSON(&buf, testRunSummary))
+	assert.JSONEq(t, `{
+		"pull_requests": 120,
+		"labels_added": 5,
+		"labels_removed": 3,
+		"comments": 2,
+		"errors": 1,
+		"api_calls": 340,
+		"duration_seconds": 80.4
+	}`, buf.String())
+}
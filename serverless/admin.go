@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverless
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/code-review-bot/logging"
+)
+
+// RecheckPath is the path of the admin endpoint which forces a recheck.
+const RecheckPath = "/admin/recheck"
+
+// RecheckHandler forces an immediate recheck of a pull request, or of all open
+// pull requests of a repo, e.g., right after someone's CLA has been recorded:
+//
+//	POST /admin/recheck?org=ORG&repo=REPO[&pr=NUMBER]
+//
+// Requests must carry the admin token as "Authorization: Bearer TOKEN".
+type RecheckHandler struct {
+	handler *Handler
+	token   []byte
+}
+
+// NewRecheckHandler returns a handler rechecking pull requests via `handler`,
+// bypassing the delay of its queue, if any. Requests are authenticated with
+// `token`, which must not be empty.
+func NewRecheckHandler(handler *Handler, token string) *RecheckHandler {
+	return &RecheckHandler{
+		handler: handler,
+		token:   []byte(token),
+	}
+}
+
+// ServeHTTP handles a single recheck request.
+func (a *RecheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if len(a.token) == 0 || subtle.ConstantTimeCompare([]byte(token), a.token) != 1 {
+		logging.Errorf("Rejecting unauthorized recheck request from %s", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	orgName := query.Get("org")
+	repoName := query.Get("repo")
+	if orgName == "" || repoName == "" {
+		http.Error(w, "org and repo parameters are required", http.StatusBadRequest)
+		return
+	}
+	if reason := a.handler.scopeReason(orgName, repoName); reason != "" {
+		http.Error(w, "not monitored: "+reason, http.StatusBadRequest)
+		return
+	}
+
+	repoSpec := a.handler.repoSpec
+	repoSpec.Org = orgName
+	repoSpec.Repo = repoName
+	repoSpec.Pulls = nil
+	repoSpec.ResumeFrom = nil
+	name := fmt.Sprintf("%s/%s", orgName, repoName)
+	if pr := query.Get("pr"); pr != "" {
+		number, err := strconv.Atoi(pr)
+		if err != nil || number <= 0 {
+			http.Error(w, fmt.Sprintf("invalid pr parameter %q", pr), http.StatusBadRequest)
+			return
+		}
+		repoSpec.Pulls = []int{number}
+		name = fmt.Sprintf("%s#%d", name, number)
+	}
+
+	logging.Infof("Recheck of %s requested by admin", name)
+	if a.handler.queue == nil {
+		a.handler.Process(repoSpec)
+		fmt.Fprintf(w, "processed: %s\n", name)
+		return
+	}
+	a.handler.queue.EnqueueNow(strings.ToLower(name), repoSpec)
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "queued: %s\n", name)
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverless
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+const adminToken = "t0k3n"
+
+func recheck(handler http.Handler, method string, query string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, RecheckPath+"?"+query, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRecheckHandler_ProcessesPullRequest(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	admin := NewRecheckHandler(newTestHandler(&processed), adminToken)
+
+	resp := recheck(admin, "POST", "org=org&repo=repo&pr=42", adminToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "processed: org/repo#42\n", resp.Body.String())
+	assert.Equal(t, []ghutil.GitHubProcessOrgRepoSpec{{
+		Org:        "org",
+		Repo:       "repo",
+		Pulls:      []int{42},
+		UpdateRepo: true,
+	}}, processed)
+}
+
+func TestRecheckHandler_QueuesRepo(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+	queue := NewQueue(time.Hour, handler.Process)
+	handler.SetQueue(queue)
+	admin := NewRecheckHandler(handler, adminToken)
+
+	resp := recheck(admin, "POST", "org=org&repo=repo", adminToken)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "queued: org/repo\n", resp.Body.String())
+	assert.Equal(t, 1, queue.Len())
+}
+
+func TestRecheckHandler_RejectsRequests(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	admin := NewRecheckHandler(newTestHandler(&processed), adminToken)
+
+	testCases := []struct {
+		method string
+		query  string
+		token  string
+		code   int
+	}{
+		{"GET", "org=org&repo=repo", adminToken, http.StatusMethodNotAllowed},
+		{"POST", "org=org&repo=repo", "", http.StatusUnauthorized},
+		{"POST", "org=org&repo=repo", "wrong", http.StatusUnauthorized},
+		{"POST", "org=org", adminToken, http.StatusBadRequest},
+		{"POST", "org=other&repo=repo", adminToken, http.StatusBadRequest},
+		{"POST", "org=org&repo=repo&pr=abc", adminToken, http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		resp := recheck(admin, testCase.method, testCase.query, testCase.token)
+		assert.Equal(t, testCase.code, resp.Code, "%s %s", testCase.method, testCase.query)
+	}
+	assert.Equal(t, 0, len(processed))
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverless
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+// Queue buffers processing jobs in memory, delaying each one so that further
+// jobs for the same key (e.g., the same pull request) which arrive in the
+// meantime are coalesced into it. This way, a burst of pushes to a pull
+// request, as from repeated force-pushes, is processed only once. Jobs are
+// processed one at a time, in the order they become due.
+type Queue struct {
+	delay   time.Duration
+	process func(ghutil.GitHubProcessOrgRepoSpec)
+
+	mu      sync.Mutex
+	pending map[string]ghutil.GitHubProcessOrgRepoSpec
+	due     chan string
+}
+
+// NewQueue returns a queue which processes each job via `process` once it has
+// been pending for `delay`; `Run` must be called to process them.
+func NewQueue(delay time.Duration, process func(ghutil.GitHubProcessOrgRepoSpec)) *Queue {
+	return &Queue{
+		delay:   delay,
+		process: process,
+		pending: make(map[string]ghutil.GitHubProcessOrgRepoSpec),
+		due:     make(chan string, 100),
+	}
+}
+
+// Enqueue adds a job with the given key, replacing any pending job with the
+// same key, in which case it returns true.
+func (q *Queue) Enqueue(key string, repoSpec ghutil.GitHubProcessOrgRepoSpec) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, coalesced := q.pending[key]
+	q.pending[key] = repoSpec
+	if !coalesced {
+		time.AfterFunc(q.delay, func() { q.due <- key })
+	}
+	return coalesced
+}
+
+// EnqueueNow adds a job with the given key which is due right away, replacing
+// any pending job with the same key.
+func (q *Queue) EnqueueNow(key string, repoSpec ghutil.GitHubProcessOrgRepoSpec) {
+	q.mu.Lock()
+	q.pending[key] = repoSpec
+	q.mu.Unlock()
+	go func() { q.due <- key }()
+}
+
+// Len returns the number of pending jobs.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Run processes jobs as they become due, until the context is done.
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case key := <-q.due:
+			q.mu.Lock()
+			repoSpec, ok := q.pending[key]
+			delete(q.pending, key)
+			q.mu.Unlock()
+			if ok {
+				q.process(repoSpec)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverless
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/ghutil"
+)
+
+func TestQueue_CoalescesJobs(t *testing.T) {
+	processed := make(chan ghutil.GitHubProcessOrgRepoSpec, 10)
+	queue := NewQueue(20*time.Millisecond, func(repoSpec ghutil.GitHubProcessOrgRepoSpec) {
+		processed <- repoSpec
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	assert.False(t, queue.Enqueue("org/repo#42", ghutil.GitHubProcessOrgRepoSpec{Repo: "first"}))
+	assert.True(t, queue.Enqueue("org/repo#42", ghutil.GitHubProcessOrgRepoSpec{Repo: "second"}))
+	assert.False(t, queue.Enqueue("org/repo#43", ghutil.GitHubProcessOrgRepoSpec{Repo: "other"}))
+	assert.Equal(t, 2, queue.Len())
+
+	var repos []string
+	for i := 0; i < 2; i++ {
+		select {
+		case repoSpec := <-processed:
+			repos = append(repos, repoSpec.Repo)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for jobs")
+		}
+	}
+	assert.ElementsMatch(t, []string{"second", "other"}, repos)
+	assert.Equal(t, 0, queue.Len())
+	select {
+	case repoSpec := <-processed:
+		t.Errorf("unexpected job: %v", repoSpec)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandler_QueuesPullRequest(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+	queue := NewQueue(time.Hour, handler.Process)
+	handler.SetQueue(queue)
+
+	payload := pullRequestPayload("synchronize", "org")
+	resp := deliver(handler, "pull_request", payload, sign(payload))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "queued: org/repo#42\n", resp.Body.String())
+	resp = deliver(handler, "pull_request", payload, sign(payload))
+	assert.Equal(t, "queued: org/repo#42 (coalesced)\n", resp.Body.String())
+	assert.Equal(t, 1, queue.Len())
+	assert.Equal(t, 0, len(processed))
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package serverless runs the bot in response to GitHub webhook deliveries
+// rather than on a schedule, e.g., as a Google Cloud Function or an AWS Lambda
+// function URL, each of which can serve an `http.Handler`. A Cloud Function
+// entrypoint, for example, only needs to construct the handler once:
+//
+//	var handler = serverless.NewHandler(ghc, repoSpec, claSigners, secret)
+//
+//	func CheckCLA(w http.ResponseWriter, r *http.Request) {
+//		handler.ServeHTTP(w, r)
+//	}
+package serverless
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v21/github"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+	"github.com/google/code-review-bot/logging"
+)
+
+// Signature256Header is the header with the SHA-256 signature of a webhook
+// delivery; the SHA-1 signature GitHub also sends isn't accepted.
+const Signature256Header = "X-Hub-Signature-256"
+
+// maxPayloadSize is the size of the largest webhook payload GitHub delivers.
+const maxPayloadSize = 25 << 20
+
+// pullRequestActions are the actions of `pull_request` events which may change
+// the compliance or labels of a pull request.
+var pullRequestActions = map[string]bool{
+	"opened":      true,
+	"reopened":    true,
+	"synchronize": true,
+	"labeled":     true,
+	"unlabeled":   true,
+}
+
+// Handler processes the pull request of each `pull_request` event delivered
+// to it, the same way as the `crbot` command does with the `-pr` flag. If the
+// bot runs as a GitHub App, the `installation` and `installation_repositories`
+// events keep the monitored repos in sync with those the App is installed on.
+type Handler struct {
+	ghc        ghutil.GitHubUtilApi
+	repoSpec   ghutil.GitHubProcessOrgRepoSpec
+	claSigners config.ClaSigners
+	secret     []byte
+
+	// uninstalled are the repos, as keyed by `repoKey`, from which the
+	// GitHub App has been removed.
+	mu          sync.Mutex
+	uninstalled map[string]bool
+
+	queue *Queue
+
+	repoLabels *ghutil.RepoLabelCache
+}
+
+// NewHandler returns a handler processing pull requests with the client and
+// the settings of `repoSpec`, whose `Org`, `Repo`, and `Pulls` are set from
+// each event; if its `Org` or `Repo` are set, events from other orgs or repos
+// are ignored. Deliveries must be signed with the webhook `secret` via
+// `Signature256Header`; it panics if the secret is empty, as unsigned
+// deliveries could be forged by anyone.
+func NewHandler(ghc ghutil.GitHubUtilApi, repoSpec ghutil.GitHubProcessOrgRepoSpec, claSigners config.ClaSigners, secret []byte) *Handler {
+	if len(secret) == 0 {
+		panic("serverless: NewHandler called with an empty webhook secret")
+	}
+	return &Handler{
+		ghc:         ghc,
+		repoSpec:    repoSpec,
+		claSigners:  claSigners,
+		secret:      secret,
+		uninstalled: make(map[string]bool),
+	}
+}
+
+// SetQueue makes the handler add the jobs for its deliveries to the queue,
+// which must process them via `Process`, instead of processing them before
+// responding to each delivery.
+func (h *Handler) SetQueue(queue *Queue) {
+	h.queue = queue
+}
+
+// SetRepoLabelCache makes the handler drop the cached label status of a repo
+// whenever GitHub reports that its labels changed via a `label` event.
+func (h *Handler) SetRepoLabelCache(cache *ghutil.RepoLabelCache) {
+	h.repoLabels = cache
+}
+
+// Process processes the pull requests of the repo spec with the handler's
+// client and CLA signers.
+func (h *Handler) Process(repoSpec ghutil.GitHubProcessOrgRepoSpec) {
+	// Errors suppressed as repeats are counted per job, rather than until
+	// the server exits.
+	defer logging.FlushRepeats()
+	if _, err := h.ghc.ProcessOrgRepo(repoSpec, h.claSigners); err != nil {
+		logging.Errorf("Error processing %s/%s: %s", repoSpec.Org, repoSpec.Repo, err)
+	}
+}
+
+// ServeHTTP handles a single webhook delivery. Events which don't require
+// processing are acknowledged, so that GitHub doesn't report them as failed.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := h.readPayload(r)
+	if err != nil {
+		logging.Errorf("Rejecting webhook delivery %s: %s", github.DeliveryID(r), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.verifySignature(r, payload); err != nil {
+		logging.Errorf("Rejecting webhook delivery %s: %s", github.DeliveryID(r), err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	eventType := github.WebHookType(r)
+	if eventType == "ping" {
+		fmt.Fprintln(w, "pong")
+		return
+	} else if eventType != "pull_request" && eventType != "installation" && eventType != "installation_repositories" && eventType != "label" {
+		fmt.Fprintf(w, "ignored: event %q\n", eventType)
+		return
+	}
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch event := event.(type) {
+	case *github.InstallationEvent:
+		switch event.GetAction() {
+		case "created", "unsuspend":
+			h.installRepos(w, event.GetInstallation().GetAccount().GetLogin(), event.Repositories, nil)
+		case "deleted", "suspend":
+			h.installRepos(w, event.GetInstallation().GetAccount().GetLogin(), nil, event.Repositories)
+		default:
+			fmt.Fprintf(w, "ignored: action %q\n", event.GetAction())
+		}
+	case *github.InstallationRepositoriesEvent:
+		h.installRepos(w, event.GetInstallation().GetAccount().GetLogin(), event.RepositoriesAdded, event.RepositoriesRemoved)
+	case *github.LabelEvent:
+		orgName := event.GetRepo().GetOwner().GetLogin()
+		repoName := event.GetRepo().GetName()
+		if h.repoLabels == nil {
+			fmt.Fprintf(w, "ignored: event %q\n", eventType)
+			return
+		}
+		h.repoLabels.Invalidate(orgName, repoName)
+		fmt.Fprintf(w, "invalidated: labels of %s/%s\n", orgName, repoName)
+	case *github.PullRequestEvent:
+		h.processPullRequest(w, r, event)
+	}
+}
+
+// processPullRequest processes the pull request of the event, unless it
+// doesn't require processing.
+func (h *Handler) processPullRequest(w http.ResponseWriter, r *http.Request, event *github.PullRequestEvent) {
+	if reason := h.ignoreReason(event); reason != "" {
+		fmt.Fprintf(w, "ignored: %s\n", reason)
+		return
+	}
+
+	repoSpec := h.repoSpec
+	repoSpec.Org = event.GetRepo().GetOwner().GetLogin()
+	repoSpec.Repo = event.GetRepo().GetName()
+	repoSpec.Pulls = []int{event.GetNumber()}
+	repoSpec.ResumeFrom = nil
+	logging.Infof("Webhook delivery %s: %s event for %s/%s#%d", github.DeliveryID(r),
+		event.GetAction(), repoSpec.Org, repoSpec.Repo, event.GetNumber())
+	h.dispatch(w, fmt.Sprintf("%s/%s#%d", repoSpec.Org, repoSpec.Repo, event.GetNumber()), repoSpec)
+}
+
+// installRepos starts monitoring the repos to which the GitHub App has been
+// added, processing all of their open pull requests right away, and stops
+// monitoring those from which it has been removed, ignoring any further
+// events from them, e.g., from an org-wide webhook.
+func (h *Handler) installRepos(w http.ResponseWriter, orgName string, added []*github.Repository, removed []*github.Repository) {
+	h.mu.Lock()
+	for _, repo := range removed {
+		h.uninstalled[repoKey(orgName, repo.GetName())] = true
+	}
+	for _, repo := range added {
+		delete(h.uninstalled, repoKey(orgName, repo.GetName()))
+	}
+	h.mu.Unlock()
+
+	for _, repo := range removed {
+		logging.Infof("Stopped monitoring %s/%s: GitHub App uninstalled", orgName, repo.GetName())
+		fmt.Fprintf(w, "stopped: %s/%s\n", orgName, repo.GetName())
+	}
+	for _, repo := range added {
+		if reason := h.scopeReason(orgName, repo.GetName()); reason != "" {
+			fmt.Fprintf(w, "ignored: %s\n", reason)
+			continue
+		}
+		repoSpec := h.repoSpec
+		repoSpec.Org = orgName
+		repoSpec.Repo = repo.GetName()
+		repoSpec.Pulls = nil
+		repoSpec.ResumeFrom = nil
+		logging.Infof("Started monitoring %s/%s: GitHub App installed", orgName, repo.GetName())
+		h.dispatch(w, fmt.Sprintf("%s/%s", orgName, repo.GetName()), repoSpec)
+	}
+}
+
+// dispatch processes the repo spec right away or, if the handler has a queue,
+// adds it to the queue, identifying the job by `name`.
+func (h *Handler) dispatch(w http.ResponseWriter, name string, repoSpec ghutil.GitHubProcessOrgRepoSpec) {
+	if h.queue == nil {
+		h.Process(repoSpec)
+		fmt.Fprintf(w, "processed: %s\n", name)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	if h.queue.Enqueue(strings.ToLower(name), repoSpec) {
+		fmt.Fprintf(w, "queued: %s (coalesced)\n", name)
+	} else {
+		fmt.Fprintf(w, "queued: %s\n", name)
+	}
+}
+
+// readPayload reads the JSON payload of the delivery.
+func (h *Handler) readPayload(r *http.Request) ([]byte, error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		return nil, fmt.Errorf("unsupported content type %q; the webhook must deliver application/json", contentType)
+	}
+	return ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxPayloadSize))
+}
+
+// verifySignature verifies that the payload was signed with the webhook secret
+// of the handler via the SHA-256 signature.
+func (h *Handler) verifySignature(r *http.Request, payload []byte) error {
+	signature := r.Header.Get(Signature256Header)
+	if signature == "" {
+		return fmt.Errorf("missing %s header; the webhook must be configured with a secret", Signature256Header)
+	}
+	if err := github.ValidateSignature(signature, payload, h.secret); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	return nil
+}
+
+// ignoreReason returns why the event doesn't require processing, if it
+// doesn't.
+func (h *Handler) ignoreReason(event *github.PullRequestEvent) string {
+	if !pullRequestActions[event.GetAction()] {
+		return fmt.Sprintf("action %q", event.GetAction())
+	}
+	orgName := event.GetRepo().GetOwner().GetLogin()
+	repoName := event.GetRepo().GetName()
+	if reason := h.scopeReason(orgName, repoName); reason != "" {
+		return reason
+	}
+	h.mu.Lock()
+	uninstalled := h.uninstalled[repoKey(orgName, repoName)]
+	h.mu.Unlock()
+	if uninstalled {
+		return fmt.Sprintf("repo %q uninstalled", repoName)
+	}
+	return ""
+}
+
+// scopeReason returns why the repo is out of the scope of the handler, if it
+// is.
+func (h *Handler) scopeReason(orgName string, repoName string) string {
+	if h.repoSpec.Org != "" && !strings.EqualFold(orgName, h.repoSpec.Org) {
+		return fmt.Sprintf("org %q", orgName)
+	}
+	if h.repoSpec.Repo != "" && !ghutil.MatchRepo(ghutil.ParseRepoSelector(h.repoSpec.Repo), repoName) {
+		return fmt.Sprintf("repo %q", repoName)
+	}
+	return ""
+}
+
+// repoKey identifies a repo case-insensitively, as GitHub does.
+func repoKey(orgName string, repoName string) string {
+	return strings.ToLower(orgName + "/" + repoName)
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverless
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/config"
+	"github.com/google/code-review-bot/ghutil"
+)
+
+const secret = "s3cr3t"
+
+func pullRequestPayload(action string, orgName string) string {
+	return `{"action": "` + action + `", "number": 42, "repository": {"name": "repo", "owner": {"login": "` + orgName + `"}}}`
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// recordingApi records the specs it processes.
+type recordingApi struct {
+	ghutil.GitHubUtilApi
+	processed *[]ghutil.GitHubProcessOrgRepoSpec
+}
+
+func (a recordingApi) ProcessOrgRepo(repoSpec ghutil.GitHubProcessOrgRepoSpec, _ config.ClaSigners) (*ghutil.Checkpoint, error) {
+	*a.processed = append(*a.processed, repoSpec)
+	return nil, nil
+}
+
+// newTestHandler returns a handler which records the specs it processes.
+func newTestHandler(processed *[]ghutil.GitHubProcessOrgRepoSpec) *Handler {
+	ghc := recordingApi{processed: processed}
+	repoSpec := ghutil.GitHubProcessOrgRepoSpec{Org: "Org", UpdateRepo: true}
+	return NewHandler(ghc, repoSpec, config.ClaSigners{}, []byte(secret))
+}
+
+func deliver(handler http.Handler, eventType string, payload string, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", eventType)
+	if signature != "" {
+		req.Header.Set(Signature256Header, signature)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandler_ProcessesPullRequest(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	payload := pullRequestPayload("synchronize", "org")
+	resp := deliver(handler, "pull_request", payload, sign(payload))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "processed: org/repo#42\n", resp.Body.String())
+	assert.Equal(t, []ghutil.GitHubProcessOrgRepoSpec{{
+		Org:        "org",
+		Repo:       "repo",
+		Pulls:      []int{42},
+		UpdateRepo: true,
+	}}, processed)
+}
+
+func TestHandler_RejectsInvalidSignature(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	payload := pullRequestPayload("opened", "org")
+	resp := deliver(handler, "pull_request", payload, sign("tampered"))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "invalid signature")
+	resp = deliver(handler, "pull_request", payload, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "missing X-Hub-Signature-256 header")
+	assert.Equal(t, 0, len(processed))
+}
+
+func TestHandler_RejectsSHA1Signature(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	payload := pullRequestPayload("opened", "org")
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(payload))
+	req := httptest.NewRequest("POST", "/", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "missing X-Hub-Signature-256 header")
+	assert.Equal(t, 0, len(processed))
+}
+
+func TestNewHandler_RequiresSecret(t *testing.T) {
+	assert.Panics(t, func() {
+		NewHandler(recordingApi{}, ghutil.GitHubProcessOrgRepoSpec{}, config.ClaSigners{}, nil)
+	})
+}
+
+func TestHandler_IgnoresEvents(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	testCases := []struct {
+		eventType string
+		payload   string
+		response  string
+	}{
+		{"ping", `{"zen": "Keep it logically awesome."}`, "pong\n"},
+		{"issues", `{"action": "opened"}`, "ignored: event \"issues\"\n"},
+		{"pull_request", pullRequestPayload("closed", "org"), "ignored: action \"closed\"\n"},
+		{"pull_request", pullRequestPayload("opened", "other"), "ignored: org \"other\"\n"},
+	}
+	for _, testCase := range testCases {
+		resp := deliver(handler, testCase.eventType, testCase.payload, sign(testCase.payload))
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, testCase.response, resp.Body.String())
+	}
+	assert.Equal(t, 0, len(processed))
+}
+
+func TestHandler_InstallationLifecycle(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	installed := `{"action": "created", "installation": {"account": {"login": "org"}}, "repositories": [{"name": "repo"}]}`
+	resp := deliver(handler, "installation", installed, sign(installed))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "processed: org/repo\n", resp.Body.String())
+	assert.Equal(t, []ghutil.GitHubProcessOrgRepoSpec{{
+		Org:        "org",
+		Repo:       "repo",
+		UpdateRepo: true,
+	}}, processed)
+
+	removed := `{"action": "removed", "installation": {"account": {"login": "org"}}, "repositories_removed": [{"name": "repo"}]}`
+	resp = deliver(handler, "installation_repositories", removed, sign(removed))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "stopped: org/repo\n", resp.Body.String())
+
+	payload := pullRequestPayload("opened", "org")
+	resp = deliver(handler, "pull_request", payload, sign(payload))
+	assert.Equal(t, "ignored: repo \"repo\" uninstalled\n", resp.Body.String())
+	assert.Equal(t, 1, len(processed))
+
+	added := `{"action": "added", "installation": {"account": {"login": "org"}}, "repositories_added": [{"name": "repo"}]}`
+	resp = deliver(handler, "installation_repositories", added, sign(added))
+	assert.Equal(t, "processed: org/repo\n", resp.Body.String())
+	resp = deliver(handler, "pull_request", payload, sign(payload))
+	assert.Equal(t, "processed: org/repo#42\n", resp.Body.String())
+	assert.Equal(t, 3, len(processed))
+}
+
+func TestHandler_InstallationOutOfScope(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	installed := `{"action": "created", "installation": {"account": {"login": "other"}}, "repositories": [{"name": "repo"}]}`
+	resp := deliver(handler, "installation", installed, sign(installed))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "ignored: org \"other\"\n", resp.Body.String())
+	assert.Equal(t, 0, len(processed))
+}
+
+func TestHandler_LabelEventInvalidatesCache(t *testing.T) {
+	var processed []ghutil.GitHubProcessOrgRepoSpec
+	handler := newTestHandler(&processed)
+
+	payload := `{"action": "created", "label": {"name": "cla: yes"}, "repository": {"name": "repo", "owner": {"login": "org"}}}`
+	resp := deliver(handler, "label", payload, sign(payload))
+	assert.Equal(t, "ignored: event \"label\"\n", resp.Body.String())
+
+	cache := ghutil.NewRepoLabelCache(time.Hour)
+	cache.Put("org", "repo", ghutil.ResolveLabels(config.Labels{}), ghutil.RepoClaLabelStatus{})
+	handler.SetRepoLabelCache(cache)
+	resp = deliver(handler, "label", payload, sign(payload))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "invalidated: labels of org/repo\n", resp.Body.String())
+	_, ok := cache.Get("org", "repo", ghutil.ResolveLabels(config.Labels{}))
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(processed))
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Key prefixes of the entries kept in Redis, so that the database can be
+// shared with other applications.
+const (
+	redisPullPrefix  = "crbot:pull:"
+	redisClaimPrefix = "crbot:claim:"
+)
+
+// redisTimeout bounds connecting to Redis and each command sent to it.
+const redisTimeout = 10 * time.Second
+
+// RedisStore is a `Store` backed by a Redis server, which multiple instances
+// of the bot can share. It speaks the Redis protocol (RESP) directly over a
+// single connection, needing only the handful of commands below. The
+// connection is dropped on any I/O error, since a reply may be left partially
+// read, and redialed by the next command.
+type RedisStore struct {
+	host string
+	user *url.Userinfo
+	db   string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// redisError is an error reply from Redis, after which the connection is
+// still usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// OpenRedis connects to the Redis server at the given URL, of the form
+// "redis://[[USER]:PASSWORD@]HOST[:PORT][/DB]".
+func OpenRedis(location string) (*RedisStore, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	store := &RedisStore{
+		host: host,
+		user: u.User,
+		db:   strings.TrimPrefix(u.Path, "/"),
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.connect(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// connect dials Redis, authenticates and selects the database; `mu` must be
+// held.
+func (s *RedisStore) connect() error {
+	conn, err := net.DialTimeout("tcp", s.host, redisTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+
+	if password, ok := s.user.Password(); ok {
+		args := []string{"AUTH", password}
+		if username := s.user.Username(); username != "" {
+			args = []string{"AUTH", username, password}
+		}
+		if _, err := s.send(args...); err != nil {
+			s.disconnect()
+			return fmt.Errorf("error authenticating to Redis: %s", err)
+		}
+	}
+	if s.db != "" {
+		if _, err := s.send("SELECT", s.db); err != nil {
+			s.disconnect()
+			return fmt.Errorf("error selecting Redis database %s: %s", s.db, err)
+		}
+	}
+	return nil
+}
+
+// disconnect closes the connection, so that the next command redials; `mu`
+// must be held.
+func (s *RedisStore) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.conn = nil
+	s.reader = nil
+}
+
+// Get returns the state of the pull request.
+func (s *RedisStore) Get(key PullKey) (PullState, error) {
+	var pullState PullState
+	reply, err := s.do("GET", redisPullPrefix+key.String())
+	if err != nil || reply == nil {
+		return pullState, err
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return pullState, fmt.Errorf("unexpected reply from Redis: %v", reply)
+	}
+	if err := json.Unmarshal([]byte(data), &pullState); err != nil {
+		return pullState, fmt.Errorf("error parsing state of %s: %s", key, err)
+	}
+	return pullState, nil
+}
+
+// Put records the state of the pull request.
+func (s *RedisStore) Put(key PullKey, state PullState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	_, err = s.do("SET", redisPullPrefix+key.String(), string(data))
+	return err
+}
+
+// Delete forgets the state of the pull request.
+func (s *RedisStore) Delete(key PullKey) error {
+	_, err := s.do("DEL", redisPullPrefix+key.String())
+	return err
+}
+
+// Claim marks the named action as taken, expiring after `ttl`; Redis
+// guarantees that only one of several concurrent claims succeeds.
+func (s *RedisStore) Claim(name string, ttl time.Duration) (bool, error) {
+	millis := ttl.Nanoseconds() / int64(time.Millisecond)
+	if millis < 1 {
+		millis = 1
+	}
+	reply, err := s.do("SET", redisClaimPrefix+name, "1", "NX", "PX", strconv.FormatInt(millis, 10))
+	return reply != nil, err
+}
+
+// Close closes the connection to Redis.
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.reader = nil
+	return err
+}
+
+// do sends a command to Redis, redialing if the connection was dropped, and
+// returns its reply: a string for simple and bulk strings, an int64 for
+// integers, nil for null replies, or a slice of replies for arrays; error
+// replies are returned as errors.
+func (s *RedisStore) do(args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return nil, err
+		}
+	}
+	return s.send(args...)
+}
+
+// send sends a command over the current connection and reads its reply,
+// dropping the connection on anything but an error reply; `mu` must be held.
+func (s *RedisStore) send(args ...string) (interface{}, error) {
+	reply, err := s.roundTrip(args)
+	if err != nil {
+		if _, ok := err.(redisError); !ok {
+			s.disconnect()
+		}
+	}
+	return reply, err
+}
+
+// roundTrip writes a command and reads its reply.
+func (s *RedisStore) roundTrip(args []string) (interface{}, error) {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := s.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(s.conn, command.String()); err != nil {
+		return nil, err
+	}
+	return readRedisReply(s.reader)
+}
+
+// readRedisReply reads a single RESP reply.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply from Redis")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		replies := make([]interface{}, count)
+		for i := range replies {
+			if replies[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply from Redis: %q", line)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/state"
+)
+
+// fakeRedis serves the subset of Redis commands used by `RedisStore`, without
+// expiring keys.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string]string
+	commands [][]string
+	// drop makes the server close the connection instead of replying to
+	// the next command.
+	drop bool
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := &fakeRedis{
+		listener: listener,
+		password: password,
+		data:     make(map[string]string),
+	}
+	go server.serve()
+	return server
+}
+
+func (f *fakeRedis) URL(userinfo string) string {
+	return fmt.Sprintf("redis://%s%s/2", userinfo, f.listener.Addr())
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := f.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		if f.drop {
+			f.drop = false
+			f.mu.Unlock()
+			return
+		}
+		var reply string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authenticated = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case cmd == "SELECT":
+			reply = "+OK\r\n"
+		case cmd == "GET":
+			if value, ok := f.data[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case cmd == "SET":
+			_, exists := f.data[args[1]]
+			if len(args) > 3 && strings.ToUpper(args[3]) == "NX" && exists {
+				reply = "$-1\r\n"
+			} else {
+				f.data[args[1]] = args[2]
+				reply = "+OK\r\n"
+			}
+		case cmd == "DEL":
+			_, exists := f.data[args[1]]
+			delete(f.data, args[1])
+			reply = ":0\r\n"
+			if exists {
+				reply = ":1\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		io.WriteString(conn, reply)
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	store, err := state.Open(server.URL(":secret@"))
+	assert.Nil(t, err)
+	defer store.Close()
+	assert.IsType(t, &state.RedisStore{}, store)
+
+	pullState, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, pullState)
+
+	pullState = state.PullState{HeadSHA: "abc123", Labels: []string{"cla: yes"}}
+	assert.Nil(t, store.Put(key, pullState))
+	loaded, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, pullState, loaded)
+
+	assert.Nil(t, store.Delete(key))
+	loaded, err = store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, loaded)
+
+	claimed, err := store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	claimed, err = store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, []string{"AUTH", "secret"}, server.commands[0])
+	assert.Equal(t, []string{"SELECT", "2"}, server.commands[1])
+	assert.Equal(t, []string{"SET", "crbot:claim:org/repo#42:comment", "1", "NX", "PX", "60000"}, server.commands[len(server.commands)-1])
+}
+
+func TestRedisStore_WrongPassword(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	_, err := state.Open(server.URL(":wrong@"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "WRONGPASS")
+}
+
+func TestRedisStore_RedialsAfterError(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	store, err := state.Open(server.URL(":secret@"))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	pullState := state.PullState{HeadSHA: "abc123"}
+	assert.Nil(t, store.Put(key, pullState))
+
+	server.mu.Lock()
+	server.drop = true
+	server.mu.Unlock()
+	_, err = store.Get(key)
+	assert.NotNil(t, err)
+
+	loaded, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, pullState, loaded)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	commands := server.commands[len(server.commands)-3:]
+	assert.Equal(t, []string{"AUTH", "secret"}, commands[0])
+	assert.Equal(t, []string{"SELECT", "2"}, commands[1])
+	assert.Equal(t, "GET", commands[2][0])
+}
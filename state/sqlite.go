@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite
+// +build sqlite
+
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// Registers the "sqlite3" driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSchema creates the tables holding the claims, and the state of each
+// pull request as JSON, so that new fields of `PullState` don't require schema
+// migrations.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS pulls (
+	org TEXT NOT NULL,
+	repo TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	state TEXT NOT NULL,
+	PRIMARY KEY (org, repo, number)
+);
+CREATE TABLE IF NOT EXISTS claims (
+	name TEXT PRIMARY KEY,
+	expires INTEGER NOT NULL
+)`
+
+// SQLiteStore is a `Store` backed by an SQLite database file; unlike
+// `FileStore`, each change is persisted immediately, so no state is lost if
+// the bot is interrupted.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// openSQLite opens the SQLite store for `Open`.
+func openSQLite(filename string) (Store, error) {
+	return OpenSQLite(filename)
+}
+
+// OpenSQLite opens the SQLite database file, creating it if needed.
+func OpenSQLite(filename string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing state database '%s': %s", filename, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Get returns the state of the pull request.
+func (s *SQLiteStore) Get(key PullKey) (PullState, error) {
+	var pullState PullState
+	var data string
+	err := s.db.QueryRow("SELECT state FROM pulls WHERE org = ? AND repo = ? AND number = ?",
+		key.Org, key.Repo, key.Number).Scan(&data)
+	if err == sql.ErrNoRows {
+		return pullState, nil
+	} else if err != nil {
+		return pullState, err
+	}
+	if err := json.Unmarshal([]byte(data), &pullState); err != nil {
+		return pullState, fmt.Errorf("error parsing state of %s: %s", key, err)
+	}
+	return pullState, nil
+}
+
+// Put records the state of the pull request.
+func (s *SQLiteStore) Put(key PullKey, state PullState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("INSERT OR REPLACE INTO pulls (org, repo, number, state) VALUES (?, ?, ?, ?)",
+		key.Org, key.Repo, key.Number, string(data))
+	return err
+}
+
+// Delete forgets the state of the pull request.
+func (s *SQLiteStore) Delete(key PullKey) error {
+	_, err := s.db.Exec("DELETE FROM pulls WHERE org = ? AND repo = ? AND number = ?",
+		key.Org, key.Repo, key.Number)
+	return err
+}
+
+// Claim marks the named action as taken until `ttl` from now, replacing any
+// expired claim.
+func (s *SQLiteStore) Claim(name string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	result, err := s.db.Exec(`INSERT INTO claims (name, expires) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET expires = excluded.expires WHERE claims.expires <= ?`,
+		name, now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build sqlite
+// +build sqlite
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/state"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	location := state.SQLitePrefix + filepath.Join(dir, "state.db")
+
+	store, err := state.Open(location)
+	assert.Nil(t, err)
+	assert.IsType(t, &state.SQLiteStore{}, store)
+
+	pullState, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, pullState)
+
+	pullState = state.PullState{
+		Commented: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		HeadSHA:   "abc123",
+		Labels:    []string{"cla: no"},
+	}
+	assert.Nil(t, store.Put(key, pullState))
+	assert.Nil(t, store.Close())
+
+	// Changes are persisted across reopening the database.
+	store, err = state.Open(location)
+	assert.Nil(t, err)
+	defer store.Close()
+	loaded, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, pullState, loaded)
+
+	assert.Nil(t, store.Delete(key))
+	loaded, err = store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, loaded)
+
+	claimed, err := store.Claim("org/repo#42:comment", -time.Second)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	claimed, err = store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed, "expired claims can be taken again")
+	claimed, err = store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+}
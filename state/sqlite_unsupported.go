@@ -0,0 +1,28 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !sqlite
+// +build !sqlite
+
+package state
+
+import (
+	"errors"
+)
+
+// openSQLite reports that SQLite stores are not available, as the SQLite
+// driver requires cgo, which not all builds of the bot have.
+func openSQLite(filename string) (Store, error) {
+	return nil, errors.New("SQLite state stores require building with `-tags sqlite`")
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package state persists what the bot has done to each pull request across
+// runs, e.g., when it last commented, so that it can avoid repeating itself.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PullKey identifies a single pull request.
+type PullKey struct {
+	Org    string
+	Repo   string
+	Number int
+}
+
+// String returns the key in the form "org/repo#42".
+func (k PullKey) String() string {
+	return fmt.Sprintf("%s/%s#%d", k.Org, k.Repo, k.Number)
+}
+
+// PullState is what the bot remembers about a single pull request.
+type PullState struct {
+	// Commented is when the bot posted the comment explaining why the
+	// pull request is not compliant.
+	Commented time.Time `json:"commented"`
+
+	// Reminders is the number of reminders posted since, the last of
+	// which was posted at LastReminder.
+	Reminders    int       `json:"reminders,omitempty"`
+	LastReminder time.Time `json:"last_reminder"`
+	// CommentedSHA is the head commit of the pull request when the bot
+	// last commented, and CommentsAtSHA the number of comments posted
+	// while it was the head commit, to cap them.
+	CommentedSHA  string `json:"commented_sha,omitempty"`
+	CommentsAtSHA int    `json:"comments_at_sha,omitempty"`
+	// HeadSHA, Labels (sorted and lowercased), and Fingerprint record
+	// the head commit and labels of the pull request when it was last
+	// processed, and a digest of the config it was processed with, to
+	// skip it while none of them change.
+	HeadSHA     string   `json:"head_sha,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	// Status is the compliance status of the pull request when it was
+	// last processed, as in `report.PullRequest.Status`, to detect when
+	// a compliant pull request regresses.
+	Status string `json:"status,omitempty"`
+}
+
+// Store persists the state of pull requests. The state of a pull request
+// which the store has no record of is the zero `PullState`.
+type Store interface {
+	Get(key PullKey) (PullState, error)
+	Put(key PullKey, state PullState) error
+	Delete(key PullKey) error
+
+	// Claim marks the named action (e.g., posting a particular comment)
+	// as taken for the duration of `ttl`, returning false if it already
+	// was, so that multiple instances of the bot sharing the store don't
+	// take the same action twice.
+	Claim(name string, ttl time.Duration) (bool, error)
+
+	// Close persists any pending changes and releases the store.
+	Close() error
+}
+
+// Prefixes of the state locations passed to `Open` which select a backend
+// other than a JSON file.
+const (
+	SQLitePrefix = "sqlite:"
+	RedisPrefix  = "redis://"
+)
+
+// Open opens the store at the given location: an SQLite database for
+// "sqlite:PATH", if built with `-tags sqlite`, a Redis server for "redis://[:PASSWORD@]HOST:PORT[/DB]", or
+// a JSON file otherwise.
+func Open(location string) (Store, error) {
+	if strings.HasPrefix(location, SQLitePrefix) {
+		return openSQLite(strings.TrimPrefix(location, SQLitePrefix))
+	} else if strings.HasPrefix(location, RedisPrefix) {
+		return OpenRedis(location)
+	}
+	return OpenFile(location)
+}
+
+// MemoryStore is a `Store` which keeps the state in memory only.
+type MemoryStore struct {
+	mu     sync.Mutex
+	pulls  map[string]PullState
+	claims map[string]time.Time
+}
+
+// NewMemoryStore creates an empty `MemoryStore`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		pulls:  make(map[string]PullState),
+		claims: make(map[string]time.Time),
+	}
+}
+
+// Get returns the state of the pull request.
+func (s *MemoryStore) Get(key PullKey) (PullState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pulls[key.String()], nil
+}
+
+// Put records the state of the pull request.
+func (s *MemoryStore) Put(key PullKey, state PullState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pulls[key.String()] = state
+	return nil
+}
+
+// Delete forgets the state of the pull request.
+func (s *MemoryStore) Delete(key PullKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pulls, key.String())
+	return nil
+}
+
+// Claim marks the named action as taken until `ttl` from now; claims are
+// never persisted, as they only matter while instances run concurrently.
+func (s *MemoryStore) Claim(name string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if expires, ok := s.claims[name]; ok && now.Before(expires) {
+		return false, nil
+	}
+	s.claims[name] = now.Add(ttl)
+	return true, nil
+}
+
+// Close does nothing, as there is nothing to persist.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// FileStore is a `Store` which keeps the state in memory, loaded from and
+// saved to a JSON file; it is meant for a single instance of the bot.
+type FileStore struct {
+	*MemoryStore
+	filename string
+}
+
+// OpenFile loads the state from the JSON file, if it exists; the state is
+// saved back to the file by `Close`.
+func OpenFile(filename string) (*FileStore, error) {
+	store := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		filename:    filename,
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.pulls); err != nil {
+		return nil, fmt.Errorf("error parsing state file '%s': %s", filename, err)
+	}
+	return store, nil
+}
+
+// Close saves the state to the file, replacing it atomically so that an
+// interrupted run cannot leave a truncated file behind.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.pulls, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.filename), filepath.Base(s.filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(append(data, '\n')); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), s.filename)
+}
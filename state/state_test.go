@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/code-review-bot/state"
+)
+
+var key = state.PullKey{Org: "org", Repo: "repo", Number: 42}
+
+func TestPullKey_String(t *testing.T) {
+	assert.Equal(t, "org/repo#42", key.String())
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := state.NewMemoryStore()
+
+	pullState, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, pullState)
+
+	commented := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Nil(t, store.Put(key, state.PullState{Commented: commented}))
+	pullState, err = store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, commented, pullState.Commented)
+
+	assert.Nil(t, store.Delete(key))
+	pullState, err = store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, state.PullState{}, pullState)
+}
+
+func TestMemoryStore_Claim(t *testing.T) {
+	store := state.NewMemoryStore()
+
+	claimed, err := store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	claimed, err = store.Claim("org/repo#42:comment", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+
+	// Expired claims can be taken again.
+	claimed, err = store.Claim("org/repo#42:review", -time.Second)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	claimed, err = store.Claim("org/repo#42:review", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+}
+
+func TestFileStore_SavesAndLoads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "state.json")
+
+	// A missing file is an empty store.
+	store, err := state.OpenFile(filename)
+	assert.Nil(t, err)
+	pullState := state.PullState{
+		Commented:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Reminders:    2,
+		LastReminder: time.Date(2026, 1, 16, 3, 4, 5, 0, time.UTC),
+	}
+	assert.Nil(t, store.Put(key, pullState))
+	assert.Nil(t, store.Close())
+
+	store, err = state.OpenFile(filename)
+	assert.Nil(t, err)
+	loaded, err := store.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, pullState, loaded)
+}
+
+func TestFileStore_InvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "state.json")
+	assert.Nil(t, ioutil.WriteFile(filename, []byte("not json"), 0644))
+
+	_, err = state.OpenFile(filename)
+	assert.NotNil(t, err)
+}